@@ -15,6 +15,8 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
@@ -59,9 +61,21 @@ func main() {
 	// Initialize centralized dependency container
 	dependencies := container.NewServiceContainer(db, validator)
 
+	// Load exchange rates from the configured file, keeping rates already stored if it fails
+	if cfg.App.ExchangeRatesFile != "" {
+		result, err := dependencies.ExchangeRateUseCase.LoadExchangeRatesFile(context.Background(), cfg.App.ExchangeRatesFile)
+		if err != nil {
+			logger.Error("Failed to load exchange rates: " + err.Error())
+		} else {
+			logger.Info(fmt.Sprintf("Loaded %d exchange rates from %s", result.Loaded, cfg.App.ExchangeRatesFile))
+		}
+	}
+
 	// Start background workers
 	cronWorker := dependencies.CronWorker
-	cronWorker.Start()
+	if err := cronWorker.Start(); err != nil {
+		logger.Fatal("Failed to start cron worker: " + err.Error())
+	}
 	logger.Info("Cron worker started successfully")
 
 	// Setup routes with dependencies
@@ -35,7 +35,6 @@ const docTemplate = `{
                 "tags": [
                     "health"
                 ],
-                "summary": "Basic health check",
                 "responses": {
                     "200": {
                         "description": "{\"status\": \"ok\", \"service\": \"finance-manager-go\", \"message\": \"Service is running\"}",
@@ -47,6 +46,645 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/worker/balance-sync": {
+            "post": {
+                "description": "Queue a manual run recomputing every wallet balance from its transactions and correcting the drifted ones. Returns the queued run right away; poll it with GET /api/v1/workers/jobs/runs/{id}. The report of drifted wallets is stored for auditing and referenced by the run summary; with dry_run nothing is written and the whole report is the run summary",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Worker"
+                ],
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Report drifted balances without correcting or storing anything",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helpers.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.JobRunResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "The job is already running on this or another replica",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/worker/balance-sync/reports": {
+            "get": {
+                "description": "List the reports of past balance sync runs, newest first. Entries are only returned by the report detail",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Worker"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helpers.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.BalanceSyncReportResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/worker/balance-sync/reports/{id}": {
+            "get": {
+                "description": "Get a balance sync report with the stored and recomputed balance of every wallet that drifted or failed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Worker"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helpers.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.BalanceSyncReportResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/worker/budget-alerts": {
+            "post": {
+                "description": "Queue a manual run evaluating budget thresholds and emailing owners (alerts already sent this period are skipped). Returns the queued run right away; poll it with GET /api/v1/workers/jobs/runs/{id}",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Worker"
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helpers.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.JobRunResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "The job is already running on this or another replica",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/worker/recurring-transactions": {
+            "post": {
+                "description": "Queue a manual run posting every due recurring transaction occurrence (already posted occurrences are skipped). Returns the queued run right away; poll it with GET /api/v1/workers/jobs/runs/{id}",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Worker"
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helpers.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.JobRunResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "The job is already running on this or another replica",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/worker/status": {
+            "get": {
+                "description": "Get the current status of the balance sync worker. Admins also get the instance ID of this replica and which replica runs each running job.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Worker"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/workers/jobs": {
+            "get": {
+                "description": "List every scheduled job with its schedule, next run, last run, last success and number of failed runs since then",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Worker"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helpers.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.JobStatusResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/workers/jobs/runs/{id}": {
+            "get": {
+                "description": "Get a scheduled or manual job run with its status and, once it finished, its duration, error and result summary. Poll it after queueing a manual run",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Worker"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job run ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helpers.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.JobRunResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/workers/jobs/{name}/pause": {
+            "post": {
+                "description": "Stop scheduling a job on every replica until it is resumed. A run in progress finishes and manual triggers still work. The change is stored and survives restarts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Worker"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "balance_sync",
+                            "recurring_transactions",
+                            "budget_alerts",
+                            "idempotency_key_cleanup"
+                        ],
+                        "type": "string",
+                        "description": "Job name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helpers.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.JobStatusResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/workers/jobs/{name}/resume": {
+            "post": {
+                "description": "Schedule a paused job again on every replica. The change is stored and survives restarts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Worker"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "balance_sync",
+                            "recurring_transactions",
+                            "budget_alerts",
+                            "idempotency_key_cleanup"
+                        ],
+                        "type": "string",
+                        "description": "Job name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helpers.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.JobStatusResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/workers/jobs/{name}/runs": {
+            "get": {
+                "description": "List the scheduled and manual runs of a job, newest first, with their status, duration, error and result summary",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Worker"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "balance_sync",
+                            "recurring_transactions",
+                            "budget_alerts",
+                            "idempotency_key_cleanup"
+                        ],
+                        "type": "string",
+                        "description": "Job name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helpers.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.JobRunResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/workers/jobs/{name}/schedule": {
+            "put": {
+                "description": "Change the cron expression of a job and optionally its time zone on every replica. A paused job stays paused. The change is stored and takes precedence over the configured schedule, also after restarts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Worker"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "balance_sync",
+                            "recurring_transactions",
+                            "budget_alerts",
+                            "idempotency_key_cleanup"
+                        ],
+                        "type": "string",
+                        "description": "Job name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New schedule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RescheduleJobRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helpers.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.JobStatusResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete the stored schedule of a job, so every replica uses its configured cron expression, time zone and enabled flag again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Worker"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "balance_sync",
+                            "recurring_transactions",
+                            "budget_alerts",
+                            "idempotency_key_cleanup"
+                        ],
+                        "type": "string",
+                        "description": "Job name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/helpers.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.JobStatusResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/health": {
             "get": {
                 "description": "Basic health check endpoint to verify service is running",
@@ -59,7 +697,6 @@ const docTemplate = `{
                 "tags": [
                     "health"
                 ],
-                "summary": "Basic health check",
                 "responses": {
                     "200": {
                         "description": "{\"status\": \"ok\", \"service\": \"finance-manager-go\", \"message\": \"Service is running\"}",
@@ -71,6 +708,36 @@ const docTemplate = `{
                 }
             }
         },
+        "/health/all": {
+            "get": {
+                "description": "Health check for all services including database and Minio",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"status\": \"ok\", \"services\": {...}}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "{\"status\": \"degraded\", \"services\": {...}}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
         "/health/db": {
             "get": {
                 "description": "Database health check with connection statistics",
@@ -83,7 +750,6 @@ const docTemplate = `{
                 "tags": [
                     "health"
                 ],
-                "summary": "Database health check",
                 "responses": {
                     "200": {
                         "description": "{\"status\": \"ok\", \"database\": \"connected\", \"connection_stats\": {...}}",
@@ -102,6 +768,137 @@ const docTemplate = `{
                 }
             }
         },
+        "/health/minio": {
+            "get": {
+                "description": "Minio health check to verify storage service connectivity",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "{\"status\": \"ok\", \"minio\": \"connected\", \"message\": \"Minio is healthy\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "{\"status\": \"error\", \"message\": \"Minio connection failed\", \"error\": \"...\"}",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/auth/change-password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change password for the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "parameters": [
+                    {
+                        "description": "Change password data",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/auth/forgot-password": {
+            "post": {
+                "description": "Send password reset email to user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "parameters": [
+                    {
+                        "description": "Forgot password data",
+                        "name": "forgot_password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ForgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/auth/login": {
             "post": {
                 "description": "Login user with email and password",
@@ -114,7 +911,6 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Login user",
                 "parameters": [
                     {
                         "description": "Login credentials",
@@ -165,7 +961,6 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Get user profile",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -200,7 +995,6 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Register a new user",
                 "parameters": [
                     {
                         "description": "Registration data",
@@ -234,6 +1028,57 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/auth/reset-password": {
+            "post": {
+                "description": "Reset user password using reset token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "parameters": [
+                    {
+                        "description": "Reset password data",
+                        "name": "reset_password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/users": {
             "get": {
                 "security": [
@@ -251,7 +1096,6 @@ const docTemplate = `{
                 "tags": [
                     "users"
                 ],
-                "summary": "Get all users",
                 "parameters": [
                     {
                         "type": "integer",
@@ -291,6 +1135,12 @@ const docTemplate = `{
                         "description": "Filter by exact name",
                         "name": "name",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated relations to preload (wallet,transactions)",
+                        "name": "preload",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -332,9 +1182,9 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new user with email, name, and password",
+                "description": "Create a new user with email, name, password and optional profile photo using form data.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
@@ -342,16 +1192,39 @@ const docTemplate = `{
                 "tags": [
                     "users"
                 ],
-                "summary": "Create a new user",
                 "parameters": [
                     {
-                        "description": "User data",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreateUserRequest"
-                        }
+                        "type": "string",
+                        "description": "Email",
+                        "name": "email",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name",
+                        "name": "name",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password",
+                        "name": "password",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Birth Date (RFC3339 format)",
+                        "name": "birth_date",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Profile Photo File (JPG/PNG, max 2MB)",
+                        "name": "profile_photo_file",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
@@ -393,7 +1266,6 @@ const docTemplate = `{
                 "tags": [
                     "users"
                 ],
-                "summary": "Get only soft deleted users",
                 "parameters": [
                     {
                         "type": "integer",
@@ -427,13 +1299,36 @@ const docTemplate = `{
                         "description": "Sort type (asc/desc)",
                         "name": "sort_type",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated relations to preload (wallet,transactions)",
+                        "name": "preload",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.UsersResponse"
+                            "type": "object",
+                            "properties": {
+                                "data": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/dto.UserResponse"
+                                    }
+                                },
+                                "message": {
+                                    "type": "string"
+                                },
+                                "meta": {
+                                    "$ref": "#/definitions/dto.PaginationMeta"
+                                },
+                                "success": {
+                                    "type": "boolean"
+                                }
+                            }
                         }
                     },
                     "500": {
@@ -496,13 +1391,36 @@ const docTemplate = `{
                         "description": "Sort type (asc/desc)",
                         "name": "sort_type",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated relations to preload (wallet,transactions)",
+                        "name": "preload",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.UsersResponse"
+                            "type": "object",
+                            "properties": {
+                                "data": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/dto.UserResponse"
+                                    }
+                                },
+                                "message": {
+                                    "type": "string"
+                                },
+                                "meta": {
+                                    "$ref": "#/definitions/dto.PaginationMeta"
+                                },
+                                "success": {
+                                    "type": "boolean"
+                                }
+                            }
                         }
                     },
                     "500": {
@@ -531,7 +1449,6 @@ const docTemplate = `{
                 "tags": [
                     "users"
                 ],
-                "summary": "Get user by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -539,6 +1456,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated relations to preload (wallet,transactions)",
+                        "name": "preload",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -574,9 +1497,9 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update user information",
+                "description": "Update user information with optional profile photo using form data.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
@@ -584,7 +1507,6 @@ const docTemplate = `{
                 "tags": [
                     "users"
                 ],
-                "summary": "Update user",
                 "parameters": [
                     {
                         "type": "string",
@@ -594,13 +1516,22 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "User data",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserRequest"
-                        }
+                        "type": "string",
+                        "description": "Name",
+                        "name": "name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Birth Date (RFC3339 format)",
+                        "name": "birth_date",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Profile Photo File (JPG/PNG, max 2MB)",
+                        "name": "profile_photo_file",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
@@ -646,7 +1577,6 @@ const docTemplate = `{
                 "tags": [
                     "users"
                 ],
-                "summary": "Delete user by ID (soft delete)",
                 "parameters": [
                     {
                         "type": "string",
@@ -692,7 +1622,6 @@ const docTemplate = `{
                 "tags": [
                     "users"
                 ],
-                "summary": "Permanently delete user by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -721,130 +1650,6 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/users/{id}/profile-photo": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Upload a profile photo for a user (JPG/PNG only, max 2MB)",
-                "consumes": [
-                    "multipart/form-data"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "users"
-                ],
-                "summary": "Upload profile photo for user",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Profile photo file (JPG/PNG, max 2MB)",
-                        "name": "profile_photo",
-                        "in": "formData",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dto.UploadProfilePhotoResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "413": {
-                        "description": "File too large",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Delete the profile photo for a user",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "users"
-                ],
-                "summary": "Delete profile photo for user",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "{\"message\": \"Profile photo deleted successfully\"}",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
         "/v1/users/{id}/restore": {
             "patch": {
                 "security": [
@@ -862,7 +1667,6 @@ const docTemplate = `{
                 "tags": [
                     "users"
                 ],
-                "summary": "Restore soft deleted user by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -896,100 +1700,280 @@ const docTemplate = `{
         "dto.AuthResponse": {
             "type": "object",
             "properties": {
-                "age": {
-                    "type": "integer",
-                    "example": 33
+                "age": {
+                    "type": "integer",
+                    "example": 33
+                },
+                "birth_date": {
+                    "type": "string",
+                    "example": "1990-01-15"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "profile_photo": {
+                    "type": "string",
+                    "example": "https://minio.example.com/public/profile-photo/2023/01/profile_photo_1641024000.jpg"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TransactionResponse"
+                    }
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01"
+                },
+                "wallets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.WalletResponse"
+                    }
+                }
+            }
+        },
+        "dto.BalanceSyncReportEntryResponse": {
+            "type": "object",
+            "properties": {
+                "corrected": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "difference": {
+                    "description": "Recomputed minus stored balance",
+                    "type": "number",
+                    "example": -50000
+                },
+                "error": {
+                    "type": "string",
+                    "example": ""
+                },
+                "recomputed_balance": {
+                    "type": "number",
+                    "example": 1450000
+                },
+                "stored_balance": {
+                    "type": "number",
+                    "example": 1500000
+                },
+                "transaction_count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "wallet_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174001"
+                }
+            }
+        },
+        "dto.BalanceSyncReportResponse": {
+            "type": "object",
+            "properties": {
+                "cancelled": {
+                    "description": "The run stopped before checking every wallet",
+                    "type": "boolean",
+                    "example": false
+                },
+                "corrected": {
+                    "description": "Drifted wallets whose balance was written, always 0 for dry runs",
+                    "type": "integer",
+                    "example": 2
+                },
+                "drifted": {
+                    "description": "Wallets whose stored balance differs from their transactions",
+                    "type": "integer",
+                    "example": 2
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "entries": {
+                    "description": "Drifted and failed wallets, left out of report lists",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BalanceSyncReportEntryResponse"
+                    }
+                },
+                "failed": {
+                    "description": "Wallets that could not be checked or corrected",
+                    "type": "integer",
+                    "example": 0
+                },
+                "finished_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:05Z"
+                },
+                "id": {
+                    "description": "Empty for dry runs, which are not stored",
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "wallets": {
+                    "description": "Number of wallets checked",
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "dto.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 8,
+                    "example": "NewPassword123!"
+                },
+                "old_password": {
+                    "type": "string",
+                    "example": "oldpassword123"
+                }
+            }
+        },
+        "dto.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "object",
+                    "additionalProperties": true
                 },
-                "birth_date": {
+                "error": {
                     "type": "string",
-                    "example": "1990-01-15T00:00:00Z"
+                    "example": "Bad Request"
                 },
-                "created_at": {
+                "message": {
                     "type": "string",
-                    "example": "2023-01-01T00:00:00Z"
-                },
+                    "example": "Invalid input data"
+                }
+            }
+        },
+        "dto.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
                 "email": {
                     "type": "string",
                     "example": "user@example.com"
+                }
+            }
+        },
+        "dto.JobRunResponse": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 5000
                 },
-                "id": {
+                "error": {
                     "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                    "example": ""
                 },
-                "name": {
+                "finished_at": {
                     "type": "string",
-                    "example": "John Doe"
+                    "example": "2024-01-01T00:00:05Z"
                 },
-                "profile_photo_file_name": {
+                "id": {
                     "type": "string",
-                    "example": "profile-photo/2024/01/profile_photo_1234567890_user123.jpg"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "profile_photo_url": {
+                "job_name": {
                     "type": "string",
-                    "example": "https://minio.example.com/public/profile-photo/2024/01/profile_photo_1234567890_user123.jpg"
+                    "example": "balance_sync"
                 },
-                "role": {
+                "started_at": {
                     "type": "string",
-                    "example": "user"
+                    "example": "2024-01-01T00:00:00Z"
                 },
-                "token": {
+                "status": {
+                    "description": "running, succeeded or failed",
                     "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                    "example": "succeeded"
                 },
-                "updated_at": {
+                "summary": {
+                    "description": "Result of the job",
+                    "type": "object"
+                },
+                "trigger": {
+                    "description": "scheduled or manual",
                     "type": "string",
-                    "example": "2023-01-01T00:00:00Z"
+                    "example": "scheduled"
                 }
             }
         },
-        "dto.CreateUserRequest": {
+        "dto.JobStatusResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "name",
-                "password"
-            ],
             "properties": {
-                "birth_date": {
-                    "type": "string",
-                    "example": "1990-01-15T00:00:00Z"
+                "consecutive_failures": {
+                    "description": "Failed runs since the last successful one",
+                    "type": "integer",
+                    "example": 0
                 },
-                "email": {
+                "enabled": {
+                    "description": "Paused jobs are not scheduled but can still be triggered manually",
+                    "type": "boolean",
+                    "example": true
+                },
+                "last_run": {
+                    "$ref": "#/definitions/dto.JobRunResponse"
+                },
+                "last_success_at": {
                     "type": "string",
-                    "example": "user@example.com"
+                    "example": "2024-01-01T00:00:05Z"
                 },
                 "name": {
                     "type": "string",
-                    "maxLength": 100,
-                    "minLength": 2,
-                    "example": "John Doe"
+                    "example": "balance_sync"
                 },
-                "password": {
+                "next_run_at": {
+                    "description": "Empty while the worker is stopped",
                     "type": "string",
-                    "maxLength": 100,
-                    "minLength": 8,
-                    "example": "password123"
+                    "example": "2024-01-02T00:00:00Z"
                 },
-                "profile_photo_file_name": {
+                "running_on": {
+                    "description": "Instance ID of the replica holding the job lock",
                     "type": "string",
-                    "example": "profile-photo/2024/01/profile_photo_1234567890_user123.jpg"
-                }
-            }
-        },
-        "dto.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "details": {
-                    "type": "object",
-                    "additionalProperties": true
+                    "example": "api-7f9c-1"
                 },
-                "error": {
+                "schedule": {
                     "type": "string",
-                    "example": "Bad Request"
+                    "example": "0 0 * * *"
                 },
-                "message": {
+                "timezone": {
                     "type": "string",
-                    "example": "Invalid input data"
+                    "example": "UTC"
                 }
             }
         },
@@ -1019,11 +2003,11 @@ const docTemplate = `{
                 },
                 "birth_date": {
                     "type": "string",
-                    "example": "1990-01-15T00:00:00Z"
+                    "example": "1990-01-15"
                 },
                 "created_at": {
                     "type": "string",
-                    "example": "2023-01-01T00:00:00Z"
+                    "example": "2023-01-01"
                 },
                 "email": {
                     "type": "string",
@@ -1037,13 +2021,9 @@ const docTemplate = `{
                     "type": "string",
                     "example": "John Doe"
                 },
-                "profile_photo_file_name": {
+                "profile_photo": {
                     "type": "string",
-                    "example": "profile-photo/2024/01/profile_photo_1234567890_user123.jpg"
-                },
-                "profile_photo_url": {
-                    "type": "string",
-                    "example": "https://minio.example.com/public/profile-photo/2024/01/profile_photo_1234567890_user123.jpg"
+                    "example": "https://minio.example.com/public/profile-photo/2023/01/profile_photo_1641024000.jpg"
                 },
                 "role": {
                     "type": "string",
@@ -1053,9 +2033,21 @@ const docTemplate = `{
                     "type": "string",
                     "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
                 },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TransactionResponse"
+                    }
+                },
                 "updated_at": {
                     "type": "string",
-                    "example": "2023-01-01T00:00:00Z"
+                    "example": "2023-01-01"
+                },
+                "wallets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.WalletResponse"
+                    }
                 }
             }
         },
@@ -1104,46 +2096,221 @@ const docTemplate = `{
                     "example": "John Doe"
                 },
                 "password": {
+                    "description": "Must contain: 1 uppercase, 1 number, 1 special character",
                     "type": "string",
                     "maxLength": 100,
                     "minLength": 8,
-                    "example": "password123"
+                    "example": "Password123!"
                 }
             }
         },
-        "dto.UpdateUserRequest": {
+        "dto.RescheduleJobRequest": {
             "type": "object",
+            "required": [
+                "schedule"
+            ],
             "properties": {
-                "birth_date": {
+                "schedule": {
+                    "description": "Standard 5-field cron expression",
                     "type": "string",
-                    "example": "1990-01-15T00:00:00Z"
+                    "maxLength": 100,
+                    "example": "0 2 * * *"
                 },
-                "name": {
+                "timezone": {
+                    "description": "IANA time zone, keeps the current one when empty",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "Asia/Jakarta"
+                }
+            }
+        },
+        "dto.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "token"
+            ],
+            "properties": {
+                "new_password": {
                     "type": "string",
                     "maxLength": 100,
-                    "minLength": 2,
-                    "example": "John Doe Updated"
+                    "minLength": 8,
+                    "example": "NewPassword123!"
                 },
-                "profile_photo_file_name": {
+                "token": {
                     "type": "string",
-                    "example": "profile-photo/2024/01/profile_photo_1234567890_user123.jpg"
+                    "example": "encrypted-reset-token"
                 }
             }
         },
-        "dto.UploadProfilePhotoResponse": {
+        "dto.TagResponse": {
             "type": "object",
             "properties": {
-                "message": {
+                "color": {
+                    "type": "string",
+                    "example": "#0EA5E9"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174007"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "trip-bali-2026"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "dto.TransactionResponse": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174005"
+                },
+                "cost": {
+                    "type": "number",
+                    "example": 50000
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "external_id": {
+                    "type": "string",
+                    "example": "20240105001"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Grocery Shopping"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Weekly grocery shopping at supermarket"
+                },
+                "occurred_at": {
+                    "type": "string",
+                    "example": "2023-01-01T09:30:00Z"
+                },
+                "occurrence_date": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "recurring_transaction_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174003"
+                },
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TransactionSplitResponse"
+                    }
+                },
+                "t_category": {
+                    "type": "string",
+                    "example": "food"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TagResponse"
+                    }
+                },
+                "transfer_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174002"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "expense"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.UserResponse"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "version": {
+                    "description": "Also sent as the ETag, send it back in If-Match to update or delete",
+                    "type": "integer",
+                    "example": 1
+                },
+                "wallet": {
+                    "$ref": "#/definitions/dto.WalletResponse"
+                },
+                "wallet_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174001"
+                },
+                "warnings": {
+                    "description": "Only set when creating a transaction",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TransactionWarning"
+                    }
+                }
+            }
+        },
+        "dto.TransactionSplitResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 30000
+                },
+                "category_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174005"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174006"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Vegetables and fruit"
+                },
+                "t_category": {
                     "type": "string",
-                    "example": "Profile photo uploaded successfully"
+                    "example": "groceries"
+                }
+            }
+        },
+        "dto.TransactionWarning": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "possible_duplicate"
                 },
-                "profile_photo_file_name": {
+                "message": {
                     "type": "string",
-                    "example": "profile-photo/2024/01/profile_photo_1234567890_user123.jpg"
+                    "example": "\"Grocery Shopping\" of 2024-01-05T10:00:00Z with the same amount was recorded 2m0s apart"
                 },
-                "profile_photo_url": {
+                "transaction_id": {
+                    "description": "The transaction it may duplicate",
                     "type": "string",
-                    "example": "https://minio.example.com/public/profile-photo/2024/01/profile_photo_1234567890_user123.jpg"
+                    "example": "123e4567-e89b-12d3-a456-426614174002"
                 }
             }
         },
@@ -1156,11 +2323,11 @@ const docTemplate = `{
                 },
                 "birth_date": {
                     "type": "string",
-                    "example": "1990-01-15T00:00:00Z"
+                    "example": "1990-01-15"
                 },
                 "created_at": {
                     "type": "string",
-                    "example": "2023-01-01T00:00:00Z"
+                    "example": "2023-01-01"
                 },
                 "email": {
                     "type": "string",
@@ -1174,35 +2341,91 @@ const docTemplate = `{
                     "type": "string",
                     "example": "John Doe"
                 },
-                "profile_photo_file_name": {
-                    "type": "string",
-                    "example": "profile-photo/2024/01/profile_photo_1234567890_user123.jpg"
-                },
-                "profile_photo_url": {
+                "profile_photo": {
                     "type": "string",
-                    "example": "https://minio.example.com/public/profile-photo/2024/01/profile_photo_1234567890_user123.jpg"
+                    "example": "https://minio.example.com/public/profile-photo/2023/01/profile_photo_1641024000.jpg"
                 },
                 "role": {
                     "type": "string",
                     "example": "user"
                 },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TransactionResponse"
+                    }
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01"
+                },
+                "wallets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.WalletResponse"
+                    }
+                }
+            }
+        },
+        "dto.WalletResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number",
+                    "example": 1000.5
+                },
+                "category": {
+                    "type": "string",
+                    "example": "income"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "personal"
+                },
                 "updated_at": {
                     "type": "string",
                     "example": "2023-01-01T00:00:00Z"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.UserResponse"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "version": {
+                    "description": "Also sent as the ETag, send it back in If-Match to update or delete",
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "dto.UsersResponse": {
+        "helpers.Response": {
             "type": "object",
             "properties": {
-                "pagination": {
-                    "$ref": "#/definitions/dto.PaginationMeta"
+                "data": {},
+                "error": {},
+                "message": {
+                    "type": "string"
                 },
-                "users": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.UserResponse"
-                    }
+                "success": {
+                    "type": "boolean"
                 }
             }
         }
@@ -20,32 +20,69 @@ type ServiceContainer struct {
 	MinioClient minio.Client
 
 	// Repositories
-	UserRepo        repositories.UserRepository
-	WalletRepo      repositories.WalletRepository
-	TransactionRepo repositories.TransactionRepository
-	DashboardRepo   repositories.DashboardRepository
+	UserRepo                  repositories.UserRepository
+	WalletRepo                repositories.WalletRepository
+	TransactionRepo           repositories.TransactionRepository
+	TransferRepo              repositories.TransferRepository
+	RecurringRepo             repositories.RecurringTransactionRepository
+	BudgetRepo                repositories.BudgetRepository
+	BudgetAlertRepo           repositories.BudgetAlertRepository
+	DashboardRepo             repositories.DashboardRepository
+	ExchangeRateRepo          repositories.ExchangeRateRepository
+	CategoryRepo              repositories.CategoryRepository
+	TagRepo                   repositories.TagRepository
+	TransactionRuleRepo       repositories.TransactionRuleRepository
+	TransactionDuplicateRepo  repositories.TransactionDuplicateRepository
+	TransactionAttachmentRepo repositories.TransactionAttachmentRepository
+	IdempotencyKeyRepo        repositories.IdempotencyKeyRepository
+	WalletBalanceEntryRepo    repositories.WalletBalanceEntryRepository
+	BalanceSyncReportRepo     repositories.BalanceSyncReportRepository
+	JobRunRepo                repositories.JobRunRepository
+	JobSettingRepo            repositories.JobSettingRepository
 
 	// Middleware
-	AuthMiddleware *middleware.AuthMiddleware
+	AuthMiddleware        *middleware.AuthMiddleware
+	IdempotencyMiddleware *middleware.IdempotencyMiddleware
 
 	// Use cases
-	AuthUseCase        usecases.AuthUseCaseInterface
-	UserUseCase        usecases.UserUseCaseInterface
-	WalletUseCase      usecases.WalletUseCaseInterface
-	TransactionUseCase usecases.TransactionUseCaseInterface
-	BalanceSyncUseCase usecases.BalanceSyncUseCaseInterface
-	DashboardUseCase   usecases.DashboardUseCaseInterface
+	AuthUseCase                  usecases.AuthUseCaseInterface
+	UserUseCase                  usecases.UserUseCaseInterface
+	WalletUseCase                usecases.WalletUseCaseInterface
+	TransactionUseCase           usecases.TransactionUseCaseInterface
+	TransferUseCase              usecases.TransferUseCaseInterface
+	RecurringTransactionUseCase  usecases.RecurringTransactionUseCaseInterface
+	BudgetUseCase                usecases.BudgetUseCaseInterface
+	BalanceSyncUseCase           usecases.BalanceSyncUseCaseInterface
+	DashboardUseCase             usecases.DashboardUseCaseInterface
+	ExchangeRateUseCase          usecases.ExchangeRateUseCaseInterface
+	CategoryUseCase              usecases.CategoryUseCaseInterface
+	TagUseCase                   usecases.TagUseCaseInterface
+	TransactionRuleUseCase       usecases.TransactionRuleUseCaseInterface
+	TransactionDuplicateUseCase  usecases.TransactionDuplicateUseCaseInterface
+	TransactionAttachmentUseCase usecases.TransactionAttachmentUseCaseInterface
+	JobRunUseCase                usecases.JobRunUseCaseInterface
+	JobSettingUseCase            usecases.JobSettingUseCaseInterface
+	IdempotencyKeyUseCase        usecases.IdempotencyKeyUseCaseInterface
 
 	// Workers
 	CronWorker *worker.CronWorker
 
 	// Handlers
-	AuthHandler        *handlers.AuthHandler
-	UserHandler        *handlers.UserHandler
-	WalletHandler      *handlers.WalletHandler
-	TransactionHandler *handlers.TransactionHandler
-	WorkerHandler      *handlers.WorkerHandler
-	DashboardHandler   *handlers.DashboardHandler
+	AuthHandler                  *handlers.AuthHandler
+	UserHandler                  *handlers.UserHandler
+	WalletHandler                *handlers.WalletHandler
+	TransactionHandler           *handlers.TransactionHandler
+	TransferHandler              *handlers.TransferHandler
+	RecurringTransactionHandler  *handlers.RecurringTransactionHandler
+	BudgetHandler                *handlers.BudgetHandler
+	WorkerHandler                *handlers.WorkerHandler
+	DashboardHandler             *handlers.DashboardHandler
+	ExchangeRateHandler          *handlers.ExchangeRateHandler
+	CategoryHandler              *handlers.CategoryHandler
+	TagHandler                   *handlers.TagHandler
+	TransactionRuleHandler       *handlers.TransactionRuleHandler
+	TransactionDuplicateHandler  *handlers.TransactionDuplicateHandler
+	TransactionAttachmentHandler *handlers.TransactionAttachmentHandler
 }
 
 // NewServiceContainer creates and initializes all application dependencies
@@ -70,29 +107,66 @@ func NewServiceContainer(db *gorm.DB, validator *validator.Validator) *ServiceCo
 	userRepo := repositories.NewUserRepository(db)
 	walletRepo := repositories.NewWalletRepository(db)
 	transactionRepo := repositories.NewTransactionRepository(db)
+	transferRepo := repositories.NewTransferRepository(db)
+	recurringRepo := repositories.NewRecurringTransactionRepository(db)
+	budgetRepo := repositories.NewBudgetRepository(db)
+	budgetAlertRepo := repositories.NewBudgetAlertRepository(db)
 	dashboardRepo := repositories.NewDashboardRepository(db)
+	exchangeRateRepo := repositories.NewExchangeRateRepository(db)
+	categoryRepo := repositories.NewCategoryRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
+	transactionRuleRepo := repositories.NewTransactionRuleRepository(db)
+	transactionDuplicateRepo := repositories.NewTransactionDuplicateRepository(db)
+	transactionAttachmentRepo := repositories.NewTransactionAttachmentRepository(db)
+	idempotencyKeyRepo := repositories.NewIdempotencyKeyRepository(db)
+	walletBalanceEntryRepo := repositories.NewWalletBalanceEntryRepository(db)
+	balanceSyncReportRepo := repositories.NewBalanceSyncReportRepository(db)
+	jobRunRepo := repositories.NewJobRunRepository(db)
+	jobSettingRepo := repositories.NewJobSettingRepository(db)
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(userRepo)
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(idempotencyKeyRepo)
 
 	// Initialize use cases
 	authUseCase := usecases.NewAuthUseCase(userRepo)
 	userUseCase := usecases.NewUserUseCase(userRepo)
-	walletUseCase := usecases.NewWalletUseCase(walletRepo, userRepo)
-	transactionUseCase := usecases.NewTransactionUseCase(transactionRepo, walletRepo, userRepo, db)
-	balanceSyncUseCase := usecases.NewBalanceSyncUseCase(walletRepo, transactionRepo, db)
-	dashboardUseCase := usecases.NewDashboardUseCase(dashboardRepo)
+	walletUseCase := usecases.NewWalletUseCase(walletRepo, userRepo, walletBalanceEntryRepo)
+	transactionUseCase := usecases.NewTransactionUseCase(transactionRepo, walletRepo, userRepo, transactionAttachmentRepo, db)
+	transferUseCase := usecases.NewTransferUseCase(transferRepo, userRepo, exchangeRateRepo, db)
+	recurringTransactionUseCase := usecases.NewRecurringTransactionUseCase(recurringRepo, walletRepo, userRepo, db)
+	budgetUseCase := usecases.NewBudgetUseCase(budgetRepo, budgetAlertRepo, transactionRepo, walletRepo, userRepo, db)
+	balanceSyncUseCase := usecases.NewBalanceSyncUseCase(walletRepo, transactionRepo, balanceSyncReportRepo, db)
+	dashboardUseCase := usecases.NewDashboardUseCase(dashboardRepo, exchangeRateRepo)
+	exchangeRateUseCase := usecases.NewExchangeRateUseCase(exchangeRateRepo)
+	categoryUseCase := usecases.NewCategoryUseCase(categoryRepo, userRepo, db)
+	tagUseCase := usecases.NewTagUseCase(tagRepo, userRepo)
+	transactionRuleUseCase := usecases.NewTransactionRuleUseCase(transactionRuleRepo, categoryRepo, walletRepo, userRepo, db)
+	transactionDuplicateUseCase := usecases.NewTransactionDuplicateUseCase(transactionDuplicateRepo, transactionRepo, db)
+	transactionAttachmentUseCase := usecases.NewTransactionAttachmentUseCase(transactionRepo, transactionAttachmentRepo)
+	jobRunUseCase := usecases.NewJobRunUseCase(jobRunRepo)
+	jobSettingUseCase := usecases.NewJobSettingUseCase(jobSettingRepo)
+	idempotencyKeyUseCase := usecases.NewIdempotencyKeyUseCase(idempotencyKeyRepo)
 
 	// Initialize workers
-	cronWorker := worker.NewCronWorker(balanceSyncUseCase, db)
+	cronWorker := worker.NewCronWorker(balanceSyncUseCase, recurringTransactionUseCase, budgetUseCase, jobRunUseCase, jobSettingUseCase, idempotencyKeyUseCase, db)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authUseCase, validator)
 	userHandler := handlers.NewUserHandler(userUseCase, validator)
 	walletHandler := handlers.NewWalletHandler(walletUseCase, validator)
 	transactionHandler := handlers.NewTransactionHandler(transactionUseCase, validator)
-	workerHandler := handlers.NewWorkerHandler(cronWorker)
+	transferHandler := handlers.NewTransferHandler(transferUseCase, validator)
+	recurringTransactionHandler := handlers.NewRecurringTransactionHandler(recurringTransactionUseCase, validator)
+	budgetHandler := handlers.NewBudgetHandler(budgetUseCase, validator)
+	workerHandler := handlers.NewWorkerHandler(cronWorker, balanceSyncUseCase, jobRunUseCase, validator)
 	dashboardHandler := handlers.NewDashboardHandler(dashboardUseCase, validator)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(exchangeRateUseCase, validator)
+	categoryHandler := handlers.NewCategoryHandler(categoryUseCase, validator)
+	tagHandler := handlers.NewTagHandler(tagUseCase, validator)
+	transactionRuleHandler := handlers.NewTransactionRuleHandler(transactionRuleUseCase, validator)
+	transactionDuplicateHandler := handlers.NewTransactionDuplicateHandler(transactionDuplicateUseCase, validator)
+	transactionAttachmentHandler := handlers.NewTransactionAttachmentHandler(transactionAttachmentUseCase, validator)
 
 	// Log successful service container initialization
 	logger.LogSuccess(
@@ -101,24 +175,63 @@ func NewServiceContainer(db *gorm.DB, validator *validator.Validator) *ServiceCo
 	)
 
 	return &ServiceContainer{
-		DB:                 db,
-		Validator:          validator,
-		MinioClient:        minioClient,
-		UserRepo:           userRepo,
-		WalletRepo:         walletRepo,
-		TransactionRepo:    transactionRepo,
-		AuthMiddleware:     authMiddleware,
-		AuthUseCase:        authUseCase,
-		UserUseCase:        userUseCase,
-		WalletUseCase:      walletUseCase,
-		TransactionUseCase: transactionUseCase,
-		BalanceSyncUseCase: balanceSyncUseCase,
-		CronWorker:         cronWorker,
-		AuthHandler:        authHandler,
-		UserHandler:        userHandler,
-		WalletHandler:      walletHandler,
-		TransactionHandler: transactionHandler,
-		WorkerHandler:      workerHandler,
-		DashboardHandler:   dashboardHandler,
+		DB:                           db,
+		Validator:                    validator,
+		MinioClient:                  minioClient,
+		UserRepo:                     userRepo,
+		WalletRepo:                   walletRepo,
+		TransactionRepo:              transactionRepo,
+		TransferRepo:                 transferRepo,
+		RecurringRepo:                recurringRepo,
+		BudgetRepo:                   budgetRepo,
+		BudgetAlertRepo:              budgetAlertRepo,
+		DashboardRepo:                dashboardRepo,
+		ExchangeRateRepo:             exchangeRateRepo,
+		CategoryRepo:                 categoryRepo,
+		TagRepo:                      tagRepo,
+		TransactionRuleRepo:          transactionRuleRepo,
+		TransactionDuplicateRepo:     transactionDuplicateRepo,
+		TransactionAttachmentRepo:    transactionAttachmentRepo,
+		IdempotencyKeyRepo:           idempotencyKeyRepo,
+		WalletBalanceEntryRepo:       walletBalanceEntryRepo,
+		BalanceSyncReportRepo:        balanceSyncReportRepo,
+		JobRunRepo:                   jobRunRepo,
+		JobSettingRepo:               jobSettingRepo,
+		AuthMiddleware:               authMiddleware,
+		IdempotencyMiddleware:        idempotencyMiddleware,
+		AuthUseCase:                  authUseCase,
+		UserUseCase:                  userUseCase,
+		WalletUseCase:                walletUseCase,
+		TransactionUseCase:           transactionUseCase,
+		TransferUseCase:              transferUseCase,
+		RecurringTransactionUseCase:  recurringTransactionUseCase,
+		BudgetUseCase:                budgetUseCase,
+		BalanceSyncUseCase:           balanceSyncUseCase,
+		DashboardUseCase:             dashboardUseCase,
+		ExchangeRateUseCase:          exchangeRateUseCase,
+		CategoryUseCase:              categoryUseCase,
+		TagUseCase:                   tagUseCase,
+		TransactionRuleUseCase:       transactionRuleUseCase,
+		TransactionDuplicateUseCase:  transactionDuplicateUseCase,
+		TransactionAttachmentUseCase: transactionAttachmentUseCase,
+		JobRunUseCase:                jobRunUseCase,
+		JobSettingUseCase:            jobSettingUseCase,
+		IdempotencyKeyUseCase:        idempotencyKeyUseCase,
+		CronWorker:                   cronWorker,
+		AuthHandler:                  authHandler,
+		UserHandler:                  userHandler,
+		WalletHandler:                walletHandler,
+		TransactionHandler:           transactionHandler,
+		TransferHandler:              transferHandler,
+		RecurringTransactionHandler:  recurringTransactionHandler,
+		BudgetHandler:                budgetHandler,
+		WorkerHandler:                workerHandler,
+		DashboardHandler:             dashboardHandler,
+		ExchangeRateHandler:          exchangeRateHandler,
+		CategoryHandler:              categoryHandler,
+		TagHandler:                   tagHandler,
+		TransactionRuleHandler:       transactionRuleHandler,
+		TransactionDuplicateHandler:  transactionDuplicateHandler,
+		TransactionAttachmentHandler: transactionAttachmentHandler,
 	}
 }
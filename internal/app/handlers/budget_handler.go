@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/usecases"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	ut "github.com/naufalfazanadi/finance-manager-go/pkg/utils"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type BudgetHandler struct {
+	budgetUseCase usecases.BudgetUseCaseInterface
+	validator     *validator.Validator
+}
+
+func NewBudgetHandler(budgetUseCase usecases.BudgetUseCaseInterface, validator *validator.Validator) *BudgetHandler {
+	return &BudgetHandler{
+		budgetUseCase: budgetUseCase,
+		validator:     validator,
+	}
+}
+
+func (h *BudgetHandler) CreateBudget(c *fiber.Ctx) error {
+	var req dto.CreateBudgetRequest
+
+	// Default to the logged user for non-admin requests
+	if c.Locals("userRole") != "admin" {
+		req.UserID = c.Locals("userID").(uuid.UUID)
+	}
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	if req.UserID != c.Locals("userID").(uuid.UUID) && c.Locals("userRole") != "admin" {
+		return helpers.HandleErrorResponse(c, helpers.NewForbiddenError("You do not have permission to create a budget for this user", "Permission denied"), "Permission denied")
+	}
+
+	budget, err := h.budgetUseCase.CreateBudget(c.Context(), &req)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedCreateMsg("Budget"))
+	}
+
+	return helpers.CreatedResponse(c, ut.SuccessCreateMsg("Budget"), budget)
+}
+
+func (h *BudgetHandler) GetBudget(c *fiber.Ctx) error {
+	budgetID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	budget, err := h.budgetUseCase.GetBudget(c.Context(), budgetID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Budget"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Budget"), budget)
+}
+
+func (h *BudgetHandler) GetBudgets(c *fiber.Ctx) error {
+	queryParams := helpers.ParseQueryParams(c)
+
+	// Validate query parameters
+	if err := h.validator.Validate(queryParams); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrInvalidQueryParams, err.Error()), ut.MsgErrInvalidQueryParams)
+	}
+
+	queryParams.LoggedUserID = loggedNonAdminUserID(c)
+
+	budgets, err := h.budgetUseCase.GetBudgets(c.Context(), queryParams)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Budgets"))
+	}
+
+	return helpers.PaginatedSuccessResponse(c, ut.SuccessRetrieveMsg("Budgets"), budgets.Data, budgets.Meta)
+}
+
+func (h *BudgetHandler) UpdateBudget(c *fiber.Ctx) error {
+	budgetID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	var req dto.UpdateBudgetRequest
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	budget, err := h.budgetUseCase.UpdateBudget(c.Context(), budgetID, loggedNonAdminUserID(c), &req)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedUpdateMsg("Budget"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessUpdateMsg("Budget"), budget)
+}
+
+func (h *BudgetHandler) DeleteBudget(c *fiber.Ctx) error {
+	budgetID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	err = h.budgetUseCase.DeleteBudget(c.Context(), budgetID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedDeleteMsg("Budget"))
+	}
+
+	return helpers.NoContentResponse(c)
+}
+
+// GetBudgetProgress returns spent versus limit for the current period, or the period containing ?date=YYYY-MM-DD
+func (h *BudgetHandler) GetBudgetProgress(c *fiber.Ctx) error {
+	budgetID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	at := time.Now()
+	if date := c.Query("date"); date != "" {
+		at, err = time.Parse("2006-01-02", date)
+		if err != nil {
+			return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidQueryParams, "date must use the YYYY-MM-DD format"), ut.MsgErrInvalidQueryParams)
+		}
+	}
+
+	progress, err := h.budgetUseCase.GetBudgetProgress(c.Context(), budgetID, loggedNonAdminUserID(c), at)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Budget progress"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Budget progress"), progress)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/usecases"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	ut "github.com/naufalfazanadi/finance-manager-go/pkg/utils"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CategoryHandler struct {
+	categoryUseCase usecases.CategoryUseCaseInterface
+	validator       *validator.Validator
+}
+
+func NewCategoryHandler(categoryUseCase usecases.CategoryUseCaseInterface, validator *validator.Validator) *CategoryHandler {
+	return &CategoryHandler{
+		categoryUseCase: categoryUseCase,
+		validator:       validator,
+	}
+}
+
+func (h *CategoryHandler) CreateCategory(c *fiber.Ctx) error {
+	var req dto.CreateCategoryRequest
+
+	// Default to the logged user for non-admin requests
+	if c.Locals("userRole") != "admin" {
+		req.UserID = c.Locals("userID").(uuid.UUID)
+	}
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	if req.UserID != c.Locals("userID").(uuid.UUID) && c.Locals("userRole") != "admin" {
+		return helpers.HandleErrorResponse(c, helpers.NewForbiddenError("You do not have permission to create a category for this user", "Permission denied"), "Permission denied")
+	}
+
+	category, err := h.categoryUseCase.CreateCategory(c.Context(), &req)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedCreateMsg("Category"))
+	}
+
+	return helpers.CreatedResponse(c, ut.SuccessCreateMsg("Category"), category)
+}
+
+func (h *CategoryHandler) GetCategory(c *fiber.Ctx) error {
+	categoryID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	category, err := h.categoryUseCase.GetCategory(c.Context(), categoryID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Category"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Category"), category)
+}
+
+// GetCategories lists categories, filterable by type, parent_id (an ID or "null" for top level), subtree_of and is_archived
+func (h *CategoryHandler) GetCategories(c *fiber.Ctx) error {
+	queryParams := helpers.ParseQueryParams(c)
+
+	// Validate query parameters
+	if err := h.validator.Validate(queryParams); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrInvalidQueryParams, err.Error()), ut.MsgErrInvalidQueryParams)
+	}
+
+	queryParams.LoggedUserID = loggedNonAdminUserID(c)
+
+	categories, err := h.categoryUseCase.GetCategories(c.Context(), queryParams)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Categories"))
+	}
+
+	return helpers.PaginatedSuccessResponse(c, ut.SuccessRetrieveMsg("Categories"), categories.Data, categories.Meta)
+}
+
+func (h *CategoryHandler) UpdateCategory(c *fiber.Ctx) error {
+	categoryID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	var req dto.UpdateCategoryRequest
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	category, err := h.categoryUseCase.UpdateCategory(c.Context(), categoryID, loggedNonAdminUserID(c), &req)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedUpdateMsg("Category"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessUpdateMsg("Category"), category)
+}
+
+func (h *CategoryHandler) DeleteCategory(c *fiber.Ctx) error {
+	categoryID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	err = h.categoryUseCase.DeleteCategory(c.Context(), categoryID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedDeleteMsg("Category"))
+	}
+
+	return helpers.NoContentResponse(c)
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"time"
+
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/usecases"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/currency"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
 	ut "github.com/naufalfazanadi/finance-manager-go/pkg/utils"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/validator"
@@ -23,25 +26,110 @@ func NewDashboardHandler(dashboardUseCase usecases.DashboardUseCaseInterface, va
 }
 
 func (h *DashboardHandler) GetMonthlySumByUser(c *fiber.Ctx) error {
-	id := c.Params("id")
-	if id == "" {
-		return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrIDRequired, ut.ErrIDRequired), ut.MsgErrIDRequired)
-	}
-
-	// Parse and validate UUID format
-	userID, err := uuid.Parse(id)
+	userID, err := dashboardUserID(c)
 	if err != nil {
-		return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidID, ut.ErrInvalidIDFormat), ut.MsgErrInvalidID)
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
 	}
 
-	if userID != c.Locals("userID").(uuid.UUID) && c.Locals("userRole") != "admin" {
-		return helpers.HandleErrorResponse(c, helpers.NewForbiddenError("You do not have permission", "Permission denied"), "Permission denied")
+	// Totals stay per wallet currency unless a currency to convert into is requested
+	targetCurrency, err := parseCurrencyQuery(c, "")
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidQueryParams)
 	}
 
-	dashboardData, err := h.dashboardUseCase.GetMonthlySumByUser(c.Context(), userID)
+	dashboardData, err := h.dashboardUseCase.GetMonthlySumByUser(c.Context(), userID, targetCurrency)
 	if err != nil {
 		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Dashboard"))
 	}
 
 	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Dashboard"), dashboardData)
 }
+
+func (h *DashboardHandler) GetBalanceSummary(c *fiber.Ctx) error {
+	userID, err := dashboardUserID(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	targetCurrency, err := parseCurrencyQuery(c, currency.Default)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidQueryParams)
+	}
+
+	date := time.Now().UTC()
+	if value := c.Query("date"); value != "" {
+		date, err = time.Parse(currency.RateDateFormat, value)
+		if err != nil {
+			return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidQueryParams, "date must use the YYYY-MM-DD format"), ut.MsgErrInvalidQueryParams)
+		}
+	}
+
+	summary, err := h.dashboardUseCase.GetBalanceSummary(c.Context(), userID, targetCurrency, date)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Balance summary"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Balance summary"), summary)
+}
+
+// GetTagSummary returns the income and expense per tag between start_date and end_date, defaulting to the current month
+func (h *DashboardHandler) GetTagSummary(c *fiber.Ctx) error {
+	userID, err := dashboardUserID(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	targetCurrency, err := parseCurrencyQuery(c, currency.Default)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidQueryParams)
+	}
+
+	now := time.Now().UTC()
+	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if value := c.Query("start_date"); value != "" {
+		startDate, err = time.Parse(currency.RateDateFormat, value)
+		if err != nil {
+			return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidQueryParams, "start_date must use the YYYY-MM-DD format"), ut.MsgErrInvalidQueryParams)
+		}
+	}
+	if value := c.Query("end_date"); value != "" {
+		endDate, err = time.Parse(currency.RateDateFormat, value)
+		if err != nil {
+			return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidQueryParams, "end_date must use the YYYY-MM-DD format"), ut.MsgErrInvalidQueryParams)
+		}
+	}
+
+	summary, err := h.dashboardUseCase.GetTagSummary(c.Context(), userID, targetCurrency, startDate, endDate)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Tag summary"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Tag summary"), summary)
+}
+
+// dashboardUserID parses the user ID route param and checks the logged user may read its dashboard
+func dashboardUserID(c *fiber.Ctx) (uuid.UUID, error) {
+	userID, err := parseIDParam(c)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if userID != c.Locals("userID").(uuid.UUID) && c.Locals("userRole") != "admin" {
+		return uuid.Nil, helpers.NewForbiddenError("You do not have permission", "Permission denied")
+	}
+
+	return userID, nil
+}
+
+// parseCurrencyQuery reads the currency query param as an ISO 4217 code, falling back to defaultCurrency
+func parseCurrencyQuery(c *fiber.Ctx, defaultCurrency string) (string, error) {
+	value := c.Query("currency")
+	if value == "" {
+		return defaultCurrency, nil
+	}
+	if !currency.IsValid(value) {
+		return "", helpers.NewBadRequestError(ut.MsgErrInvalidQueryParams, "currency must be an ISO 4217 currency code such as IDR or USD")
+	}
+	return currency.Normalize(value), nil
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/usecases"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/upload"
+	ut "github.com/naufalfazanadi/finance-manager-go/pkg/utils"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExchangeRateHandler struct {
+	exchangeRateUseCase usecases.ExchangeRateUseCaseInterface
+	validator           *validator.Validator
+}
+
+func NewExchangeRateHandler(exchangeRateUseCase usecases.ExchangeRateUseCaseInterface, validator *validator.Validator) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		exchangeRateUseCase: exchangeRateUseCase,
+		validator:           validator,
+	}
+}
+
+func (h *ExchangeRateHandler) GetExchangeRates(c *fiber.Ctx) error {
+	queryParams := helpers.ParseQueryParams(c)
+
+	// Validate query parameters
+	if err := h.validator.Validate(queryParams); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrInvalidQueryParams, err.Error()), ut.MsgErrInvalidQueryParams)
+	}
+
+	rates, err := h.exchangeRateUseCase.GetExchangeRates(c.Context(), queryParams)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Exchange rates"))
+	}
+
+	return helpers.PaginatedSuccessResponse(c, ut.SuccessRetrieveMsg("Exchange rates"), rates.Data, rates.Meta)
+}
+
+func (h *ExchangeRateHandler) UpsertExchangeRates(c *fiber.Ctx) error {
+	var req dto.UpsertExchangeRatesRequest
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	result, err := h.exchangeRateUseCase.UpsertExchangeRates(c.Context(), &req)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, "Failed to save exchange rates")
+	}
+
+	return helpers.SuccessResponse(c, "Exchange rates saved successfully", result)
+}
+
+func (h *ExchangeRateHandler) ImportExchangeRates(c *fiber.Ctx) error {
+	var req dto.ImportExchangeRatesRequest
+
+	// Parse form data with strict field validation and struct validation
+	if err := h.validator.ParseFormAndValidate(c, &req); err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(fiberErr.Message, fiberErr.Error()), fiberErr.Message)
+		}
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError("Validation failed", err.Error()), "Validation failed")
+	}
+
+	// Validate the uploaded CSV file
+	fileResult := h.validator.ValidateFile(req.File, upload.CSVImportValidation)
+	if !fileResult.Valid {
+		return helpers.HandleErrorResponse(c, helpers.NewBadRequestError("CSV file validation failed", fileResult.Error), "CSV file validation failed")
+	}
+
+	result, err := h.exchangeRateUseCase.ImportExchangeRates(c.Context(), &req)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, "Failed to import exchange rates")
+	}
+
+	return helpers.SuccessResponse(c, "Exchange rates imported successfully", result)
+}
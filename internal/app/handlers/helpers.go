@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	ut "github.com/naufalfazanadi/finance-manager-go/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// parseIDParam parses and validates the :id route parameter
+func parseIDParam(c *fiber.Ctx) (uuid.UUID, error) {
+	id := c.Params("id")
+	if id == "" {
+		return uuid.Nil, helpers.NewBadRequestError(ut.MsgErrIDRequired, ut.ErrIDRequired)
+	}
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, helpers.NewBadRequestError(ut.MsgErrInvalidID, ut.ErrInvalidIDFormat)
+	}
+
+	return parsedID, nil
+}
+
+// loggedNonAdminUserID returns the logged user ID for regular users and uuid.Nil for admins
+func loggedNonAdminUserID(c *fiber.Ctx) uuid.UUID {
+	if c.Locals("userRole") == "admin" {
+		return uuid.Nil
+	}
+	return c.Locals("userID").(uuid.UUID)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/usecases"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	ut "github.com/naufalfazanadi/finance-manager-go/pkg/utils"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type RecurringTransactionHandler struct {
+	recurringUseCase usecases.RecurringTransactionUseCaseInterface
+	validator        *validator.Validator
+}
+
+func NewRecurringTransactionHandler(recurringUseCase usecases.RecurringTransactionUseCaseInterface, validator *validator.Validator) *RecurringTransactionHandler {
+	return &RecurringTransactionHandler{
+		recurringUseCase: recurringUseCase,
+		validator:        validator,
+	}
+}
+
+func (h *RecurringTransactionHandler) CreateRecurringTransaction(c *fiber.Ctx) error {
+	var req dto.CreateRecurringTransactionRequest
+
+	// Default to the logged user for non-admin requests
+	if c.Locals("userRole") != "admin" {
+		req.UserID = c.Locals("userID").(uuid.UUID)
+	}
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	if req.UserID != c.Locals("userID").(uuid.UUID) && c.Locals("userRole") != "admin" {
+		return helpers.HandleErrorResponse(c, helpers.NewForbiddenError("You do not have permission to create a recurring transaction for this user", "Permission denied"), "Permission denied")
+	}
+
+	recurring, err := h.recurringUseCase.CreateRecurringTransaction(c.Context(), &req)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedCreateMsg("Recurring transaction"))
+	}
+
+	return helpers.CreatedResponse(c, ut.SuccessCreateMsg("Recurring transaction"), recurring)
+}
+
+func (h *RecurringTransactionHandler) GetRecurringTransaction(c *fiber.Ctx) error {
+	recurringID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	recurring, err := h.recurringUseCase.GetRecurringTransaction(c.Context(), recurringID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Recurring transaction"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Recurring transaction"), recurring)
+}
+
+func (h *RecurringTransactionHandler) GetRecurringTransactions(c *fiber.Ctx) error {
+	queryParams := helpers.ParseQueryParams(c)
+
+	// Validate query parameters
+	if err := h.validator.Validate(queryParams); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrInvalidQueryParams, err.Error()), ut.MsgErrInvalidQueryParams)
+	}
+
+	queryParams.LoggedUserID = loggedNonAdminUserID(c)
+
+	recurrings, err := h.recurringUseCase.GetRecurringTransactions(c.Context(), queryParams)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Recurring transactions"))
+	}
+
+	return helpers.PaginatedSuccessResponse(c, ut.SuccessRetrieveMsg("Recurring transactions"), recurrings.Data, recurrings.Meta)
+}
+
+func (h *RecurringTransactionHandler) UpdateRecurringTransaction(c *fiber.Ctx) error {
+	recurringID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	var req dto.UpdateRecurringTransactionRequest
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	recurring, err := h.recurringUseCase.UpdateRecurringTransaction(c.Context(), recurringID, loggedNonAdminUserID(c), &req)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedUpdateMsg("Recurring transaction"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessUpdateMsg("Recurring transaction"), recurring)
+}
+
+func (h *RecurringTransactionHandler) DeleteRecurringTransaction(c *fiber.Ctx) error {
+	recurringID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	err = h.recurringUseCase.DeleteRecurringTransaction(c.Context(), recurringID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedDeleteMsg("Recurring transaction"))
+	}
+
+	return helpers.NoContentResponse(c)
+}
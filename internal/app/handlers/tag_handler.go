@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/usecases"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	ut "github.com/naufalfazanadi/finance-manager-go/pkg/utils"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type TagHandler struct {
+	tagUseCase usecases.TagUseCaseInterface
+	validator  *validator.Validator
+}
+
+func NewTagHandler(tagUseCase usecases.TagUseCaseInterface, validator *validator.Validator) *TagHandler {
+	return &TagHandler{
+		tagUseCase: tagUseCase,
+		validator:  validator,
+	}
+}
+
+func (h *TagHandler) CreateTag(c *fiber.Ctx) error {
+	var req dto.CreateTagRequest
+
+	// Default to the logged user for non-admin requests
+	if c.Locals("userRole") != "admin" {
+		req.UserID = c.Locals("userID").(uuid.UUID)
+	}
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	if req.UserID != c.Locals("userID").(uuid.UUID) && c.Locals("userRole") != "admin" {
+		return helpers.HandleErrorResponse(c, helpers.NewForbiddenError("You do not have permission to create a tag for this user", "Permission denied"), "Permission denied")
+	}
+
+	tag, err := h.tagUseCase.CreateTag(c.Context(), &req)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedCreateMsg("Tag"))
+	}
+
+	return helpers.CreatedResponse(c, ut.SuccessCreateMsg("Tag"), tag)
+}
+
+func (h *TagHandler) GetTag(c *fiber.Ctx) error {
+	tagID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	tag, err := h.tagUseCase.GetTag(c.Context(), tagID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Tag"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Tag"), tag)
+}
+
+// GetTags lists tags, searchable by name
+func (h *TagHandler) GetTags(c *fiber.Ctx) error {
+	queryParams := helpers.ParseQueryParams(c)
+
+	// Validate query parameters
+	if err := h.validator.Validate(queryParams); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrInvalidQueryParams, err.Error()), ut.MsgErrInvalidQueryParams)
+	}
+
+	queryParams.LoggedUserID = loggedNonAdminUserID(c)
+
+	tags, err := h.tagUseCase.GetTags(c.Context(), queryParams)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Tags"))
+	}
+
+	return helpers.PaginatedSuccessResponse(c, ut.SuccessRetrieveMsg("Tags"), tags.Data, tags.Meta)
+}
+
+func (h *TagHandler) UpdateTag(c *fiber.Ctx) error {
+	tagID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	var req dto.UpdateTagRequest
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	tag, err := h.tagUseCase.UpdateTag(c.Context(), tagID, loggedNonAdminUserID(c), &req)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedUpdateMsg("Tag"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessUpdateMsg("Tag"), tag)
+}
+
+func (h *TagHandler) DeleteTag(c *fiber.Ctx) error {
+	tagID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	err = h.tagUseCase.DeleteTag(c.Context(), tagID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedDeleteMsg("Tag"))
+	}
+
+	return helpers.NoContentResponse(c)
+}
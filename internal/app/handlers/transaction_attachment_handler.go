@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/usecases"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/upload"
+	ut "github.com/naufalfazanadi/finance-manager-go/pkg/utils"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type TransactionAttachmentHandler struct {
+	attachmentUseCase usecases.TransactionAttachmentUseCaseInterface
+	validator         *validator.Validator
+}
+
+func NewTransactionAttachmentHandler(attachmentUseCase usecases.TransactionAttachmentUseCaseInterface, validator *validator.Validator) *TransactionAttachmentHandler {
+	return &TransactionAttachmentHandler{
+		attachmentUseCase: attachmentUseCase,
+		validator:         validator,
+	}
+}
+
+// UploadAttachment attaches a receipt (PDF, Word document, JPEG or PNG) sent as the multipart field "file"
+func (h *TransactionAttachmentHandler) UploadAttachment(c *fiber.Ctx) error {
+	transactionID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	var req dto.UploadTransactionAttachmentRequest
+
+	// Parse form data with strict field validation and struct validation
+	if err := h.validator.ParseFormAndValidate(c, &req); err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(fiberErr.Message, fiberErr.Error()), fiberErr.Message)
+		}
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError("Validation failed", err.Error()), "Validation failed")
+	}
+
+	// Validate the uploaded attachment
+	fileResult := h.validator.ValidateFile(req.File, upload.TransactionAttachmentValidation)
+	if !fileResult.Valid {
+		return helpers.HandleErrorResponse(c, helpers.NewBadRequestError("Attachment validation failed", fileResult.Error), "Attachment validation failed")
+	}
+
+	attachment, err := h.attachmentUseCase.UploadAttachment(c.Context(), transactionID, loggedNonAdminUserID(c), req.File)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedCreateMsg("Attachment"))
+	}
+
+	return helpers.CreatedResponse(c, ut.SuccessCreateMsg("Attachment"), attachment)
+}
+
+func (h *TransactionAttachmentHandler) GetAttachments(c *fiber.Ctx) error {
+	transactionID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	attachments, err := h.attachmentUseCase.GetAttachments(c.Context(), transactionID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Attachments"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Attachments"), attachments)
+}
+
+func (h *TransactionAttachmentHandler) DeleteAttachment(c *fiber.Ctx) error {
+	transactionID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	attachmentID, err := uuid.Parse(c.Params("attachmentId"))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidID, ut.ErrInvalidIDFormat), ut.MsgErrInvalidID)
+	}
+
+	err = h.attachmentUseCase.DeleteAttachment(c.Context(), transactionID, attachmentID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedDeleteMsg("Attachment"))
+	}
+
+	return helpers.NoContentResponse(c)
+}
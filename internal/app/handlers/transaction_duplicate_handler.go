@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/usecases"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	ut "github.com/naufalfazanadi/finance-manager-go/pkg/utils"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type TransactionDuplicateHandler struct {
+	duplicateUseCase usecases.TransactionDuplicateUseCaseInterface
+	validator        *validator.Validator
+}
+
+func NewTransactionDuplicateHandler(duplicateUseCase usecases.TransactionDuplicateUseCaseInterface, validator *validator.Validator) *TransactionDuplicateHandler {
+	return &TransactionDuplicateHandler{
+		duplicateUseCase: duplicateUseCase,
+		validator:        validator,
+	}
+}
+
+// GetDuplicates lists likely duplicate pairs, optionally for one wallet_id and with a window in minutes
+// instead of the configured one. Admins see every user unless they pass user_id. Pages continue from the
+// next_cursor of the previous one passed as cursor.
+func (h *TransactionDuplicateHandler) GetDuplicates(c *fiber.Ctx) error {
+	queryParams := helpers.ParseQueryParams(c)
+
+	// Validate query parameters
+	if err := h.validator.Validate(queryParams); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrInvalidQueryParams, err.Error()), ut.MsgErrInvalidQueryParams)
+	}
+
+	queryParams.LoggedUserID = loggedNonAdminUserID(c)
+	if value := c.Query("user_id"); value != "" && queryParams.LoggedUserID == uuid.Nil {
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidQueryParams, "user_id must be a UUID"), ut.MsgErrInvalidQueryParams)
+		}
+		queryParams.LoggedUserID = userID
+	}
+
+	var walletID *uuid.UUID
+	if value := c.Query("wallet_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidQueryParams, "wallet_id must be a UUID"), ut.MsgErrInvalidQueryParams)
+		}
+		walletID = &id
+	}
+
+	windowMinutes := c.QueryInt("window", 0)
+	if windowMinutes < 0 || windowMinutes > 7*24*60 {
+		return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidQueryParams, "window must be between 1 and 10080 minutes"), ut.MsgErrInvalidQueryParams)
+	}
+
+	duplicates, err := h.duplicateUseCase.GetDuplicates(c.Context(), queryParams, walletID, time.Duration(windowMinutes)*time.Minute, c.Query("cursor"))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Duplicate transactions"))
+	}
+
+	return helpers.PaginatedSuccessResponse(c, ut.SuccessRetrieveMsg("Duplicate transactions"), duplicates.Data, duplicates.Meta)
+}
+
+// MergeDuplicate keeps transaction_id and soft deletes duplicate_id, moving its tags and attachments over
+func (h *TransactionDuplicateHandler) MergeDuplicate(c *fiber.Ctx) error {
+	var req dto.TransactionDuplicatePairRequest
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	transaction, err := h.duplicateUseCase.MergeDuplicate(c.Context(), &req, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, "Failed to merge duplicate transactions")
+	}
+
+	return helpers.SuccessResponse(c, "Duplicate transactions merged successfully", transaction)
+}
+
+// DismissDuplicate keeps both transactions and stops reporting them as a duplicate pair
+func (h *TransactionDuplicateHandler) DismissDuplicate(c *fiber.Ctx) error {
+	var req dto.TransactionDuplicatePairRequest
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	if err := h.duplicateUseCase.DismissDuplicate(c.Context(), &req, loggedNonAdminUserID(c)); err != nil {
+		return helpers.HandleErrorResponse(c, err, "Failed to dismiss duplicate transactions")
+	}
+
+	return helpers.NoContentResponse(c)
+}
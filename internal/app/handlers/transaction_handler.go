@@ -1,9 +1,18 @@
 package handlers
 
 import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/usecases"
 	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/exporter"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/upload"
 	ut "github.com/naufalfazanadi/finance-manager-go/pkg/utils"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/validator"
 
@@ -44,6 +53,7 @@ func (h *TransactionHandler) CreateTransaction(c *fiber.Ctx) error {
 		return helpers.HandleErrorResponse(c, err, ut.FailedCreateMsg("Transaction"))
 	}
 
+	helpers.SetETag(c, transaction.Version)
 	return helpers.CreatedResponse(c, ut.SuccessCreateMsg("Transaction"), transaction)
 }
 
@@ -70,6 +80,7 @@ func (h *TransactionHandler) GetTransaction(c *fiber.Ctx) error {
 		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Transaction"))
 	}
 
+	helpers.SetETag(c, transaction.Version)
 	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Transaction"), transaction)
 }
 
@@ -105,6 +116,12 @@ func (h *TransactionHandler) UpdateTransaction(c *fiber.Ctx) error {
 		return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidID, ut.ErrInvalidIDFormat), ut.MsgErrInvalidID)
 	}
 
+	// Only update the version the client last saw, when it sent one
+	expectedVersion, err := helpers.ParseIfMatch(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, "Invalid If-Match header")
+	}
+
 	var req dto.UpdateTransactionRequest
 
 	// Parse strict JSON validation and struct validation
@@ -112,11 +129,12 @@ func (h *TransactionHandler) UpdateTransaction(c *fiber.Ctx) error {
 		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
 	}
 
-	transaction, err := h.transactionUseCase.UpdateTransaction(c.Context(), transactionID, &req)
+	transaction, err := h.transactionUseCase.UpdateTransaction(c.Context(), transactionID, &req, expectedVersion)
 	if err != nil {
 		return helpers.HandleErrorResponse(c, err, ut.FailedUpdateMsg("Transaction"))
 	}
 
+	helpers.SetETag(c, transaction.Version)
 	return helpers.SuccessResponse(c, ut.SuccessUpdateMsg("Transaction"), transaction)
 }
 
@@ -132,10 +150,153 @@ func (h *TransactionHandler) DeleteTransaction(c *fiber.Ctx) error {
 		return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidID, ut.ErrInvalidIDFormat), ut.MsgErrInvalidID)
 	}
 
-	err = h.transactionUseCase.DeleteTransaction(c.Context(), transactionID)
+	// Only delete the version the client last saw, when it sent one
+	expectedVersion, err := helpers.ParseIfMatch(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, "Invalid If-Match header")
+	}
+
+	err = h.transactionUseCase.DeleteTransaction(c.Context(), transactionID, expectedVersion)
 	if err != nil {
 		return helpers.HandleErrorResponse(c, err, ut.FailedDeleteMsg("Transaction"))
 	}
 
 	return helpers.NoContentResponse(c)
 }
+
+// HardDeleteTransaction permanently deletes a transaction with its splits and attachment files
+func (h *TransactionHandler) HardDeleteTransaction(c *fiber.Ctx) error {
+	transactionID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	err = h.transactionUseCase.HardDeleteTransaction(c.Context(), transactionID)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedDeleteMsg("Transaction"))
+	}
+
+	return helpers.NoContentResponse(c)
+}
+
+func (h *TransactionHandler) ImportTransactions(c *fiber.Ctx) error {
+	var req dto.ImportTransactionsRequest
+
+	// Parse form data with strict field validation and struct validation
+	if err := h.validator.ParseFormAndValidate(c, &req); err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(fiberErr.Message, fiberErr.Error()), fiberErr.Message)
+		}
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError("Validation failed", err.Error()), "Validation failed")
+	}
+
+	// Validate the uploaded CSV file
+	fileResult := h.validator.ValidateFile(req.File, upload.CSVImportValidation)
+	if !fileResult.Valid {
+		return helpers.HandleErrorResponse(c, helpers.NewBadRequestError("CSV file validation failed", fileResult.Error), "CSV file validation failed")
+	}
+
+	dryRun := c.QueryBool("dry_run", false)
+	result, err := h.transactionUseCase.ImportTransactions(c.Context(), &req, loggedNonAdminUserID(c), dryRun)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, "Failed to import transactions")
+	}
+
+	if dryRun {
+		return helpers.SuccessResponse(c, "Transactions import validated successfully", result)
+	}
+	return helpers.CreatedResponse(c, "Transactions imported successfully", result)
+}
+
+func (h *TransactionHandler) ImportStatement(c *fiber.Ctx) error {
+	walletID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	var req dto.ImportStatementRequest
+
+	// Parse form data with strict field validation and struct validation
+	if err := h.validator.ParseFormAndValidate(c, &req); err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(fiberErr.Message, fiberErr.Error()), fiberErr.Message)
+		}
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError("Validation failed", err.Error()), "Validation failed")
+	}
+
+	// Validate the uploaded statement file
+	fileResult := h.validator.ValidateFile(req.File, upload.StatementValidation)
+	if !fileResult.Valid {
+		return helpers.HandleErrorResponse(c, helpers.NewBadRequestError("Statement file validation failed", fileResult.Error), "Statement file validation failed")
+	}
+
+	dryRun := c.QueryBool("dry_run", false)
+	result, err := h.transactionUseCase.ImportStatement(c.Context(), walletID, &req, loggedNonAdminUserID(c), dryRun)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, "Failed to import statement")
+	}
+
+	if dryRun {
+		return helpers.SuccessResponse(c, "Statement import validated successfully", result)
+	}
+	return helpers.CreatedResponse(c, "Statement imported successfully", result)
+}
+
+func (h *TransactionHandler) ExportTransactions(c *fiber.Ctx) error {
+	format := strings.ToLower(c.Query("format", exporter.FormatCSV))
+	if !exporter.IsSupported(format) {
+		return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidQueryParams, "format must be one of csv, xlsx or json"), ut.MsgErrInvalidQueryParams)
+	}
+
+	// Same search, filter and sort parameters as the list endpoint; pagination is ignored
+	queryParams := helpers.ParseQueryParams(c)
+	delete(queryParams.Filters, "format")
+
+	// Validate query parameters
+	if err := h.validator.Validate(queryParams); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrInvalidQueryParams, err.Error()), ut.MsgErrInvalidQueryParams)
+	}
+
+	queryParams.LoggedUserID = loggedNonAdminUserID(c)
+
+	// Detach the query from the request buffer, it is used after the handler returns
+	queryParams.FilterQuery = queryParams.FilterQuery.Clone()
+	format = strings.Clone(format)
+
+	filename := fmt.Sprintf("transactions-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	c.Set(fiber.HeaderContentType, exporter.ContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	// The body is written after the handler returns, so the request context must not be used by the export.
+	// The export runs in the background into a pipe that fasthttp sends as the chunked body.
+	ctx, cancel := context.WithCancel(context.Background())
+	body, pipe := io.Pipe()
+	go func() {
+		w := bufio.NewWriterSize(pipe, exportBufferSize)
+		err := h.transactionUseCase.ExportTransactions(ctx, queryParams, format, w)
+		if err == nil {
+			err = w.Flush()
+		}
+		// Errors are logged by the use case. Closing the pipe with the error aborts the response without its final
+		// chunk, so the client sees a broken download instead of a complete looking 200.
+		pipe.CloseWithError(err)
+	}()
+	c.Context().SetBodyStream(&exportBody{PipeReader: body, cancel: cancel}, -1)
+
+	return nil
+}
+
+// exportBufferSize is how much of an export is buffered before it is sent as a chunk
+const exportBufferSize = 32 * 1024
+
+// exportBody is the response body of an export. fasthttp closes it once the response is sent or the client went
+// away, which stops the export and its query.
+type exportBody struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (b *exportBody) Close() error {
+	b.cancel()
+	return b.PipeReader.Close()
+}
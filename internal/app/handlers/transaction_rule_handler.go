@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/usecases"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	ut "github.com/naufalfazanadi/finance-manager-go/pkg/utils"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type TransactionRuleHandler struct {
+	ruleUseCase usecases.TransactionRuleUseCaseInterface
+	validator   *validator.Validator
+}
+
+func NewTransactionRuleHandler(ruleUseCase usecases.TransactionRuleUseCaseInterface, validator *validator.Validator) *TransactionRuleHandler {
+	return &TransactionRuleHandler{
+		ruleUseCase: ruleUseCase,
+		validator:   validator,
+	}
+}
+
+func (h *TransactionRuleHandler) CreateTransactionRule(c *fiber.Ctx) error {
+	var req dto.CreateTransactionRuleRequest
+
+	// Default to the logged user for non-admin requests
+	if c.Locals("userRole") != "admin" {
+		req.UserID = c.Locals("userID").(uuid.UUID)
+	}
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	if req.UserID != c.Locals("userID").(uuid.UUID) && c.Locals("userRole") != "admin" {
+		return helpers.HandleErrorResponse(c, helpers.NewForbiddenError("You do not have permission to create a rule for this user", "Permission denied"), "Permission denied")
+	}
+
+	rule, err := h.ruleUseCase.CreateRule(c.Context(), &req)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedCreateMsg("Transaction rule"))
+	}
+
+	return helpers.CreatedResponse(c, ut.SuccessCreateMsg("Transaction rule"), rule)
+}
+
+func (h *TransactionRuleHandler) GetTransactionRule(c *fiber.Ctx) error {
+	ruleID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	rule, err := h.ruleUseCase.GetRule(c.Context(), ruleID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Transaction rule"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Transaction rule"), rule)
+}
+
+// GetTransactionRules lists rules in the order they run, filterable by wallet_id, category_id, type and is_active
+func (h *TransactionRuleHandler) GetTransactionRules(c *fiber.Ctx) error {
+	queryParams := helpers.ParseQueryParams(c)
+
+	// Validate query parameters
+	if err := h.validator.Validate(queryParams); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrInvalidQueryParams, err.Error()), ut.MsgErrInvalidQueryParams)
+	}
+
+	queryParams.LoggedUserID = loggedNonAdminUserID(c)
+
+	rules, err := h.ruleUseCase.GetRules(c.Context(), queryParams)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Transaction rules"))
+	}
+
+	return helpers.PaginatedSuccessResponse(c, ut.SuccessRetrieveMsg("Transaction rules"), rules.Data, rules.Meta)
+}
+
+func (h *TransactionRuleHandler) UpdateTransactionRule(c *fiber.Ctx) error {
+	ruleID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	var req dto.UpdateTransactionRuleRequest
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	rule, err := h.ruleUseCase.UpdateRule(c.Context(), ruleID, loggedNonAdminUserID(c), &req)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedUpdateMsg("Transaction rule"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessUpdateMsg("Transaction rule"), rule)
+}
+
+func (h *TransactionRuleHandler) DeleteTransactionRule(c *fiber.Ctx) error {
+	ruleID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	err = h.ruleUseCase.DeleteRule(c.Context(), ruleID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedDeleteMsg("Transaction rule"))
+	}
+
+	return helpers.NoContentResponse(c)
+}
+
+// PreviewTransactionRule lists the existing transactions the rule would change without changing them
+func (h *TransactionRuleHandler) PreviewTransactionRule(c *fiber.Ctx) error {
+	ruleID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	result, err := h.ruleUseCase.PreviewRule(c.Context(), ruleID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, "Failed to preview transaction rule")
+	}
+
+	return helpers.SuccessResponse(c, "Transaction rule previewed successfully", result)
+}
+
+// ApplyTransactionRule applies the rule to the existing transactions it matches
+func (h *TransactionRuleHandler) ApplyTransactionRule(c *fiber.Ctx) error {
+	ruleID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	result, err := h.ruleUseCase.ApplyRule(c.Context(), ruleID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, "Failed to apply transaction rule")
+	}
+
+	return helpers.SuccessResponse(c, "Transaction rule applied successfully", result)
+}
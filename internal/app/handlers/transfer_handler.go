@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/usecases"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	ut "github.com/naufalfazanadi/finance-manager-go/pkg/utils"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type TransferHandler struct {
+	transferUseCase usecases.TransferUseCaseInterface
+	validator       *validator.Validator
+}
+
+func NewTransferHandler(transferUseCase usecases.TransferUseCaseInterface, validator *validator.Validator) *TransferHandler {
+	return &TransferHandler{
+		transferUseCase: transferUseCase,
+		validator:       validator,
+	}
+}
+
+func (h *TransferHandler) CreateTransfer(c *fiber.Ctx) error {
+	var req dto.CreateTransferRequest
+
+	// Default to the logged user for non-admin requests
+	if c.Locals("userRole") != "admin" {
+		req.UserID = c.Locals("userID").(uuid.UUID)
+	}
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	if req.UserID != c.Locals("userID").(uuid.UUID) && c.Locals("userRole") != "admin" {
+		return helpers.HandleErrorResponse(c, helpers.NewForbiddenError("You do not have permission to create a transfer for this user", "Permission denied"), "Permission denied")
+	}
+
+	transfer, err := h.transferUseCase.CreateTransfer(c.Context(), &req)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedCreateMsg("Transfer"))
+	}
+
+	return helpers.CreatedResponse(c, ut.SuccessCreateMsg("Transfer"), transfer)
+}
+
+func (h *TransferHandler) GetTransfer(c *fiber.Ctx) error {
+	transferID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	transfer, err := h.transferUseCase.GetTransfer(c.Context(), transferID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Transfer"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Transfer"), transfer)
+}
+
+func (h *TransferHandler) GetTransfers(c *fiber.Ctx) error {
+	queryParams := helpers.ParseQueryParams(c)
+
+	// Validate query parameters
+	if err := h.validator.Validate(queryParams); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrInvalidQueryParams, err.Error()), ut.MsgErrInvalidQueryParams)
+	}
+
+	queryParams.LoggedUserID = loggedNonAdminUserID(c)
+
+	transfers, err := h.transferUseCase.GetTransfers(c.Context(), queryParams)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Transfers"))
+	}
+
+	return helpers.PaginatedSuccessResponse(c, ut.SuccessRetrieveMsg("Transfers"), transfers.Data, transfers.Meta)
+}
+
+func (h *TransferHandler) UpdateTransfer(c *fiber.Ctx) error {
+	transferID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	var req dto.UpdateTransferRequest
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	transfer, err := h.transferUseCase.UpdateTransfer(c.Context(), transferID, loggedNonAdminUserID(c), &req)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedUpdateMsg("Transfer"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessUpdateMsg("Transfer"), transfer)
+}
+
+func (h *TransferHandler) DeleteTransfer(c *fiber.Ctx) error {
+	transferID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	err = h.transferUseCase.DeleteTransfer(c.Context(), transferID, loggedNonAdminUserID(c))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedDeleteMsg("Transfer"))
+	}
+
+	return helpers.NoContentResponse(c)
+}
@@ -266,7 +266,7 @@ func (h *UserHandler) RestoreUser(c *fiber.Ctx) error {
 }
 
 // GetUsersWithDeleted godoc
-// @Sum Get all users including soft deleted ones
+// @Summary Get all users including soft deleted ones
 // @Description Get all users including those that have been soft deleted
 // @Tags users
 // @Accept json
@@ -277,7 +277,7 @@ func (h *UserHandler) RestoreUser(c *fiber.Ctx) error {
 // @Param sort_by query string false "Sort by field"
 // @Param sort_type query string false "Sort type (asc/desc)" default(desc)
 // @Param preload query string false "Comma-separated relations to preload (wallet,transactions)"
-// @Success 200 {object} dto.UsersResponse
+// @Success 200 {object} object{success=bool,message=string,data=[]dto.UserResponse,meta=dto.PaginationMeta}
 // @Failure 500 {object} dto.ErrorResponse
 // @Security BearerAuth
 // @Router /v1/users/with-deleted [get]
@@ -312,7 +312,7 @@ func (h *UserHandler) GetUsersWithDeleted(c *fiber.Ctx) error {
 // @Param sort_by query string false "Sort by field"
 // @Param sort_type query string false "Sort type (asc/desc)" default(desc)
 // @Param preload query string false "Comma-separated relations to preload (wallet,transactions)"
-// @Success 200 {object} dto.UsersResponse
+// @Success 200 {object} object{success=bool,message=string,data=[]dto.UserResponse,meta=dto.PaginationMeta}
 // @Failure 500 {object} dto.ErrorResponse
 // @Security BearerAuth
 // @Router /v1/users/deleted [get]
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"time"
+
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/usecases"
 	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/currency"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
 	ut "github.com/naufalfazanadi/finance-manager-go/pkg/utils"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/validator"
@@ -44,6 +47,7 @@ func (h *WalletHandler) CreateWallet(c *fiber.Ctx) error {
 		return helpers.HandleErrorResponse(c, err, ut.FailedCreateMsg("Wallet"))
 	}
 
+	helpers.SetETag(c, wallet.Version)
 	return helpers.CreatedResponse(c, ut.SuccessCreateMsg("Wallet"), wallet)
 }
 
@@ -70,6 +74,7 @@ func (h *WalletHandler) GetWallet(c *fiber.Ctx) error {
 		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Wallet"))
 	}
 
+	helpers.SetETag(c, wallet.Version)
 	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Wallet"), wallet)
 }
 
@@ -93,6 +98,37 @@ func (h *WalletHandler) GetWallets(c *fiber.Ctx) error {
 	return helpers.PaginatedSuccessResponse(c, ut.SuccessRetrieveMsg("Wallets"), wallets.Data, wallets.Meta)
 }
 
+// GetBalanceHistory returns the end of day balance of a wallet for every day from start_date to end_date
+func (h *WalletHandler) GetBalanceHistory(c *fiber.Ctx) error {
+	walletID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	now := time.Now().UTC()
+	endDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	startDate := endDate.AddDate(0, 0, -29)
+	if value := c.Query("start_date"); value != "" {
+		startDate, err = time.Parse(currency.RateDateFormat, value)
+		if err != nil {
+			return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidQueryParams, "start_date must use the YYYY-MM-DD format"), ut.MsgErrInvalidQueryParams)
+		}
+	}
+	if value := c.Query("end_date"); value != "" {
+		endDate, err = time.Parse(currency.RateDateFormat, value)
+		if err != nil {
+			return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidQueryParams, "end_date must use the YYYY-MM-DD format"), ut.MsgErrInvalidQueryParams)
+		}
+	}
+
+	history, err := h.walletUseCase.GetBalanceHistory(c.Context(), walletID, loggedNonAdminUserID(c), startDate, endDate)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Balance history"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Balance history"), history)
+}
+
 func (h *WalletHandler) UpdateWallet(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
@@ -105,6 +141,12 @@ func (h *WalletHandler) UpdateWallet(c *fiber.Ctx) error {
 		return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidID, ut.ErrInvalidIDFormat), ut.MsgErrInvalidID)
 	}
 
+	// Only update the version the client last saw, when it sent one
+	expectedVersion, err := helpers.ParseIfMatch(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, "Invalid If-Match header")
+	}
+
 	var req dto.UpdateWalletRequest
 
 	// Parse strict JSON validation and struct validation
@@ -112,11 +154,12 @@ func (h *WalletHandler) UpdateWallet(c *fiber.Ctx) error {
 		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
 	}
 
-	wallet, err := h.walletUseCase.UpdateWallet(c.Context(), walletID, &req)
+	wallet, err := h.walletUseCase.UpdateWallet(c.Context(), walletID, &req, expectedVersion)
 	if err != nil {
 		return helpers.HandleErrorResponse(c, err, ut.FailedUpdateMsg("Wallet"))
 	}
 
+	helpers.SetETag(c, wallet.Version)
 	return helpers.SuccessResponse(c, ut.SuccessUpdateMsg("Wallet"), wallet)
 }
 
@@ -132,7 +175,13 @@ func (h *WalletHandler) DeleteWallet(c *fiber.Ctx) error {
 		return helpers.HandleErrorResponse(c, helpers.NewBadRequestError(ut.MsgErrInvalidID, ut.ErrInvalidIDFormat), ut.MsgErrInvalidID)
 	}
 
-	err = h.walletUseCase.DeleteWallet(c.Context(), walletID)
+	// Only delete the version the client last saw, when it sent one
+	expectedVersion, err := helpers.ParseIfMatch(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, "Invalid If-Match header")
+	}
+
+	err = h.walletUseCase.DeleteWallet(c.Context(), walletID, expectedVersion)
 	if err != nil {
 		return helpers.HandleErrorResponse(c, err, ut.FailedDeleteMsg("Wallet"))
 	}
@@ -1,26 +1,43 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/usecases"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
 	"github.com/naufalfazanadi/finance-manager-go/internal/worker"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	ut "github.com/naufalfazanadi/finance-manager-go/pkg/utils"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/validator"
 	"github.com/sirupsen/logrus"
 )
 
 type WorkerHandler struct {
-	cronWorker *worker.CronWorker
+	cronWorker         *worker.CronWorker
+	balanceSyncUseCase usecases.BalanceSyncUseCaseInterface
+	jobRunUseCase      usecases.JobRunUseCaseInterface
+	validator          *validator.Validator
 }
 
-func NewWorkerHandler(cronWorker *worker.CronWorker) *WorkerHandler {
+func NewWorkerHandler(
+	cronWorker *worker.CronWorker,
+	balanceSyncUseCase usecases.BalanceSyncUseCaseInterface,
+	jobRunUseCase usecases.JobRunUseCaseInterface,
+	validator *validator.Validator,
+) *WorkerHandler {
 	return &WorkerHandler{
-		cronWorker: cronWorker,
+		cronWorker:         cronWorker,
+		balanceSyncUseCase: balanceSyncUseCase,
+		jobRunUseCase:      jobRunUseCase,
+		validator:          validator,
 	}
 }
 
 // GetWorkerStatus gets the current status of the worker
 // @Sum Get worker status
-// @Description Get the current status of the balance sync worker
+// @Description Get the current status of the balance sync worker. Admins also get the instance ID of this replica and which replica runs each running job.
 // @Tags Worker
 // @Accept json
 // @Produce json
@@ -29,7 +46,7 @@ func NewWorkerHandler(cronWorker *worker.CronWorker) *WorkerHandler {
 func (h *WorkerHandler) GetWorkerStatus(c *fiber.Ctx) error {
 	funcCtx := "WorkerHandler.GetWorkerStatus"
 
-	status := h.cronWorker.GetStatus()
+	status := h.cronWorker.GetStatus(c.Context(), c.Locals("userRole") == "admin")
 
 	logger.LogSuccess(funcCtx, "Retrieved worker status", logrus.Fields{
 		"is_running": status["is_running"],
@@ -38,25 +55,286 @@ func (h *WorkerHandler) GetWorkerStatus(c *fiber.Ctx) error {
 	return helpers.SuccessResponse(c, "Worker status retrieved successfully", status)
 }
 
-// TriggerBalanceSync manually triggers balance sync for all wallets
+// GetJobs lists the registered jobs with their recent history
+// @Sum List worker jobs
+// @Description List every scheduled job with its schedule, next run, last run, last success and number of failed runs since then
+// @Tags Worker
+// @Accept json
+// @Produce json
+// @Success 200 {object} helpers.Response{data=[]dto.JobStatusResponse}
+// @Router /api/v1/workers/jobs [get]
+func (h *WorkerHandler) GetJobs(c *fiber.Ctx) error {
+	jobs, err := h.cronWorker.GetJobs(c.Context())
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Jobs"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Jobs"), jobs)
+}
+
+// PauseJob pauses the schedule of a job
+// @Sum Pause job
+// @Description Stop scheduling a job on every replica until it is resumed. A run in progress finishes and manual triggers still work. The change is stored and survives restarts
+// @Tags Worker
+// @Accept json
+// @Produce json
+// @Param name path string true "Job name" Enums(balance_sync, recurring_transactions, budget_alerts, idempotency_key_cleanup)
+// @Success 200 {object} helpers.Response{data=dto.JobStatusResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /api/v1/workers/jobs/{name}/pause [post]
+func (h *WorkerHandler) PauseJob(c *fiber.Ctx) error {
+	job, err := h.cronWorker.PauseJob(c.Context(), c.Params("name"))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, "Failed to pause job")
+	}
+
+	return helpers.SuccessResponse(c, "Job paused successfully", job)
+}
+
+// ResumeJob resumes the schedule of a paused job
+// @Sum Resume job
+// @Description Schedule a paused job again on every replica. The change is stored and survives restarts
+// @Tags Worker
+// @Accept json
+// @Produce json
+// @Param name path string true "Job name" Enums(balance_sync, recurring_transactions, budget_alerts, idempotency_key_cleanup)
+// @Success 200 {object} helpers.Response{data=dto.JobStatusResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /api/v1/workers/jobs/{name}/resume [post]
+func (h *WorkerHandler) ResumeJob(c *fiber.Ctx) error {
+	job, err := h.cronWorker.ResumeJob(c.Context(), c.Params("name"))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, "Failed to resume job")
+	}
+
+	return helpers.SuccessResponse(c, "Job resumed successfully", job)
+}
+
+// RescheduleJob changes the schedule of a job
+// @Sum Reschedule job
+// @Description Change the cron expression of a job and optionally its time zone on every replica. A paused job stays paused. The change is stored and takes precedence over the configured schedule, also after restarts
+// @Tags Worker
+// @Accept json
+// @Produce json
+// @Param name path string true "Job name" Enums(balance_sync, recurring_transactions, budget_alerts, idempotency_key_cleanup)
+// @Param request body dto.RescheduleJobRequest true "New schedule"
+// @Success 200 {object} helpers.Response{data=dto.JobStatusResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/v1/workers/jobs/{name}/schedule [put]
+func (h *WorkerHandler) RescheduleJob(c *fiber.Ctx) error {
+	var req dto.RescheduleJobRequest
+
+	// Parse strict JSON validation and struct validation
+	if err := h.validator.ParseAndValidate(c, &req); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrReqBody, err.Error()), ut.MsgErrReqBody)
+	}
+
+	job, err := h.cronWorker.RescheduleJob(c.Context(), c.Params("name"), req.Schedule, req.Timezone)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, "Failed to reschedule job")
+	}
+
+	return helpers.SuccessResponse(c, "Job rescheduled successfully", job)
+}
+
+// ResetJob resets the schedule of a job to the configured one
+// @Sum Reset job schedule
+// @Description Delete the stored schedule of a job, so every replica uses its configured cron expression, time zone and enabled flag again
+// @Tags Worker
+// @Accept json
+// @Produce json
+// @Param name path string true "Job name" Enums(balance_sync, recurring_transactions, budget_alerts, idempotency_key_cleanup)
+// @Success 200 {object} helpers.Response{data=dto.JobStatusResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /api/v1/workers/jobs/{name}/schedule [delete]
+func (h *WorkerHandler) ResetJob(c *fiber.Ctx) error {
+	job, err := h.cronWorker.ResetJob(c.Context(), c.Params("name"))
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, "Failed to reset job schedule")
+	}
+
+	return helpers.SuccessResponse(c, "Job schedule reset successfully", job)
+}
+
+// GetJobRun gets a job run
+// @Sum Get job run
+// @Description Get a scheduled or manual job run with its status and, once it finished, its duration, error and result summary. Poll it after queueing a manual run
+// @Tags Worker
+// @Accept json
+// @Produce json
+// @Param id path string true "Job run ID"
+// @Success 200 {object} helpers.Response{data=dto.JobRunResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /api/v1/workers/jobs/runs/{id} [get]
+func (h *WorkerHandler) GetJobRun(c *fiber.Ctx) error {
+	runID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	run, err := h.jobRunUseCase.GetJobRun(c.Context(), runID)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Job run"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Job run"), run)
+}
+
+// GetJobRuns lists the runs of a job
+// @Sum List job runs
+// @Description List the scheduled and manual runs of a job, newest first, with their status, duration, error and result summary
+// @Tags Worker
+// @Accept json
+// @Produce json
+// @Param name path string true "Job name" Enums(balance_sync, recurring_transactions, budget_alerts, idempotency_key_cleanup)
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} helpers.Response{data=[]dto.JobRunResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /api/v1/workers/jobs/{name}/runs [get]
+func (h *WorkerHandler) GetJobRuns(c *fiber.Ctx) error {
+	jobName := c.Params("name")
+	if !h.cronWorker.HasJob(jobName) {
+		return helpers.HandleErrorResponse(c, helpers.NewNotFoundError("job not found", jobName), ut.FailedGetMsg("Job runs"))
+	}
+
+	queryParams := helpers.ParseQueryParams(c)
+
+	// Validate query parameters
+	if err := h.validator.Validate(queryParams); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrInvalidQueryParams, err.Error()), ut.MsgErrInvalidQueryParams)
+	}
+
+	runs, err := h.jobRunUseCase.GetJobRuns(c.Context(), jobName, queryParams)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Job runs"))
+	}
+
+	return helpers.PaginatedSuccessResponse(c, ut.SuccessRetrieveMsg("Job runs"), runs.Data, runs.Meta)
+}
+
+// TriggerBalanceSync queues a manual balance sync for all wallets
 // @Sum Trigger balance sync
-// @Description Manually trigger balance sync for all wallets
+// @Description Queue a manual run recomputing every wallet balance from its transactions and correcting the drifted ones. Returns the queued run right away; poll it with GET /api/v1/workers/jobs/runs/{id}. The report of drifted wallets is stored for auditing and referenced by the run summary; with dry_run nothing is written and the whole report is the run summary
 // @Tags Worker
 // @Accept json
 // @Produce json
-// @Success 200 {object} helpers.Response
+// @Param dry_run query bool false "Report drifted balances without correcting or storing anything"
+// @Success 202 {object} helpers.Response{data=dto.JobRunResponse}
+// @Failure 409 {object} helpers.Response "The job is already running on this or another replica"
 // @Router /api/v1/worker/balance-sync [post]
 func (h *WorkerHandler) TriggerBalanceSync(c *fiber.Ctx) error {
 	funcCtx := "WorkerHandler.TriggerBalanceSync"
 
-	logger.LogSuccess(funcCtx, "Manual balance sync triggered", logrus.Fields{})
+	dryRun := c.QueryBool("dry_run", false)
+
+	logger.LogSuccess(funcCtx, "Manual balance sync triggered", logrus.Fields{
+		"dry_run": dryRun,
+	})
+
+	run, err := h.cronWorker.TriggerSync(dryRun)
+	if err != nil {
+		return handleTriggerError(c, err, "Failed to trigger balance sync")
+	}
+
+	logger.LogSuccess(funcCtx, "Balance sync queued", logrus.Fields{
+		"job_run_id": run.ID.String(),
+	})
+	return helpers.AcceptedResponse(c, "Balance sync queued successfully", run)
+}
+
+// GetBalanceSyncReports lists the stored balance sync reports
+// @Sum List balance sync reports
+// @Description List the reports of past balance sync runs, newest first. Entries are only returned by the report detail
+// @Tags Worker
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} helpers.Response{data=[]dto.BalanceSyncReportResponse}
+// @Router /api/v1/worker/balance-sync/reports [get]
+func (h *WorkerHandler) GetBalanceSyncReports(c *fiber.Ctx) error {
+	queryParams := helpers.ParseQueryParams(c)
+
+	// Validate query parameters
+	if err := h.validator.Validate(queryParams); err != nil {
+		return helpers.HandleErrorResponse(c, helpers.NewValidationError(ut.MsgErrInvalidQueryParams, err.Error()), ut.MsgErrInvalidQueryParams)
+	}
+
+	reports, err := h.balanceSyncUseCase.GetSyncReports(c.Context(), queryParams)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Balance sync reports"))
+	}
+
+	return helpers.PaginatedSuccessResponse(c, ut.SuccessRetrieveMsg("Balance sync reports"), reports.Data, reports.Meta)
+}
+
+// GetBalanceSyncReport gets a stored balance sync report
+// @Sum Get balance sync report
+// @Description Get a balance sync report with the stored and recomputed balance of every wallet that drifted or failed
+// @Tags Worker
+// @Accept json
+// @Produce json
+// @Param id path string true "Report ID"
+// @Success 200 {object} helpers.Response{data=dto.BalanceSyncReportResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /api/v1/worker/balance-sync/reports/{id} [get]
+func (h *WorkerHandler) GetBalanceSyncReport(c *fiber.Ctx) error {
+	reportID, err := parseIDParam(c)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.MsgErrInvalidID)
+	}
+
+	report, err := h.balanceSyncUseCase.GetSyncReport(c.Context(), reportID)
+	if err != nil {
+		return helpers.HandleErrorResponse(c, err, ut.FailedGetMsg("Balance sync report"))
+	}
+
+	return helpers.SuccessResponse(c, ut.SuccessRetrieveMsg("Balance sync report"), report)
+}
+
+// TriggerRecurringTransactions queues a manual run posting all due recurring transactions
+// @Sum Trigger recurring transactions
+// @Description Queue a manual run posting every due recurring transaction occurrence (already posted occurrences are skipped). Returns the queued run right away; poll it with GET /api/v1/workers/jobs/runs/{id}
+// @Tags Worker
+// @Accept json
+// @Produce json
+// @Success 202 {object} helpers.Response{data=dto.JobRunResponse}
+// @Failure 409 {object} helpers.Response "The job is already running on this or another replica"
+// @Router /api/v1/worker/recurring-transactions [post]
+func (h *WorkerHandler) TriggerRecurringTransactions(c *fiber.Ctx) error {
+	run, err := h.cronWorker.TriggerRecurringTransactions()
+	if err != nil {
+		return handleTriggerError(c, err, "Failed to trigger recurring transactions")
+	}
+
+	return helpers.AcceptedResponse(c, "Recurring transactions run queued successfully", run)
+}
 
-	err := h.cronWorker.TriggerSync(c.Context())
+// TriggerBudgetAlerts queues a manual budget alert run
+// @Sum Trigger budget alerts
+// @Description Queue a manual run evaluating budget thresholds and emailing owners (alerts already sent this period are skipped). Returns the queued run right away; poll it with GET /api/v1/workers/jobs/runs/{id}
+// @Tags Worker
+// @Accept json
+// @Produce json
+// @Success 202 {object} helpers.Response{data=dto.JobRunResponse}
+// @Failure 409 {object} helpers.Response "The job is already running on this or another replica"
+// @Router /api/v1/worker/budget-alerts [post]
+func (h *WorkerHandler) TriggerBudgetAlerts(c *fiber.Ctx) error {
+	run, err := h.cronWorker.TriggerBudgetAlerts()
 	if err != nil {
-		logger.LogError(funcCtx, "Failed to trigger balance sync", err, logrus.Fields{})
-		return helpers.InternalServerErrorResponse(c, "Failed to trigger balance sync", err.Error())
+		return handleTriggerError(c, err, "Failed to trigger budget alerts")
 	}
 
-	logger.LogSuccess(funcCtx, "Balance sync completed successfully", logrus.Fields{})
-	return helpers.SuccessResponse(c, "Balance sync completed successfully", nil)
+	return helpers.AcceptedResponse(c, "Budget alerts run queued successfully", run)
+}
+
+// handleTriggerError answers a manual trigger that could not queue its run, a job already running is a conflict
+func handleTriggerError(c *fiber.Ctx, err error, message string) error {
+	if errors.Is(err, worker.ErrJobLocked) {
+		return helpers.HandleErrorResponse(c, helpers.NewConflictError(err.Error(), "wait for the running job to finish"), message)
+	}
+	logger.LogError("WorkerHandler.handleTriggerError", message, err, logrus.Fields{})
+	return helpers.HandleErrorResponse(c, err, message)
 }
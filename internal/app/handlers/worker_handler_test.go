@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/naufalfazanadi/finance-manager-go/internal/worker"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleTriggerError(t *testing.T) {
+	logger.Init("info")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{
+			name:       "job already running",
+			err:        fmt.Errorf("balance_sync: %w", worker.ErrJobLocked),
+			wantStatus: fiber.StatusConflict,
+		},
+		{
+			name:       "run not recorded",
+			err:        helpers.NewInternalError("failed to record job run", worker.JobBalanceSync),
+			wantStatus: fiber.StatusInternalServerError,
+		},
+		{
+			name:       "lock query failed",
+			err:        errors.New("connection refused"),
+			wantStatus: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Post("/trigger", func(c *fiber.Ctx) error {
+				return handleTriggerError(c, tt.err, "Failed to trigger balance sync")
+			})
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/trigger", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
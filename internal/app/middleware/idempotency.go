@@ -0,0 +1,299 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/infrastructure/auth"
+	"github.com/naufalfazanadi/finance-manager-go/internal/infrastructure/cache"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// IdempotencyMiddleware replays the stored response of POST, PUT and DELETE requests retried with the same
+// Idempotency-Key header, so a retry after a dropped connection doesn't create or delete anything twice
+type IdempotencyMiddleware struct {
+	idempotencyKeyRepo repositories.IdempotencyKeyRepository
+}
+
+// NewIdempotencyMiddleware creates a new idempotency middleware
+func NewIdempotencyMiddleware(idempotencyKeyRepo repositories.IdempotencyKeyRepository) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		idempotencyKeyRepo: idempotencyKeyRepo,
+	}
+}
+
+// Idempotency keys responses by user and key. Responses are kept in Redis, or in Postgres while Redis is unavailable,
+// for 24 hours. The key is claimed before the request runs, so a retry sent while the first request is still running
+// gets 409 instead of running it twice. Reusing a key with a different method, path, query or body is rejected with 422.
+// Server errors are not stored and release the claim, so those requests can be retried with the same key. Requests
+// without the header, or without a valid token, pass through unchanged and are left to the route's own authentication.
+func (im *IdempotencyMiddleware) Idempotency() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		funcCtx := "IdempotencyMiddleware"
+
+		method := c.Method()
+		if method != fiber.MethodPost && method != fiber.MethodPut && method != fiber.MethodDelete {
+			return c.Next()
+		}
+
+		key := strings.TrimSpace(c.Get(IdempotencyKeyHeader))
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return helpers.HandleErrorResponse(c, helpers.NewBadRequestError("Invalid Idempotency-Key", "Idempotency-Key must be at most 255 characters"), "Invalid Idempotency-Key")
+		}
+
+		userID, ok := idempotencyUserID(c)
+		if !ok {
+			return c.Next()
+		}
+
+		path := idempotencyRequestPath(c)
+		requestHash := hashIdempotentRequest(c, method, path)
+
+		if stored := im.get(c, userID, key); stored != nil {
+			return replayIdempotentResponse(c, stored, requestHash)
+		}
+
+		now := time.Now()
+		idempotencyKey := &entities.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Method:      method,
+			Path:        path,
+			RequestHash: requestHash,
+			Status:      entities.IdempotencyKeyStatusProcessing,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(cache.IdempotencyClaimTTL),
+		}
+		claimed, inRedis := im.claim(c, idempotencyKey, funcCtx)
+		if !claimed {
+			// Another request took the key between the lookup and the claim
+			stored := im.get(c, userID, key)
+			if stored == nil {
+				return idempotencyInProgress(c)
+			}
+			return replayIdempotentResponse(c, stored, requestHash)
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				im.release(c, idempotencyKey, inRedis, funcCtx)
+				panic(r)
+			}
+		}()
+		if err := c.Next(); err != nil {
+			im.release(c, idempotencyKey, inRedis, funcCtx)
+			return err
+		}
+
+		statusCode := c.Response().StatusCode()
+		if statusCode >= fiber.StatusInternalServerError {
+			im.release(c, idempotencyKey, inRedis, funcCtx)
+			return nil
+		}
+
+		idempotencyKey.Status = entities.IdempotencyKeyStatusCompleted
+		idempotencyKey.StatusCode = statusCode
+		idempotencyKey.ContentType = string(c.Response().Header.ContentType())
+		idempotencyKey.ETag = string(c.Response().Header.Peek(fiber.HeaderETag))
+		idempotencyKey.ResponseBody = append([]byte(nil), c.Response().Body()...)
+		idempotencyKey.ExpiresAt = now.Add(cache.DefaultIdempotencyCacheTTL)
+		im.save(c, idempotencyKey, funcCtx)
+
+		return nil
+	}
+}
+
+// replayIdempotentResponse answers a retry with the response stored for its key, 409 while the first request is
+// still running, or 422 when the key was used for another request
+func replayIdempotentResponse(c *fiber.Ctx, stored *entities.IdempotencyKey, requestHash string) error {
+	if stored.RequestHash != requestHash {
+		return helpers.ErrorResponse(c, fiber.StatusUnprocessableEntity, "Idempotency-Key already used", "Idempotency-Key was already used with a different request")
+	}
+	if stored.Status == entities.IdempotencyKeyStatusProcessing {
+		return idempotencyInProgress(c)
+	}
+
+	c.Set(IdempotentReplayedHeader, "true")
+	if stored.ContentType != "" {
+		c.Set(fiber.HeaderContentType, stored.ContentType)
+	}
+	if stored.ETag != "" {
+		c.Set(fiber.HeaderETag, stored.ETag)
+	}
+	return c.Status(stored.StatusCode).Send(stored.ResponseBody)
+}
+
+func idempotencyInProgress(c *fiber.Ctx) error {
+	return helpers.ErrorResponse(c, fiber.StatusConflict, "Request in progress", "A request with this Idempotency-Key is still being processed, retry later")
+}
+
+// get looks the key up in Redis first, then in Postgres for responses stored while Redis was down
+func (im *IdempotencyMiddleware) get(c *fiber.Ctx, userID uuid.UUID, key string) *entities.IdempotencyKey {
+	if cache.IsRedisAvailable() {
+		if stored, err := cache.GetIdempotencyKey(c.Context(), userID, key); err == nil {
+			return stored
+		}
+	}
+	stored, err := im.idempotencyKeyRepo.Get(c.Context(), userID, key)
+	if err != nil {
+		return nil
+	}
+	return stored
+}
+
+// claim marks the key as processing in Redis, falling back to Postgres when Redis is unavailable, and reports whether
+// this request holds the key and where. When neither store can be written the request runs unprotected, like before
+// the key was sent.
+func (im *IdempotencyMiddleware) claim(c *fiber.Ctx, idempotencyKey *entities.IdempotencyKey, funcCtx string) (claimed bool, inRedis bool) {
+	if cache.IsRedisAvailable() {
+		claimed, err := cache.ClaimIdempotencyKey(c.Context(), idempotencyKey)
+		if err == nil {
+			return claimed, true
+		}
+		logger.LogError(funcCtx, "failed to claim idempotency key in redis, falling back to database", err, logrus.Fields{
+			"user_id": idempotencyKey.UserID.String(),
+		})
+	}
+
+	claimed, err := im.idempotencyKeyRepo.Claim(c.Context(), idempotencyKey)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to claim idempotency key", err, logrus.Fields{
+			"user_id": idempotencyKey.UserID.String(),
+		})
+		return true, false
+	}
+	return claimed, false
+}
+
+// release drops the claim of a request that failed. A claim that can't be deleted expires on its own.
+func (im *IdempotencyMiddleware) release(c *fiber.Ctx, idempotencyKey *entities.IdempotencyKey, inRedis bool, funcCtx string) {
+	var err error
+	if inRedis {
+		err = cache.DeleteIdempotencyKey(c.Context(), idempotencyKey.UserID, idempotencyKey.Key)
+	} else {
+		err = im.idempotencyKeyRepo.Release(c.Context(), idempotencyKey.UserID, idempotencyKey.Key)
+	}
+	if err != nil {
+		logger.LogError(funcCtx, "failed to release idempotency key", err, logrus.Fields{
+			"user_id": idempotencyKey.UserID.String(),
+		})
+	}
+}
+
+// save keeps the response in Redis, falling back to Postgres when Redis is unavailable. Failures are only logged,
+// since the request itself already succeeded.
+func (im *IdempotencyMiddleware) save(c *fiber.Ctx, idempotencyKey *entities.IdempotencyKey, funcCtx string) {
+	if cache.IsRedisAvailable() {
+		err := cache.SetIdempotencyKey(c.Context(), idempotencyKey)
+		if err == nil {
+			return
+		}
+		logger.LogError(funcCtx, "failed to store idempotency key in redis, falling back to database", err, logrus.Fields{
+			"user_id": idempotencyKey.UserID.String(),
+		})
+	}
+	if err := im.idempotencyKeyRepo.Save(c.Context(), idempotencyKey); err != nil {
+		logger.LogError(funcCtx, "failed to store idempotency key", err, logrus.Fields{
+			"user_id": idempotencyKey.UserID.String(),
+		})
+	}
+}
+
+// idempotencyUserID reads the user from the bearer token, since the middleware runs before the route's JWTAuth
+func idempotencyUserID(c *fiber.Ctx) (uuid.UUID, bool) {
+	authHeader := c.Get("Authorization")
+	token := strings.TrimSpace(authHeader)
+	if strings.HasPrefix(authHeader, "Bearer ") || strings.HasPrefix(authHeader, "bearer ") {
+		token = strings.TrimSpace(authHeader[7:])
+	}
+	if token == "" {
+		return uuid.Nil, false
+	}
+
+	claims, err := auth.ValidateToken(token)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return claims.UserID, true
+}
+
+// idempotencyRequestPath is the path with its query parameters sorted, so ?a=1&b=2 and ?b=2&a=1 are the same request
+func idempotencyRequestPath(c *fiber.Ctx) string {
+	rawQuery := string(c.Request().URI().QueryString())
+	if rawQuery == "" {
+		return c.Path()
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return c.Path() + "?" + rawQuery
+	}
+	return c.Path() + "?" + query.Encode()
+}
+
+// hashIdempotentRequest fingerprints a request so a reused key can be told apart from a retry
+func hashIdempotentRequest(c *fiber.Ctx, method, path string) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(path))
+	hash.Write([]byte{0})
+	if !hashMultipartForm(c, hash) {
+		hash.Write(c.Body())
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// hashMultipartForm writes the fields and files of a multipart request in name order instead of its raw body, whose
+// boundary changes on every retry of the same upload. It reports false when the request isn't a readable multipart form.
+func hashMultipartForm(c *fiber.Ctx, hash io.Writer) bool {
+	if !strings.HasPrefix(string(c.Request().Header.ContentType()), fiber.MIMEMultipartForm) {
+		return false
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		return false
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(form.Value)) {
+		for _, value := range form.Value[name] {
+			fmt.Fprintf(hash, "value\x00%s\x00%d\x00%s\x00", name, len(value), value)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(form.File)) {
+		for _, header := range form.File[name] {
+			fmt.Fprintf(hash, "file\x00%s\x00%s\x00%d\x00", name, header.Filename, header.Size)
+			file, err := header.Open()
+			if err != nil {
+				return false
+			}
+			_, err = io.Copy(hash, file)
+			file.Close()
+			if err != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,256 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/infrastructure/auth"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/config"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryIdempotencyKeyRepository stands in for Postgres, which is used while Redis is unavailable
+type memoryIdempotencyKeyRepository struct {
+	mu   sync.Mutex
+	keys map[string]entities.IdempotencyKey
+}
+
+func (r *memoryIdempotencyKeyRepository) Get(_ context.Context, userID uuid.UUID, key string) (*entities.IdempotencyKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, ok := r.keys[userID.String()+key]; ok && stored.ExpiresAt.After(time.Now()) {
+		return &stored, nil
+	}
+	return nil, errors.New("idempotency key not found")
+}
+
+func (r *memoryIdempotencyKeyRepository) Claim(_ context.Context, idempotencyKey *entities.IdempotencyKey) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, ok := r.keys[idempotencyKey.UserID.String()+idempotencyKey.Key]; ok && stored.ExpiresAt.After(time.Now()) {
+		return false, nil
+	}
+	r.keys[idempotencyKey.UserID.String()+idempotencyKey.Key] = *idempotencyKey
+	return true, nil
+}
+
+func (r *memoryIdempotencyKeyRepository) Save(_ context.Context, idempotencyKey *entities.IdempotencyKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys[idempotencyKey.UserID.String()+idempotencyKey.Key] = *idempotencyKey
+	return nil
+}
+
+func (r *memoryIdempotencyKeyRepository) Release(_ context.Context, userID uuid.UUID, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, ok := r.keys[userID.String()+key]; ok && stored.Status == entities.IdempotencyKeyStatusProcessing {
+		delete(r.keys, userID.String()+key)
+	}
+	return nil
+}
+
+func (r *memoryIdempotencyKeyRepository) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for id, stored := range r.keys {
+		if !stored.ExpiresAt.After(now) {
+			delete(r.keys, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func testToken(t *testing.T, userID uuid.UUID) string {
+	claims := &auth.JWTClaims{
+		UserID: userID,
+		Role:   entities.UserRoleUser,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.LoadConfig().JWT.Secret))
+	require.NoError(t, err)
+	return token
+}
+
+func TestIdempotency(t *testing.T) {
+	logger.Init("info")
+
+	calls := 0
+	app := fiber.New()
+	app.Use(NewIdempotencyMiddleware(&memoryIdempotencyKeyRepository{keys: map[string]entities.IdempotencyKey{}}).Idempotency())
+	app.Post("/transactions", func(c *fiber.Ctx) error {
+		calls++
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": calls})
+	})
+
+	token := testToken(t, uuid.New())
+	send := func(key, body string) (int, string, string) {
+		req := httptest.NewRequest(fiber.MethodPost, "/transactions", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(respBody), resp.Header.Get(IdempotentReplayedHeader)
+	}
+
+	status, body, replayed := send("key-1", `{"cost":"10.00"}`)
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.JSONEq(t, `{"call":1}`, body)
+	assert.Empty(t, replayed)
+
+	// A retry replays the first response without running the handler again
+	status, body, replayed = send("key-1", `{"cost":"10.00"}`)
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.JSONEq(t, `{"call":1}`, body)
+	assert.Equal(t, "true", replayed)
+
+	// The same key with another body is rejected
+	status, _, _ = send("key-1", `{"cost":"20.00"}`)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, status)
+
+	// Requests without a key always run
+	status, body, _ = send("", `{"cost":"10.00"}`)
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.JSONEq(t, `{"call":2}`, body)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_ConcurrentRetry(t *testing.T) {
+	logger.Init("info")
+
+	var calls atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	app := fiber.New()
+	app.Use(NewIdempotencyMiddleware(&memoryIdempotencyKeyRepository{keys: map[string]entities.IdempotencyKey{}}).Idempotency())
+	app.Post("/transfers", func(c *fiber.Ctx) error {
+		call := calls.Add(1)
+		if call == 1 {
+			close(started)
+			<-release
+		}
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": call})
+	})
+	app.Post("/failing", func(c *fiber.Ctx) error {
+		if calls.Add(1) == 1 {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "boom"})
+		}
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"ok": true})
+	})
+
+	token := testToken(t, uuid.New())
+	send := func(path, key string) (int, string) {
+		req := httptest.NewRequest(fiber.MethodPost, path, strings.NewReader(`{"amount":"10"}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(IdempotencyKeyHeader, key)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(respBody)
+	}
+
+	first := make(chan int, 1)
+	go func() {
+		status, _ := send("/transfers", "transfer-1")
+		first <- status
+	}()
+	<-started
+
+	// A retry while the first request still runs is turned away without running the handler
+	status, _ := send("/transfers", "transfer-1")
+	assert.Equal(t, fiber.StatusConflict, status)
+
+	close(release)
+	assert.Equal(t, fiber.StatusCreated, <-first)
+
+	// Once the first request finished its response is replayed
+	status, body := send("/transfers", "transfer-1")
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.JSONEq(t, `{"call":1}`, body)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// A server error releases the key, so the retry runs the handler again
+	calls.Store(0)
+	status, _ = send("/failing", "failing-1")
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	status, _ = send("/failing", "failing-1")
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestIdempotency_RequestFingerprint(t *testing.T) {
+	logger.Init("info")
+
+	calls := 0
+	app := fiber.New()
+	app.Use(NewIdempotencyMiddleware(&memoryIdempotencyKeyRepository{keys: map[string]entities.IdempotencyKey{}}).Idempotency())
+	app.Post("/transactions/import", func(c *fiber.Ctx) error {
+		calls++
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"call": calls, "dry_run": c.Query("dry_run")})
+	})
+
+	token := testToken(t, uuid.New())
+	send := func(target, key, boundary, csv string) (int, string) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		require.NoError(t, writer.SetBoundary(boundary))
+		require.NoError(t, writer.WriteField("wallet_id", "wallet-1"))
+		part, err := writer.CreateFormFile("file", "statement.csv")
+		require.NoError(t, err)
+		_, err = part.Write([]byte(csv))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(fiber.MethodPost, target, &body)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set(fiber.HeaderContentType, writer.FormDataContentType())
+		req.Header.Set(IdempotencyKeyHeader, key)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(respBody)
+	}
+
+	csv := "date,name,amount\n2026-01-01,Coffee,10\n"
+	status, body := send("/transactions/import?dry_run=true&format=csv", "import-1", "first-boundary", csv)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.JSONEq(t, `{"call":1,"dry_run":"true"}`, body)
+
+	// The same upload with another boundary and its query in another order is a retry
+	status, body = send("/transactions/import?format=csv&dry_run=true", "import-1", "second-boundary", csv)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.JSONEq(t, `{"call":1,"dry_run":"true"}`, body)
+
+	// Reusing the dry run key for the real import, or for another file, is rejected
+	status, _ = send("/transactions/import?dry_run=false&format=csv", "import-1", "first-boundary", csv)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, status)
+	status, _ = send("/transactions/import?dry_run=true&format=csv", "import-1", "first-boundary", csv+"2026-01-02,Tea,5\n")
+	assert.Equal(t, fiber.StatusUnprocessableEntity, status)
+	assert.Equal(t, 1, calls)
+}
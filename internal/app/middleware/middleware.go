@@ -42,9 +42,10 @@ func ErrorHandler(c *fiber.Ctx, err error) error {
 // CORS middleware
 func CORS() fiber.Handler {
 	return cors.New(cors.Config{
-		AllowOrigins: config.LoadConfig().CORS.AllowOrigins,
-		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization",
+		AllowOrigins:  config.LoadConfig().CORS.AllowOrigins,
+		AllowMethods:  "GET,POST,PUT,DELETE,OPTIONS",
+		AllowHeaders:  "Origin,Content-Type,Accept,Authorization,Idempotency-Key,If-Match",
+		ExposeHeaders: "ETag,Idempotent-Replayed",
 	})
 }
 
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/naufalfazanadi/finance-manager-go/internal/app/container"
+)
+
+// BudgetRoutes handles per-category budget routes using centralized dependencies
+func BudgetRoutes(api fiber.Router, dependencies *container.ServiceContainer) {
+	// Get handlers and middleware from centralized container
+	authMiddleware := dependencies.AuthMiddleware
+	budgetHandler := dependencies.BudgetHandler
+
+	// Budget routes
+	v1 := api.Group("/v1")
+	budgets := v1.Group("/budgets")
+
+	// Protected routes (authentication required)
+	budgets.Post("/", authMiddleware.JWTAuth(), budgetHandler.CreateBudget)                 // Create budget
+	budgets.Get("/", authMiddleware.JWTAuth(), budgetHandler.GetBudgets)                    // Get all budgets
+	budgets.Get("/:id", authMiddleware.JWTAuth(), budgetHandler.GetBudget)                  // Get budget by ID
+	budgets.Get("/:id/progress", authMiddleware.JWTAuth(), budgetHandler.GetBudgetProgress) // Get spent versus limit for a period
+	budgets.Put("/:id", authMiddleware.JWTAuth(), budgetHandler.UpdateBudget)               // Update budget
+	budgets.Delete("/:id", authMiddleware.JWTAuth(), budgetHandler.DeleteBudget)            // Soft delete budget
+}
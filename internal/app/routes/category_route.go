@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/naufalfazanadi/finance-manager-go/internal/app/container"
+)
+
+// CategoryRoutes handles user category routes using centralized dependencies
+func CategoryRoutes(api fiber.Router, dependencies *container.ServiceContainer) {
+	// Get handlers and middleware from centralized container
+	authMiddleware := dependencies.AuthMiddleware
+	categoryHandler := dependencies.CategoryHandler
+
+	// Category routes
+	v1 := api.Group("/v1")
+	categories := v1.Group("/categories")
+
+	// Protected routes (authentication required)
+	categories.Post("/", authMiddleware.JWTAuth(), categoryHandler.CreateCategory)      // Create category
+	categories.Get("/", authMiddleware.JWTAuth(), categoryHandler.GetCategories)        // Get all categories
+	categories.Get("/:id", authMiddleware.JWTAuth(), categoryHandler.GetCategory)       // Get category by ID
+	categories.Put("/:id", authMiddleware.JWTAuth(), categoryHandler.UpdateCategory)    // Update, move or archive category
+	categories.Delete("/:id", authMiddleware.JWTAuth(), categoryHandler.DeleteCategory) // Soft delete unused category
+}
@@ -16,5 +16,7 @@ func DashboardRoutes(api fiber.Router, dependencies *container.ServiceContainer)
 	dashboard := v1.Group("/dashboard")
 
 	// Protected routes (authentication required)
-	dashboard.Get("/users/:id/monthly-summary", authMiddleware.JWTAuth(), dashboardHandler.GetMonthlySumByUser) // Get monthly sum by user ID
+	dashboard.Get("/users/:id/monthly-summary", authMiddleware.JWTAuth(), dashboardHandler.GetMonthlySumByUser) // Get monthly sum by user ID, converted with ?currency=
+	dashboard.Get("/users/:id/balance-summary", authMiddleware.JWTAuth(), dashboardHandler.GetBalanceSummary)   // Get wallet balances converted with ?currency=&date=
+	dashboard.Get("/users/:id/tag-summary", authMiddleware.JWTAuth(), dashboardHandler.GetTagSummary)           // Get totals per tag with ?start_date=&end_date=&currency=
 }
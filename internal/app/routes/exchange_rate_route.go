@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/naufalfazanadi/finance-manager-go/internal/app/container"
+	"github.com/naufalfazanadi/finance-manager-go/internal/app/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExchangeRateRoutes handles exchange rate routes using centralized dependencies
+func ExchangeRateRoutes(api fiber.Router, dependencies *container.ServiceContainer) {
+	// Get handlers and middleware from centralized container
+	authMiddleware := dependencies.AuthMiddleware
+	exchangeRateHandler := dependencies.ExchangeRateHandler
+
+	// Exchange rate routes
+	v1 := api.Group("/v1")
+	exchangeRates := v1.Group("/exchange-rates")
+
+	// Protected routes (authentication required)
+	exchangeRates.Get("/", authMiddleware.JWTAuth(), exchangeRateHandler.GetExchangeRates)                                      // Get all exchange rates
+	exchangeRates.Post("/", authMiddleware.JWTAuth(), middleware.RequireAdmin(), exchangeRateHandler.UpsertExchangeRates)       // Create or replace exchange rates (admin only)
+	exchangeRates.Post("/import", authMiddleware.JWTAuth(), middleware.RequireAdmin(), exchangeRateHandler.ImportExchangeRates) // Import exchange rates from a CSV file (admin only)
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/naufalfazanadi/finance-manager-go/internal/app/container"
+)
+
+// RecurringTransactionRoutes handles recurring transaction template routes using centralized dependencies
+func RecurringTransactionRoutes(api fiber.Router, dependencies *container.ServiceContainer) {
+	// Get handlers and middleware from centralized container
+	authMiddleware := dependencies.AuthMiddleware
+	recurringHandler := dependencies.RecurringTransactionHandler
+
+	// Recurring transaction routes
+	v1 := api.Group("/v1")
+	recurrings := v1.Group("/recurring-transactions")
+
+	// Protected routes (authentication required)
+	recurrings.Post("/", authMiddleware.JWTAuth(), recurringHandler.CreateRecurringTransaction)      // Create recurring transaction template
+	recurrings.Get("/", authMiddleware.JWTAuth(), recurringHandler.GetRecurringTransactions)         // Get all recurring transactions
+	recurrings.Get("/:id", authMiddleware.JWTAuth(), recurringHandler.GetRecurringTransaction)       // Get recurring transaction by ID
+	recurrings.Put("/:id", authMiddleware.JWTAuth(), recurringHandler.UpdateRecurringTransaction)    // Update template, pause/resume or reschedule
+	recurrings.Delete("/:id", authMiddleware.JWTAuth(), recurringHandler.DeleteRecurringTransaction) // Soft delete template (posted transactions are kept)
+}
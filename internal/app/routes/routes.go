@@ -49,13 +49,23 @@ func Setup(dependencies *container.ServiceContainer) *fiber.App {
 	// API-specific rate limiter - More restrictive for API endpoints
 	api.Use(middleware.APIRateLimiter())
 
+	// Replay stored responses of POST/PUT/DELETE requests retried with the same Idempotency-Key header
+	api.Use(dependencies.IdempotencyMiddleware.Idempotency())
+
 	// Setup routes with centralized dependencies
 	AuthRoutes(api, dependencies)
 	UserRoutes(api, dependencies)
 	WalletRoutes(api, dependencies)
 	TransactionRoutes(api, dependencies)
+	TransferRoutes(api, dependencies)
+	RecurringTransactionRoutes(api, dependencies)
+	BudgetRoutes(api, dependencies)
 	WorkerRoutes(api, dependencies)
 	DashboardRoutes(api, dependencies)
+	ExchangeRateRoutes(api, dependencies)
+	CategoryRoutes(api, dependencies)
+	TagRoutes(api, dependencies)
+	TransactionRuleRoutes(api, dependencies)
 
 	return app
 }
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/naufalfazanadi/finance-manager-go/internal/app/container"
+)
+
+// TagRoutes handles transaction tag routes using centralized dependencies
+func TagRoutes(api fiber.Router, dependencies *container.ServiceContainer) {
+	// Get handlers and middleware from centralized container
+	authMiddleware := dependencies.AuthMiddleware
+	tagHandler := dependencies.TagHandler
+
+	// Tag routes
+	v1 := api.Group("/v1")
+	tags := v1.Group("/tags")
+
+	// Protected routes (authentication required)
+	tags.Post("/", authMiddleware.JWTAuth(), tagHandler.CreateTag)      // Create tag
+	tags.Get("/", authMiddleware.JWTAuth(), tagHandler.GetTags)         // Get all tags
+	tags.Get("/:id", authMiddleware.JWTAuth(), tagHandler.GetTag)       // Get tag by ID
+	tags.Put("/:id", authMiddleware.JWTAuth(), tagHandler.UpdateTag)    // Rename or recolor tag
+	tags.Delete("/:id", authMiddleware.JWTAuth(), tagHandler.DeleteTag) // Delete tag and remove it from its transactions
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/naufalfazanadi/finance-manager-go/internal/app/container"
+	"github.com/naufalfazanadi/finance-manager-go/internal/app/middleware"
 )
 
 // TransactionRoutes handles transaction-related routes using centralized dependencies
@@ -10,15 +11,30 @@ func TransactionRoutes(api fiber.Router, dependencies *container.ServiceContaine
 	// Get handlers and middleware from centralized container
 	authMiddleware := dependencies.AuthMiddleware
 	transactionHandler := dependencies.TransactionHandler
+	attachmentHandler := dependencies.TransactionAttachmentHandler
+	duplicateHandler := dependencies.TransactionDuplicateHandler
 
 	// Transaction routes
 	v1 := api.Group("/v1")
 	transactions := v1.Group("/transactions")
 
 	// Protected routes (authentication required)
-	transactions.Post("/", authMiddleware.JWTAuth(), transactionHandler.CreateTransaction)      // Create transaction
-	transactions.Get("/", authMiddleware.JWTAuth(), transactionHandler.GetTransactions)         // Get all transactions
-	transactions.Get("/:id", authMiddleware.JWTAuth(), transactionHandler.GetTransaction)       // Get transaction by ID
-	transactions.Put("/:id", authMiddleware.JWTAuth(), transactionHandler.UpdateTransaction)    // Update transaction
-	transactions.Delete("/:id", authMiddleware.JWTAuth(), transactionHandler.DeleteTransaction) // Soft delete transaction
+	transactions.Post("/", authMiddleware.JWTAuth(), transactionHandler.CreateTransaction)                // Create transaction
+	transactions.Get("/", authMiddleware.JWTAuth(), transactionHandler.GetTransactions)                   // Get all transactions
+	transactions.Get("/export", authMiddleware.JWTAuth(), transactionHandler.ExportTransactions)          // Export all matching transactions (?format=csv|xlsx|json, same filters as the list)
+	transactions.Post("/import", authMiddleware.JWTAuth(), transactionHandler.ImportTransactions)         // Import transactions from a CSV file (supports ?dry_run=true)
+	transactions.Get("/duplicates", authMiddleware.JWTAuth(), duplicateHandler.GetDuplicates)             // List likely duplicate pairs (?wallet_id=&window= in minutes&cursor=)
+	transactions.Post("/duplicates/merge", authMiddleware.JWTAuth(), duplicateHandler.MergeDuplicate)     // Keep one transaction of a pair and delete the other
+	transactions.Post("/duplicates/dismiss", authMiddleware.JWTAuth(), duplicateHandler.DismissDuplicate) // Keep both transactions of a pair
+	transactions.Get("/:id", authMiddleware.JWTAuth(), transactionHandler.GetTransaction)                 // Get transaction by ID
+	transactions.Put("/:id", authMiddleware.JWTAuth(), transactionHandler.UpdateTransaction)              // Update transaction
+	transactions.Delete("/:id", authMiddleware.JWTAuth(), transactionHandler.DeleteTransaction)           // Soft delete transaction
+
+	// Receipt attachments, served through short-lived signed URLs
+	transactions.Post("/:id/attachments", authMiddleware.JWTAuth(), attachmentHandler.UploadAttachment)                 // Attach a receipt (multipart field "file")
+	transactions.Get("/:id/attachments", authMiddleware.JWTAuth(), attachmentHandler.GetAttachments)                    // List attachments with signed URLs
+	transactions.Delete("/:id/attachments/:attachmentId", authMiddleware.JWTAuth(), attachmentHandler.DeleteAttachment) // Delete attachment and its file
+
+	// Admin only routes
+	transactions.Delete("/:id/hard", authMiddleware.JWTAuth(), middleware.RequireAdmin(), transactionHandler.HardDeleteTransaction) // Hard delete transaction permanently, including attachment files
 }
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/naufalfazanadi/finance-manager-go/internal/app/container"
+)
+
+// TransactionRuleRoutes handles categorisation rule routes using centralized dependencies
+func TransactionRuleRoutes(api fiber.Router, dependencies *container.ServiceContainer) {
+	// Get handlers and middleware from centralized container
+	authMiddleware := dependencies.AuthMiddleware
+	ruleHandler := dependencies.TransactionRuleHandler
+
+	// Transaction rule routes
+	v1 := api.Group("/v1")
+	rules := v1.Group("/transaction-rules")
+
+	// Protected routes (authentication required)
+	rules.Post("/", authMiddleware.JWTAuth(), ruleHandler.CreateTransactionRule)             // Create rule
+	rules.Get("/", authMiddleware.JWTAuth(), ruleHandler.GetTransactionRules)                // Get all rules in priority order
+	rules.Get("/:id", authMiddleware.JWTAuth(), ruleHandler.GetTransactionRule)              // Get rule by ID
+	rules.Put("/:id", authMiddleware.JWTAuth(), ruleHandler.UpdateTransactionRule)           // Update rule
+	rules.Delete("/:id", authMiddleware.JWTAuth(), ruleHandler.DeleteTransactionRule)        // Soft delete rule
+	rules.Post("/:id/preview", authMiddleware.JWTAuth(), ruleHandler.PreviewTransactionRule) // List the existing transactions the rule would change
+	rules.Post("/:id/apply", authMiddleware.JWTAuth(), ruleHandler.ApplyTransactionRule)     // Apply the rule to existing transactions
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/naufalfazanadi/finance-manager-go/internal/app/container"
+)
+
+// TransferRoutes handles wallet-to-wallet transfer routes using centralized dependencies
+func TransferRoutes(api fiber.Router, dependencies *container.ServiceContainer) {
+	// Get handlers and middleware from centralized container
+	authMiddleware := dependencies.AuthMiddleware
+	transferHandler := dependencies.TransferHandler
+
+	// Transfer routes
+	v1 := api.Group("/v1")
+	transfers := v1.Group("/transfers")
+
+	// Protected routes (authentication required)
+	transfers.Post("/", authMiddleware.JWTAuth(), transferHandler.CreateTransfer)      // Create transfer (debits source, credits destination)
+	transfers.Get("/", authMiddleware.JWTAuth(), transferHandler.GetTransfers)         // Get all transfers
+	transfers.Get("/:id", authMiddleware.JWTAuth(), transferHandler.GetTransfer)       // Get transfer by ID
+	transfers.Put("/:id", authMiddleware.JWTAuth(), transferHandler.UpdateTransfer)    // Update transfer and re-balance both wallets
+	transfers.Delete("/:id", authMiddleware.JWTAuth(), transferHandler.DeleteTransfer) // Soft delete transfer and reverse both wallets
+}
@@ -10,15 +10,18 @@ func WalletRoutes(api fiber.Router, dependencies *container.ServiceContainer) {
 	// Get handlers and middleware from centralized container
 	authMiddleware := dependencies.AuthMiddleware
 	walletHandler := dependencies.WalletHandler
+	transactionHandler := dependencies.TransactionHandler
 
 	// Wallet routes
 	v1 := api.Group("/v1")
 	wallets := v1.Group("/wallets")
 
 	// Protected routes (authentication required)
-	wallets.Post("/", authMiddleware.JWTAuth(), walletHandler.CreateWallet)      // Create wallet (signup) - supports both JSON and multipart with optional photo
-	wallets.Get("/", authMiddleware.JWTAuth(), walletHandler.GetWallets)         // Get all wallets (wallet/admin)
-	wallets.Get("/:id", authMiddleware.JWTAuth(), walletHandler.GetWallet)       // Get wallet by ID (wallet/admin)
-	wallets.Put("/:id", authMiddleware.JWTAuth(), walletHandler.UpdateWallet)    // Update wallet (wallet/admin) - supports both JSON and multipart with optional photo
-	wallets.Delete("/:id", authMiddleware.JWTAuth(), walletHandler.DeleteWallet) // Soft delete wallet (admin only)
+	wallets.Post("/", authMiddleware.JWTAuth(), walletHandler.CreateWallet)                       // Create wallet (signup) - supports both JSON and multipart with optional photo
+	wallets.Get("/", authMiddleware.JWTAuth(), walletHandler.GetWallets)                          // Get all wallets (wallet/admin)
+	wallets.Get("/:id", authMiddleware.JWTAuth(), walletHandler.GetWallet)                        // Get wallet by ID (wallet/admin)
+	wallets.Get("/:id/history", authMiddleware.JWTAuth(), walletHandler.GetBalanceHistory)        // Get the end of day balance per day (?start_date=&end_date=, YYYY-MM-DD, last 30 days by default)
+	wallets.Put("/:id", authMiddleware.JWTAuth(), walletHandler.UpdateWallet)                     // Update wallet (wallet/admin) - supports both JSON and multipart with optional photo
+	wallets.Delete("/:id", authMiddleware.JWTAuth(), walletHandler.DeleteWallet)                  // Soft delete wallet (admin only)
+	wallets.Post("/:id/statements", authMiddleware.JWTAuth(), transactionHandler.ImportStatement) // Import an OFX/QIF bank statement into the wallet (supports ?dry_run=true)
 }
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/naufalfazanadi/finance-manager-go/internal/app/container"
+	"github.com/naufalfazanadi/finance-manager-go/internal/app/middleware"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -17,6 +18,17 @@ func WorkerRoutes(api fiber.Router, dependencies *container.ServiceContainer) {
 	workers := v1.Group("/workers")
 
 	// All worker routes require authentication
-	workers.Get("/status", authMiddleware.JWTAuth(), workerHandler.GetWorkerStatus)           // Get worker status
-	workers.Post("/balance-sync", authMiddleware.JWTAuth(), workerHandler.TriggerBalanceSync) // Trigger manual balance sync
+	workers.Get("/status", authMiddleware.JWTAuth(), workerHandler.GetWorkerStatus)                                                          // Get worker status
+	workers.Get("/jobs", authMiddleware.JWTAuth(), middleware.RequireAdmin(), workerHandler.GetJobs)                                         // List jobs with their last run and failure streak (admin only)
+	workers.Get("/jobs/runs/:id", authMiddleware.JWTAuth(), middleware.RequireAdmin(), workerHandler.GetJobRun)                              // Poll a job run, e.g. one queued by a manual trigger (admin only)
+	workers.Post("/jobs/:name/pause", authMiddleware.JWTAuth(), middleware.RequireAdmin(), workerHandler.PauseJob)                           // Stop scheduling a job on every replica (admin only)
+	workers.Post("/jobs/:name/resume", authMiddleware.JWTAuth(), middleware.RequireAdmin(), workerHandler.ResumeJob)                         // Schedule a paused job again (admin only)
+	workers.Put("/jobs/:name/schedule", authMiddleware.JWTAuth(), middleware.RequireAdmin(), workerHandler.RescheduleJob)                    // Change the cron expression and time zone of a job (admin only)
+	workers.Delete("/jobs/:name/schedule", authMiddleware.JWTAuth(), middleware.RequireAdmin(), workerHandler.ResetJob)                      // Go back to the configured schedule of a job (admin only)
+	workers.Get("/jobs/:name/runs", authMiddleware.JWTAuth(), middleware.RequireAdmin(), workerHandler.GetJobRuns)                           // List the run history of a job (admin only)
+	workers.Post("/balance-sync", authMiddleware.JWTAuth(), middleware.RequireAdmin(), workerHandler.TriggerBalanceSync)                     // Queue a manual balance sync, or a dry run with ?dry_run=true (admin only)
+	workers.Get("/balance-sync/reports", authMiddleware.JWTAuth(), middleware.RequireAdmin(), workerHandler.GetBalanceSyncReports)           // List balance sync reports (admin only)
+	workers.Get("/balance-sync/reports/:id", authMiddleware.JWTAuth(), middleware.RequireAdmin(), workerHandler.GetBalanceSyncReport)        // Get balance sync report with drifted wallets (admin only)
+	workers.Post("/recurring-transactions", authMiddleware.JWTAuth(), middleware.RequireAdmin(), workerHandler.TriggerRecurringTransactions) // Queue posting due recurring transactions (admin only)
+	workers.Post("/budget-alerts", authMiddleware.JWTAuth(), middleware.RequireAdmin(), workerHandler.TriggerBudgetAlerts)                   // Queue a budget alert evaluation (admin only)
 }
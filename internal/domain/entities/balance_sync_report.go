@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+)
+
+// TableName sets the table name
+func (BalanceSyncReport) TableName() string {
+	return "balance_sync_reports"
+}
+
+// TableName sets the table name
+func (BalanceSyncReportEntry) TableName() string {
+	return "balance_sync_report_entries"
+}
+
+// BalanceSyncReport is the outcome of a balance sync run, kept so admins can audit which balances were corrected.
+// Only wallets whose balance drifted or that failed to sync get an entry, the counts cover every wallet checked.
+type BalanceSyncReport struct {
+	ID         uuid.UUID                `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Wallets    int                      `json:"wallets" gorm:"not null"`
+	Drifted    int                      `json:"drifted" gorm:"not null"`
+	Corrected  int                      `json:"corrected" gorm:"not null"`
+	Failed     int                      `json:"failed" gorm:"not null"`
+	Cancelled  bool                     `json:"cancelled" gorm:"not null;default:false"` // The run stopped before checking every wallet
+	StartedAt  time.Time                `json:"started_at" gorm:"not null;index"`
+	FinishedAt time.Time                `json:"finished_at" gorm:"not null"`
+	CreatedAt  time.Time                `json:"created_at"`
+	Entries    []BalanceSyncReportEntry `json:"entries,omitempty" gorm:"foreignKey:ReportID;constraint:OnDelete:CASCADE"`
+}
+
+// BalanceSyncReportEntry is the stored and recomputed balance of one wallet in a balance sync run
+type BalanceSyncReportEntry struct {
+	ID                uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ReportID          uuid.UUID   `json:"report_id" gorm:"type:uuid;not null;index"`
+	WalletID          uuid.UUID   `json:"wallet_id" gorm:"type:uuid;not null;index"`
+	StoredBalance     money.Money `json:"stored_balance" gorm:"type:decimal(20,8);not null"`
+	RecomputedBalance money.Money `json:"recomputed_balance" gorm:"type:decimal(20,8);not null"`
+	Difference        money.Money `json:"difference" gorm:"type:decimal(20,8);not null"` // RecomputedBalance - StoredBalance
+	TransactionCount  int         `json:"transaction_count" gorm:"not null"`
+	Corrected         bool        `json:"corrected" gorm:"not null;default:false"`
+	Error             string      `json:"error,omitempty" gorm:"type:text"`
+}
+
+// IsDrifted reports whether the stored balance differs from the one recomputed from transactions
+func (e *BalanceSyncReportEntry) IsDrifted() bool {
+	return !e.Difference.IsZero()
+}
@@ -0,0 +1,126 @@
+package entities
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+	"gorm.io/gorm"
+)
+
+// TableName sets the table name
+func (Budget) TableName() string {
+	return "budgets"
+}
+
+// BudgetPeriod represents the calendar period a budget limit applies to
+type BudgetPeriod string
+
+const (
+	BudgetPeriodWeekly  BudgetPeriod = "weekly"
+	BudgetPeriodMonthly BudgetPeriod = "monthly"
+	BudgetPeriodYearly  BudgetPeriod = "yearly"
+)
+
+// DefaultBudgetAlertThresholds are the percentages of the limit that trigger an email when crossed
+const DefaultBudgetAlertThresholds = "80,100"
+
+// Budget caps the expenses of one transaction category per calendar period, optionally scoped to a wallet.
+// With Rollover enabled the unused (or overspent) amount of past periods carries into the current one.
+type Budget struct {
+	ID        uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name      string       `json:"name" gorm:"not null"`
+	UserID    uuid.UUID    `json:"user_id" gorm:"type:uuid;not null;index"`
+	WalletID  *uuid.UUID   `json:"wallet_id" gorm:"type:uuid;index"` // Nil means all wallets of the user
+	TCategory string       `json:"t_category" gorm:"column:t_category;not null;index"`
+	Period    BudgetPeriod `json:"period" gorm:"type:varchar(20);not null"`
+	Amount    money.Money  `json:"amount" gorm:"type:decimal(20,8);not null"`
+	Rollover  bool         `json:"rollover" gorm:"not null;default:false"`
+	// Spending alerts, thresholds are comma separated percentages of the limit (e.g. "80,100")
+	AlertsEnabled   bool           `json:"alerts_enabled" gorm:"not null;default:false;index"`
+	AlertThresholds string         `json:"alert_thresholds" gorm:"type:varchar(100);not null;default:'80,100'"`
+	IsDeleted       bool           `json:"is_deleted" gorm:"column:is_deleted;default:false;index"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	// Relationships
+	// Belongs to User
+	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	// Optionally belongs to Wallet
+	Wallet *Wallet `json:"wallet,omitempty" gorm:"foreignKey:WalletID"`
+}
+
+// IsSoftDeleted checks if budget is soft deleted (either by boolean flag or DeletedAt timestamp)
+func (b *Budget) IsSoftDeleted() bool {
+	return b.IsDeleted || b.DeletedAt.Valid
+}
+
+// GetAlertThresholds parses AlertThresholds into sorted percentages, ignoring invalid entries
+func (b *Budget) GetAlertThresholds() []int {
+	var thresholds []int
+	for _, part := range strings.Split(b.AlertThresholds, ",") {
+		threshold, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || threshold <= 0 {
+			continue
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	sort.Ints(thresholds)
+	return thresholds
+}
+
+// SetAlertThresholds stores the percentages as a sorted comma separated list
+func (b *Budget) SetAlertThresholds(thresholds []int) {
+	sorted := append([]int(nil), thresholds...)
+	sort.Ints(sorted)
+
+	parts := make([]string, 0, len(sorted))
+	for i, threshold := range sorted {
+		if i > 0 && threshold == sorted[i-1] {
+			continue
+		}
+		parts = append(parts, strconv.Itoa(threshold))
+	}
+	b.AlertThresholds = strings.Join(parts, ",")
+}
+
+// PeriodStart returns the start of the period containing at, in UTC (weeks start on Monday)
+func (b *Budget) PeriodStart(at time.Time) time.Time {
+	at = at.UTC()
+	year, month, day := at.Date()
+
+	switch b.Period {
+	case BudgetPeriodWeekly:
+		offset := (int(at.Weekday()) + 6) % 7 // Days since Monday
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, time.UTC)
+	case BudgetPeriodYearly:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// NextPeriodStart returns the start of the period following the one that starts at start
+func (b *Budget) NextPeriodStart(start time.Time) time.Time {
+	switch b.Period {
+	case BudgetPeriodWeekly:
+		return start.AddDate(0, 0, 7)
+	case BudgetPeriodYearly:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+// PeriodsBetween counts the whole periods from the period containing from up to (excluding) the period starting at to
+func (b *Budget) PeriodsBetween(from, to time.Time) int {
+	count := 0
+	for start := b.PeriodStart(from); start.Before(to); start = b.NextPeriodStart(start) {
+		count++
+	}
+	return count
+}
@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+)
+
+// TableName sets the table name
+func (BudgetAlert) TableName() string {
+	return "budget_alerts"
+}
+
+// BudgetAlert records a threshold alert for a budget period.
+// The unique index on (budget, period, threshold) guarantees an alert is never sent twice in the same period.
+// The alert is committed before its email is sent: EmailPending marks the one alert of a run that still owes an email,
+// so a failed email is retried by the next run.
+type BudgetAlert struct {
+	ID           uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	BudgetID     uuid.UUID   `json:"budget_id" gorm:"type:uuid;not null;uniqueIndex:idx_budget_alerts_period_threshold"`
+	UserID       uuid.UUID   `json:"user_id" gorm:"type:uuid;not null;index"`
+	PeriodStart  time.Time   `json:"period_start" gorm:"not null;uniqueIndex:idx_budget_alerts_period_threshold"`
+	Threshold    int         `json:"threshold" gorm:"not null;uniqueIndex:idx_budget_alerts_period_threshold"`
+	Spent        money.Money `json:"spent" gorm:"type:decimal(20,8);not null"`
+	Limit        money.Money `json:"limit" gorm:"column:limit_amount;type:decimal(20,8);not null"`
+	PercentUsed  float64     `json:"percent_used" gorm:"type:decimal(10,2);not null"`
+	EmailPending bool        `json:"email_pending" gorm:"not null;default:false"`
+	EmailedAt    *time.Time  `json:"emailed_at"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func utcDay(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestBudget_PeriodsBetween(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+
+	tests := []struct {
+		name   string
+		period BudgetPeriod
+		from   time.Time
+		to     time.Time
+		want   int
+	}{
+		{"monthly whole periods", BudgetPeriodMonthly, utcDay(2024, 1, 1), utcDay(2024, 4, 1), 3},
+		{"monthly from the middle of a period counts that period", BudgetPeriodMonthly, utcDay(2024, 1, 15), utcDay(2024, 4, 1), 3},
+		{"monthly to the middle of a period counts that period", BudgetPeriodMonthly, utcDay(2024, 1, 1), utcDay(2024, 4, 10), 4},
+		{"monthly same period start", BudgetPeriodMonthly, utcDay(2024, 1, 1), utcDay(2024, 1, 1), 0},
+		{"monthly from after to", BudgetPeriodMonthly, utcDay(2024, 5, 1), utcDay(2024, 4, 1), 0},
+		{"monthly across a year end", BudgetPeriodMonthly, utcDay(2023, 11, 30), utcDay(2024, 2, 1), 3},
+		{"monthly from is read in UTC", BudgetPeriodMonthly, time.Date(2024, 2, 1, 1, 0, 0, 0, jakarta), utcDay(2024, 2, 1), 1},
+		{"weekly whole periods", BudgetPeriodWeekly, utcDay(2024, 1, 1), utcDay(2024, 1, 22), 3},
+		{"weekly from a Wednesday starts on its Monday", BudgetPeriodWeekly, utcDay(2024, 1, 3), utcDay(2024, 1, 22), 3},
+		{"weekly from a Sunday belongs to the week before", BudgetPeriodWeekly, utcDay(2024, 1, 7), utcDay(2024, 1, 8), 1},
+		{"weekly to the middle of a week counts that week", BudgetPeriodWeekly, utcDay(2024, 1, 1), utcDay(2024, 1, 10), 2},
+		{"yearly whole periods", BudgetPeriodYearly, utcDay(2022, 1, 1), utcDay(2024, 1, 1), 2},
+		{"yearly from the middle of a year counts that year", BudgetPeriodYearly, utcDay(2022, 6, 15), utcDay(2024, 1, 1), 2},
+		{"yearly to the middle of a year counts that year", BudgetPeriodYearly, utcDay(2022, 1, 1), utcDay(2024, 3, 1), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := &Budget{Period: tt.period}
+
+			assert.Equal(t, tt.want, budget.PeriodsBetween(tt.from, tt.to))
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TableName sets the table name
+func (Category) TableName() string {
+	return "categories"
+}
+
+// Category groups transactions of one type for a user. Categories form a tree through ParentID,
+// e.g. Food > Restaurants, and archived categories are kept for history but can't be assigned anymore.
+type Category struct {
+	ID         uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID     uuid.UUID       `json:"user_id" gorm:"type:uuid;not null;index"`
+	ParentID   *uuid.UUID      `json:"parent_id" gorm:"type:uuid;index"` // Nil for top-level categories
+	Name       string          `json:"name" gorm:"type:varchar(100);not null"`
+	Type       TransactionType `json:"type" gorm:"type:varchar(20);not null"`
+	Icon       string          `json:"icon" gorm:"type:varchar(50)"`
+	Color      string          `json:"color" gorm:"type:varchar(7)"` // Hex color such as #F97316
+	IsArchived bool            `json:"is_archived" gorm:"not null;default:false;index"`
+	IsDeleted  bool            `json:"is_deleted" gorm:"column:is_deleted;default:false;index"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt  `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// IsSoftDeleted checks if category is soft deleted (either by boolean flag or DeletedAt timestamp)
+func (c *Category) IsSoftDeleted() bool {
+	return c.IsDeleted || c.DeletedAt.Valid
+}
+
+// IsActive checks if category is not soft deleted (neither boolean flag nor DeletedAt timestamp)
+func (c *Category) IsActive() bool {
+	return !c.IsDeleted && !c.DeletedAt.Valid
+}
+
+// defaultCategory describes a seeded top-level category and the names of its subcategories
+type defaultCategory struct {
+	Name     string
+	Type     TransactionType
+	Icon     string
+	Color    string
+	Children []string
+}
+
+// defaultCategories is the starter set every user gets
+var defaultCategories = []defaultCategory{
+	{Name: "Food & Drinks", Type: TransactionTypeExpense, Icon: "utensils", Color: "#F97316", Children: []string{"Groceries", "Restaurants", "Coffee"}},
+	{Name: "Transportation", Type: TransactionTypeExpense, Icon: "car", Color: "#3B82F6", Children: []string{"Fuel", "Public Transport", "Parking"}},
+	{Name: "Housing", Type: TransactionTypeExpense, Icon: "home", Color: "#8B5CF6", Children: []string{"Rent", "Maintenance"}},
+	{Name: "Bills & Utilities", Type: TransactionTypeExpense, Icon: "receipt", Color: "#EAB308", Children: []string{"Electricity", "Water", "Internet", "Phone"}},
+	{Name: "Shopping", Type: TransactionTypeExpense, Icon: "shopping-bag", Color: "#EC4899"},
+	{Name: "Health", Type: TransactionTypeExpense, Icon: "heart-pulse", Color: "#EF4444"},
+	{Name: "Entertainment", Type: TransactionTypeExpense, Icon: "film", Color: "#14B8A6"},
+	{Name: "Education", Type: TransactionTypeExpense, Icon: "book", Color: "#6366F1"},
+	{Name: "Other Expenses", Type: TransactionTypeExpense, Icon: "circle", Color: "#6B7280"},
+	{Name: "Salary", Type: TransactionTypeIncome, Icon: "briefcase", Color: "#22C55E"},
+	{Name: "Bonus", Type: TransactionTypeIncome, Icon: "gift", Color: "#10B981"},
+	{Name: "Investment", Type: TransactionTypeIncome, Icon: "trending-up", Color: "#0EA5E9"},
+	{Name: "Other Income", Type: TransactionTypeIncome, Icon: "circle", Color: "#6B7280"},
+}
+
+// DefaultCategoriesFor builds the default categories of a user, parents before their children
+func DefaultCategoriesFor(userID uuid.UUID) []*Category {
+	var categories []*Category
+	for _, def := range defaultCategories {
+		parent := &Category{
+			ID:     uuid.New(),
+			UserID: userID,
+			Name:   def.Name,
+			Type:   def.Type,
+			Icon:   def.Icon,
+			Color:  def.Color,
+		}
+		categories = append(categories, parent)
+
+		for _, name := range def.Children {
+			categories = append(categories, &Category{
+				ID:       uuid.New(),
+				UserID:   userID,
+				ParentID: &parent.ID,
+				Name:     name,
+				Type:     def.Type,
+				Icon:     def.Icon,
+				Color:    def.Color,
+			})
+		}
+	}
+	return categories
+}
+
+// categoryUniqueNameIndex keeps category names unique among siblings of the same type, ignoring case.
+// parent_id is coalesced because Postgres treats NULLs as distinct, which would let top-level names repeat.
+const categoryUniqueNameIndex = `
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_unique_name
+ON categories (user_id, type, COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'::uuid), LOWER(name))
+WHERE deleted_at IS NULL`
+
+// MigrateTransactionCategories seeds the default categories of users that have none, renames siblings
+// whose names only differ by case by appending " (2)", " (3)"... so idx_categories_unique_name can be
+// built, then links every transaction without a category to the category named like its t_category
+// (matched case-insensitively and ignoring surrounding spaces, preferring top-level categories as
+// CategoryRepository.GetByName does), creating a top-level category of the same type when none exists.
+// It must run after AutoMigrate, which creates the categories table and transactions.category_id.
+func MigrateTransactionCategories(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var userIDs []uuid.UUID
+		if err := tx.Model(&User{}).
+			Where("NOT EXISTS (SELECT 1 FROM categories WHERE categories.user_id = users.id)").
+			Pluck("id", &userIDs).Error; err != nil {
+			return err
+		}
+		for _, userID := range userIDs {
+			if err := tx.Create(DefaultCategoriesFor(userID)).Error; err != nil {
+				return err
+			}
+		}
+
+		var renamed []Category
+		if err := tx.Raw(`
+WITH ranked AS (
+  SELECT id, ROW_NUMBER() OVER (
+    PARTITION BY user_id, type, parent_id, LOWER(name) ORDER BY created_at, id
+  ) AS position
+  FROM categories
+  WHERE deleted_at IS NULL
+)
+UPDATE categories c
+SET name = LEFT(c.name, 90) || ' (' || ranked.position || ')', updated_at = NOW()
+FROM ranked
+WHERE c.id = ranked.id AND ranked.position > 1
+RETURNING c.id, c.name`).Scan(&renamed).Error; err != nil {
+			return err
+		}
+		for _, category := range renamed {
+			for _, model := range []interface{}{&Transaction{}, &TransactionSplit{}} {
+				if err := tx.Model(model).Where("category_id = ?", category.ID).Update("t_category", category.Name).Error; err != nil {
+					return err
+				}
+			}
+		}
+		if err := tx.Exec(categoryUniqueNameIndex).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+INSERT INTO categories (user_id, name, type, created_at, updated_at)
+SELECT DISTINCT ON (t.user_id, t.type, LOWER(TRIM(t.t_category)))
+  t.user_id, TRIM(t.t_category), t.type, NOW(), NOW()
+FROM transactions t
+WHERE t.category_id IS NULL
+  AND t.transfer_id IS NULL
+  AND TRIM(t.t_category) <> ''
+  AND NOT EXISTS (
+    SELECT 1 FROM categories c
+    WHERE c.user_id = t.user_id AND c.type = t.type
+      AND c.deleted_at IS NULL AND LOWER(c.name) = LOWER(TRIM(t.t_category))
+  )
+ORDER BY t.user_id, t.type, LOWER(TRIM(t.t_category)), t.created_at
+ON CONFLICT DO NOTHING`).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+UPDATE transactions t
+SET category_id = c.id, t_category = c.name
+FROM (
+  SELECT DISTINCT ON (user_id, type, LOWER(name)) id, user_id, type, name
+  FROM categories
+  WHERE deleted_at IS NULL
+  ORDER BY user_id, type, LOWER(name), parent_id NULLS FIRST, created_at
+) c
+WHERE t.category_id IS NULL
+  AND t.transfer_id IS NULL
+  AND c.user_id = t.user_id AND c.type = t.type AND LOWER(c.name) = LOWER(TRIM(t.t_category))`).Error
+	})
+}
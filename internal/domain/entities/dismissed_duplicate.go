@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TableName sets the table name
+func (DismissedDuplicate) TableName() string {
+	return "dismissed_duplicates"
+}
+
+// DismissedDuplicate records a pair of transactions a user reviewed and kept both of, so the pair is no longer
+// reported as a likely duplicate. The pair is stored with the lower ID first, see NewDismissedDuplicate.
+type DismissedDuplicate struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID        uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	TransactionID uuid.UUID `json:"transaction_id" gorm:"type:uuid;not null;uniqueIndex:idx_dismissed_duplicates_pair,priority:1"`
+	DuplicateID   uuid.UUID `json:"duplicate_id" gorm:"type:uuid;not null;uniqueIndex:idx_dismissed_duplicates_pair,priority:2"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// NewDismissedDuplicate orders the pair the way Postgres orders UUIDs, so (a, b) and (b, a) are the same row
+func NewDismissedDuplicate(userID, a, b uuid.UUID) *DismissedDuplicate {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return &DismissedDuplicate{UserID: userID, TransactionID: a, DuplicateID: b}
+}
@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+)
+
+// Exchange rate sources
+const (
+	ExchangeRateSourceFile  = "file"
+	ExchangeRateSourceAdmin = "admin"
+)
+
+// TableName sets the table name
+func (ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
+
+// ExchangeRate is the price of one unit of Base in Quote on a day, e.g. 1 USD = 15500 IDR.
+// Only one rate is kept per day and pair; loading the same day again replaces it.
+type ExchangeRate struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Date      time.Time  `json:"date" gorm:"type:date;not null;uniqueIndex:idx_exchange_rates_pair_date,priority:3"`
+	Base      string     `json:"base" gorm:"type:varchar(3);not null;uniqueIndex:idx_exchange_rates_pair_date,priority:1"`
+	Quote     string     `json:"quote" gorm:"type:varchar(3);not null;uniqueIndex:idx_exchange_rates_pair_date,priority:2"`
+	Rate      money.Rate `json:"rate" gorm:"type:decimal(24,12);not null"`
+	Source    string     `json:"source" gorm:"type:varchar(20);not null"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
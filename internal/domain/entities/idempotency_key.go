@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TableName sets the table name
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// IdempotencyKeyStatus tells whether the request of an idempotency key is still running
+type IdempotencyKeyStatus string
+
+const (
+	IdempotencyKeyStatusProcessing IdempotencyKeyStatus = "processing" // Claimed by the request running now
+	IdempotencyKeyStatusCompleted  IdempotencyKeyStatus = "completed"  // Holds the response to replay
+)
+
+// IdempotencyKey is the stored outcome of a mutating request sent with an Idempotency-Key header. Retries with the
+// same key and request replay the stored response instead of running the request again. A key is claimed as
+// processing before its request runs, so a retry arriving meanwhile is turned away instead of running it twice.
+// Rows live in Postgres only while Redis is unavailable; otherwise the same value is kept in Redis.
+type IdempotencyKey struct {
+	ID           uuid.UUID            `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID       uuid.UUID            `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_idempotency_keys_user_key,priority:1"`
+	Key          string               `json:"key" gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_user_key,priority:2"`
+	Method       string               `json:"method" gorm:"type:varchar(10);not null"`
+	Path         string               `json:"path" gorm:"type:text;not null"` // With its query parameters sorted
+	RequestHash  string               `json:"request_hash" gorm:"type:varchar(64);not null"`
+	Status       IdempotencyKeyStatus `json:"status" gorm:"type:varchar(20);not null;default:completed"`
+	StatusCode   int                  `json:"status_code" gorm:"not null"`
+	ContentType  string               `json:"content_type" gorm:"type:varchar(255)"`
+	ETag         string               `json:"etag" gorm:"column:etag;type:varchar(64)"`
+	ResponseBody []byte               `json:"response_body" gorm:"type:bytea"`
+	CreatedAt    time.Time            `json:"created_at"`
+	ExpiresAt    time.Time            `json:"expires_at" gorm:"not null;index"`
+}
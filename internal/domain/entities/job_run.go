@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TableName sets the table name
+func (JobRun) TableName() string {
+	return "job_runs"
+}
+
+// JobTrigger is what started a job run
+type JobTrigger string
+
+const (
+	JobTriggerScheduled JobTrigger = "scheduled"
+	JobTriggerManual    JobTrigger = "manual"
+)
+
+// JobRunStatus is the state of a job run
+type JobRunStatus string
+
+const (
+	JobRunStatusRunning   JobRunStatus = "running"
+	JobRunStatusSucceeded JobRunStatus = "succeeded"
+	JobRunStatusFailed    JobRunStatus = "failed"
+)
+
+// JobRun records one execution of a background job. The row is created when the job starts and completed when it
+// finishes, so a run left in running status was interrupted by a shutdown or crash.
+type JobRun struct {
+	ID         uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	JobName    string          `json:"job_name" gorm:"type:varchar(50);not null;index:idx_job_runs_job_started,priority:1"`
+	Trigger    JobTrigger      `json:"trigger" gorm:"type:varchar(20);not null"`
+	Status     JobRunStatus    `json:"status" gorm:"type:varchar(20);not null"`
+	StartedAt  time.Time       `json:"started_at" gorm:"not null;index:idx_job_runs_job_started,priority:2"`
+	FinishedAt *time.Time      `json:"finished_at"`
+	DurationMs int64           `json:"duration_ms" gorm:"not null;default:0"`
+	Error      string          `json:"error,omitempty" gorm:"type:text"`
+	Summary    json.RawMessage `json:"summary,omitempty" gorm:"type:jsonb"` // Result of the job, e.g. the counts of posted or corrected items
+}
+
+// Finish completes the run with the outcome of the job
+func (r *JobRun) Finish(summary json.RawMessage, err error) {
+	finishedAt := time.Now().UTC()
+	r.FinishedAt = &finishedAt
+	r.DurationMs = finishedAt.Sub(r.StartedAt).Milliseconds()
+	r.Summary = summary
+	r.Status = JobRunStatusSucceeded
+	if err != nil {
+		r.Status = JobRunStatusFailed
+		r.Error = err.Error()
+	}
+}
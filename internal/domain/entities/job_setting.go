@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// TableName sets the table name
+func (JobSetting) TableName() string {
+	return "job_settings"
+}
+
+// JobSetting is the schedule of a background job as changed at runtime by an admin. It takes precedence over the
+// configured schedule and is shared by every replica.
+type JobSetting struct {
+	JobName   string    `json:"job_name" gorm:"type:varchar(50);primary_key"`
+	Schedule  string    `json:"schedule" gorm:"type:varchar(100);not null"`
+	Timezone  string    `json:"timezone" gorm:"type:varchar(64);not null"`
+	Enabled   bool      `json:"enabled" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
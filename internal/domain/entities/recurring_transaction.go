@@ -0,0 +1,108 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+	"gorm.io/gorm"
+)
+
+// TableName sets the table name
+func (RecurringTransaction) TableName() string {
+	return "recurring_transactions"
+}
+
+// RecurrenceFrequency represents the RRULE FREQ part of a recurring transaction
+type RecurrenceFrequency string
+
+const (
+	RecurrenceFrequencyDaily   RecurrenceFrequency = "daily"
+	RecurrenceFrequencyWeekly  RecurrenceFrequency = "weekly"
+	RecurrenceFrequencyMonthly RecurrenceFrequency = "monthly"
+	RecurrenceFrequencyYearly  RecurrenceFrequency = "yearly"
+)
+
+// RecurringTransaction is a template that the scheduler materialises into transactions.
+// Occurrences are always computed from StartDate (occurrence n = StartDate + n*Interval*Frequency),
+// so month-end dates don't drift after a short month.
+type RecurringTransaction struct {
+	ID               uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name             string              `json:"name" gorm:"not null"`
+	Cost             money.Money         `json:"cost" gorm:"type:decimal(20,8);not null"`
+	Type             TransactionType     `json:"type" gorm:"type:varchar(20);not null"`
+	Note             string              `json:"note" gorm:"type:text"`
+	TCategory        string              `json:"t_category" gorm:"column:t_category;not null"`
+	UserID           uuid.UUID           `json:"user_id" gorm:"type:uuid;not null;index"`
+	WalletID         uuid.UUID           `json:"wallet_id" gorm:"type:uuid;not null;index"`
+	Frequency        RecurrenceFrequency `json:"frequency" gorm:"type:varchar(20);not null"`
+	Interval         int                 `json:"interval" gorm:"not null;default:1"`
+	StartDate        time.Time           `json:"start_date" gorm:"not null"`
+	EndDate          *time.Time          `json:"end_date"`
+	OccurrenceIndex  int                 `json:"occurrence_index" gorm:"not null;default:0"` // Number of occurrences already materialised
+	NextOccurrenceAt *time.Time          `json:"next_occurrence_at" gorm:"index"`            // Nil once the schedule is exhausted
+	LastOccurrenceAt *time.Time          `json:"last_occurrence_at"`
+	IsActive         bool                `json:"is_active" gorm:"not null;default:true"`
+	IsDeleted        bool                `json:"is_deleted" gorm:"column:is_deleted;default:false;index"`
+	CreatedAt        time.Time           `json:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt      `json:"deleted_at,omitempty" gorm:"index"`
+
+	// Relationships
+	// Belongs to User
+	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	// Belongs to Wallet
+	Wallet Wallet `json:"wallet,omitempty" gorm:"foreignKey:WalletID"`
+}
+
+// IsSoftDeleted checks if recurring transaction is soft deleted (either by boolean flag or DeletedAt timestamp)
+func (r *RecurringTransaction) IsSoftDeleted() bool {
+	return r.IsDeleted || r.DeletedAt.Valid
+}
+
+// OccurrenceAt returns the date of the n-th occurrence (0-based) anchored on StartDate.
+// Monthly and yearly rules clamp to the last day of the month, e.g. Jan 31 -> Feb 28 -> Mar 31.
+func (r *RecurringTransaction) OccurrenceAt(n int) time.Time {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	steps := n * interval
+
+	switch r.Frequency {
+	case RecurrenceFrequencyDaily:
+		return r.StartDate.AddDate(0, 0, steps)
+	case RecurrenceFrequencyWeekly:
+		return r.StartDate.AddDate(0, 0, 7*steps)
+	case RecurrenceFrequencyYearly:
+		return addMonthsClamped(r.StartDate, 12*steps)
+	default:
+		return addMonthsClamped(r.StartDate, steps)
+	}
+}
+
+// ScheduleNext sets NextOccurrenceAt to the occurrence at OccurrenceIndex, or nil when it is past EndDate
+func (r *RecurringTransaction) ScheduleNext() {
+	next := r.OccurrenceAt(r.OccurrenceIndex)
+	if r.EndDate != nil && next.After(*r.EndDate) {
+		r.NextOccurrenceAt = nil
+		return
+	}
+	r.NextOccurrenceAt = &next
+}
+
+// IsDue checks if the next occurrence should be materialised at the given time
+func (r *RecurringTransaction) IsDue(now time.Time) bool {
+	return r.IsActive && !r.IsSoftDeleted() && r.NextOccurrenceAt != nil && !r.NextOccurrenceAt.After(now)
+}
+
+// addMonthsClamped adds months to t, clamping the day to the last day of the resulting month
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	firstOfTarget := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(firstOfTarget.Year(), firstOfTarget.Month(), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
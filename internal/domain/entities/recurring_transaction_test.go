@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+}
+
+func TestRecurringTransaction_OccurrenceAt(t *testing.T) {
+	tests := []struct {
+		name      string
+		frequency RecurrenceFrequency
+		interval  int
+		start     time.Time
+		n         int
+		want      time.Time
+	}{
+		{"daily", RecurrenceFrequencyDaily, 1, date(2024, 2, 28), 2, date(2024, 3, 1)},
+		{"every 3 days", RecurrenceFrequencyDaily, 3, date(2024, 1, 30), 1, date(2024, 2, 2)},
+		{"weekly", RecurrenceFrequencyWeekly, 1, date(2024, 12, 30), 1, date(2025, 1, 6)},
+		{"biweekly", RecurrenceFrequencyWeekly, 2, date(2024, 1, 1), 3, date(2024, 2, 12)},
+		{"monthly first occurrence is the start", RecurrenceFrequencyMonthly, 1, date(2024, 1, 31), 0, date(2024, 1, 31)},
+		{"monthly month end in a leap year", RecurrenceFrequencyMonthly, 1, date(2024, 1, 31), 1, date(2024, 2, 29)},
+		{"monthly month end in a common year", RecurrenceFrequencyMonthly, 1, date(2023, 1, 31), 1, date(2023, 2, 28)},
+		{"monthly month end does not drift after a short month", RecurrenceFrequencyMonthly, 1, date(2023, 1, 31), 2, date(2023, 3, 31)},
+		{"monthly 31st in a 30-day month", RecurrenceFrequencyMonthly, 1, date(2024, 3, 31), 1, date(2024, 4, 30)},
+		{"monthly across a year end", RecurrenceFrequencyMonthly, 1, date(2024, 11, 30), 2, date(2025, 1, 30)},
+		{"every 2 months", RecurrenceFrequencyMonthly, 2, date(2024, 12, 31), 1, date(2025, 2, 28)},
+		{"quarterly", RecurrenceFrequencyMonthly, 3, date(2024, 5, 31), 3, date(2025, 2, 28)},
+		{"zero interval counts as one", RecurrenceFrequencyMonthly, 0, date(2024, 1, 15), 1, date(2024, 2, 15)},
+		{"yearly from a leap day", RecurrenceFrequencyYearly, 1, date(2024, 2, 29), 1, date(2025, 2, 28)},
+		{"yearly from a leap day back on a leap year", RecurrenceFrequencyYearly, 1, date(2024, 2, 29), 4, date(2028, 2, 29)},
+		{"every 2 years", RecurrenceFrequencyYearly, 2, date(2023, 6, 15), 2, date(2027, 6, 15)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &RecurringTransaction{Frequency: tt.frequency, Interval: tt.interval, StartDate: tt.start}
+
+			assert.Equal(t, tt.want, rule.OccurrenceAt(tt.n))
+		})
+	}
+}
+
+func TestRecurringTransaction_ScheduleNextStopsAfterEndDate(t *testing.T) {
+	endDate := date(2024, 3, 31)
+	rule := &RecurringTransaction{
+		Frequency: RecurrenceFrequencyMonthly,
+		Interval:  1,
+		StartDate: date(2024, 1, 31),
+		EndDate:   &endDate,
+	}
+
+	rule.OccurrenceIndex = 2
+	rule.ScheduleNext()
+	assert.Equal(t, date(2024, 3, 31), *rule.NextOccurrenceAt)
+
+	rule.OccurrenceIndex = 3
+	rule.ScheduleNext()
+	assert.Nil(t, rule.NextOccurrenceAt)
+}
@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TableName sets the table name
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// Tag is a free-form label of a user, such as "trip-bali-2026" or "reimbursable", that cuts across categories.
+// A transaction can carry many tags and a tag many transactions, linked through the transaction_tags table.
+type Tag struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_tags_user_name,priority:1"`
+	Name      string    `json:"name" gorm:"type:varchar(50);not null;uniqueIndex:idx_tags_user_name,priority:2"` // Normalized with NormalizeTagName
+	Color     string    `json:"color" gorm:"type:varchar(7)"`                                                    // Hex color such as #0EA5E9
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NormalizeTagName lowercases a tag name and joins its words with dashes, so "Trip Bali 2026" and
+// "trip-bali-2026" are the same tag
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), "-"))
+}
+
+// NormalizeTagNames normalizes tag names, dropping empty and repeated ones while keeping their order
+func NormalizeTagNames(names []string) []string {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = NormalizeTagName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
 	"gorm.io/gorm"
 )
 
@@ -21,24 +22,53 @@ const (
 )
 
 type Transaction struct {
-	ID        uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Name      string          `json:"name" gorm:"not null"`
-	Cost      float64         `json:"cost" gorm:"type:decimal(20,8);not null"`
-	Type      TransactionType `json:"type" gorm:"type:varchar(20);not null"`
-	Note      string          `json:"note" gorm:"type:text"`
-	TCategory string          `json:"t_category" gorm:"column:t_category;not null"`
-	UserID    uuid.UUID       `json:"user_id" gorm:"type:uuid;not null;index"`
-	WalletID  uuid.UUID       `json:"wallet_id" gorm:"type:uuid;not null;index"`
-	IsDeleted bool            `json:"is_deleted" gorm:"column:is_deleted;default:false;index"`
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
-	DeletedAt gorm.DeletedAt  `json:"deleted_at,omitempty" gorm:"index"`
+	ID         uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name       string          `json:"name" gorm:"not null"`
+	Cost       money.Money     `json:"cost" gorm:"type:decimal(20,8);not null"`
+	Type       TransactionType `json:"type" gorm:"type:varchar(20);not null"`
+	Note       string          `json:"note" gorm:"type:text"`
+	TCategory  string          `json:"t_category" gorm:"column:t_category;not null"` // Name of the category, kept in sync for reporting and exports
+	CategoryID *uuid.UUID      `json:"category_id" gorm:"type:uuid;index"`           // Nil only for transfer legs
+	UserID     uuid.UUID       `json:"user_id" gorm:"type:uuid;not null;index"`
+	WalletID   uuid.UUID       `json:"wallet_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_transactions_wallet_external_id,priority:1"`
+	OccurredAt time.Time       `json:"occurred_at" gorm:"not null;default:CURRENT_TIMESTAMP;index"` // When the transaction happened, used for reporting and date filters
+	TransferID *uuid.UUID      `json:"transfer_id,omitempty" gorm:"type:uuid;index"`
+	// Set when materialised from a recurring template; unique together so an occurrence is never posted twice
+	RecurringTransactionID *uuid.UUID `json:"recurring_transaction_id,omitempty" gorm:"type:uuid;uniqueIndex:idx_transactions_recurring_occurrence"`
+	OccurrenceDate         *time.Time `json:"occurrence_date,omitempty" gorm:"uniqueIndex:idx_transactions_recurring_occurrence"`
+	// Bank identifier of an imported statement entry (OFX FITID or content fingerprint); unique per wallet so re-imports skip it
+	ExternalID *string        `json:"external_id,omitempty" gorm:"type:varchar(255);uniqueIndex:idx_transactions_wallet_external_id,priority:2"`
+	Version    int64          `json:"version" gorm:"not null;default:1"` // Bumped on every change, exposed as the ETag
+	IsDeleted  bool           `json:"is_deleted" gorm:"column:is_deleted;default:false;index"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 
 	// Relationships
 	// Belongs to User
 	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
 	// Belongs to Wallet
 	Wallet Wallet `json:"wallet,omitempty" gorm:"foreignKey:WalletID"`
+	// Has many split lines, largest first; empty for a transaction with a single category
+	Splits []TransactionSplit `json:"splits,omitempty" gorm:"foreignKey:TransactionID;constraint:OnDelete:CASCADE"`
+	// Many to many tags through transaction_tags
+	Tags []Tag `json:"tags,omitempty" gorm:"many2many:transaction_tags;constraint:OnDelete:CASCADE"`
+}
+
+// MigrateTransactionOccurredAt adds the occurred_at column and backfills it from created_at.
+// It must run before AutoMigrate, otherwise the column would be added with the migration time as default.
+func MigrateTransactionOccurredAt(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&Transaction{}) || migrator.HasColumn(&Transaction{}, "OccurredAt") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`ALTER TABLE transactions ADD COLUMN occurred_at timestamptz`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`UPDATE transactions SET occurred_at = created_at WHERE occurred_at IS NULL`).Error
+	})
 }
 
 // IsSoftDeleted checks if transaction is soft deleted (either by boolean flag or DeletedAt timestamp)
@@ -68,21 +98,28 @@ func (t *Transaction) Restore() {
 	}
 }
 
+// IsTransferLeg checks if transaction is one side of a wallet-to-wallet transfer
+func (t *Transaction) IsTransferLeg() bool {
+	return t.TransferID != nil
+}
+
+// IsSplit checks if the transaction is divided across several categories
+func (t *Transaction) IsSplit() bool {
+	return len(t.Splits) > 0
+}
+
 // GetAbsoluteCost returns the absolute value of the transaction cost
-func (t *Transaction) GetAbsoluteCost() float64 {
-	if t.Cost < 0 {
-		return -t.Cost
-	}
-	return t.Cost
+func (t *Transaction) GetAbsoluteCost() money.Money {
+	return t.Cost.Abs()
 }
 
 // GetWalletImpact returns the amount that should be added to wallet balance
 // For income: positive cost adds to balance
 // For expense: positive cost subtracts from balance (returns negative value)
-func (t *Transaction) GetWalletImpact() float64 {
+func (t *Transaction) GetWalletImpact() money.Money {
 	if t.Type == TransactionTypeIncome {
 		return t.GetAbsoluteCost() // Always add positive amount for income
 	} else {
-		return -t.GetAbsoluteCost() // Always subtract positive amount for expense
+		return t.GetAbsoluteCost().Neg() // Always subtract positive amount for expense
 	}
 }
@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TableName sets the table name
+func (TransactionAttachment) TableName() string {
+	return "transaction_attachments"
+}
+
+// TransactionAttachment is a receipt or other file attached to a transaction. The file itself lives in the
+// private MinIO bucket under Path and is only ever handed out through short-lived signed URLs.
+type TransactionAttachment struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TransactionID uuid.UUID `json:"transaction_id" gorm:"type:uuid;not null;index"`
+	UserID        uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	FileName      string    `json:"file_name" gorm:"type:varchar(255);not null"` // Original name of the uploaded file
+	Path          string    `json:"-" gorm:"type:varchar(500);not null"`         // Object path in the private bucket
+	ContentType   string    `json:"content_type" gorm:"type:varchar(100);not null"`
+	Size          int64     `json:"size" gorm:"not null"`
+	CreatedAt     time.Time `json:"created_at"`
+}
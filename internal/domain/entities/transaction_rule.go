@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+	"gorm.io/gorm"
+)
+
+// TableName sets the table name
+func (TransactionRule) TableName() string {
+	return "transaction_rules"
+}
+
+// TransactionRule categorises the transactions of a user automatically. Every condition that is set must match;
+// the actions then set the category, add tags and set the note. Active rules run in ascending priority order.
+type TransactionRule struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID   uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name     string    `json:"name" gorm:"type:varchar(255);not null"`
+	Priority int       `json:"priority" gorm:"not null;default:0;index"` // Lower runs first
+	IsActive bool      `json:"is_active" gorm:"not null;default:true"`
+	// Conditions, patterns are case-insensitive regular expressions
+	NamePattern string          `json:"name_pattern" gorm:"type:varchar(255)"`
+	NotePattern string          `json:"note_pattern" gorm:"type:varchar(255)"`
+	MinAmount   *money.Money    `json:"min_amount" gorm:"type:decimal(20,8)"` // Inclusive, compared with the absolute cost
+	MaxAmount   *money.Money    `json:"max_amount" gorm:"type:decimal(20,8)"` // Inclusive, compared with the absolute cost
+	WalletID    *uuid.UUID      `json:"wallet_id" gorm:"type:uuid"`
+	Type        TransactionType `json:"type" gorm:"type:varchar(20)"` // Empty matches income and expense
+	// Actions
+	CategoryID     *uuid.UUID     `json:"category_id" gorm:"type:uuid"`
+	Tags           string         `json:"tags" gorm:"type:varchar(1100)"` // Comma separated normalized tag names
+	Note           string         `json:"note" gorm:"type:text"`
+	StopProcessing bool           `json:"stop_processing" gorm:"not null;default:false"` // Skip the rules after this one when it matches
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	// Relationships
+	// Optionally belongs to Category
+	Category *Category `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+}
+
+// GetTags splits Tags into tag names
+func (r *TransactionRule) GetTags() []string {
+	if r.Tags == "" {
+		return nil
+	}
+	return strings.Split(r.Tags, ",")
+}
+
+// SetTags normalizes the tag names and stores them as a comma separated list
+func (r *TransactionRule) SetTags(names []string) {
+	r.Tags = strings.Join(NormalizeTagNames(names), ",")
+}
+
+// HasActions checks if the rule changes anything when it matches
+func (r *TransactionRule) HasActions() bool {
+	return r.CategoryID != nil || r.Tags != "" || r.Note != ""
+}
@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+)
+
+// TableName sets the table name
+func (TransactionSplit) TableName() string {
+	return "transaction_splits"
+}
+
+// TransactionSplit is one line of a transaction divided across several categories, e.g. the groceries
+// and the household items of one supermarket receipt. The amounts of the splits add up to the parent cost,
+// which alone drives the wallet balance; category filters and budgets count the split amounts instead.
+type TransactionSplit struct {
+	ID            uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TransactionID uuid.UUID   `json:"transaction_id" gorm:"type:uuid;not null;index"`
+	CategoryID    *uuid.UUID  `json:"category_id" gorm:"type:uuid;index"`
+	TCategory     string      `json:"t_category" gorm:"column:t_category;not null"` // Name of the category, kept in sync like Transaction.TCategory
+	Amount        money.Money `json:"amount" gorm:"type:decimal(20,8);not null"`
+	Note          string      `json:"note" gorm:"type:text"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
@@ -0,0 +1,68 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+	"gorm.io/gorm"
+)
+
+// TransferCategory is the t_category assigned to both legs of a transfer
+const TransferCategory = "transfer"
+
+// TableName sets the table name
+func (Transfer) TableName() string {
+	return "transfers"
+}
+
+// Transfer moves money between two wallets of the same user.
+// Each transfer owns two linked transactions (an outgoing expense leg and an incoming income leg)
+// so wallet balances stay derivable from transactions while reporting can exclude them.
+// Amount is in the source wallet currency and ReceivedAmount in the destination wallet currency.
+type Transfer struct {
+	ID                    uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Amount                money.Money    `json:"amount" gorm:"type:decimal(20,8);not null"`
+	ReceivedAmount        money.Money    `json:"received_amount" gorm:"type:decimal(20,8);not null;default:0"`
+	Note                  string         `json:"note" gorm:"type:text"`
+	UserID                uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`
+	FromWalletID          uuid.UUID      `json:"from_wallet_id" gorm:"type:uuid;not null;index"`
+	ToWalletID            uuid.UUID      `json:"to_wallet_id" gorm:"type:uuid;not null;index"`
+	OutgoingTransactionID *uuid.UUID     `json:"outgoing_transaction_id" gorm:"type:uuid"`
+	IncomingTransactionID *uuid.UUID     `json:"incoming_transaction_id" gorm:"type:uuid"`
+	IsDeleted             bool           `json:"is_deleted" gorm:"column:is_deleted;default:false;index"`
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
+	DeletedAt             gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	// Relationships
+	// Belongs to User
+	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	// Belongs to source and destination Wallet
+	FromWallet Wallet `json:"from_wallet,omitempty" gorm:"foreignKey:FromWalletID"`
+	ToWallet   Wallet `json:"to_wallet,omitempty" gorm:"foreignKey:ToWalletID"`
+}
+
+// IsSoftDeleted checks if transfer is soft deleted (either by boolean flag or DeletedAt timestamp)
+func (t *Transfer) IsSoftDeleted() bool {
+	return t.IsDeleted || t.DeletedAt.Valid
+}
+
+// IsActive checks if transfer is not soft deleted (neither boolean flag nor DeletedAt timestamp)
+func (t *Transfer) IsActive() bool {
+	return !t.IsDeleted && !t.DeletedAt.Valid
+}
+
+// GetAbsoluteAmount returns the absolute value of the transfer amount
+func (t *Transfer) GetAbsoluteAmount() money.Money {
+	return t.Amount.Abs()
+}
+
+// GetReceivedAmount returns the absolute amount credited to the destination wallet.
+// Transfers created before ReceivedAmount existed have it unset and credited Amount.
+func (t *Transfer) GetReceivedAmount() money.Money {
+	if t.ReceivedAmount.IsZero() {
+		return t.GetAbsoluteAmount()
+	}
+	return t.ReceivedAmount.Abs()
+}
@@ -251,9 +251,8 @@ func (u *User) AfterFind(tx *gorm.DB) error {
 
 // AfterCreate hook - runs after record is created
 func (u *User) AfterCreate(tx *gorm.DB) error {
-	// Add any post-create logic here
-	// For example: send welcome email, create related records, etc.
-	return nil
+	// Seed the default categories within the same database transaction as the user
+	return tx.Session(&gorm.Session{NewDB: true}).Create(DefaultCategoriesFor(u.ID)).Error
 }
 
 // AfterUpdate hook - runs after record is updated
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
 	"gorm.io/gorm"
 )
 
@@ -17,9 +18,10 @@ type Wallet struct {
 	Name      string         `json:"name" gorm:"not null"`
 	Type      string         `json:"type" gorm:"not null"`
 	Category  string         `json:"category" gorm:"not null"`
-	Balance   float64        `json:"balance" gorm:"type:decimal(20,8);default:0"`
+	Balance   money.Money    `json:"balance" gorm:"type:decimal(20,8);default:0"`
 	Currency  string         `json:"currency" gorm:"not null;default:'IDR'"`
 	UserID    uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`
+	Version   int64          `json:"version" gorm:"not null;default:1"` // Bumped on every change, exposed as the ETag
 	IsDeleted bool           `json:"is_deleted" gorm:"column:is_deleted;default:false;index"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
@@ -0,0 +1,52 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+)
+
+// TableName sets the table name
+func (WalletBalanceEntry) TableName() string {
+	return "wallet_balance_entries"
+}
+
+// BalanceChangeCause is what changed a wallet balance
+type BalanceChangeCause string
+
+const (
+	BalanceCauseWalletCreated      BalanceChangeCause = "wallet_created"
+	BalanceCauseWalletUpdated      BalanceChangeCause = "wallet_updated"
+	BalanceCauseTransactionCreated BalanceChangeCause = "transaction_created"
+	BalanceCauseTransactionUpdated BalanceChangeCause = "transaction_updated"
+	BalanceCauseTransactionDeleted BalanceChangeCause = "transaction_deleted"
+	BalanceCauseTransferCreated    BalanceChangeCause = "transfer_created"
+	BalanceCauseTransferUpdated    BalanceChangeCause = "transfer_updated"
+	BalanceCauseTransferDeleted    BalanceChangeCause = "transfer_deleted"
+	BalanceCauseDuplicateMerged    BalanceChangeCause = "duplicate_merged"
+	BalanceCauseBalanceSync        BalanceChangeCause = "balance_sync"
+)
+
+// WalletBalanceEntry is one change of a wallet balance. Entries are append-only: they are written in the same
+// database transaction as the change and never updated or deleted, so they keep the balance history of a wallet.
+type WalletBalanceEntry struct {
+	ID            uuid.UUID          `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	WalletID      uuid.UUID          `json:"wallet_id" gorm:"type:uuid;not null;index:idx_wallet_balance_entries_wallet_created,priority:1"`
+	TransactionID *uuid.UUID         `json:"transaction_id,omitempty" gorm:"type:uuid;index"` // The transaction or transfer leg behind the change, if any
+	Cause         BalanceChangeCause `json:"cause" gorm:"type:varchar(30);not null"`
+	Amount        money.Money        `json:"amount" gorm:"type:decimal(20,8);not null"` // BalanceAfter - BalanceBefore
+	BalanceBefore money.Money        `json:"balance_before" gorm:"type:decimal(20,8);not null"`
+	BalanceAfter  money.Money        `json:"balance_after" gorm:"type:decimal(20,8);not null"`
+	CreatedAt     time.Time          `json:"created_at" gorm:"not null;index:idx_wallet_balance_entries_wallet_created,priority:2"`
+}
+
+// NewBalanceChange describes a change of amount to a wallet balance, the balances are filled in when it is applied
+func NewBalanceChange(walletID uuid.UUID, amount money.Money, cause BalanceChangeCause, transactionID *uuid.UUID) *WalletBalanceEntry {
+	return &WalletBalanceEntry{
+		WalletID:      walletID,
+		TransactionID: transactionID,
+		Cause:         cause,
+		Amount:        amount,
+	}
+}
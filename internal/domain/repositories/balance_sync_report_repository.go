@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BalanceSyncReportRepository interface {
+	Create(ctx context.Context, report *entities.BalanceSyncReport) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.BalanceSyncReport, error)
+	GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.BalanceSyncReport, error)
+	Count(ctx context.Context) (int64, error)
+}
+
+type balanceSyncReportRepository struct {
+	db *gorm.DB
+}
+
+func NewBalanceSyncReportRepository(db *gorm.DB) BalanceSyncReportRepository {
+	return &balanceSyncReportRepository{db: db}
+}
+
+// Create stores the report together with its entries
+func (r *balanceSyncReportRepository) Create(ctx context.Context, report *entities.BalanceSyncReport) error {
+	return r.db.WithContext(ctx).Create(report).Error
+}
+
+// GetByID returns the report with its entries, largest corrections first
+func (r *balanceSyncReportRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.BalanceSyncReport, error) {
+	var report entities.BalanceSyncReport
+	err := r.db.WithContext(ctx).
+		Preload("Entries", func(db *gorm.DB) *gorm.DB {
+			return db.Order("ABS(difference) DESC")
+		}).
+		First(&report, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetAll returns the reports without their entries, newest first
+func (r *balanceSyncReportRepository) GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.BalanceSyncReport, error) {
+	var reports []*entities.BalanceSyncReport
+	query := r.db.WithContext(ctx).Order("started_at DESC")
+
+	// Apply pagination
+	if queryParams.Limit > 0 {
+		query = query.Limit(queryParams.Limit)
+	}
+	if queryParams.GetOffset() > 0 {
+		query = query.Offset(queryParams.GetOffset())
+	}
+
+	if err := query.Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+func (r *balanceSyncReportRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.BalanceSyncReport{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BudgetAlertRepository interface {
+	CreateIfAbsent(ctx context.Context, alert *entities.BudgetAlert) (bool, error)
+	GetSentThresholds(ctx context.Context, budgetID uuid.UUID, periodStart time.Time) ([]int, error)
+	GetPendingEmail(ctx context.Context, budgetID uuid.UUID, periodStart time.Time) (*entities.BudgetAlert, error)
+	ClearPendingEmails(ctx context.Context, budgetID uuid.UUID, periodStart time.Time) error
+	MarkEmailed(ctx context.Context, id uuid.UUID, emailedAt time.Time) error
+}
+
+type budgetAlertRepository struct {
+	db *gorm.DB
+}
+
+func NewBudgetAlertRepository(db *gorm.DB) BudgetAlertRepository {
+	return &budgetAlertRepository{db: db}
+}
+
+// CreateIfAbsent records the alert and reports false when it was already recorded for the period
+func (r *budgetAlertRepository) CreateIfAbsent(ctx context.Context, alert *entities.BudgetAlert) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(alert)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetSentThresholds returns the thresholds already alerted for a budget period
+func (r *budgetAlertRepository) GetSentThresholds(ctx context.Context, budgetID uuid.UUID, periodStart time.Time) ([]int, error) {
+	var thresholds []int
+	if err := r.db.WithContext(ctx).Model(&entities.BudgetAlert{}).
+		Where("budget_id = ? AND period_start = ?", budgetID, periodStart).
+		Pluck("threshold", &thresholds).Error; err != nil {
+		return nil, err
+	}
+	return thresholds, nil
+}
+
+// GetPendingEmail returns the alert of a budget period whose email was not sent yet, nil when there is none
+func (r *budgetAlertRepository) GetPendingEmail(ctx context.Context, budgetID uuid.UUID, periodStart time.Time) (*entities.BudgetAlert, error) {
+	var alerts []*entities.BudgetAlert
+	if err := r.db.WithContext(ctx).
+		Where("budget_id = ? AND period_start = ? AND email_pending = ?", budgetID, periodStart, true).
+		Order("threshold DESC").
+		Limit(1).
+		Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+	return alerts[0], nil
+}
+
+// ClearPendingEmails drops the emails still owed for a budget period, used when a higher threshold supersedes them
+func (r *budgetAlertRepository) ClearPendingEmails(ctx context.Context, budgetID uuid.UUID, periodStart time.Time) error {
+	return r.db.WithContext(ctx).Model(&entities.BudgetAlert{}).
+		Where("budget_id = ? AND period_start = ? AND email_pending = ?", budgetID, periodStart, true).
+		Update("email_pending", false).Error
+}
+
+// MarkEmailed records that the email of an alert was sent
+func (r *budgetAlertRepository) MarkEmailed(ctx context.Context, id uuid.UUID, emailedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&entities.BudgetAlert{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"email_pending": false, "emailed_at": emailedAt}).Error
+}
@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BudgetRepository interface {
+	Create(ctx context.Context, budget *entities.Budget) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Budget, error)
+	GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.Budget, error)
+	Update(ctx context.Context, budget *entities.Budget) error
+	CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error)
+	SoftDelete(ctx context.Context, id uuid.UUID) error
+}
+
+type budgetRepository struct {
+	db *gorm.DB
+}
+
+func NewBudgetRepository(db *gorm.DB) BudgetRepository {
+	return &budgetRepository{db: db}
+}
+
+func (r *budgetRepository) Create(ctx context.Context, budget *entities.Budget) error {
+	if err := r.db.WithContext(ctx).Create(budget).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *budgetRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Budget, error) {
+	var budget entities.Budget
+	if err := r.db.Preload("Wallet").WithContext(ctx).First(&budget, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("budget not found")
+		}
+		return nil, err
+	}
+	return &budget, nil
+}
+
+func (r *budgetRepository) GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.Budget, error) {
+	var budgets []*entities.Budget
+	query := r.applyFilters(r.db.WithContext(ctx), queryParams)
+
+	// Apply sorting
+	if queryParams.HasSort() {
+		// Only allow safe column names for sorting
+		allowedSortColumns := map[string]bool{
+			"name":       true,
+			"amount":     true,
+			"t_category": true,
+			"created_at": true,
+			"updated_at": true,
+		}
+
+		if allowedSortColumns[queryParams.SortBy] {
+			orderClause := queryParams.SortBy + " " + queryParams.SortType
+			query = query.Order(orderClause)
+		}
+	} else {
+		// Default sorting
+		query = query.Order("created_at DESC")
+	}
+
+	// Apply pagination
+	if queryParams.Limit > 0 {
+		query = query.Limit(queryParams.Limit)
+	}
+	if queryParams.GetOffset() > 0 {
+		query = query.Offset(queryParams.GetOffset())
+	}
+
+	if err := query.Preload("Wallet").Find(&budgets).Error; err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+func (r *budgetRepository) Update(ctx context.Context, budget *entities.Budget) error {
+	if err := r.db.WithContext(ctx).Save(budget).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *budgetRepository) CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error) {
+	var count int64
+	query := r.applyFilters(r.db.WithContext(ctx).Model(&entities.Budget{}), queryParams)
+
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// SoftDelete soft deletes a budget by ID
+func (r *budgetRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&entities.Budget{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"is_deleted": true,
+			"deleted_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("budget not found")
+	}
+
+	return nil
+}
+
+// applyFilters applies the user scope, search and custom filters shared by GetAll and CountWithFilters
+func (r *budgetRepository) applyFilters(query *gorm.DB, queryParams *dto.QueryParams) *gorm.DB {
+	if queryParams.LoggedUserID != uuid.Nil {
+		query = query.Where("user_id = ?", queryParams.LoggedUserID)
+	}
+
+	// Apply search if provided
+	if queryParams.HasSearch() {
+		searchTerm := "%" + queryParams.Search + "%"
+		query = query.Where("name ILIKE ? OR t_category ILIKE ?", searchTerm, searchTerm)
+	}
+
+	// Apply custom filters
+	if queryParams.HasFilters() {
+		for key, value := range queryParams.Filters {
+			// Only allow safe column names to prevent SQL injection
+			switch key {
+			case "t_category":
+				query = query.Where("LOWER(t_category) = LOWER(?)", value)
+			case "period", "wallet_id", "user_id", "rollover", "alerts_enabled":
+				query = query.Where(key+" = ?", value)
+			case "amount_min":
+				query = query.Where("amount >= ?", value)
+			case "amount_max":
+				query = query.Where("amount <= ?", value)
+			}
+		}
+	}
+
+	return query
+}
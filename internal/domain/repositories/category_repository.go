@@ -0,0 +1,255 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// categorySubtreeQuery selects the ID of a category and of all its descendants
+const categorySubtreeQuery = `WITH RECURSIVE subtree AS (
+  SELECT id FROM categories WHERE id = ? AND deleted_at IS NULL
+  UNION ALL
+  SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id WHERE c.deleted_at IS NULL
+) SELECT id FROM subtree`
+
+type CategoryRepository interface {
+	Create(ctx context.Context, category *entities.Category) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Category, error)
+	GetByName(ctx context.Context, userID uuid.UUID, categoryType entities.TransactionType, name string) (*entities.Category, error)
+	GetSibling(ctx context.Context, userID uuid.UUID, categoryType entities.TransactionType, parentID *uuid.UUID, name string) (*entities.Category, error)
+	CreateIfNotExists(ctx context.Context, category *entities.Category) (bool, error)
+	GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.Category, error)
+	Update(ctx context.Context, category *entities.Category) error
+	CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error)
+	SoftDelete(ctx context.Context, id uuid.UUID) error
+	GetSubtreeIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	CountChildren(ctx context.Context, id uuid.UUID) (int64, error)
+	CountTransactions(ctx context.Context, id uuid.UUID) (int64, error)
+	RenameTransactions(ctx context.Context, id uuid.UUID, name string) error
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) Create(ctx context.Context, category *entities.Category) error {
+	if err := r.db.WithContext(ctx).Create(category).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *categoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Category, error) {
+	var category entities.Category
+	if err := r.db.WithContext(ctx).First(&category, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetByName finds a category of a user by name, case-insensitively, preferring top-level categories.
+// It returns gorm.ErrRecordNotFound when no category has that name.
+func (r *categoryRepository) GetByName(ctx context.Context, userID uuid.UUID, categoryType entities.TransactionType, name string) (*entities.Category, error) {
+	var category entities.Category
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND type = ? AND LOWER(name) = LOWER(?)", userID, categoryType, strings.TrimSpace(name)).
+		Order("parent_id NULLS FIRST").
+		Order("created_at").
+		First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetSibling finds a category by name, case-insensitively, among the children of parentID (nil for top level).
+// It returns gorm.ErrRecordNotFound when no sibling has that name.
+func (r *categoryRepository) GetSibling(ctx context.Context, userID uuid.UUID, categoryType entities.TransactionType, parentID *uuid.UUID, name string) (*entities.Category, error) {
+	var category entities.Category
+	query := r.db.WithContext(ctx).
+		Where("user_id = ? AND type = ? AND LOWER(name) = LOWER(?)", userID, categoryType, strings.TrimSpace(name))
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+
+	if err := query.First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// CreateIfNotExists creates a category unless a sibling with the same name exists, as enforced by
+// idx_categories_unique_name, and reports whether it was created
+func (r *categoryRepository) CreateIfNotExists(ctx context.Context, category *entities.Category) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(category)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *categoryRepository) GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.Category, error) {
+	var categories []*entities.Category
+	query := r.applyFilters(r.db.WithContext(ctx), queryParams)
+
+	// Apply sorting
+	if queryParams.HasSort() {
+		// Only allow safe column names for sorting
+		allowedSortColumns := map[string]bool{
+			"name":       true,
+			"type":       true,
+			"created_at": true,
+			"updated_at": true,
+		}
+
+		if allowedSortColumns[queryParams.SortBy] {
+			orderClause := queryParams.SortBy + " " + queryParams.SortType
+			query = query.Order(orderClause)
+		}
+	} else {
+		// Default sorting
+		query = query.Order("type").Order("name")
+	}
+
+	// Apply pagination
+	if queryParams.Limit > 0 {
+		query = query.Limit(queryParams.Limit)
+	}
+	if queryParams.GetOffset() > 0 {
+		query = query.Offset(queryParams.GetOffset())
+	}
+
+	if err := query.Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) Update(ctx context.Context, category *entities.Category) error {
+	if err := r.db.WithContext(ctx).Save(category).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *categoryRepository) CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error) {
+	var count int64
+	query := r.applyFilters(r.db.WithContext(ctx).Model(&entities.Category{}), queryParams)
+
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// SoftDelete soft deletes a category by ID
+func (r *categoryRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&entities.Category{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"is_deleted": true,
+			"deleted_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("category not found")
+	}
+
+	return nil
+}
+
+// GetSubtreeIDs returns the ID of a category followed by the IDs of all its descendants
+func (r *categoryRepository) GetSubtreeIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).Raw(categorySubtreeQuery, id).Scan(&ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// CountChildren counts the direct subcategories of a category
+func (r *categoryRepository) CountChildren(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.Category{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountTransactions counts the transactions assigned to a category, directly or through a split line
+func (r *categoryRepository) CountTransactions(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.Transaction{}).
+		Where("category_id = ? OR EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = transactions.id AND s.category_id = ?)", id, id).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// RenameTransactions copies a new category name onto the t_category of its transactions and split lines
+func (r *categoryRepository) RenameTransactions(ctx context.Context, id uuid.UUID, name string) error {
+	if err := r.db.WithContext(ctx).Model(&entities.Transaction{}).
+		Where("category_id = ?", id).
+		Update("t_category", name).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Model(&entities.TransactionSplit{}).
+		Where("category_id = ?", id).
+		Update("t_category", name).Error
+}
+
+// applyFilters applies the user scope, search and custom filters shared by GetAll and CountWithFilters
+func (r *categoryRepository) applyFilters(query *gorm.DB, queryParams *dto.QueryParams) *gorm.DB {
+	if queryParams.LoggedUserID != uuid.Nil {
+		query = query.Where("user_id = ?", queryParams.LoggedUserID)
+	}
+
+	// Apply search if provided
+	if queryParams.HasSearch() {
+		searchTerm := "%" + queryParams.Search + "%"
+		query = query.Where("name ILIKE ?", searchTerm)
+	}
+
+	// Apply custom filters
+	if queryParams.HasFilters() {
+		for key, value := range queryParams.Filters {
+			// Only allow safe column names to prevent SQL injection
+			switch key {
+			case "type", "user_id", "is_archived":
+				query = query.Where(key+" = ?", value)
+			case "parent_id":
+				if value == "null" {
+					query = query.Where("parent_id IS NULL")
+				} else {
+					query = query.Where("parent_id = ?", value)
+				}
+			case "subtree_of":
+				query = query.Where("id IN ("+categorySubtreeQuery+")", value)
+			}
+		}
+	}
+
+	return query
+}
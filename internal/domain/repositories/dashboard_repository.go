@@ -3,14 +3,41 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
 	"gorm.io/gorm"
 )
 
 type DashboardRepository interface {
 	GetMonthlySumByUser(ctx context.Context, userID uuid.UUID) ([]*entities.VMonthlyTransactionSum, error)
+	GetDailySumByUser(ctx context.Context, userID uuid.UUID) ([]*DailyWalletSum, error)
+	GetDailyTagSumByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*DailyTagSum, error)
+	GetWalletsByUser(ctx context.Context, userID uuid.UUID) ([]*entities.Wallet, error)
+}
+
+// DailyWalletSum is the per wallet and day breakdown of v_monthly_transaction_sum,
+// fine grained enough to apply the exchange rate of each transaction date
+type DailyWalletSum struct {
+	WalletID         uuid.UUID
+	Currency         string
+	Day              time.Time
+	Month            string
+	TransactionCount int64
+	TotalCost        money.Money
+}
+
+// DailyTagSum is the per tag, type and day total of a user's transactions in one wallet currency
+type DailyTagSum struct {
+	TagID            uuid.UUID
+	Name             string
+	Currency         string
+	Day              time.Time
+	Type             entities.TransactionType
+	TransactionCount int64
+	TotalCost        money.Money
 }
 
 type dashboardRepository struct {
@@ -32,3 +59,56 @@ func (r *dashboardRepository) GetMonthlySumByUser(ctx context.Context, userID uu
 	}
 	return summaries, nil
 }
+
+// GetDailySumByUser groups transactions the same way as v_monthly_transaction_sum, split by day and tagged with the wallet currency
+func (r *dashboardRepository) GetDailySumByUser(ctx context.Context, userID uuid.UUID) ([]*DailyWalletSum, error) {
+	var sums []*DailyWalletSum
+	if err := r.db.WithContext(ctx).
+		Table("transactions AS t").
+		Select(`t.wallet_id, w.currency, DATE(t.occurred_at) AS day,
+			TO_CHAR(DATE_TRUNC('month', t.occurred_at), 'YYYY-MM') AS month,
+			COUNT(*) AS transaction_count, SUM(t.cost) AS total_cost`).
+		Joins("JOIN wallets AS w ON w.id = t.wallet_id").
+		Where("t.user_id = ? AND t.transfer_id IS NULL", userID).
+		Group("t.wallet_id, w.currency, DATE(t.occurred_at), DATE_TRUNC('month', t.occurred_at)").
+		Order("month DESC").
+		Order("day").
+		Scan(&sums).Error; err != nil {
+		return nil, fmt.Errorf("query daily summary: %w", err)
+	}
+	return sums, nil
+}
+
+// GetDailyTagSumByUser totals the tagged transactions of a user that occurred from the start of from to the end of to,
+// leaving out transfers. A transaction with several tags counts toward each of them.
+func (r *dashboardRepository) GetDailyTagSumByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*DailyTagSum, error) {
+	var sums []*DailyTagSum
+	if err := r.db.WithContext(ctx).
+		Table("transactions AS t").
+		Select(`tg.id AS tag_id, tg.name, w.currency, DATE(t.occurred_at) AS day, t.type,
+			COUNT(*) AS transaction_count, SUM(t.cost) AS total_cost`).
+		Joins("JOIN transaction_tags AS tt ON tt.transaction_id = t.id").
+		Joins("JOIN tags AS tg ON tg.id = tt.tag_id").
+		Joins("JOIN wallets AS w ON w.id = t.wallet_id").
+		Where("t.user_id = ? AND t.transfer_id IS NULL AND t.deleted_at IS NULL", userID).
+		Where("t.occurred_at >= ? AND t.occurred_at < ?", from, to.AddDate(0, 0, 1)).
+		Group("tg.id, tg.name, w.currency, DATE(t.occurred_at), t.type").
+		Order("tg.name").
+		Order("day").
+		Scan(&sums).Error; err != nil {
+		return nil, fmt.Errorf("query tag summary: %w", err)
+	}
+	return sums, nil
+}
+
+// GetWalletsByUser returns all active wallets of a user
+func (r *dashboardRepository) GetWalletsByUser(ctx context.Context, userID uuid.UUID) ([]*entities.Wallet, error) {
+	var wallets []*entities.Wallet
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("name").
+		Find(&wallets).Error; err != nil {
+		return nil, fmt.Errorf("query wallets: %w", err)
+	}
+	return wallets, nil
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExchangeRateRepository interface {
+	Upsert(ctx context.Context, rates []*entities.ExchangeRate) error
+	GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.ExchangeRate, error)
+	CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error)
+	GetUpTo(ctx context.Context, currencies []string, until time.Time) ([]*entities.ExchangeRate, error)
+}
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+// Upsert stores the rates, replacing the rate already stored for the same day and pair
+func (r *exchangeRateRepository) Upsert(ctx context.Context, rates []*entities.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "base"}, {Name: "quote"}, {Name: "date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "updated_at"}),
+		}).
+		CreateInBatches(rates, 500).Error
+}
+
+func (r *exchangeRateRepository) GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.ExchangeRate, error) {
+	var rates []*entities.ExchangeRate
+	query := r.applyFilters(r.db.WithContext(ctx), queryParams)
+
+	// Apply sorting
+	if queryParams.HasSort() {
+		// Only allow safe column names for sorting
+		allowedSortColumns := map[string]bool{
+			"date":       true,
+			"base":       true,
+			"quote":      true,
+			"created_at": true,
+			"updated_at": true,
+		}
+
+		if allowedSortColumns[queryParams.SortBy] {
+			orderClause := queryParams.SortBy + " " + queryParams.SortType
+			query = query.Order(orderClause)
+		}
+	} else {
+		// Default sorting
+		query = query.Order("date DESC").Order("base").Order("quote")
+	}
+
+	// Apply pagination
+	if queryParams.Limit > 0 {
+		query = query.Limit(queryParams.Limit)
+	}
+	if queryParams.GetOffset() > 0 {
+		query = query.Offset(queryParams.GetOffset())
+	}
+
+	if err := query.Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (r *exchangeRateRepository) CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error) {
+	var count int64
+	query := r.applyFilters(r.db.WithContext(ctx).Model(&entities.ExchangeRate{}), queryParams)
+
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetUpTo returns every rate dated on or before until, oldest first, whose base or quote is one of currencies.
+// Rates from other pairs of those currencies are included so cross rates can be derived.
+func (r *exchangeRateRepository) GetUpTo(ctx context.Context, currencies []string, until time.Time) ([]*entities.ExchangeRate, error) {
+	var rates []*entities.ExchangeRate
+	if err := r.db.WithContext(ctx).
+		Where("date <= ?", until).
+		Where("base IN ? OR quote IN ?", currencies, currencies).
+		Order("date").
+		Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// applyFilters applies the custom filters shared by GetAll and CountWithFilters
+func (r *exchangeRateRepository) applyFilters(query *gorm.DB, queryParams *dto.QueryParams) *gorm.DB {
+	if queryParams.HasFilters() {
+		for key, value := range queryParams.Filters {
+			// Only allow safe column names to prevent SQL injection
+			switch key {
+			case "base", "quote":
+				query = query.Where(key+" = ?", strings.ToUpper(value))
+			case "source":
+				query = query.Where("source = ?", value)
+			case "date":
+				query = query.Where("date = ?", value)
+			case "date_from":
+				query = query.Where("date >= ?", value)
+			case "date_to":
+				query = query.Where("date <= ?", value)
+			}
+		}
+	}
+
+	return query
+}
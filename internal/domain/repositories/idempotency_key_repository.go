@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyKeyRepository interface {
+	Get(ctx context.Context, userID uuid.UUID, key string) (*entities.IdempotencyKey, error)
+	Claim(ctx context.Context, idempotencyKey *entities.IdempotencyKey) (bool, error)
+	Save(ctx context.Context, idempotencyKey *entities.IdempotencyKey) error
+	Release(ctx context.Context, userID uuid.UUID, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// idempotencyKeyColumns are the columns replaced when a key is claimed again or its response is stored
+var idempotencyKeyColumns = []string{
+	"method", "path", "request_hash", "status", "status_code", "content_type", "etag", "response_body", "created_at", "expires_at",
+}
+
+type idempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyKeyRepository(db *gorm.DB) IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+// Get returns the unexpired stored response of a user's idempotency key
+func (r *idempotencyKeyRepository) Get(ctx context.Context, userID uuid.UUID, key string) (*entities.IdempotencyKey, error) {
+	var idempotencyKey entities.IdempotencyKey
+	if err := r.db.WithContext(ctx).
+		First(&idempotencyKey, "user_id = ? AND key = ? AND expires_at > ?", userID, key, time.Now()).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("idempotency key not found")
+		}
+		return nil, err
+	}
+	return &idempotencyKey, nil
+}
+
+// Claim stores a processing idempotency key unless a live row already holds the key, and reports whether it did.
+// An expired row, including a claim left behind by a crashed request, is taken over.
+func (r *idempotencyKeyRepository) Claim(ctx context.Context, idempotencyKey *entities.IdempotencyKey) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns(idempotencyKeyColumns),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "idempotency_keys.expires_at <= ?", Vars: []interface{}{time.Now()}},
+		}},
+	}).Create(idempotencyKey)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Save stores the response of an idempotency key over its processing claim. An expired row for the same key is
+// overwritten, a live response is kept so the first stored response wins.
+func (r *idempotencyKeyRepository) Save(ctx context.Context, idempotencyKey *entities.IdempotencyKey) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns(idempotencyKeyColumns),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{
+				SQL:  "idempotency_keys.expires_at <= ? OR (idempotency_keys.status = ? AND idempotency_keys.request_hash = ?)",
+				Vars: []interface{}{time.Now(), entities.IdempotencyKeyStatusProcessing, idempotencyKey.RequestHash},
+			},
+		}},
+	}).Create(idempotencyKey).Error
+}
+
+// Release deletes the processing claim of a key whose request failed, so it can be retried with the same key
+func (r *idempotencyKeyRepository) Release(ctx context.Context, userID uuid.UUID, key string) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND key = ? AND status = ?", userID, key, entities.IdempotencyKeyStatusProcessing).
+		Delete(&entities.IdempotencyKey{}).Error
+}
+
+// DeleteExpired deletes the keys expired at now and returns how many were deleted
+func (r *idempotencyKeyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&entities.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
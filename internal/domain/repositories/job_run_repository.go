@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+
+	"gorm.io/gorm"
+)
+
+type JobRunRepository interface {
+	Create(ctx context.Context, run *entities.JobRun) error
+	Update(ctx context.Context, run *entities.JobRun) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.JobRun, error)
+	GetByJobName(ctx context.Context, jobName string, queryParams *dto.QueryParams) ([]*entities.JobRun, error)
+	CountByJobName(ctx context.Context, jobName string) (int64, error)
+	GetStats(ctx context.Context, jobName string) (*JobRunStats, error)
+}
+
+// JobRunStats sums up the recent history of a job
+type JobRunStats struct {
+	LastRun             *entities.JobRun
+	LastSuccessAt       *time.Time
+	ConsecutiveFailures int64 // Failed runs since the last successful one
+}
+
+type jobRunRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRunRepository(db *gorm.DB) JobRunRepository {
+	return &jobRunRepository{db: db}
+}
+
+func (r *jobRunRepository) Create(ctx context.Context, run *entities.JobRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+func (r *jobRunRepository) Update(ctx context.Context, run *entities.JobRun) error {
+	return r.db.WithContext(ctx).Save(run).Error
+}
+
+func (r *jobRunRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.JobRun, error) {
+	var run entities.JobRun
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&run).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// GetByJobName returns the runs of a job, newest first
+func (r *jobRunRepository) GetByJobName(ctx context.Context, jobName string, queryParams *dto.QueryParams) ([]*entities.JobRun, error) {
+	var runs []*entities.JobRun
+	query := r.db.WithContext(ctx).Where("job_name = ?", jobName).Order("started_at DESC")
+
+	// Apply pagination
+	if queryParams.Limit > 0 {
+		query = query.Limit(queryParams.Limit)
+	}
+	if queryParams.GetOffset() > 0 {
+		query = query.Offset(queryParams.GetOffset())
+	}
+
+	if err := query.Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (r *jobRunRepository) CountByJobName(ctx context.Context, jobName string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.JobRun{}).Where("job_name = ?", jobName).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetStats returns the last run of a job, when it last succeeded and how many runs failed since
+func (r *jobRunRepository) GetStats(ctx context.Context, jobName string) (*JobRunStats, error) {
+	stats := &JobRunStats{}
+
+	var lastRun entities.JobRun
+	err := r.db.WithContext(ctx).Where("job_name = ?", jobName).Order("started_at DESC").First(&lastRun).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	stats.LastRun = &lastRun
+
+	var lastSuccess entities.JobRun
+	err = r.db.WithContext(ctx).
+		Where("job_name = ? AND status = ?", jobName, entities.JobRunStatusSucceeded).
+		Order("started_at DESC").
+		First(&lastSuccess).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	failures := r.db.WithContext(ctx).Model(&entities.JobRun{}).
+		Where("job_name = ? AND status = ?", jobName, entities.JobRunStatusFailed)
+	if err == nil {
+		stats.LastSuccessAt = lastSuccess.FinishedAt
+		failures = failures.Where("started_at > ?", lastSuccess.StartedAt)
+	}
+	if err := failures.Count(&stats.ConsecutiveFailures).Error; err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobSettingRepository interface {
+	GetAll(ctx context.Context) ([]*entities.JobSetting, error)
+	Upsert(ctx context.Context, setting *entities.JobSetting) error
+	Delete(ctx context.Context, jobName string) error
+}
+
+type jobSettingRepository struct {
+	db *gorm.DB
+}
+
+func NewJobSettingRepository(db *gorm.DB) JobSettingRepository {
+	return &jobSettingRepository{db: db}
+}
+
+func (r *jobSettingRepository) GetAll(ctx context.Context) ([]*entities.JobSetting, error) {
+	var settings []*entities.JobSetting
+	if err := r.db.WithContext(ctx).Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// Upsert stores the setting, replacing the one already stored for the same job
+func (r *jobSettingRepository) Upsert(ctx context.Context, setting *entities.JobSetting) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "job_name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"schedule", "timezone", "enabled", "updated_at"}),
+		}).
+		Create(setting).Error
+}
+
+// Delete removes the setting stored for a job, deleting a job without one is not an error
+func (r *jobSettingRepository) Delete(ctx context.Context, jobName string) error {
+	return r.db.WithContext(ctx).Where("job_name = ?", jobName).Delete(&entities.JobSetting{}).Error
+}
@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecurringTransactionRepository interface {
+	Create(ctx context.Context, recurring *entities.RecurringTransaction) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.RecurringTransaction, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entities.RecurringTransaction, error)
+	GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.RecurringTransaction, error)
+	GetDueIDs(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error)
+	Update(ctx context.Context, recurring *entities.RecurringTransaction) error
+	CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error)
+	SoftDelete(ctx context.Context, id uuid.UUID) error
+}
+
+type recurringTransactionRepository struct {
+	db *gorm.DB
+}
+
+func NewRecurringTransactionRepository(db *gorm.DB) RecurringTransactionRepository {
+	return &recurringTransactionRepository{db: db}
+}
+
+func (r *recurringTransactionRepository) Create(ctx context.Context, recurring *entities.RecurringTransaction) error {
+	if err := r.db.WithContext(ctx).Create(recurring).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *recurringTransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.RecurringTransaction, error) {
+	var recurring entities.RecurringTransaction
+	if err := r.db.Preload("Wallet").WithContext(ctx).First(&recurring, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("recurring transaction not found")
+		}
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+// GetByIDForUpdate locks the template row (SELECT ... FOR UPDATE) so concurrent runs materialise it one at a time.
+// Must be called on a repository created from a DB transaction.
+func (r *recurringTransactionRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entities.RecurringTransaction, error) {
+	var recurring entities.RecurringTransaction
+	if err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&recurring, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("recurring transaction not found")
+		}
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+func (r *recurringTransactionRepository) GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.RecurringTransaction, error) {
+	var recurrings []*entities.RecurringTransaction
+	query := r.applyFilters(r.db.WithContext(ctx), queryParams)
+
+	// Apply sorting
+	if queryParams.HasSort() {
+		// Only allow safe column names for sorting
+		allowedSortColumns := map[string]bool{
+			"name":               true,
+			"cost":               true,
+			"start_date":         true,
+			"next_occurrence_at": true,
+			"created_at":         true,
+			"updated_at":         true,
+		}
+
+		if allowedSortColumns[queryParams.SortBy] {
+			orderClause := queryParams.SortBy + " " + queryParams.SortType
+			query = query.Order(orderClause)
+		}
+	} else {
+		// Default sorting
+		query = query.Order("created_at DESC")
+	}
+
+	// Apply pagination
+	if queryParams.Limit > 0 {
+		query = query.Limit(queryParams.Limit)
+	}
+	if queryParams.GetOffset() > 0 {
+		query = query.Offset(queryParams.GetOffset())
+	}
+
+	if err := query.Preload("Wallet").Find(&recurrings).Error; err != nil {
+		return nil, err
+	}
+	return recurrings, nil
+}
+
+// GetDueIDs returns the IDs of active templates whose next occurrence is at or before now
+func (r *recurringTransactionRepository) GetDueIDs(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	query := r.db.WithContext(ctx).Model(&entities.RecurringTransaction{}).
+		Where("is_active = ? AND is_deleted = ?", true, false).
+		Where("next_occurrence_at IS NOT NULL AND next_occurrence_at <= ?", now).
+		Order("next_occurrence_at ASC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *recurringTransactionRepository) Update(ctx context.Context, recurring *entities.RecurringTransaction) error {
+	if err := r.db.WithContext(ctx).Save(recurring).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *recurringTransactionRepository) CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error) {
+	var count int64
+	query := r.applyFilters(r.db.WithContext(ctx).Model(&entities.RecurringTransaction{}), queryParams)
+
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// SoftDelete soft deletes a recurring transaction by ID
+func (r *recurringTransactionRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&entities.RecurringTransaction{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"is_deleted": true,
+			"is_active":  false,
+			"deleted_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("recurring transaction not found")
+	}
+
+	return nil
+}
+
+// applyFilters applies the user scope, search and custom filters shared by GetAll and CountWithFilters
+func (r *recurringTransactionRepository) applyFilters(query *gorm.DB, queryParams *dto.QueryParams) *gorm.DB {
+	if queryParams.LoggedUserID != uuid.Nil {
+		query = query.Where("user_id = ?", queryParams.LoggedUserID)
+	}
+
+	// Apply search if provided
+	if queryParams.HasSearch() {
+		searchTerm := "%" + queryParams.Search + "%"
+		query = query.Where("name ILIKE ? OR note ILIKE ?", searchTerm, searchTerm)
+	}
+
+	// Apply custom filters
+	if queryParams.HasFilters() {
+		for key, value := range queryParams.Filters {
+			// Only allow safe column names to prevent SQL injection
+			switch key {
+			case "type", "t_category", "frequency", "user_id", "wallet_id", "is_active":
+				query = query.Where(key+" = ?", value)
+			case "cost_min":
+				query = query.Where("cost >= ?", value)
+			case "cost_max":
+				query = query.Where("cost <= ?", value)
+			}
+		}
+	}
+
+	return query
+}
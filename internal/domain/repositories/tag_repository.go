@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TagRepository interface {
+	Create(ctx context.Context, tag *entities.Tag) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Tag, error)
+	GetByName(ctx context.Context, userID uuid.UUID, name string) (*entities.Tag, error)
+	GetByNames(ctx context.Context, userID uuid.UUID, names []string) ([]entities.Tag, error)
+	GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.Tag, error)
+	Update(ctx context.Context, tag *entities.Tag) error
+	CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) Create(ctx context.Context, tag *entities.Tag) error {
+	if err := r.db.WithContext(ctx).Create(tag).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *tagRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Tag, error) {
+	var tag entities.Tag
+	if err := r.db.WithContext(ctx).First(&tag, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetByName finds a tag of a user by its normalized name
+func (r *tagRepository) GetByName(ctx context.Context, userID uuid.UUID, name string) (*entities.Tag, error) {
+	var tag entities.Tag
+	if err := r.db.WithContext(ctx).First(&tag, "user_id = ? AND name = ?", userID, name).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetByNames returns the existing tags of a user among the given normalized names
+func (r *tagRepository) GetByNames(ctx context.Context, userID uuid.UUID, names []string) ([]entities.Tag, error) {
+	var tags []entities.Tag
+	if len(names) == 0 {
+		return tags, nil
+	}
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND name IN ?", userID, names).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.Tag, error) {
+	var tags []*entities.Tag
+	query := r.applyFilters(r.db.WithContext(ctx), queryParams)
+
+	// Apply sorting
+	if queryParams.HasSort() {
+		// Only allow safe column names for sorting
+		allowedSortColumns := map[string]bool{
+			"name":       true,
+			"created_at": true,
+			"updated_at": true,
+		}
+
+		if allowedSortColumns[queryParams.SortBy] {
+			orderClause := queryParams.SortBy + " " + queryParams.SortType
+			query = query.Order(orderClause)
+		}
+	} else {
+		// Default sorting
+		query = query.Order("name")
+	}
+
+	// Apply pagination
+	if queryParams.Limit > 0 {
+		query = query.Limit(queryParams.Limit)
+	}
+	if queryParams.GetOffset() > 0 {
+		query = query.Offset(queryParams.GetOffset())
+	}
+
+	if err := query.Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) Update(ctx context.Context, tag *entities.Tag) error {
+	if err := r.db.WithContext(ctx).Save(tag).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *tagRepository) CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error) {
+	var count int64
+	query := r.applyFilters(r.db.WithContext(ctx).Model(&entities.Tag{}), queryParams)
+
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Delete permanently deletes a tag and unlinks it from its transactions
+func (r *tagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM transaction_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&entities.Tag{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("tag not found")
+		}
+		return nil
+	})
+}
+
+// applyFilters applies the user scope, search and custom filters shared by GetAll and CountWithFilters
+func (r *tagRepository) applyFilters(query *gorm.DB, queryParams *dto.QueryParams) *gorm.DB {
+	if queryParams.LoggedUserID != uuid.Nil {
+		query = query.Where("user_id = ?", queryParams.LoggedUserID)
+	}
+
+	// Apply search if provided
+	if queryParams.HasSearch() {
+		searchTerm := "%" + queryParams.Search + "%"
+		query = query.Where("name ILIKE ?", searchTerm)
+	}
+
+	// Apply custom filters
+	if queryParams.HasFilters() {
+		for key, value := range queryParams.Filters {
+			// Only allow safe column names to prevent SQL injection
+			switch key {
+			case "user_id":
+				query = query.Where("user_id = ?", value)
+			}
+		}
+	}
+
+	return query
+}
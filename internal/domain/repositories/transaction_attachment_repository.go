@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TransactionAttachmentRepository interface {
+	Create(ctx context.Context, attachment *entities.TransactionAttachment) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.TransactionAttachment, error)
+	GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entities.TransactionAttachment, error)
+	CountByTransactionID(ctx context.Context, transactionID uuid.UUID) (int64, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteByTransactionID(ctx context.Context, transactionID uuid.UUID) error
+	MoveToTransaction(ctx context.Context, fromTransactionID, toTransactionID uuid.UUID) error
+}
+
+type transactionAttachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewTransactionAttachmentRepository(db *gorm.DB) TransactionAttachmentRepository {
+	return &transactionAttachmentRepository{db: db}
+}
+
+func (r *transactionAttachmentRepository) Create(ctx context.Context, attachment *entities.TransactionAttachment) error {
+	if err := r.db.WithContext(ctx).Create(attachment).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *transactionAttachmentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.TransactionAttachment, error) {
+	var attachment entities.TransactionAttachment
+	if err := r.db.WithContext(ctx).First(&attachment, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attachment not found")
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// GetByTransactionID returns the attachments of a transaction, oldest first
+func (r *transactionAttachmentRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entities.TransactionAttachment, error) {
+	var attachments []*entities.TransactionAttachment
+	if err := r.db.WithContext(ctx).
+		Where("transaction_id = ?", transactionID).
+		Order("created_at").
+		Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *transactionAttachmentRepository) CountByTransactionID(ctx context.Context, transactionID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.TransactionAttachment{}).
+		Where("transaction_id = ?", transactionID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *transactionAttachmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entities.TransactionAttachment{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("attachment not found")
+	}
+
+	return nil
+}
+
+func (r *transactionAttachmentRepository) DeleteByTransactionID(ctx context.Context, transactionID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("transaction_id = ?", transactionID).Delete(&entities.TransactionAttachment{}).Error
+}
+
+// MoveToTransaction reassigns every attachment of one transaction to another, the stored files are kept as they are
+func (r *transactionAttachmentRepository) MoveToTransaction(ctx context.Context, fromTransactionID, toTransactionID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&entities.TransactionAttachment{}).
+		Where("transaction_id = ?", fromTransactionID).
+		Update("transaction_id", toTransactionID).Error
+}
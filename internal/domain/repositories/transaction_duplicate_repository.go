@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// notDismissedQuery excludes the pairs of transactions a and b that were dismissed
+const notDismissedQuery = `NOT EXISTS (SELECT 1 FROM dismissed_duplicates d
+	WHERE d.transaction_id = LEAST(a.id, b.id) AND d.duplicate_id = GREATEST(a.id, b.id))`
+
+type TransactionDuplicateRepository interface {
+	GetCandidates(ctx context.Context, transaction *entities.Transaction, window time.Duration) ([]*entities.Transaction, error)
+	GetCandidatePairs(ctx context.Context, userID uuid.UUID, walletID *uuid.UUID, window time.Duration, after *DuplicatePairCursor, limit int) ([]DuplicatePair, error)
+	GetPairTransactions(ctx context.Context, ids []uuid.UUID) ([]*entities.Transaction, error)
+	Dismiss(ctx context.Context, dismissal *entities.DismissedDuplicate) error
+}
+
+// DuplicatePair is two active transactions with the same wallet, amount and type close in time, the earlier one first
+type DuplicatePair struct {
+	Original  *entities.Transaction
+	Duplicate *entities.Transaction
+}
+
+// DuplicatePairCursor is the position of a pair in the newest first order of GetCandidatePairs
+type DuplicatePairCursor struct {
+	OccurredAt  time.Time // Of the duplicate
+	DuplicateID uuid.UUID
+	OriginalID  uuid.UUID
+}
+
+// Cursor returns the position of the pair, GetCandidatePairs continues after it
+func (p DuplicatePair) Cursor() DuplicatePairCursor {
+	return DuplicatePairCursor{OccurredAt: p.Duplicate.OccurredAt, DuplicateID: p.Duplicate.ID, OriginalID: p.Original.ID}
+}
+
+type transactionDuplicateRepository struct {
+	db *gorm.DB
+}
+
+func NewTransactionDuplicateRepository(db *gorm.DB) TransactionDuplicateRepository {
+	return &transactionDuplicateRepository{db: db}
+}
+
+// GetCandidates returns the other active transactions with the wallet, amount and type of transaction that
+// occurred within window of it, leaving out transfer legs and dismissed pairs
+func (r *transactionDuplicateRepository) GetCandidates(ctx context.Context, transaction *entities.Transaction, window time.Duration) ([]*entities.Transaction, error) {
+	var candidates []*entities.Transaction
+	if err := r.db.WithContext(ctx).
+		Table("transactions AS b").
+		Select("b.*").
+		Joins("JOIN transactions AS a ON a.id = ?", transaction.ID).
+		Where("b.id <> a.id AND b.wallet_id = a.wallet_id AND b.cost = a.cost AND b.type = a.type").
+		Where("b.transfer_id IS NULL AND b.deleted_at IS NULL").
+		Where("b.occurred_at BETWEEN ? AND ?", transaction.OccurredAt.Add(-window), transaction.OccurredAt.Add(window)).
+		Where(notDismissedQuery).
+		Order("b.occurred_at").
+		Find(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("query duplicate candidates: %w", err)
+	}
+	return candidates, nil
+}
+
+// GetCandidatePairs returns up to limit pairs of active transactions of a user (every user when userID is nil) with
+// the same wallet, amount and type that occurred within window of each other, leaving out transfer legs and dismissed
+// pairs. Pairs come newest first, starting after the after cursor when it is set. Only the ID, name and occurrence
+// time of the transactions are read, GetPairTransactions loads the pairs that are returned.
+func (r *transactionDuplicateRepository) GetCandidatePairs(ctx context.Context, userID uuid.UUID, walletID *uuid.UUID, window time.Duration, after *DuplicatePairCursor, limit int) ([]DuplicatePair, error) {
+	var rows []struct {
+		OriginalID          uuid.UUID
+		OriginalName        string
+		OriginalOccurredAt  time.Time
+		DuplicateID         uuid.UUID
+		DuplicateName       string
+		DuplicateOccurredAt time.Time
+	}
+	query := r.db.WithContext(ctx).
+		Table("transactions AS a").
+		Select(`a.id AS original_id, a.name AS original_name, a.occurred_at AS original_occurred_at,
+			b.id AS duplicate_id, b.name AS duplicate_name, b.occurred_at AS duplicate_occurred_at`).
+		Joins("JOIN transactions AS b ON b.wallet_id = a.wallet_id AND b.cost = a.cost AND b.type = a.type AND (b.occurred_at, b.id) > (a.occurred_at, a.id)").
+		Where("a.transfer_id IS NULL AND a.deleted_at IS NULL AND b.transfer_id IS NULL AND b.deleted_at IS NULL").
+		Where("b.occurred_at - a.occurred_at <= ?::interval", fmt.Sprintf("%d seconds", int64(window.Seconds()))).
+		Where(notDismissedQuery)
+	if userID != uuid.Nil {
+		query = query.Where("a.user_id = ?", userID)
+	}
+	if walletID != nil {
+		query = query.Where("a.wallet_id = ?", *walletID)
+	}
+	if after != nil {
+		query = query.Where("(b.occurred_at, b.id, a.id) < (?, ?, ?)", after.OccurredAt, after.DuplicateID, after.OriginalID)
+	}
+	if err := query.Order("b.occurred_at DESC").Order("b.id DESC").Order("a.id DESC").Limit(limit).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("query duplicate pairs: %w", err)
+	}
+
+	pairs := make([]DuplicatePair, 0, len(rows))
+	for _, row := range rows {
+		pairs = append(pairs, DuplicatePair{
+			Original:  &entities.Transaction{ID: row.OriginalID, Name: row.OriginalName, OccurredAt: row.OriginalOccurredAt},
+			Duplicate: &entities.Transaction{ID: row.DuplicateID, Name: row.DuplicateName, OccurredAt: row.DuplicateOccurredAt},
+		})
+	}
+	return pairs, nil
+}
+
+// GetPairTransactions returns the transactions with the given IDs with their wallet and tags
+func (r *transactionDuplicateRepository) GetPairTransactions(ctx context.Context, ids []uuid.UUID) ([]*entities.Transaction, error) {
+	var transactions []*entities.Transaction
+	if err := r.db.WithContext(ctx).
+		Preload("Wallet").
+		Preload("Tags", orderTags).
+		Where("id IN ?", ids).
+		Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("query duplicate transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+// Dismiss stores a dismissed pair, dismissing it again is a no-op
+func (r *transactionDuplicateRepository) Dismiss(ctx context.Context, dismissal *entities.DismissedDuplicate) error {
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(dismissal).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -3,10 +3,12 @@ package repositories
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
 	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -15,19 +17,43 @@ import (
 type TransactionRepository interface {
 	Create(ctx context.Context, transaction *entities.Transaction) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error)
+	GetByIDWithDeleted(ctx context.Context, id uuid.UUID) (*entities.Transaction, error)
 	GetOne(ctx context.Context, filter map[string]interface{}) (*entities.Transaction, error)
 	GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.Transaction, error)
 	Update(ctx context.Context, transaction *entities.Transaction) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	Count(ctx context.Context) (int64, error)
 	CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error)
+	SumCostWithFilters(ctx context.Context, queryParams *dto.QueryParams) (money.Money, error)
 	SoftDelete(ctx context.Context, id uuid.UUID) error
 	HardDelete(ctx context.Context, id uuid.UUID) error
 	Restore(ctx context.Context, id uuid.UUID) error
 	GetByWalletID(ctx context.Context, walletID uuid.UUID) ([]*entities.Transaction, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Transaction, error)
+	SumWalletImpacts(ctx context.Context, walletIDs []uuid.UUID) (map[uuid.UUID]*WalletImpactSum, error)
+	ExistsRecurringOccurrence(ctx context.Context, recurringTransactionID uuid.UUID, occurrenceDate time.Time) (bool, error)
+	GetExistingExternalIDs(ctx context.Context, walletID uuid.UUID, externalIDs []string) (map[string]bool, error)
+	StreamWithFilters(ctx context.Context, queryParams *dto.QueryParams, fn func(transaction *entities.Transaction) error) error
+	ReplaceSplits(ctx context.Context, transactionID uuid.UUID, splits []entities.TransactionSplit) error
+	ReplaceTags(ctx context.Context, transaction *entities.Transaction, tags []entities.Tag) error
 }
 
+// splitsExistQuery checks whether the transaction of the current row is split
+const splitsExistQuery = "EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = transactions.id)"
+
+// orderSplits preloads split lines largest first
+func orderSplits(db *gorm.DB) *gorm.DB {
+	return db.Order("amount DESC").Order("id")
+}
+
+// orderTags preloads tags by name
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("name")
+}
+
+// transactionTagNamesQuery selects the tag names of the transaction of the current row among the given names
+const transactionTagNamesQuery = "SELECT tg.name FROM transaction_tags tt JOIN tags tg ON tg.id = tt.tag_id WHERE tt.transaction_id = transactions.id AND tg.name IN ?"
+
 type transactionRepository struct {
 	db *gorm.DB
 }
@@ -45,7 +71,19 @@ func (r *transactionRepository) Create(ctx context.Context, transaction *entitie
 
 func (r *transactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
 	var transaction entities.Transaction
-	if err := r.db.Preload("User").Preload("Wallet").WithContext(ctx).First(&transaction, "id = ?", id).Error; err != nil {
+	if err := r.db.Preload("User").Preload("Wallet").Preload("Splits", orderSplits).Preload("Tags", orderTags).WithContext(ctx).First(&transaction, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transaction not found")
+		}
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// GetByIDWithDeleted gets a transaction by ID including soft deleted ones
+func (r *transactionRepository) GetByIDWithDeleted(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
+	var transaction entities.Transaction
+	if err := r.db.WithContext(ctx).Unscoped().First(&transaction, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("transaction not found")
 		}
@@ -62,7 +100,7 @@ func (r *transactionRepository) GetOne(ctx context.Context, filter map[string]in
 		query = query.Where("LOWER("+key+") = LOWER(?)", value)
 	}
 
-	if err := query.Preload("User").Preload("Wallet").First(&transaction).Error; err != nil {
+	if err := query.Preload("User").Preload("Wallet").Preload("Splits", orderSplits).Preload("Tags", orderTags).First(&transaction).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("transaction not found")
 		}
@@ -73,58 +111,7 @@ func (r *transactionRepository) GetOne(ctx context.Context, filter map[string]in
 
 func (r *transactionRepository) GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.Transaction, error) {
 	var transactions []*entities.Transaction
-	query := r.db.WithContext(ctx)
-
-	if queryParams.LoggedUserID != uuid.Nil {
-		query = query.Where("user_id = ?", queryParams.LoggedUserID)
-	}
-
-	// Apply search if provided
-	if queryParams.HasSearch() {
-		searchTerm := "%" + queryParams.Search + "%"
-		query = query.Where("name ILIKE ? OR note ILIKE ? OR t_category ILIKE ? OR type ILIKE ?", searchTerm, searchTerm, searchTerm, searchTerm)
-	}
-
-	// Apply custom filters
-	if queryParams.HasFilters() {
-		for key, value := range queryParams.Filters {
-			// Only allow safe column names to prevent SQL injection
-			switch key {
-			case "t_category", "name", "type":
-				query = query.Where("LOWER("+key+") = LOWER(?)", value)
-			case "wallet_id", "user_id":
-				query = query.Where(key+" = ?", value)
-			case "cost_min":
-				query = query.Where("cost >= ?", value)
-			case "cost_max":
-				query = query.Where("cost <= ?", value)
-			case "created_after":
-				query = query.Where("created_at >= ?", value)
-			case "created_before":
-				query = query.Where("created_at <= ?", value)
-			}
-		}
-	}
-
-	// Apply sorting
-	if queryParams.HasSort() {
-		// Only allow safe column names for sorting
-		allowedSortColumns := map[string]bool{
-			"name":       true,
-			"cost":       true,
-			"t_category": true,
-			"created_at": true,
-			"updated_at": true,
-		}
-
-		if allowedSortColumns[queryParams.SortBy] {
-			orderClause := queryParams.SortBy + " " + queryParams.SortType
-			query = query.Order(orderClause)
-		}
-	} else {
-		// Default sorting
-		query = query.Order("created_at DESC")
-	}
+	query := r.applySort(r.applyFilters(r.db.WithContext(ctx), queryParams), queryParams)
 
 	// Apply pagination
 	if queryParams.Limit > 0 {
@@ -134,17 +121,15 @@ func (r *transactionRepository) GetAll(ctx context.Context, queryParams *dto.Que
 		query = query.Offset(queryParams.GetOffset())
 	}
 
-	if err := query.Preload("User").Preload("Wallet").Find(&transactions).Error; err != nil {
+	if err := query.Preload("User").Preload("Wallet").Preload("Splits", orderSplits).Preload("Tags", orderTags).Find(&transactions).Error; err != nil {
 		return nil, err
 	}
 	return transactions, nil
 }
 
+// Update saves the transaction without its splits and tags, failing with ErrVersionConflict when it changed since it was read
 func (r *transactionRepository) Update(ctx context.Context, transaction *entities.Transaction) error {
-	if err := r.db.WithContext(ctx).Save(transaction).Error; err != nil {
-		return err
-	}
-	return nil
+	return updateVersioned(r.db.WithContext(ctx), transaction, &transaction.Version)
 }
 
 func (r *transactionRepository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -164,43 +149,32 @@ func (r *transactionRepository) Count(ctx context.Context) (int64, error) {
 
 func (r *transactionRepository) CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error) {
 	var count int64
-	query := r.db.WithContext(ctx).Model(&entities.Transaction{})
+	query := r.applyFilters(r.db.WithContext(ctx).Model(&entities.Transaction{}), queryParams)
 
-	if queryParams.LoggedUserID != uuid.Nil {
-		query = query.Where("user_id = ?", queryParams.LoggedUserID)
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
 	}
+	return count, nil
+}
 
-	// Apply search if provided
-	if queryParams.HasSearch() {
-		searchTerm := "%" + queryParams.Search + "%"
-		query = query.Where("name ILIKE ? OR note ILIKE ? OR t_category ILIKE ? OR type ILIKE ?", searchTerm, searchTerm, searchTerm, searchTerm)
-	}
+// SumCostWithFilters sums the cost of the transactions matching the same filters as GetAll.
+// When filtering by category, split transactions only count the amounts of their matching splits.
+func (r *transactionRepository) SumCostWithFilters(ctx context.Context, queryParams *dto.QueryParams) (money.Money, error) {
+	var total money.Money
+	query := r.applyFilters(r.db.WithContext(ctx).Model(&entities.Transaction{}), queryParams)
 
-	// Apply custom filters
-	if queryParams.HasFilters() {
-		for key, value := range queryParams.Filters {
-			// Only allow safe column names to prevent SQL injection
-			switch key {
-			case "t_category", "name", "type":
-				query = query.Where("LOWER("+key+") = LOWER(?)", value)
-			case "wallet_id", "user_id":
-				query = query.Where(key+" = ?", value)
-			case "cost_min":
-				query = query.Where("cost >= ?", value)
-			case "cost_max":
-				query = query.Where("cost <= ?", value)
-			case "created_after":
-				query = query.Where("created_at >= ?", value)
-			case "created_before":
-				query = query.Where("created_at <= ?", value)
-			}
-		}
+	if condition, args := categoryFilterCondition("s", queryParams.Filters); condition != "" {
+		query = query.Select("COALESCE(SUM(CASE WHEN "+splitsExistQuery+
+			" THEN (SELECT COALESCE(SUM(s.amount), 0) FROM transaction_splits s WHERE s.transaction_id = transactions.id AND "+condition+")"+
+			" ELSE transactions.cost END), 0)", args...)
+	} else {
+		query = query.Select("COALESCE(SUM(cost), 0)")
 	}
 
-	if err := query.Count(&count).Error; err != nil {
-		return 0, err
+	if err := query.Row().Scan(&total); err != nil {
+		return money.Zero, err
 	}
-	return count, nil
+	return total, nil
 }
 
 // SoftDelete soft deletes a transaction by ID
@@ -210,6 +184,7 @@ func (r *transactionRepository) SoftDelete(ctx context.Context, id uuid.UUID) er
 		Updates(map[string]interface{}{
 			"is_deleted": true,
 			"deleted_at": time.Now(),
+			"version":    gorm.Expr("version + 1"),
 		})
 
 	if result.Error != nil {
@@ -268,3 +243,220 @@ func (r *transactionRepository) GetByUserID(ctx context.Context, userID uuid.UUI
 	}
 	return transactions, nil
 }
+
+// ExistsRecurringOccurrence checks if an occurrence of a recurring template was already posted, including soft deleted ones
+func (r *transactionRepository) ExistsRecurringOccurrence(ctx context.Context, recurringTransactionID uuid.UUID, occurrenceDate time.Time) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Unscoped().Model(&entities.Transaction{}).
+		Where("recurring_transaction_id = ? AND occurrence_date = ?", recurringTransactionID, occurrenceDate).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// WalletImpactSum is the balance a wallet should have according to its active transactions
+type WalletImpactSum struct {
+	WalletID         uuid.UUID
+	Balance          money.Money
+	TransactionCount int64
+}
+
+// SumWalletImpacts adds up the wallet impact of the active transactions of each wallet in one grouped query, the same
+// way Transaction.GetWalletImpact does. Wallets without active transactions are left out of the result.
+func (r *transactionRepository) SumWalletImpacts(ctx context.Context, walletIDs []uuid.UUID) (map[uuid.UUID]*WalletImpactSum, error) {
+	sums := make(map[uuid.UUID]*WalletImpactSum, len(walletIDs))
+	if len(walletIDs) == 0 {
+		return sums, nil
+	}
+
+	var rows []*WalletImpactSum
+	if err := r.db.WithContext(ctx).Model(&entities.Transaction{}).
+		Select("wallet_id, SUM(CASE WHEN type = ? THEN ABS(cost) ELSE -ABS(cost) END) AS balance, COUNT(*) AS transaction_count", entities.TransactionTypeIncome).
+		Where("wallet_id IN ? AND is_deleted = false", walletIDs).
+		Group("wallet_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		sums[row.WalletID] = row
+	}
+	return sums, nil
+}
+
+// GetExistingExternalIDs returns which of the external IDs were already imported into the wallet, including soft deleted ones
+func (r *transactionRepository) GetExistingExternalIDs(ctx context.Context, walletID uuid.UUID, externalIDs []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(externalIDs) == 0 {
+		return existing, nil
+	}
+
+	var found []string
+	if err := r.db.WithContext(ctx).Unscoped().Model(&entities.Transaction{}).
+		Where("wallet_id = ? AND external_id IN ?", walletID, externalIDs).
+		Pluck("external_id", &found).Error; err != nil {
+		return nil, err
+	}
+
+	for _, id := range found {
+		existing[id] = true
+	}
+	return existing, nil
+}
+
+// StreamWithFilters calls fn for every transaction matching the filters and sort of queryParams, ignoring
+// pagination. Rows are read from a cursor one at a time so the result set is never held in memory.
+func (r *transactionRepository) StreamWithFilters(ctx context.Context, queryParams *dto.QueryParams, fn func(transaction *entities.Transaction) error) error {
+	query := r.applySort(r.applyFilters(r.db.WithContext(ctx).Model(&entities.Transaction{}), queryParams), queryParams)
+
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transaction entities.Transaction
+		if err := r.db.ScanRows(rows, &transaction); err != nil {
+			return err
+		}
+		if err := fn(&transaction); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ReplaceSplits replaces the split lines of a transaction, removing them all when splits is empty
+func (r *transactionRepository) ReplaceSplits(ctx context.Context, transactionID uuid.UUID, splits []entities.TransactionSplit) error {
+	if err := r.db.WithContext(ctx).Where("transaction_id = ?", transactionID).Delete(&entities.TransactionSplit{}).Error; err != nil {
+		return err
+	}
+	if len(splits) == 0 {
+		return nil
+	}
+
+	for i := range splits {
+		splits[i].TransactionID = transactionID
+	}
+	return r.db.WithContext(ctx).Create(&splits).Error
+}
+
+// ReplaceTags replaces the tags of a transaction, removing them all when tags is empty
+func (r *transactionRepository) ReplaceTags(ctx context.Context, transaction *entities.Transaction, tags []entities.Tag) error {
+	if len(tags) == 0 {
+		transaction.Tags = nil
+		return r.db.WithContext(ctx).Model(transaction).Association("Tags").Clear()
+	}
+	return r.db.WithContext(ctx).Model(transaction).Association("Tags").Replace(tags)
+}
+
+// applySort applies the requested sort when the column is allowed, otherwise the newest transactions come first
+func (r *transactionRepository) applySort(query *gorm.DB, queryParams *dto.QueryParams) *gorm.DB {
+	if !queryParams.HasSort() {
+		// Default sorting
+		return query.Order("occurred_at DESC").Order("created_at DESC")
+	}
+
+	// Only allow safe column names for sorting
+	allowedSortColumns := map[string]bool{
+		"name":        true,
+		"cost":        true,
+		"t_category":  true,
+		"occurred_at": true,
+		"created_at":  true,
+		"updated_at":  true,
+	}
+
+	if allowedSortColumns[queryParams.SortBy] {
+		orderClause := queryParams.SortBy + " " + queryParams.SortType
+		query = query.Order(orderClause)
+	}
+	return query
+}
+
+// applyFilters applies the user scope, search and custom filters shared by GetAll and CountWithFilters.
+// Date filters use occurred_at; created_after/created_before are kept as aliases for existing clients.
+func (r *transactionRepository) applyFilters(query *gorm.DB, queryParams *dto.QueryParams) *gorm.DB {
+	if queryParams.LoggedUserID != uuid.Nil {
+		query = query.Where("user_id = ?", queryParams.LoggedUserID)
+	}
+
+	// Apply search if provided
+	if queryParams.HasSearch() {
+		searchTerm := "%" + queryParams.Search + "%"
+		query = query.Where("name ILIKE ? OR note ILIKE ? OR t_category ILIKE ? OR type ILIKE ?", searchTerm, searchTerm, searchTerm, searchTerm)
+	}
+
+	// Category filters match the transaction itself, or any of its split lines when it is split
+	if condition, args := categoryFilterCondition("transactions", queryParams.Filters); condition != "" {
+		splitCondition, _ := categoryFilterCondition("s", queryParams.Filters)
+		query = query.Where(
+			"(NOT "+splitsExistQuery+" AND "+condition+") OR "+
+				"EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = transactions.id AND "+splitCondition+")",
+			append(args, args...)...)
+	}
+
+	// Apply custom filters
+	if queryParams.HasFilters() {
+		for key, value := range queryParams.Filters {
+			// Only allow safe column names to prevent SQL injection
+			switch key {
+			case "name", "type":
+				query = query.Where("LOWER("+key+") = LOWER(?)", value)
+			case "wallet_id", "user_id":
+				query = query.Where(key+" = ?", value)
+			case "tags_any":
+				// Comma-separated tag names, at least one must be present
+				if names := entities.NormalizeTagNames(strings.Split(value, ",")); len(names) > 0 {
+					query = query.Where("EXISTS ("+transactionTagNamesQuery+")", names)
+				}
+			case "tags_all":
+				// Comma-separated tag names, all must be present
+				if names := entities.NormalizeTagNames(strings.Split(value, ",")); len(names) > 0 {
+					query = query.Where("(SELECT COUNT(DISTINCT tg.name) FROM ("+transactionTagNamesQuery+") AS tg) = ?", names, len(names))
+				}
+			case "cost_min":
+				query = query.Where("cost >= ?", value)
+			case "cost_max":
+				query = query.Where("cost <= ?", value)
+			case "occurred_after", "created_after":
+				query = query.Where("occurred_at >= ?", value)
+			case "occurred_before", "created_before":
+				query = query.Where("occurred_at <= ?", value)
+			}
+		}
+	}
+
+	return query
+}
+
+// categoryFilterCondition builds the t_category, category_id and category_subtree filters against the columns of
+// alias, so the same filters can be checked on transactions and on split lines. It returns an empty condition when
+// none of them is set.
+func categoryFilterCondition(alias string, filters map[string]string) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	// Fixed order keeps the placeholders in line with args
+	for _, key := range []string{"t_category", "category_id", "category_subtree"} {
+		value, ok := filters[key]
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "t_category":
+			conditions = append(conditions, "LOWER("+alias+".t_category) = LOWER(?)")
+		case "category_id":
+			conditions = append(conditions, alias+".category_id = ?")
+		case "category_subtree":
+			// The category and all of its subcategories
+			conditions = append(conditions, alias+".category_id IN ("+categorySubtreeQuery+")")
+		}
+		args = append(args, value)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,188 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TransactionRuleRepository interface {
+	Create(ctx context.Context, rule *entities.TransactionRule) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.TransactionRule, error)
+	GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.TransactionRule, error)
+	GetActiveByUser(ctx context.Context, userID uuid.UUID) ([]*entities.TransactionRule, error)
+	GetCandidateTransactions(ctx context.Context, rule *entities.TransactionRule, after *entities.Transaction, limit int) ([]*entities.Transaction, error)
+	Update(ctx context.Context, rule *entities.TransactionRule) error
+	CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error)
+	SoftDelete(ctx context.Context, id uuid.UUID) error
+}
+
+type transactionRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewTransactionRuleRepository(db *gorm.DB) TransactionRuleRepository {
+	return &transactionRuleRepository{db: db}
+}
+
+func (r *transactionRuleRepository) Create(ctx context.Context, rule *entities.TransactionRule) error {
+	if err := r.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *transactionRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.TransactionRule, error) {
+	var rule entities.TransactionRule
+	if err := r.db.WithContext(ctx).Preload("Category").First(&rule, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transaction rule not found")
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *transactionRuleRepository) GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.TransactionRule, error) {
+	var rules []*entities.TransactionRule
+	query := r.applyFilters(r.db.WithContext(ctx).Preload("Category"), queryParams)
+
+	// Apply sorting
+	if queryParams.HasSort() {
+		// Only allow safe column names for sorting
+		allowedSortColumns := map[string]bool{
+			"name":       true,
+			"priority":   true,
+			"created_at": true,
+			"updated_at": true,
+		}
+
+		if allowedSortColumns[queryParams.SortBy] {
+			orderClause := queryParams.SortBy + " " + queryParams.SortType
+			query = query.Order(orderClause)
+		}
+	} else {
+		// Default sorting, the order the rules run in
+		query = query.Order("priority").Order("created_at")
+	}
+
+	// Apply pagination
+	if queryParams.Limit > 0 {
+		query = query.Limit(queryParams.Limit)
+	}
+	if queryParams.GetOffset() > 0 {
+		query = query.Offset(queryParams.GetOffset())
+	}
+
+	if err := query.Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetActiveByUser returns the active rules of a user in the order they run
+func (r *transactionRuleRepository) GetActiveByUser(ctx context.Context, userID uuid.UUID) ([]*entities.TransactionRule, error) {
+	var rules []*entities.TransactionRule
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Order("priority").
+		Order("created_at").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetCandidateTransactions returns up to limit active transactions of the rule's user that match its wallet, type and
+// amount conditions, oldest first, starting after the given transaction (nil for the first batch). Transfer legs are
+// left out. The name and note patterns are checked by the caller.
+func (r *transactionRuleRepository) GetCandidateTransactions(ctx context.Context, rule *entities.TransactionRule, after *entities.Transaction, limit int) ([]*entities.Transaction, error) {
+	var transactions []*entities.Transaction
+	query := r.db.WithContext(ctx).
+		Preload("Splits", orderSplits).
+		Preload("Tags", orderTags).
+		Where("user_id = ? AND transfer_id IS NULL", rule.UserID)
+
+	if rule.WalletID != nil {
+		query = query.Where("wallet_id = ?", *rule.WalletID)
+	}
+	if rule.Type != "" {
+		query = query.Where("type = ?", rule.Type)
+	}
+	if rule.MinAmount != nil {
+		query = query.Where("ABS(cost) >= ?", *rule.MinAmount)
+	}
+	if rule.MaxAmount != nil {
+		query = query.Where("ABS(cost) <= ?", *rule.MaxAmount)
+	}
+
+	if after != nil {
+		query = query.Where("(occurred_at, id) > (?, ?)", after.OccurredAt, after.ID)
+	}
+
+	if err := query.Order("occurred_at").Order("id").Limit(limit).Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (r *transactionRuleRepository) Update(ctx context.Context, rule *entities.TransactionRule) error {
+	if err := r.db.WithContext(ctx).Omit("Category").Save(rule).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *transactionRuleRepository) CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error) {
+	var count int64
+	query := r.applyFilters(r.db.WithContext(ctx).Model(&entities.TransactionRule{}), queryParams)
+
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *transactionRuleRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entities.TransactionRule{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("transaction rule not found")
+	}
+	return nil
+}
+
+// applyFilters applies the user scope, search and custom filters shared by GetAll and CountWithFilters
+func (r *transactionRuleRepository) applyFilters(query *gorm.DB, queryParams *dto.QueryParams) *gorm.DB {
+	if queryParams.LoggedUserID != uuid.Nil {
+		query = query.Where("user_id = ?", queryParams.LoggedUserID)
+	}
+
+	// Apply search if provided
+	if queryParams.HasSearch() {
+		searchTerm := "%" + queryParams.Search + "%"
+		query = query.Where("name ILIKE ?", searchTerm)
+	}
+
+	// Apply custom filters
+	if queryParams.HasFilters() {
+		for key, value := range queryParams.Filters {
+			// Only allow safe column names to prevent SQL injection
+			switch key {
+			case "user_id", "wallet_id", "category_id", "type":
+				query = query.Where(key+" = ?", value)
+			case "is_active":
+				query = query.Where("is_active = ?", value == "true")
+			}
+		}
+	}
+
+	return query
+}
@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TransferRepository interface {
+	Create(ctx context.Context, transfer *entities.Transfer) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Transfer, error)
+	GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.Transfer, error)
+	Update(ctx context.Context, transfer *entities.Transfer) error
+	CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error)
+	SoftDelete(ctx context.Context, id uuid.UUID) error
+}
+
+type transferRepository struct {
+	db *gorm.DB
+}
+
+func NewTransferRepository(db *gorm.DB) TransferRepository {
+	return &transferRepository{db: db}
+}
+
+func (r *transferRepository) Create(ctx context.Context, transfer *entities.Transfer) error {
+	if err := r.db.WithContext(ctx).Create(transfer).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *transferRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Transfer, error) {
+	var transfer entities.Transfer
+	if err := r.db.Preload("FromWallet").Preload("ToWallet").WithContext(ctx).First(&transfer, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transfer not found")
+		}
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *transferRepository) GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.Transfer, error) {
+	var transfers []*entities.Transfer
+	query := r.applyFilters(r.db.WithContext(ctx), queryParams)
+
+	// Apply sorting
+	if queryParams.HasSort() {
+		// Only allow safe column names for sorting
+		allowedSortColumns := map[string]bool{
+			"amount":     true,
+			"created_at": true,
+			"updated_at": true,
+		}
+
+		if allowedSortColumns[queryParams.SortBy] {
+			orderClause := queryParams.SortBy + " " + queryParams.SortType
+			query = query.Order(orderClause)
+		}
+	} else {
+		// Default sorting
+		query = query.Order("created_at DESC")
+	}
+
+	// Apply pagination
+	if queryParams.Limit > 0 {
+		query = query.Limit(queryParams.Limit)
+	}
+	if queryParams.GetOffset() > 0 {
+		query = query.Offset(queryParams.GetOffset())
+	}
+
+	if err := query.Preload("FromWallet").Preload("ToWallet").Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+func (r *transferRepository) Update(ctx context.Context, transfer *entities.Transfer) error {
+	if err := r.db.WithContext(ctx).Save(transfer).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *transferRepository) CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error) {
+	var count int64
+	query := r.applyFilters(r.db.WithContext(ctx).Model(&entities.Transfer{}), queryParams)
+
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// SoftDelete soft deletes a transfer by ID
+func (r *transferRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&entities.Transfer{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"is_deleted": true,
+			"deleted_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("transfer not found")
+	}
+
+	return nil
+}
+
+// applyFilters applies the user scope, search and custom filters shared by GetAll and CountWithFilters
+func (r *transferRepository) applyFilters(query *gorm.DB, queryParams *dto.QueryParams) *gorm.DB {
+	if queryParams.LoggedUserID != uuid.Nil {
+		query = query.Where("user_id = ?", queryParams.LoggedUserID)
+	}
+
+	// Apply search if provided
+	if queryParams.HasSearch() {
+		searchTerm := "%" + queryParams.Search + "%"
+		query = query.Where("note ILIKE ?", searchTerm)
+	}
+
+	// Apply custom filters
+	if queryParams.HasFilters() {
+		for key, value := range queryParams.Filters {
+			// Only allow safe column names to prevent SQL injection
+			switch key {
+			case "from_wallet_id", "to_wallet_id", "user_id":
+				query = query.Where(key+" = ?", value)
+			case "wallet_id":
+				query = query.Where("(from_wallet_id = ? OR to_wallet_id = ?)", value, value)
+			case "amount_min":
+				query = query.Where("amount >= ?", value)
+			case "amount_max":
+				query = query.Where("amount <= ?", value)
+			case "created_after":
+				query = query.Where("created_at >= ?", value)
+			case "created_before":
+				query = query.Where("created_at <= ?", value)
+			}
+		}
+	}
+
+	return query
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrVersionConflict is returned by the update of a versioned entity when its row changed after it was read
+var ErrVersionConflict = errors.New("record was changed by another request")
+
+// updateVersioned writes every column of a versioned entity and bumps its version, but only while the row still
+// has the version that was read. Associations are left alone, as with Save.
+func updateVersioned(db *gorm.DB, model interface{}, version *int64) error {
+	currentVersion := *version
+	*version = currentVersion + 1
+
+	result := db.Unscoped().Model(model).
+		Where("version = ?", currentVersion).
+		Select("*").
+		Omit(clause.Associations, "ID", "CreatedAt").
+		Updates(model)
+	if result.Error != nil {
+		*version = currentVersion
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		*version = currentVersion
+		return ErrVersionConflict
+	}
+	return nil
+}
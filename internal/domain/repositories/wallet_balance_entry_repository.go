@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WalletBalanceEntryRepository reads the balance ledger. Entries are written by WalletRepository together with the
+// balance change itself and are never updated or deleted.
+type WalletBalanceEntryRepository interface {
+	SumSince(ctx context.Context, walletID uuid.UUID, since time.Time) (money.Money, error)
+	GetDailyChanges(ctx context.Context, walletID uuid.UUID, from, to time.Time) ([]*DailyBalanceChange, error)
+}
+
+// DailyBalanceChange is the net change of a wallet balance on one day
+type DailyBalanceChange struct {
+	Day        time.Time
+	Amount     money.Money
+	EntryCount int64
+}
+
+type walletBalanceEntryRepository struct {
+	db *gorm.DB
+}
+
+func NewWalletBalanceEntryRepository(db *gorm.DB) WalletBalanceEntryRepository {
+	return &walletBalanceEntryRepository{db: db}
+}
+
+// SumSince returns the net change of a wallet balance recorded at or after since
+func (r *walletBalanceEntryRepository) SumSince(ctx context.Context, walletID uuid.UUID, since time.Time) (money.Money, error) {
+	var sum money.Money
+	if err := r.db.WithContext(ctx).
+		Table("wallet_balance_entries").
+		Select("COALESCE(SUM(amount), 0)").
+		Where("wallet_id = ? AND created_at >= ?", walletID, since).
+		Scan(&sum).Error; err != nil {
+		return money.Zero, err
+	}
+	return sum, nil
+}
+
+// GetDailyChanges returns the net change of a wallet balance per UTC day from the start of from to the end of to,
+// leaving out days without changes
+func (r *walletBalanceEntryRepository) GetDailyChanges(ctx context.Context, walletID uuid.UUID, from, to time.Time) ([]*DailyBalanceChange, error) {
+	var changes []*DailyBalanceChange
+	if err := r.db.WithContext(ctx).
+		Table("wallet_balance_entries").
+		Select("DATE(created_at AT TIME ZONE 'UTC') AS day, SUM(amount) AS amount, COUNT(*) AS entry_count").
+		Where("wallet_id = ?", walletID).
+		Where("created_at >= ? AND created_at < ?", from, to.AddDate(0, 0, 1)).
+		Group("DATE(created_at AT TIME ZONE 'UTC')").
+		Order("day").
+		Scan(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type WalletRepository interface {
@@ -17,7 +18,9 @@ type WalletRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Wallet, error)
 	GetOne(ctx context.Context, filter map[string]interface{}) (*entities.Wallet, error)
 	GetAll(ctx context.Context, queryParams *dto.QueryParams) ([]*entities.Wallet, error)
-	Update(ctx context.Context, wallet *entities.Wallet) error
+	GetBatchAfter(ctx context.Context, afterID uuid.UUID, limit int) ([]*entities.Wallet, error)
+	Update(ctx context.Context, wallet *entities.Wallet, cause entities.BalanceChangeCause) error
+	AdjustBalance(ctx context.Context, change *entities.WalletBalanceEntry) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	Count(ctx context.Context) (int64, error)
 	CountWithFilters(ctx context.Context, queryParams *dto.QueryParams) (int64, error)
@@ -34,11 +37,19 @@ func NewWalletRepository(db *gorm.DB) WalletRepository {
 	return &walletRepository{db: db}
 }
 
+// Create saves the wallet and records its opening balance in the balance ledger
 func (r *walletRepository) Create(ctx context.Context, wallet *entities.Wallet) error {
-	if err := r.db.WithContext(ctx).Create(wallet).Error; err != nil {
-		return err
-	}
-	return nil
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(wallet).Error; err != nil {
+			return err
+		}
+		if wallet.Balance.IsZero() {
+			return nil
+		}
+		entry := entities.NewBalanceChange(wallet.ID, wallet.Balance, entities.BalanceCauseWalletCreated, nil)
+		entry.BalanceAfter = wallet.Balance
+		return tx.Create(entry).Error
+	})
 }
 
 func (r *walletRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Wallet, error) {
@@ -52,6 +63,20 @@ func (r *walletRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities
 	return &wallet, nil
 }
 
+// GetBatchAfter returns up to limit wallets with an ID greater than afterID ordered by ID, so every wallet can be
+// walked in batches that stay stable while wallets are created or deleted. Start with uuid.Nil.
+func (r *walletRepository) GetBatchAfter(ctx context.Context, afterID uuid.UUID, limit int) ([]*entities.Wallet, error) {
+	var wallets []*entities.Wallet
+	if err := r.db.WithContext(ctx).
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&wallets).Error; err != nil {
+		return nil, err
+	}
+	return wallets, nil
+}
+
 func (r *walletRepository) GetOne(ctx context.Context, filter map[string]interface{}) (*entities.Wallet, error) {
 	var wallet entities.Wallet
 	query := r.db.WithContext(ctx)
@@ -132,11 +157,60 @@ func (r *walletRepository) GetAll(ctx context.Context, queryParams *dto.QueryPar
 	return wallets, nil
 }
 
-func (r *walletRepository) Update(ctx context.Context, wallet *entities.Wallet) error {
-	if err := r.db.WithContext(ctx).Save(wallet).Error; err != nil {
-		return err
-	}
-	return nil
+// Update saves the wallet, failing with ErrVersionConflict when it changed since it was read. A changed balance is
+// recorded in the balance ledger with the given cause. Balance changes caused by transactions go through AdjustBalance instead.
+func (r *walletRepository) Update(ctx context.Context, wallet *entities.Wallet, cause entities.BalanceChangeCause) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the version that was read, so the stored balance is the one the ledger entry starts from
+		var stored entities.Wallet
+		if err := tx.Unscoped().Select("balance").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&stored, "id = ? AND version = ?", wallet.ID, wallet.Version).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrVersionConflict
+			}
+			return err
+		}
+
+		if err := updateVersioned(tx, wallet, &wallet.Version); err != nil {
+			return err
+		}
+		if wallet.Balance == stored.Balance {
+			return nil
+		}
+
+		entry := entities.NewBalanceChange(wallet.ID, wallet.Balance.Sub(stored.Balance), cause, nil)
+		entry.BalanceBefore = stored.Balance
+		entry.BalanceAfter = wallet.Balance
+		return tx.Create(entry).Error
+	})
+}
+
+// AdjustBalance adds change.Amount to the wallet balance in a single statement, so concurrent transactions on the
+// same wallet can't overwrite each other's change, and appends change to the balance ledger with the balances filled in
+func (r *walletRepository) AdjustBalance(ctx context.Context, change *entities.WalletBalanceEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var wallet entities.Wallet
+		result := tx.Model(&wallet).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "balance"}}}).
+			Where("id = ?", change.WalletID).
+			Updates(map[string]interface{}{
+				"balance": gorm.Expr("balance + ?", change.Amount),
+				"version": gorm.Expr("version + 1"),
+			})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("wallet not found")
+		}
+
+		change.BalanceAfter = wallet.Balance
+		change.BalanceBefore = wallet.Balance.Sub(change.Amount)
+		return tx.Create(change).Error
+	})
 }
 
 func (r *walletRepository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -197,6 +271,7 @@ func (r *walletRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
 		Updates(map[string]interface{}{
 			"is_deleted": true,
 			"deleted_at": time.Now(),
+			"version":    gorm.Expr("version + 1"),
 		})
 
 	if result.Error != nil {
@@ -2,138 +2,418 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
 	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 type BalanceSyncUseCaseInterface interface {
-	SyncAllWalletBalances(ctx context.Context) error
-	SyncWalletBalance(ctx context.Context, wallet *entities.Wallet) error
+	SyncAllWalletBalances(ctx context.Context, dryRun bool) (*dto.BalanceSyncReportResponse, error)
+	SyncWalletBalance(ctx context.Context, wallet *entities.Wallet, dryRun bool) (*entities.BalanceSyncReportEntry, error)
+	GetSyncReports(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.BalanceSyncReportResponse], error)
+	GetSyncReport(ctx context.Context, id uuid.UUID) (*dto.BalanceSyncReportResponse, error)
+	GetSyncProgress() *dto.BalanceSyncProgressResponse
 }
 
+const (
+	// balanceSyncBatchSize is the number of wallets read and summed per query
+	balanceSyncBatchSize = 500
+	// balanceSyncConcurrency bounds the drifted wallets that are re-checked and corrected at the same time
+	balanceSyncConcurrency = 8
+)
+
 type BalanceSyncUseCase struct {
-	db              *gorm.DB
-	walletRepo      repositories.WalletRepository
-	transactionRepo repositories.TransactionRepository
+	db                    *gorm.DB
+	walletRepo            repositories.WalletRepository
+	transactionRepo       repositories.TransactionRepository
+	balanceSyncReportRepo repositories.BalanceSyncReportRepository
+	progress              atomic.Pointer[dto.BalanceSyncProgressResponse]
 }
 
-func NewBalanceSyncUseCase(walletRepo repositories.WalletRepository, transactionRepo repositories.TransactionRepository, db *gorm.DB) BalanceSyncUseCaseInterface {
+func NewBalanceSyncUseCase(
+	walletRepo repositories.WalletRepository,
+	transactionRepo repositories.TransactionRepository,
+	balanceSyncReportRepo repositories.BalanceSyncReportRepository,
+	db *gorm.DB,
+) BalanceSyncUseCaseInterface {
 	return &BalanceSyncUseCase{
-		db:              db,
-		walletRepo:      walletRepo,
-		transactionRepo: transactionRepo,
+		db:                    db,
+		walletRepo:            walletRepo,
+		transactionRepo:       transactionRepo,
+		balanceSyncReportRepo: balanceSyncReportRepo,
 	}
 }
 
-// SyncAllWalletBalances recalculates and updates all wallet balances based on active transactions
-func (uc *BalanceSyncUseCase) SyncAllWalletBalances(ctx context.Context) error {
+// SyncAllWalletBalances recalculates all wallet balances based on active transactions and reports every wallet that
+// drifted. A dry run only reports, a real run corrects the drifted balances and stores the report for auditing.
+// Wallets that fail to sync are reported instead of failing the run.
+//
+// Wallets are walked in batches by ID and the balances of a batch are computed with one grouped query, so only the
+// drifted wallets are loaded again to be corrected, a few at a time. Cancelling ctx stops the run after the current
+// batch; what was corrected until then is still stored, marked as cancelled.
+func (uc *BalanceSyncUseCase) SyncAllWalletBalances(ctx context.Context, dryRun bool) (*dto.BalanceSyncReportResponse, error) {
 	funcCtx := "BalanceSyncUseCase.SyncAllWalletBalances"
 
-	logger.LogSuccess(funcCtx, "Starting wallet balance sync for all wallets", logrus.Fields{})
+	total, err := uc.walletRepo.Count(ctx)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to count wallets", err, logrus.Fields{})
+		return nil, fmt.Errorf("failed to count wallets: %w", err)
+	}
+
+	logger.LogSuccess(funcCtx, "Starting wallet balance sync for all wallets", logrus.Fields{
+		"dry_run":       dryRun,
+		"total_wallets": total,
+	})
+
+	report := &entities.BalanceSyncReport{StartedAt: time.Now().UTC()}
+	uc.setProgress(report, total, dryRun, true)
+
+	var runErr error
+	afterID := uuid.Nil
+	for {
+		if ctx.Err() != nil {
+			report.Cancelled = true
+			runErr = fmt.Errorf("balance sync cancelled after %d of %d wallets: %w", report.Wallets, total, ctx.Err())
+			break
+		}
+
+		wallets, err := uc.walletRepo.GetBatchAfter(ctx, afterID, balanceSyncBatchSize)
+		if err != nil {
+			logger.LogError(funcCtx, "failed to get wallets", err, logrus.Fields{
+				"after_wallet_id": afterID.String(),
+			})
+			runErr = fmt.Errorf("failed to get wallets: %w", err)
+			break
+		}
+		if len(wallets) == 0 {
+			break
+		}
+
+		if err := uc.syncWalletBatch(ctx, report, wallets, dryRun); err != nil {
+			logger.LogError(funcCtx, "failed to sync wallet batch", err, logrus.Fields{
+				"after_wallet_id": afterID.String(),
+			})
+			runErr = err
+			break
+		}
+		afterID = wallets[len(wallets)-1].ID
+
+		uc.setProgress(report, total, dryRun, true)
+		logger.LogSuccess(funcCtx, "Wallet balance sync progress", logrus.Fields{
+			"dry_run":         dryRun,
+			"processed":       report.Wallets,
+			"total_wallets":   total,
+			"drifted_count":   report.Drifted,
+			"corrected_count": report.Corrected,
+			"error_count":     report.Failed,
+		})
+	}
+
+	report.FinishedAt = time.Now().UTC()
+	uc.setProgress(report, total, dryRun, false)
+
+	// Store the report even when the run stopped early, the balances corrected so far must stay auditable
+	if !dryRun && (runErr == nil || report.Wallets > 0) {
+		if err := uc.balanceSyncReportRepo.Create(context.WithoutCancel(ctx), report); err != nil {
+			logger.LogError(funcCtx, "failed to store balance sync report", err, logrus.Fields{
+				"corrected_count": report.Corrected,
+			})
+			if runErr == nil {
+				return nil, fmt.Errorf("failed to store balance sync report: %w", err)
+			}
+		}
+	}
 
-	// Get all active wallets using the correct method signature
-	queryParams := &dto.QueryParams{
-		PaginationQuery: &dto.PaginationQuery{
-			Page:  1,
-			Limit: 1000, // Process in batches if needed
-		},
-		FilterQuery: &dto.FilterQuery{},
+	if runErr != nil {
+		return nil, runErr
 	}
 
-	wallets, err := uc.walletRepo.GetAll(ctx, queryParams)
+	logger.LogSuccess(funcCtx, "Completed wallet balance sync", logrus.Fields{
+		"dry_run":         dryRun,
+		"total_wallets":   report.Wallets,
+		"drifted_count":   report.Drifted,
+		"corrected_count": report.Corrected,
+		"error_count":     report.Failed,
+	})
+
+	return dto.MapToBalanceSyncReportResponse(report, dryRun), nil
+}
+
+// syncWalletBatch compares the stored balances of a batch of wallets with their transactions and syncs the drifted
+// ones concurrently. SyncWalletBalance checks a drifted wallet again, so a transaction posted between reading the
+// wallet and summing its transactions is not reported as drift.
+func (uc *BalanceSyncUseCase) syncWalletBatch(ctx context.Context, report *entities.BalanceSyncReport, wallets []*entities.Wallet, dryRun bool) error {
+	funcCtx := "BalanceSyncUseCase.syncWalletBatch"
+
+	walletIDs := make([]uuid.UUID, len(wallets))
+	for i, wallet := range wallets {
+		walletIDs[i] = wallet.ID
+	}
+
+	sums, err := uc.transactionRepo.SumWalletImpacts(ctx, walletIDs)
 	if err != nil {
-		logger.LogError(funcCtx, "failed to get wallets", err, logrus.Fields{})
-		return fmt.Errorf("failed to get wallets: %w", err)
+		return fmt.Errorf("failed to sum wallet transactions: %w", err)
 	}
 
-	var syncErrors []error
-	syncedCount := 0
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	slots := make(chan struct{}, balanceSyncConcurrency)
 
-	// Process each wallet
 	for _, wallet := range wallets {
-		if err := uc.SyncWalletBalance(ctx, wallet); err != nil {
-			logger.LogError(funcCtx, "failed to sync wallet balance", err, logrus.Fields{
-				"wallet_id":   wallet.ID.String(),
-				"wallet_name": wallet.Name,
-			})
-			syncErrors = append(syncErrors, fmt.Errorf("wallet %s: %w", wallet.ID.String(), err))
+		calculatedBalance := money.Zero
+		if sum, ok := sums[wallet.ID]; ok {
+			calculatedBalance = sum.Balance
+		}
+
+		if wallet.Balance == calculatedBalance {
+			mu.Lock()
+			report.Wallets++
+			mu.Unlock()
 			continue
 		}
-		syncedCount++
+
+		// Stop starting corrections once cancelled, the run reports the wallets checked so far
+		if ctx.Err() != nil {
+			break
+		}
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			storedBalance := wallet.Balance
+			entry, err := uc.SyncWalletBalance(ctx, wallet, dryRun)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Wallets++
+			if err != nil {
+				logger.LogError(funcCtx, "failed to sync wallet balance", err, logrus.Fields{
+					"wallet_id":   wallet.ID.String(),
+					"wallet_name": wallet.Name,
+				})
+				if entry == nil {
+					entry = &entities.BalanceSyncReportEntry{WalletID: wallet.ID, StoredBalance: storedBalance, RecomputedBalance: storedBalance}
+				}
+				entry.Error = err.Error()
+				report.Failed++
+			}
+
+			if entry.IsDrifted() {
+				report.Drifted++
+			}
+			if entry.Corrected {
+				report.Corrected++
+			}
+			if entry.IsDrifted() || entry.Error != "" {
+				report.Entries = append(report.Entries, *entry)
+			}
+		}()
 	}
 
-	logger.LogSuccess(funcCtx, "Completed wallet balance sync", logrus.Fields{
-		"total_wallets": len(wallets),
-		"synced_count":  syncedCount,
-		"error_count":   len(syncErrors),
+	wg.Wait()
+	return nil
+}
+
+// setProgress publishes the counts of the report as the progress of the current run
+func (uc *BalanceSyncUseCase) setProgress(report *entities.BalanceSyncReport, total int64, dryRun bool, running bool) {
+	uc.progress.Store(&dto.BalanceSyncProgressResponse{
+		Running:   running,
+		DryRun:    dryRun,
+		Total:     total,
+		Processed: report.Wallets,
+		Drifted:   report.Drifted,
+		Corrected: report.Corrected,
+		Failed:    report.Failed,
+		StartedAt: report.StartedAt,
+		UpdatedAt: time.Now().UTC(),
 	})
+}
 
-	if len(syncErrors) > 0 {
-		return fmt.Errorf("sync completed with %d errors: %v", len(syncErrors), syncErrors)
+// GetSyncProgress returns the progress of the running or last finished balance sync, nil if none ran yet
+func (uc *BalanceSyncUseCase) GetSyncProgress() *dto.BalanceSyncProgressResponse {
+	progress := uc.progress.Load()
+	if progress == nil {
+		return nil
 	}
+	snapshot := *progress
+	return &snapshot
+}
 
-	return nil
+// GetSyncReports lists the stored balance sync reports, newest first and without their entries
+func (uc *BalanceSyncUseCase) GetSyncReports(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.BalanceSyncReportResponse], error) {
+	funcCtx := "BalanceSyncUseCase.GetSyncReports"
+
+	reports, err := uc.balanceSyncReportRepo.GetAll(ctx, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get balance sync reports", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to get balance sync reports", err.Error())
+	}
+
+	total, err := uc.balanceSyncReportRepo.Count(ctx)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to count balance sync reports", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to count balance sync reports", err.Error())
+	}
+
+	reportResponses := make([]dto.BalanceSyncReportResponse, len(reports))
+	for i, report := range reports {
+		reportResponses[i] = *dto.MapToBalanceSyncReportResponse(report, false)
+	}
+
+	return &dto.PaginationData[dto.BalanceSyncReportResponse]{
+		Data: reportResponses,
+		Meta: helpers.NewPaginationMeta(queryParams.Page, queryParams.Limit, total),
+	}, nil
 }
 
-// SyncWalletBalance performs the actual balance sync for a wallet
-func (uc *BalanceSyncUseCase) SyncWalletBalance(ctx context.Context, wallet *entities.Wallet) error {
+// GetSyncReport returns a stored balance sync report with the wallets it found drifted
+func (uc *BalanceSyncUseCase) GetSyncReport(ctx context.Context, id uuid.UUID) (*dto.BalanceSyncReportResponse, error) {
+	funcCtx := "BalanceSyncUseCase.GetSyncReport"
+
+	report, err := uc.balanceSyncReportRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, helpers.NewNotFoundError("balance sync report not found", "")
+		}
+		logger.LogError(funcCtx, "failed to get balance sync report", err, logrus.Fields{
+			"report_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to get balance sync report", err.Error())
+	}
+
+	return dto.MapToBalanceSyncReportResponse(report, false), nil
+}
+
+// maxBalanceSyncAttempts bounds the retries of a wallet whose balance keeps changing while it is being synced
+const maxBalanceSyncAttempts = 3
+
+// SyncWalletBalance performs the actual balance sync for a wallet and reports its stored and recomputed balance.
+// The balance is written with a version check, so a transaction posted while the sum was being computed isn't lost;
+// the wallet is then reloaded and synced again. A dry run never writes the balance.
+func (uc *BalanceSyncUseCase) SyncWalletBalance(ctx context.Context, wallet *entities.Wallet, dryRun bool) (*entities.BalanceSyncReportEntry, error) {
 	funcCtx := "BalanceSyncUseCase.SyncWalletBalance"
 
-	// Get all active transactions for this wallet using the correct method
-	transactions, err := uc.transactionRepo.GetByWalletID(ctx, wallet.ID)
+	for attempt := 1; ; attempt++ {
+		entry, err := uc.syncWalletBalance(ctx, wallet, dryRun)
+		if !errors.Is(err, repositories.ErrVersionConflict) {
+			return entry, err
+		}
+		if attempt == maxBalanceSyncAttempts {
+			return entry, fmt.Errorf("wallet kept changing during %d sync attempts: %w", attempt, err)
+		}
+
+		logger.LogSuccess(funcCtx, "Wallet changed during balance sync, retrying", logrus.Fields{
+			"wallet_id": wallet.ID.String(),
+			"attempt":   attempt,
+		})
+
+		reloaded, err := uc.walletRepo.GetByID(ctx, wallet.ID)
+		if err != nil {
+			logger.LogError(funcCtx, "failed to reload wallet", err, logrus.Fields{
+				"wallet_id": wallet.ID.String(),
+			})
+			return entry, fmt.Errorf("failed to reload wallet: %w", err)
+		}
+		*wallet = *reloaded
+	}
+}
+
+func (uc *BalanceSyncUseCase) syncWalletBalance(ctx context.Context, wallet *entities.Wallet, dryRun bool) (*entities.BalanceSyncReportEntry, error) {
+	funcCtx := "BalanceSyncUseCase.SyncWalletBalance"
+
+	// Sum the active transactions of this wallet in the database
+	sums, err := uc.transactionRepo.SumWalletImpacts(ctx, []uuid.UUID{wallet.ID})
 	if err != nil {
-		logger.LogError(funcCtx, "failed to get transactions for wallet", err, logrus.Fields{
+		logger.LogError(funcCtx, "failed to sum transactions for wallet", err, logrus.Fields{
 			"wallet_id": wallet.ID.String(),
 		})
-		return fmt.Errorf("failed to get transactions for wallet: %w", err)
+		return nil, fmt.Errorf("failed to sum transactions for wallet: %w", err)
 	}
 
-	// Calculate the correct balance based on transactions
-	calculatedBalance := 0.0
-	for _, transaction := range transactions {
-		if !transaction.IsDeleted && !transaction.DeletedAt.Valid {
-			calculatedBalance += transaction.GetWalletImpact()
-		}
+	// Wallets without active transactions should have a zero balance
+	calculatedBalance := money.Zero
+	transactionCount := 0
+	if sum, ok := sums[wallet.ID]; ok {
+		calculatedBalance = sum.Balance
+		transactionCount = int(sum.TransactionCount)
 	}
 
-	// Check if balance needs updating
-	if wallet.Balance == calculatedBalance {
+	entry := &entities.BalanceSyncReportEntry{
+		WalletID:          wallet.ID,
+		StoredBalance:     wallet.Balance,
+		RecomputedBalance: calculatedBalance,
+		Difference:        calculatedBalance.Sub(wallet.Balance),
+		TransactionCount:  transactionCount,
+	}
+
+	// Check if balance needs updating, money amounts are exact so any difference is a real one
+	if !entry.IsDrifted() {
 		logger.LogSuccess(funcCtx, "Wallet balance already correct", logrus.Fields{
 			"wallet_id":          wallet.ID.String(),
 			"wallet_name":        wallet.Name,
 			"balance":            wallet.Balance,
 			"calculated_balance": calculatedBalance,
 		})
-		return nil
+		return entry, nil
+	}
+
+	if dryRun {
+		logger.LogSuccess(funcCtx, "Wallet balance drifted, left as is in dry run", logrus.Fields{
+			"wallet_id":          wallet.ID.String(),
+			"wallet_name":        wallet.Name,
+			"balance":            wallet.Balance,
+			"calculated_balance": calculatedBalance,
+			"difference":         entry.Difference,
+		})
+		return entry, nil
 	}
 
 	// Update wallet balance
 	oldBalance := wallet.Balance
 	wallet.Balance = calculatedBalance
 
-	if err := uc.walletRepo.Update(ctx, wallet); err != nil {
+	if err := uc.walletRepo.Update(ctx, wallet, entities.BalanceCauseBalanceSync); err != nil {
+		wallet.Balance = oldBalance
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			return entry, err
+		}
 		logger.LogError(funcCtx, "failed to update wallet balance", err, logrus.Fields{
 			"wallet_id":          wallet.ID.String(),
 			"old_balance":        oldBalance,
 			"calculated_balance": calculatedBalance,
 		})
-		return fmt.Errorf("failed to update wallet balance: %w", err)
+		return entry, fmt.Errorf("failed to update wallet balance: %w", err)
 	}
+	entry.Corrected = true
 
 	logger.LogSuccess(funcCtx, "Successfully synced wallet balance", logrus.Fields{
 		"wallet_id":         wallet.ID.String(),
 		"wallet_name":       wallet.Name,
 		"old_balance":       oldBalance,
 		"new_balance":       calculatedBalance,
-		"difference":        calculatedBalance - oldBalance,
-		"transaction_count": len(transactions),
+		"difference":        entry.Difference,
+		"transaction_count": transactionCount,
 	})
 
-	return nil
+	return entry, nil
 }
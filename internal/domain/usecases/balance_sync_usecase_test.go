@@ -0,0 +1,222 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// stubTransactionRepository sums a fixed list of transactions like the grouped query does, other methods are not
+// used by the balance sync
+type stubTransactionRepository struct {
+	repositories.TransactionRepository
+	transactions []*entities.Transaction
+}
+
+func (r *stubTransactionRepository) SumWalletImpacts(ctx context.Context, walletIDs []uuid.UUID) (map[uuid.UUID]*repositories.WalletImpactSum, error) {
+	sums := make(map[uuid.UUID]*repositories.WalletImpactSum)
+	for _, walletID := range walletIDs {
+		for _, transaction := range r.transactions {
+			if transaction.WalletID != walletID {
+				continue
+			}
+			sum, ok := sums[walletID]
+			if !ok {
+				sum = &repositories.WalletImpactSum{WalletID: walletID}
+				sums[walletID] = sum
+			}
+			sum.Balance = sum.Balance.Add(transaction.GetWalletImpact())
+			sum.TransactionCount++
+		}
+	}
+	return sums, nil
+}
+
+// stubBalanceSyncReportRepository keeps the stored reports in memory
+type stubBalanceSyncReportRepository struct {
+	repositories.BalanceSyncReportRepository
+	reports []*entities.BalanceSyncReport
+}
+
+func (r *stubBalanceSyncReportRepository) Create(ctx context.Context, report *entities.BalanceSyncReport) error {
+	report.ID = uuid.New()
+	r.reports = append(r.reports, report)
+	return nil
+}
+
+func smallTransactions(walletID uuid.UUID) []*entities.Transaction {
+	var transactions []*entities.Transaction
+	for i := 0; i < 10000; i++ {
+		transactions = append(transactions,
+			&entities.Transaction{WalletID: walletID, Type: entities.TransactionTypeIncome, Cost: money.MustParse("0.1")},
+			&entities.Transaction{WalletID: walletID, Type: entities.TransactionTypeExpense, Cost: money.MustParse("0.07")},
+		)
+	}
+	return transactions
+}
+
+func TestSyncWalletBalance_ManySmallTransactionsAreExact(t *testing.T) {
+	logger.Init("info")
+	wallet := &entities.Wallet{ID: uuid.New(), Balance: money.FromInt(300)} // 10000 * (0.10 - 0.07)
+	walletRepo := new(MockWalletRepository)
+	useCase := NewBalanceSyncUseCase(walletRepo, &stubTransactionRepository{transactions: smallTransactions(wallet.ID)}, nil, nil)
+
+	entry, err := useCase.SyncWalletBalance(context.Background(), wallet, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromInt(300), wallet.Balance)
+	assert.False(t, entry.IsDrifted())
+	assert.Equal(t, 20000, entry.TransactionCount)
+	walletRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSyncWalletBalance_FixesDriftedBalance(t *testing.T) {
+	logger.Init("info")
+	wallet := &entities.Wallet{ID: uuid.New(), Balance: money.MustParse("299.99")}
+	walletRepo := new(MockWalletRepository)
+	walletRepo.On("Update", mock.Anything, wallet, entities.BalanceCauseBalanceSync).Return(nil)
+	useCase := NewBalanceSyncUseCase(walletRepo, &stubTransactionRepository{transactions: smallTransactions(wallet.ID)}, nil, nil)
+
+	entry, err := useCase.SyncWalletBalance(context.Background(), wallet, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromInt(300), wallet.Balance)
+	assert.Equal(t, money.MustParse("0.01"), entry.Difference)
+	assert.True(t, entry.Corrected)
+	walletRepo.AssertExpectations(t)
+}
+
+func TestSyncWalletBalance_RetriesWhenWalletChanged(t *testing.T) {
+	logger.Init("info")
+	wallet := &entities.Wallet{ID: uuid.New(), Balance: money.MustParse("299.99"), Version: 1}
+	reloaded := &entities.Wallet{ID: wallet.ID, Balance: money.MustParse("299.98"), Version: 2}
+	walletRepo := new(MockWalletRepository)
+	walletRepo.On("Update", mock.Anything, mock.MatchedBy(func(w *entities.Wallet) bool { return w.Version == 1 }), entities.BalanceCauseBalanceSync).
+		Return(repositories.ErrVersionConflict).Once()
+	walletRepo.On("GetByID", mock.Anything, wallet.ID).Return(reloaded, nil).Once()
+	walletRepo.On("Update", mock.Anything, mock.MatchedBy(func(w *entities.Wallet) bool { return w.Version == 2 }), entities.BalanceCauseBalanceSync).
+		Return(nil).Once()
+	useCase := NewBalanceSyncUseCase(walletRepo, &stubTransactionRepository{transactions: smallTransactions(wallet.ID)}, nil, nil)
+
+	entry, err := useCase.SyncWalletBalance(context.Background(), wallet, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromInt(300), wallet.Balance)
+	assert.Equal(t, int64(2), wallet.Version)
+	assert.Equal(t, money.MustParse("299.98"), entry.StoredBalance)
+	walletRepo.AssertExpectations(t)
+}
+
+func TestSyncAllWalletBalances_DryRunOnlyReports(t *testing.T) {
+	logger.Init("info")
+	walletID := uuid.New()
+	drifted := &entities.Wallet{ID: walletID, Balance: money.MustParse("299.99")}
+	correct := &entities.Wallet{ID: uuid.New(), Balance: money.FromInt(300)}
+	walletRepo := new(MockWalletRepository)
+	walletRepo.On("Count", mock.Anything).Return(int64(2), nil)
+	walletRepo.On("GetBatchAfter", mock.Anything, uuid.Nil, balanceSyncBatchSize).Return([]*entities.Wallet{drifted, correct}, nil)
+	walletRepo.On("GetBatchAfter", mock.Anything, correct.ID, balanceSyncBatchSize).Return([]*entities.Wallet{}, nil)
+	reportRepo := &stubBalanceSyncReportRepository{}
+	transactions := append(smallTransactions(walletID), &entities.Transaction{WalletID: correct.ID, Type: entities.TransactionTypeIncome, Cost: money.FromInt(300)})
+	useCase := NewBalanceSyncUseCase(walletRepo, &stubTransactionRepository{transactions: transactions}, reportRepo, nil)
+
+	report, err := useCase.SyncAllWalletBalances(context.Background(), true)
+
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Nil(t, report.ID)
+	assert.Equal(t, 2, report.Wallets)
+	assert.Equal(t, 1, report.Drifted)
+	assert.Equal(t, 0, report.Corrected)
+	if assert.Len(t, report.Entries, 1) {
+		assert.Equal(t, walletID, report.Entries[0].WalletID)
+		assert.Equal(t, money.MustParse("299.99"), report.Entries[0].StoredBalance)
+		assert.Equal(t, money.FromInt(300), report.Entries[0].RecomputedBalance)
+		assert.Equal(t, money.MustParse("0.01"), report.Entries[0].Difference)
+		assert.Equal(t, 20000, report.Entries[0].TransactionCount)
+	}
+	assert.Equal(t, money.MustParse("299.99"), drifted.Balance)
+	assert.Empty(t, reportRepo.reports)
+	walletRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSyncAllWalletBalances_StoresReportOfCorrections(t *testing.T) {
+	logger.Init("info")
+	wallet := &entities.Wallet{ID: uuid.New(), Balance: money.MustParse("299.99")}
+	walletRepo := new(MockWalletRepository)
+	walletRepo.On("Count", mock.Anything).Return(int64(1), nil)
+	walletRepo.On("GetBatchAfter", mock.Anything, uuid.Nil, balanceSyncBatchSize).Return([]*entities.Wallet{wallet}, nil)
+	walletRepo.On("GetBatchAfter", mock.Anything, wallet.ID, balanceSyncBatchSize).Return([]*entities.Wallet{}, nil)
+	walletRepo.On("Update", mock.Anything, wallet, entities.BalanceCauseBalanceSync).Return(nil)
+	reportRepo := &stubBalanceSyncReportRepository{}
+	useCase := NewBalanceSyncUseCase(walletRepo, &stubTransactionRepository{transactions: smallTransactions(wallet.ID)}, reportRepo, nil)
+
+	report, err := useCase.SyncAllWalletBalances(context.Background(), false)
+
+	assert.NoError(t, err)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, 1, report.Corrected)
+	if assert.Len(t, reportRepo.reports, 1) {
+		assert.Equal(t, reportRepo.reports[0].ID, *report.ID)
+		if assert.Len(t, reportRepo.reports[0].Entries, 1) {
+			assert.True(t, reportRepo.reports[0].Entries[0].Corrected)
+			assert.Equal(t, money.MustParse("299.99"), reportRepo.reports[0].Entries[0].StoredBalance)
+		}
+	}
+	walletRepo.AssertExpectations(t)
+}
+
+func TestSyncAllWalletBalances_WalksEveryBatch(t *testing.T) {
+	logger.Init("info")
+	var transactions []*entities.Transaction
+	var firstBatch []*entities.Wallet
+	for i := 0; i < balanceSyncBatchSize; i++ {
+		wallet := &entities.Wallet{ID: uuid.New(), Balance: money.FromInt(10)}
+		transactions = append(transactions, &entities.Transaction{WalletID: wallet.ID, Type: entities.TransactionTypeIncome, Cost: money.FromInt(10)})
+		firstBatch = append(firstBatch, wallet)
+	}
+	lastWallet := &entities.Wallet{ID: uuid.New(), Balance: money.FromInt(5)} // No transactions, should be 0
+
+	walletRepo := new(MockWalletRepository)
+	walletRepo.On("Count", mock.Anything).Return(int64(balanceSyncBatchSize+1), nil)
+	walletRepo.On("GetBatchAfter", mock.Anything, uuid.Nil, balanceSyncBatchSize).Return(firstBatch, nil).Once()
+	walletRepo.On("GetBatchAfter", mock.Anything, firstBatch[len(firstBatch)-1].ID, balanceSyncBatchSize).Return([]*entities.Wallet{lastWallet}, nil).Once()
+	walletRepo.On("GetBatchAfter", mock.Anything, lastWallet.ID, balanceSyncBatchSize).Return([]*entities.Wallet{}, nil).Once()
+	walletRepo.On("Update", mock.Anything, lastWallet, entities.BalanceCauseBalanceSync).Return(nil).Once()
+	useCase := NewBalanceSyncUseCase(walletRepo, &stubTransactionRepository{transactions: transactions}, &stubBalanceSyncReportRepository{}, nil)
+
+	report, err := useCase.SyncAllWalletBalances(context.Background(), false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, balanceSyncBatchSize+1, report.Wallets)
+	assert.Equal(t, 1, report.Corrected)
+	assert.True(t, lastWallet.Balance.IsZero())
+	progress := useCase.GetSyncProgress()
+	assert.False(t, progress.Running)
+	assert.Equal(t, balanceSyncBatchSize+1, progress.Processed)
+	walletRepo.AssertExpectations(t)
+}
+
+func TestSyncAllWalletBalances_StopsWhenCancelled(t *testing.T) {
+	logger.Init("info")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	walletRepo := new(MockWalletRepository)
+	walletRepo.On("Count", mock.Anything).Return(int64(1), nil)
+	reportRepo := &stubBalanceSyncReportRepository{}
+	useCase := NewBalanceSyncUseCase(walletRepo, &stubTransactionRepository{}, reportRepo, nil)
+
+	report, err := useCase.SyncAllWalletBalances(ctx, false)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, report)
+	assert.Empty(t, reportRepo.reports)
+	walletRepo.AssertNotCalled(t, "GetBatchAfter", mock.Anything, mock.Anything, mock.Anything)
+}
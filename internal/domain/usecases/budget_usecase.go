@@ -0,0 +1,539 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/mail"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// budgetAlertPageSize is the number of budgets loaded per page by the alert job
+const budgetAlertPageSize = 100
+
+type BudgetUseCaseInterface interface {
+	CreateBudget(ctx context.Context, req *dto.CreateBudgetRequest) (*dto.BudgetResponse, error)
+	GetBudget(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.BudgetResponse, error)
+	GetBudgets(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.BudgetResponse], error)
+	UpdateBudget(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, req *dto.UpdateBudgetRequest) (*dto.BudgetResponse, error)
+	DeleteBudget(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) error
+	GetBudgetProgress(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, at time.Time) (*dto.BudgetProgressResponse, error)
+	EvaluateBudgetAlerts(ctx context.Context, now time.Time) (*dto.BudgetAlertRunResponse, error)
+}
+
+type BudgetUseCase struct {
+	budgetRepo      repositories.BudgetRepository
+	budgetAlertRepo repositories.BudgetAlertRepository
+	transactionRepo repositories.TransactionRepository
+	walletRepo      repositories.WalletRepository
+	userRepo        repositories.UserRepository
+	db              *gorm.DB
+}
+
+func NewBudgetUseCase(
+	budgetRepo repositories.BudgetRepository,
+	budgetAlertRepo repositories.BudgetAlertRepository,
+	transactionRepo repositories.TransactionRepository,
+	walletRepo repositories.WalletRepository,
+	userRepo repositories.UserRepository,
+	db *gorm.DB,
+) BudgetUseCaseInterface {
+	return &BudgetUseCase{
+		budgetRepo:      budgetRepo,
+		budgetAlertRepo: budgetAlertRepo,
+		transactionRepo: transactionRepo,
+		walletRepo:      walletRepo,
+		userRepo:        userRepo,
+		db:              db,
+	}
+}
+
+func (uc *BudgetUseCase) CreateBudget(ctx context.Context, req *dto.CreateBudgetRequest) (*dto.BudgetResponse, error) {
+	funcCtx := "CreateBudget"
+
+	// Verify user exists
+	if _, err := uc.userRepo.GetByID(ctx, req.UserID); err != nil {
+		logger.LogError(funcCtx, "user not found", err, logrus.Fields{"user_id": req.UserID.String()})
+		return nil, helpers.NewNotFoundError("user not found", "")
+	}
+
+	if req.WalletID != nil {
+		if err := checkWalletOwnership(ctx, uc.walletRepo, funcCtx, *req.WalletID, req.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	budget := &entities.Budget{
+		Name:      req.Name,
+		UserID:    req.UserID,
+		WalletID:  req.WalletID,
+		TCategory: req.TCategory,
+		Period:    entities.BudgetPeriod(req.Period),
+		Amount:    req.Amount,
+		Rollover:  req.Rollover,
+		// Alerts are on by default
+		AlertsEnabled:   req.AlertsEnabled == nil || *req.AlertsEnabled,
+		AlertThresholds: entities.DefaultBudgetAlertThresholds,
+	}
+	if len(req.AlertThresholds) > 0 {
+		budget.SetAlertThresholds(req.AlertThresholds)
+	}
+
+	if err := uc.budgetRepo.Create(ctx, budget); err != nil {
+		logger.LogError(funcCtx, "failed to create budget", err, logrus.Fields{
+			"user_id":    req.UserID.String(),
+			"t_category": req.TCategory,
+		})
+		return nil, helpers.NewInternalError("failed to create budget", err.Error())
+	}
+
+	// Reload budget with relationships
+	createdBudget, err := uc.budgetRepo.GetByID(ctx, budget.ID)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to reload created budget", err, logrus.Fields{
+			"budget_id": budget.ID.String(),
+		})
+		return nil, helpers.NewInternalError("failed to reload created budget", err.Error())
+	}
+
+	return dto.MapToBudgetResponse(createdBudget), nil
+}
+
+func (uc *BudgetUseCase) GetBudget(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.BudgetResponse, error) {
+	budget, err := uc.getOwnedBudget(ctx, "GetBudget", id, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.MapToBudgetResponse(budget), nil
+}
+
+func (uc *BudgetUseCase) GetBudgets(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.BudgetResponse], error) {
+	funcCtx := "GetBudgets"
+
+	budgets, err := uc.budgetRepo.GetAll(ctx, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get budgets", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to get budgets", err.Error())
+	}
+
+	total, err := uc.budgetRepo.CountWithFilters(ctx, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to count budgets", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to count budgets", err.Error())
+	}
+
+	budgetResponses := make([]dto.BudgetResponse, len(budgets))
+	for i, budget := range budgets {
+		budgetResponses[i] = *dto.MapToBudgetResponse(budget)
+	}
+
+	paginationMeta := helpers.NewPaginationMeta(queryParams.Page, queryParams.Limit, total)
+
+	return &dto.PaginationData[dto.BudgetResponse]{
+		Data: budgetResponses,
+		Meta: paginationMeta,
+	}, nil
+}
+
+func (uc *BudgetUseCase) UpdateBudget(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, req *dto.UpdateBudgetRequest) (*dto.BudgetResponse, error) {
+	funcCtx := "UpdateBudget"
+
+	budget, err := uc.getOwnedBudget(ctx, funcCtx, id, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ClearWallet {
+		budget.WalletID = nil
+	} else if req.WalletID != nil {
+		if err := checkWalletOwnership(ctx, uc.walletRepo, funcCtx, *req.WalletID, budget.UserID); err != nil {
+			return nil, err
+		}
+		budget.WalletID = req.WalletID
+	}
+
+	// Update budget fields
+	if req.Name != "" {
+		budget.Name = req.Name
+	}
+	if req.TCategory != "" {
+		budget.TCategory = req.TCategory
+	}
+	if req.Period != "" {
+		budget.Period = entities.BudgetPeriod(req.Period)
+	}
+	if !req.Amount.IsZero() {
+		budget.Amount = req.Amount
+	}
+	if req.Rollover != nil {
+		budget.Rollover = *req.Rollover
+	}
+	if req.AlertsEnabled != nil {
+		budget.AlertsEnabled = *req.AlertsEnabled
+	}
+	if len(req.AlertThresholds) > 0 {
+		budget.SetAlertThresholds(req.AlertThresholds)
+	}
+
+	// Clear preloaded relation so Save doesn't write back the stale wallet
+	budget.Wallet = nil
+
+	if err := uc.budgetRepo.Update(ctx, budget); err != nil {
+		logger.LogError(funcCtx, "failed to update budget", err, logrus.Fields{
+			"budget_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to update budget", err.Error())
+	}
+
+	// Reload budget with relationships
+	updatedBudget, err := uc.budgetRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to reload updated budget", err, logrus.Fields{
+			"budget_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to reload updated budget", err.Error())
+	}
+
+	return dto.MapToBudgetResponse(updatedBudget), nil
+}
+
+func (uc *BudgetUseCase) DeleteBudget(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) error {
+	funcCtx := "DeleteBudget"
+
+	if _, err := uc.getOwnedBudget(ctx, funcCtx, id, loggedUserID); err != nil {
+		return err
+	}
+
+	if err := uc.budgetRepo.SoftDelete(ctx, id); err != nil {
+		logger.LogError(funcCtx, "failed to delete budget", err, logrus.Fields{
+			"budget_id": id.String(),
+		})
+		return helpers.NewInternalError("failed to delete budget", err.Error())
+	}
+
+	return nil
+}
+
+// GetBudgetProgress computes spent versus limit for the budget period containing at
+func (uc *BudgetUseCase) GetBudgetProgress(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, at time.Time) (*dto.BudgetProgressResponse, error) {
+	funcCtx := "GetBudgetProgress"
+
+	budget, err := uc.getOwnedBudget(ctx, funcCtx, id, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := computeBudgetProgress(ctx, uc.transactionRepo, budget, at)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to compute budget progress", err, logrus.Fields{
+			"budget_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to compute budget progress", err.Error())
+	}
+
+	return progress, nil
+}
+
+// EvaluateBudgetAlerts emails budget owners when spending crosses one of their alert thresholds.
+// Every budget is evaluated on its own so one failure doesn't block the others.
+func (uc *BudgetUseCase) EvaluateBudgetAlerts(ctx context.Context, now time.Time) (*dto.BudgetAlertRunResponse, error) {
+	funcCtx := "EvaluateBudgetAlerts"
+
+	result := &dto.BudgetAlertRunResponse{}
+	queryParams := &dto.QueryParams{
+		PaginationQuery: &dto.PaginationQuery{Page: 1, Limit: budgetAlertPageSize},
+		FilterQuery: &dto.FilterQuery{
+			SortBy:   "created_at",
+			SortType: "asc",
+			Filters:  map[string]string{"alerts_enabled": "true"},
+		},
+	}
+
+	for {
+		budgets, err := uc.budgetRepo.GetAll(ctx, queryParams)
+		if err != nil {
+			logger.LogError(funcCtx, "failed to get budgets", err, logrus.Fields{"page": queryParams.Page})
+			return result, helpers.NewInternalError("failed to get budgets", err.Error())
+		}
+
+		for _, budget := range budgets {
+			if err := ctx.Err(); err != nil {
+				logger.LogError(funcCtx, "budget alert evaluation cancelled", err, logrus.Fields{
+					"evaluated": result.Budgets,
+				})
+				return result, err
+			}
+
+			result.Budgets++
+			sent, err := uc.evaluateBudgetAlert(ctx, budget, now)
+			if err != nil {
+				result.Failed++
+				logger.LogError(funcCtx, "failed to evaluate budget alert", err, logrus.Fields{
+					"budget_id": budget.ID.String(),
+				})
+				continue
+			}
+			if sent {
+				result.AlertsSent++
+			}
+		}
+
+		if len(budgets) < budgetAlertPageSize {
+			break
+		}
+		queryParams.Page++
+	}
+
+	logger.LogSuccess(funcCtx, "Evaluated budget alerts", logrus.Fields{
+		"budgets":     result.Budgets,
+		"alerts_sent": result.AlertsSent,
+		"failed":      result.Failed,
+	})
+
+	return result, nil
+}
+
+// evaluateBudgetAlert sends at most one email for the highest newly crossed threshold.
+// Crossed thresholds are committed first with the email marked pending, and the email is sent once the DB transaction
+// is closed. A failed email stays pending and is retried by the next run, a sent one is never repeated within the
+// period. Runs never overlap (the job holds a lock), so a pending email is only picked up by one run.
+func (uc *BudgetUseCase) evaluateBudgetAlert(ctx context.Context, budget *entities.Budget, now time.Time) (bool, error) {
+	progress, err := computeBudgetProgress(ctx, uc.transactionRepo, budget, now)
+	if err != nil {
+		return false, err
+	}
+
+	sentThresholds, err := uc.budgetAlertRepo.GetSentThresholds(ctx, budget.ID, progress.PeriodStart)
+	if err != nil {
+		return false, err
+	}
+	alreadySent := make(map[int]bool, len(sentThresholds))
+	for _, threshold := range sentThresholds {
+		alreadySent[threshold] = true
+	}
+
+	// An overspent rollover can leave no limit at all, any spending then crosses every threshold
+	noLimitLeft := !progress.Limit.IsPositive() && progress.Spent.IsPositive()
+	var crossed []int
+	for _, threshold := range budget.GetAlertThresholds() {
+		if !alreadySent[threshold] && (noLimitLeft || progress.PercentUsed >= float64(threshold)) {
+			crossed = append(crossed, threshold)
+		}
+	}
+
+	if len(crossed) == 0 {
+		// Retry the email a previous run recorded but failed to send
+		pending, err := uc.budgetAlertRepo.GetPendingEmail(ctx, budget.ID, progress.PeriodStart)
+		if err != nil || pending == nil {
+			return false, err
+		}
+		return uc.sendPendingBudgetAlert(ctx, budget, progress, pending)
+	}
+
+	pending, created, err := uc.recordBudgetAlerts(ctx, budget, progress, crossed)
+	if err != nil || !created {
+		return false, err
+	}
+
+	return uc.sendPendingBudgetAlert(ctx, budget, progress, pending)
+}
+
+// recordBudgetAlerts commits the crossed thresholds of a budget period, the highest one owing the email and
+// superseding any email still pending for a lower threshold. It reports false when another run recorded the highest
+// threshold first and owns its email.
+func (uc *BudgetUseCase) recordBudgetAlerts(ctx context.Context, budget *entities.Budget, progress *dto.BudgetProgressResponse, crossed []int) (*entities.BudgetAlert, bool, error) {
+	highest := crossed[len(crossed)-1]
+
+	tx := uc.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	budgetAlertRepo := repositories.NewBudgetAlertRepository(tx)
+	if err := budgetAlertRepo.ClearPendingEmails(ctx, budget.ID, progress.PeriodStart); err != nil {
+		tx.Rollback()
+		return nil, false, err
+	}
+
+	var pending *entities.BudgetAlert
+	for _, threshold := range crossed {
+		alert := &entities.BudgetAlert{
+			BudgetID:     budget.ID,
+			UserID:       budget.UserID,
+			PeriodStart:  progress.PeriodStart,
+			Threshold:    threshold,
+			Spent:        progress.Spent,
+			Limit:        progress.Limit,
+			PercentUsed:  progress.PercentUsed,
+			EmailPending: threshold == highest,
+		}
+		created, err := budgetAlertRepo.CreateIfAbsent(ctx, alert)
+		if err != nil {
+			tx.Rollback()
+			return nil, false, err
+		}
+		if threshold == highest {
+			// Another run recorded it first and owns the email
+			if !created {
+				tx.Rollback()
+				return nil, false, nil
+			}
+			pending = alert
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, false, err
+	}
+
+	return pending, true, nil
+}
+
+// sendPendingBudgetAlert emails a recorded alert and marks it as emailed. If marking fails after the email went out,
+// the next run sends it again, an alert is emailed at least once.
+func (uc *BudgetUseCase) sendPendingBudgetAlert(ctx context.Context, budget *entities.Budget, progress *dto.BudgetProgressResponse, alert *entities.BudgetAlert) (bool, error) {
+	if err := uc.sendBudgetAlertEmail(ctx, budget, progress, alert.Threshold); err != nil {
+		return false, err
+	}
+
+	if err := uc.budgetAlertRepo.MarkEmailed(ctx, alert.ID, time.Now().UTC()); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// sendBudgetAlertEmail renders budget_alert.html and emails it to the budget owner
+func (uc *BudgetUseCase) sendBudgetAlertEmail(ctx context.Context, budget *entities.Budget, progress *dto.BudgetProgressResponse, threshold int) error {
+	user, err := uc.userRepo.GetByID(ctx, budget.UserID)
+	if err != nil {
+		return err
+	}
+
+	templateData := mail.BudgetAlertTemplateData{
+		Name:        user.Name,
+		BudgetName:  budget.Name,
+		Category:    budget.TCategory,
+		Threshold:   threshold,
+		PercentUsed: fmt.Sprintf("%.0f", progress.PercentUsed),
+		Spent:       progress.Spent.StringFixed(2),
+		Limit:       progress.Limit.StringFixed(2),
+		Remaining:   progress.Remaining.StringFixed(2),
+		PeriodStart: progress.PeriodStart.Format("2 Jan 2006"),
+		PeriodEnd:   progress.PeriodEnd.Format("2 Jan 2006"),
+		IsExceeded:  progress.IsExceeded,
+	}
+
+	htmlBody, err := mail.LoadTemplate("budget_alert.html", templateData)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("Budget Alert: %s reached %d%% - Finance Manager", budget.Name, threshold)
+	return mail.SendEmailWithTemplate(user.Email, subject, htmlBody)
+}
+
+// computeBudgetProgress sums the budget's expense transactions in the period containing at.
+// With rollover, the unused amount of every period since the budget was created is carried over.
+func computeBudgetProgress(ctx context.Context, transactionRepo repositories.TransactionRepository, budget *entities.Budget, at time.Time) (*dto.BudgetProgressResponse, error) {
+	periodStart := budget.PeriodStart(at)
+	periodEnd := budget.NextPeriodStart(periodStart)
+
+	periodQuery := budgetSpendingQuery(budget, periodStart, periodEnd)
+	spent, err := transactionRepo.SumCostWithFilters(ctx, periodQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := transactionRepo.CountWithFilters(ctx, periodQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	rolloverAmount := money.Zero
+	firstPeriodStart := budget.PeriodStart(budget.CreatedAt)
+	if budget.Rollover && periodStart.After(firstPeriodStart) {
+		spentBefore, err := transactionRepo.SumCostWithFilters(ctx, budgetSpendingQuery(budget, firstPeriodStart, periodStart))
+		if err != nil {
+			return nil, err
+		}
+		rolloverAmount = budget.Amount.Mul(int64(budget.PeriodsBetween(firstPeriodStart, periodStart))).Sub(spentBefore)
+	}
+
+	limit := budget.Amount.Add(rolloverAmount)
+	percentUsed := 0.0
+	if limit.IsPositive() {
+		percentUsed = spent.Float64() / limit.Float64() * 100
+	}
+
+	return &dto.BudgetProgressResponse{
+		BudgetID:         budget.ID,
+		TCategory:        budget.TCategory,
+		Period:           string(budget.Period),
+		PeriodStart:      periodStart,
+		PeriodEnd:        periodEnd.Add(-time.Microsecond),
+		Amount:           budget.Amount,
+		RolloverAmount:   rolloverAmount,
+		Limit:            limit,
+		Spent:            spent,
+		Remaining:        limit.Sub(spent),
+		PercentUsed:      percentUsed,
+		TransactionCount: count,
+		IsExceeded:       spent.Cmp(limit) > 0,
+	}, nil
+}
+
+// budgetSpendingQuery builds the transaction filters matching a budget's expenses in [from, to),
+// so spending is computed with the same category matching as the transaction list endpoint
+func budgetSpendingQuery(budget *entities.Budget, from, to time.Time) *dto.QueryParams {
+	filters := map[string]string{
+		"t_category":      budget.TCategory,
+		"type":            string(entities.TransactionTypeExpense),
+		"occurred_after":  from.Format(time.RFC3339Nano),
+		"occurred_before": to.Add(-time.Microsecond).Format(time.RFC3339Nano), // Postgres timestamps have microsecond precision
+	}
+	if budget.WalletID != nil {
+		filters["wallet_id"] = budget.WalletID.String()
+	}
+
+	return &dto.QueryParams{
+		PaginationQuery: &dto.PaginationQuery{},
+		FilterQuery:     &dto.FilterQuery{Filters: filters},
+		LoggedUserID:    budget.UserID,
+	}
+}
+
+// getOwnedBudget loads a budget and hides it from non-admin users who don't own it
+func (uc *BudgetUseCase) getOwnedBudget(ctx context.Context, funcCtx string, id uuid.UUID, loggedUserID uuid.UUID) (*entities.Budget, error) {
+	budget, err := uc.budgetRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get budget", err, logrus.Fields{
+			"budget_id": id.String(),
+		})
+		return nil, helpers.NewNotFoundError("budget not found", "")
+	}
+
+	if loggedUserID != uuid.Nil && loggedUserID != budget.UserID {
+		logger.LogError(funcCtx, "unauthorized access to budget", nil, logrus.Fields{
+			"budget_id":      id.String(),
+			"budget_user_id": budget.UserID.String(),
+			"logged_user_id": loggedUserID.String(),
+		})
+		return nil, helpers.NewNotFoundError("budget not found", "")
+	}
+
+	return budget, nil
+}
@@ -0,0 +1,405 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type CategoryUseCaseInterface interface {
+	CreateCategory(ctx context.Context, req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error)
+	GetCategory(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.CategoryResponse, error)
+	GetCategories(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.CategoryResponse], error)
+	UpdateCategory(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, req *dto.UpdateCategoryRequest) (*dto.CategoryResponse, error)
+	DeleteCategory(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) error // Only unused categories without subcategories can be deleted
+}
+
+type CategoryUseCase struct {
+	categoryRepo repositories.CategoryRepository
+	userRepo     repositories.UserRepository
+	db           *gorm.DB
+}
+
+func NewCategoryUseCase(
+	categoryRepo repositories.CategoryRepository,
+	userRepo repositories.UserRepository,
+	db *gorm.DB,
+) CategoryUseCaseInterface {
+	return &CategoryUseCase{
+		categoryRepo: categoryRepo,
+		userRepo:     userRepo,
+		db:           db,
+	}
+}
+
+func (uc *CategoryUseCase) CreateCategory(ctx context.Context, req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
+	funcCtx := "CreateCategory"
+
+	// Verify user exists
+	if _, err := uc.userRepo.GetByID(ctx, req.UserID); err != nil {
+		logger.LogError(funcCtx, "user not found", err, logrus.Fields{"user_id": req.UserID.String()})
+		return nil, helpers.NewNotFoundError("user not found", "")
+	}
+
+	category := &entities.Category{
+		UserID:   req.UserID,
+		ParentID: req.ParentID,
+		Name:     strings.TrimSpace(req.Name),
+		Type:     entities.TransactionType(req.Type),
+		Icon:     req.Icon,
+		Color:    strings.ToUpper(req.Color),
+	}
+
+	if category.ParentID != nil {
+		if _, err := uc.getParentCategory(ctx, funcCtx, *category.ParentID, category); err != nil {
+			return nil, err
+		}
+	}
+	if err := uc.checkUniqueName(ctx, funcCtx, category); err != nil {
+		return nil, err
+	}
+
+	created, err := uc.categoryRepo.CreateIfNotExists(ctx, category)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to create category", err, logrus.Fields{
+			"user_id": req.UserID.String(),
+			"name":    category.Name,
+		})
+		return nil, helpers.NewInternalError("failed to create category", err.Error())
+	}
+	if !created {
+		// A sibling with the same name was created since checkUniqueName
+		return nil, helpers.NewConflictError(fmt.Sprintf("category %q already exists", category.Name), "")
+	}
+
+	return dto.MapToCategoryResponse(category), nil
+}
+
+func (uc *CategoryUseCase) GetCategory(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.CategoryResponse, error) {
+	category, err := uc.getOwnedCategory(ctx, "GetCategory", id, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.MapToCategoryResponse(category), nil
+}
+
+func (uc *CategoryUseCase) GetCategories(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.CategoryResponse], error) {
+	funcCtx := "GetCategories"
+
+	categories, err := uc.categoryRepo.GetAll(ctx, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get categories", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to get categories", err.Error())
+	}
+
+	total, err := uc.categoryRepo.CountWithFilters(ctx, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to count categories", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to count categories", err.Error())
+	}
+
+	categoryResponses := make([]dto.CategoryResponse, len(categories))
+	for i, category := range categories {
+		categoryResponses[i] = *dto.MapToCategoryResponse(category)
+	}
+
+	paginationMeta := helpers.NewPaginationMeta(queryParams.Page, queryParams.Limit, total)
+
+	return &dto.PaginationData[dto.CategoryResponse]{
+		Data: categoryResponses,
+		Meta: paginationMeta,
+	}, nil
+}
+
+func (uc *CategoryUseCase) UpdateCategory(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, req *dto.UpdateCategoryRequest) (*dto.CategoryResponse, error) {
+	funcCtx := "UpdateCategory"
+
+	category, err := uc.getOwnedCategory(ctx, funcCtx, id, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	originalName := category.Name
+	originalParentID := category.ParentID
+
+	if req.ClearParent {
+		category.ParentID = nil
+	} else if req.ParentID != nil {
+		if _, err := uc.getParentCategory(ctx, funcCtx, *req.ParentID, category); err != nil {
+			return nil, err
+		}
+
+		// Moving a category under its own subtree would create a cycle
+		subtreeIDs, err := uc.categoryRepo.GetSubtreeIDs(ctx, category.ID)
+		if err != nil {
+			logger.LogError(funcCtx, "failed to get subcategories", err, logrus.Fields{"category_id": id.String()})
+			return nil, helpers.NewInternalError("failed to get subcategories", err.Error())
+		}
+		for _, subtreeID := range subtreeIDs {
+			if subtreeID == *req.ParentID {
+				return nil, helpers.NewBadRequestError("a category can't be moved under itself or one of its subcategories", "")
+			}
+		}
+		category.ParentID = req.ParentID
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		category.Name = name
+	}
+	if req.Icon != "" {
+		category.Icon = req.Icon
+	}
+	if req.Color != "" {
+		category.Color = strings.ToUpper(req.Color)
+	}
+	if req.IsArchived != nil {
+		category.IsArchived = *req.IsArchived
+	}
+
+	nameChanged := category.Name != originalName
+	parentChanged := (category.ParentID == nil) != (originalParentID == nil) ||
+		(category.ParentID != nil && *category.ParentID != *originalParentID)
+	if nameChanged || parentChanged {
+		if err := uc.checkUniqueName(ctx, funcCtx, category); err != nil {
+			return nil, err
+		}
+	}
+
+	// Start transaction so the category and the names copied onto its transactions change together
+	tx := uc.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	categoryRepo := repositories.NewCategoryRepository(tx)
+
+	if err := categoryRepo.Update(ctx, category); err != nil {
+		tx.Rollback()
+		logger.LogError(funcCtx, "failed to update category", err, logrus.Fields{
+			"category_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to update category", err.Error())
+	}
+
+	if nameChanged {
+		if err := categoryRepo.RenameTransactions(ctx, category.ID, category.Name); err != nil {
+			tx.Rollback()
+			logger.LogError(funcCtx, "failed to rename category of transactions", err, logrus.Fields{
+				"category_id": id.String(),
+			})
+			return nil, helpers.NewInternalError("failed to rename category of transactions", err.Error())
+		}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		logger.LogError(funcCtx, "failed to commit category update", err, logrus.Fields{
+			"category_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to commit category update", err.Error())
+	}
+
+	return dto.MapToCategoryResponse(category), nil
+}
+
+func (uc *CategoryUseCase) DeleteCategory(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) error {
+	funcCtx := "DeleteCategory"
+
+	if _, err := uc.getOwnedCategory(ctx, funcCtx, id, loggedUserID); err != nil {
+		return err
+	}
+
+	children, err := uc.categoryRepo.CountChildren(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to count subcategories", err, logrus.Fields{"category_id": id.String()})
+		return helpers.NewInternalError("failed to count subcategories", err.Error())
+	}
+	if children > 0 {
+		return helpers.NewConflictError("category has subcategories", "delete or move its subcategories first")
+	}
+
+	transactions, err := uc.categoryRepo.CountTransactions(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to count category transactions", err, logrus.Fields{"category_id": id.String()})
+		return helpers.NewInternalError("failed to count category transactions", err.Error())
+	}
+	if transactions > 0 {
+		return helpers.NewConflictError("category is used by transactions", "archive the category instead")
+	}
+
+	if err := uc.categoryRepo.SoftDelete(ctx, id); err != nil {
+		logger.LogError(funcCtx, "failed to delete category", err, logrus.Fields{
+			"category_id": id.String(),
+		})
+		return helpers.NewInternalError("failed to delete category", err.Error())
+	}
+
+	return nil
+}
+
+// getOwnedCategory loads a category and hides it from non-admin users who don't own it
+func (uc *CategoryUseCase) getOwnedCategory(ctx context.Context, funcCtx string, id uuid.UUID, loggedUserID uuid.UUID) (*entities.Category, error) {
+	category, err := uc.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get category", err, logrus.Fields{
+			"category_id": id.String(),
+		})
+		return nil, helpers.NewNotFoundError("category not found", "")
+	}
+
+	if loggedUserID != uuid.Nil && loggedUserID != category.UserID {
+		logger.LogError(funcCtx, "unauthorized access to category", nil, logrus.Fields{
+			"category_id":      id.String(),
+			"category_user_id": category.UserID.String(),
+			"logged_user_id":   loggedUserID.String(),
+		})
+		return nil, helpers.NewNotFoundError("category not found", "")
+	}
+
+	return category, nil
+}
+
+// getParentCategory loads the parent of category and checks it belongs to the same user and type
+func (uc *CategoryUseCase) getParentCategory(ctx context.Context, funcCtx string, parentID uuid.UUID, category *entities.Category) (*entities.Category, error) {
+	parent, err := uc.categoryRepo.GetByID(ctx, parentID)
+	if err != nil || parent.UserID != category.UserID {
+		logger.LogError(funcCtx, "parent category not found", err, logrus.Fields{
+			"parent_id": parentID.String(),
+			"user_id":   category.UserID.String(),
+		})
+		return nil, helpers.NewNotFoundError("parent category not found", "")
+	}
+
+	if parent.Type != category.Type {
+		return nil, helpers.NewBadRequestError(
+			fmt.Sprintf("parent category is an %s category, expected %s", parent.Type, category.Type), "")
+	}
+
+	return parent, nil
+}
+
+// checkUniqueName rejects a category whose name is already used by a sibling of the same type
+func (uc *CategoryUseCase) checkUniqueName(ctx context.Context, funcCtx string, category *entities.Category) error {
+	existing, err := uc.categoryRepo.GetSibling(ctx, category.UserID, category.Type, category.ParentID, category.Name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		logger.LogError(funcCtx, "failed to check category name", err, logrus.Fields{
+			"user_id": category.UserID.String(),
+			"name":    category.Name,
+		})
+		return helpers.NewInternalError("failed to check category name", err.Error())
+	}
+	if existing.ID != category.ID {
+		logger.LogError(funcCtx, "category name already used", nil, logrus.Fields{
+			"user_id":     category.UserID.String(),
+			"name":        category.Name,
+			"existing_id": existing.ID.String(),
+		})
+		return helpers.NewConflictError(fmt.Sprintf("category %q already exists", category.Name), "")
+	}
+	return nil
+}
+
+// assignCategory sets the category of a transaction and copies its name to t_category.
+// An explicit categoryID must belong to the transaction user, match its type and not be archived.
+// Otherwise the category is looked up by the t_category name and created at the top level when missing,
+// which is how imports and recurring templates that only know a name are categorised.
+func assignCategory(ctx context.Context, categoryRepo repositories.CategoryRepository, funcCtx string, transaction *entities.Transaction, categoryID *uuid.UUID) error {
+	category, err := resolveCategory(ctx, categoryRepo, funcCtx, transaction.UserID, transaction.Type, categoryID, transaction.TCategory)
+	if err != nil {
+		return err
+	}
+
+	transaction.CategoryID = &category.ID
+	transaction.TCategory = category.Name
+	return nil
+}
+
+// resolveCategory returns the category a transaction or split line of userID and categoryType refers to,
+// either by categoryID or by name, following the rules of assignCategory
+func resolveCategory(ctx context.Context, categoryRepo repositories.CategoryRepository, funcCtx string, userID uuid.UUID, categoryType entities.TransactionType, categoryID *uuid.UUID, name string) (*entities.Category, error) {
+	if categoryID != nil {
+		category, err := categoryRepo.GetByID(ctx, *categoryID)
+		if err != nil || category.UserID != userID {
+			logger.LogError(funcCtx, "category not found", err, logrus.Fields{
+				"category_id": categoryID.String(),
+				"user_id":     userID.String(),
+			})
+			return nil, helpers.NewNotFoundError("category not found", "")
+		}
+		if category.Type != categoryType {
+			return nil, helpers.NewBadRequestError(
+				fmt.Sprintf("category %q is an %s category but the transaction is an %s", category.Name, category.Type, categoryType), "")
+		}
+		if category.IsArchived {
+			return nil, helpers.NewBadRequestError(fmt.Sprintf("category %q is archived", category.Name), "")
+		}
+		return category, nil
+	}
+
+	name = strings.TrimSpace(name)
+	category, err := findCategoryByName(ctx, categoryRepo, funcCtx, userID, categoryType, name)
+	if err != nil || category != nil {
+		return category, err
+	}
+
+	// Another request may create the same category concurrently, the unique index keeps only one of them
+	category = &entities.Category{
+		UserID: userID,
+		Name:   name,
+		Type:   categoryType,
+	}
+	created, err := categoryRepo.CreateIfNotExists(ctx, category)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to create category", err, logrus.Fields{
+			"user_id": userID.String(),
+			"name":    name,
+		})
+		return nil, helpers.NewInternalError("failed to create category", err.Error())
+	}
+	if created {
+		return category, nil
+	}
+
+	category, err = findCategoryByName(ctx, categoryRepo, funcCtx, userID, categoryType, name)
+	if err == nil && category == nil {
+		err = helpers.NewInternalError("failed to create category", fmt.Sprintf("category %q was neither created nor found", name))
+	}
+	return category, err
+}
+
+// findCategoryByName returns the category of userID and categoryType named name, or nil when there is none.
+// An archived category is an error, it must be restored or renamed before transactions use its name again.
+func findCategoryByName(ctx context.Context, categoryRepo repositories.CategoryRepository, funcCtx string, userID uuid.UUID, categoryType entities.TransactionType, name string) (*entities.Category, error) {
+	category, err := categoryRepo.GetByName(ctx, userID, categoryType, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get category", err, logrus.Fields{
+			"user_id": userID.String(),
+			"name":    name,
+		})
+		return nil, helpers.NewInternalError("failed to get category", err.Error())
+	}
+	if category.IsArchived {
+		return nil, helpers.NewBadRequestError(fmt.Sprintf("category %q is archived", category.Name), "")
+	}
+	return category, nil
+}
@@ -0,0 +1,143 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// memoryCategoryRepository keeps categories in memory. A name created by another request between the lookup
+// and the insert can be simulated with concurrent, which is added on the first CreateIfNotExists.
+type memoryCategoryRepository struct {
+	repositories.CategoryRepository
+	categories []*entities.Category
+	concurrent *entities.Category
+	getErr     error
+	created    int
+}
+
+func (r *memoryCategoryRepository) GetByName(ctx context.Context, userID uuid.UUID, categoryType entities.TransactionType, name string) (*entities.Category, error) {
+	if r.getErr != nil {
+		return nil, r.getErr
+	}
+	for _, category := range r.categories {
+		if category.UserID == userID && category.Type == categoryType && strings.EqualFold(category.Name, name) {
+			return category, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryCategoryRepository) GetSibling(ctx context.Context, userID uuid.UUID, categoryType entities.TransactionType, parentID *uuid.UUID, name string) (*entities.Category, error) {
+	return r.GetByName(ctx, userID, categoryType, name)
+}
+
+func (r *memoryCategoryRepository) CreateIfNotExists(ctx context.Context, category *entities.Category) (bool, error) {
+	if r.concurrent != nil {
+		r.categories = append(r.categories, r.concurrent)
+		r.concurrent = nil
+		return false, nil
+	}
+	category.ID = uuid.New()
+	r.categories = append(r.categories, category)
+	r.created++
+	return true, nil
+}
+
+func TestResolveCategory_ByName(t *testing.T) {
+	logger.Init("info")
+	userID := uuid.New()
+	groceries := &entities.Category{ID: uuid.New(), UserID: userID, Name: "Groceries", Type: entities.TransactionTypeExpense}
+	archived := &entities.Category{ID: uuid.New(), UserID: userID, Name: "Old Gym", Type: entities.TransactionTypeExpense, IsArchived: true}
+
+	tests := []struct {
+		name        string
+		repo        *memoryCategoryRepository
+		category    string
+		wantID      *uuid.UUID
+		wantCreated int
+		wantErr     helpers.ErrorType
+	}{
+		{
+			name:     "existing category matched case-insensitively",
+			repo:     &memoryCategoryRepository{categories: []*entities.Category{groceries}},
+			category: " groceries ",
+			wantID:   &groceries.ID,
+		},
+		{
+			name:        "missing category is created",
+			repo:        &memoryCategoryRepository{categories: []*entities.Category{groceries}},
+			category:    "Coffee",
+			wantCreated: 1,
+		},
+		{
+			name:     "category created concurrently is reused",
+			repo:     &memoryCategoryRepository{concurrent: groceries},
+			category: "Groceries",
+			wantID:   &groceries.ID,
+		},
+		{
+			name:     "archived category is rejected",
+			repo:     &memoryCategoryRepository{categories: []*entities.Category{archived}},
+			category: "old gym",
+			wantErr:  helpers.ErrorTypeBadRequest,
+		},
+		{
+			name:     "database error is not treated as missing",
+			repo:     &memoryCategoryRepository{getErr: errors.New("connection refused")},
+			category: "Groceries",
+			wantErr:  helpers.ErrorTypeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, err := resolveCategory(context.Background(), tt.repo, "test", userID, entities.TransactionTypeExpense, nil, tt.category)
+
+			if tt.wantErr != "" {
+				var appErr *helpers.AppError
+				require.ErrorAs(t, err, &appErr)
+				assert.Equal(t, tt.wantErr, appErr.Type)
+				assert.Zero(t, tt.repo.created)
+				return
+			}
+
+			require.NoError(t, err)
+			if tt.wantID != nil {
+				assert.Equal(t, *tt.wantID, category.ID)
+			}
+			assert.Equal(t, tt.wantCreated, tt.repo.created)
+		})
+	}
+}
+
+func TestCheckUniqueName(t *testing.T) {
+	logger.Init("info")
+	userID := uuid.New()
+	existing := &entities.Category{ID: uuid.New(), UserID: userID, Name: "Groceries", Type: entities.TransactionTypeExpense}
+
+	uc := &CategoryUseCase{categoryRepo: &memoryCategoryRepository{categories: []*entities.Category{existing}}}
+
+	// Renaming a category to its own name is allowed
+	assert.NoError(t, uc.checkUniqueName(context.Background(), "test", existing))
+
+	var appErr *helpers.AppError
+	err := uc.checkUniqueName(context.Background(), "test", &entities.Category{UserID: userID, Name: "GROCERIES", Type: entities.TransactionTypeExpense})
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, helpers.ErrorTypeConflict, appErr.Type)
+
+	uc.categoryRepo = &memoryCategoryRepository{getErr: errors.New("connection refused")}
+	err = uc.checkUniqueName(context.Background(), "test", &entities.Category{UserID: userID, Name: "Coffee", Type: entities.TransactionTypeExpense})
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, helpers.ErrorTypeInternal, appErr.Type)
+}
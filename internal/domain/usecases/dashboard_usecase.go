@@ -2,32 +2,55 @@ package usecases
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/currency"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
 	"github.com/sirupsen/logrus"
 )
 
 type DashboardUseCaseInterface interface {
-	GetMonthlySumByUser(ctx context.Context, id uuid.UUID) (interface{}, error)
+	GetMonthlySumByUser(ctx context.Context, id uuid.UUID, targetCurrency string) (interface{}, error)
+	GetBalanceSummary(ctx context.Context, id uuid.UUID, targetCurrency string, date time.Time) (*dto.BalanceSummaryResponse, error)
+	GetTagSummary(ctx context.Context, id uuid.UUID, targetCurrency string, from, to time.Time) (*dto.TagSummaryResponse, error)
 }
 
 type DashboardUseCase struct {
-	dashboardRepo repositories.DashboardRepository
+	dashboardRepo    repositories.DashboardRepository
+	exchangeRateRepo repositories.ExchangeRateRepository
 }
 
-func NewDashboardUseCase(dashboardRepo repositories.DashboardRepository) DashboardUseCaseInterface {
+func NewDashboardUseCase(dashboardRepo repositories.DashboardRepository, exchangeRateRepo repositories.ExchangeRateRepository) DashboardUseCaseInterface {
 	return &DashboardUseCase{
-		dashboardRepo: dashboardRepo,
+		dashboardRepo:    dashboardRepo,
+		exchangeRateRepo: exchangeRateRepo,
 	}
 }
 
-func (uc *DashboardUseCase) GetMonthlySumByUser(ctx context.Context, id uuid.UUID) (interface{}, error) {
+// GetMonthlySumByUser returns the monthly totals per wallet. When targetCurrency is set the totals
+// are converted into it with the rate of each transaction date and summed across wallets.
+func (uc *DashboardUseCase) GetMonthlySumByUser(ctx context.Context, id uuid.UUID, targetCurrency string) (interface{}, error) {
 	funcCtx := "GetMonthlySumByUser"
 
-	dashboardData, err := uc.dashboardRepo.GetMonthlySumByUser(ctx, id)
+	if targetCurrency == "" {
+		dashboardData, err := uc.dashboardRepo.GetMonthlySumByUser(ctx, id)
+		if err != nil {
+			logger.LogError(funcCtx, "failed to get dashboard data", err, logrus.Fields{
+				"dashboard_id": id.String(),
+			})
+			return nil, helpers.NewNotFoundError("dashboard data not found", "")
+		}
+
+		return dashboardData, nil
+	}
+
+	dailySums, err := uc.dashboardRepo.GetDailySumByUser(ctx, id)
 	if err != nil {
 		logger.LogError(funcCtx, "failed to get dashboard data", err, logrus.Fields{
 			"dashboard_id": id.String(),
@@ -35,5 +58,165 @@ func (uc *DashboardUseCase) GetMonthlySumByUser(ctx context.Context, id uuid.UUI
 		return nil, helpers.NewNotFoundError("dashboard data not found", "")
 	}
 
-	return dashboardData, nil
+	currencies := []string{targetCurrency}
+	var until time.Time
+	for _, sum := range dailySums {
+		sum.Currency = currency.Normalize(sum.Currency)
+		currencies = append(currencies, sum.Currency)
+		if sum.Day.After(until) {
+			until = sum.Day
+		}
+	}
+
+	rates, err := loadRateTable(ctx, uc.exchangeRateRepo, currencies, until)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get exchange rates", err, logrus.Fields{"currency": targetCurrency})
+		return nil, helpers.NewInternalError("failed to get exchange rates", err.Error())
+	}
+
+	// Rows come grouped by month, newest first, so months and wallets keep their first-seen order
+	result := &dto.MonthlySummaryResponse{Currency: targetCurrency, Months: []dto.MonthlySummaryMonth{}}
+	monthIndex := make(map[string]int)
+	walletIndex := make(map[string]map[uuid.UUID]int)
+	for _, sum := range dailySums {
+		converted, err := rates.convert(sum.TotalCost, sum.Currency, targetCurrency, sum.Day)
+		if err != nil {
+			logger.LogError(funcCtx, "failed to convert amount", err, logrus.Fields{"dashboard_id": id.String()})
+			return nil, conversionError(err, "missing exchange rate")
+		}
+
+		mi, ok := monthIndex[sum.Month]
+		if !ok {
+			mi = len(result.Months)
+			monthIndex[sum.Month] = mi
+			walletIndex[sum.Month] = make(map[uuid.UUID]int)
+			result.Months = append(result.Months, dto.MonthlySummaryMonth{Month: sum.Month, Wallets: []dto.MonthlySummaryWallet{}})
+		}
+		month := &result.Months[mi]
+		month.TransactionCount += sum.TransactionCount
+		month.TotalCost = month.TotalCost.Add(converted)
+
+		wi, ok := walletIndex[sum.Month][sum.WalletID]
+		if !ok {
+			wi = len(month.Wallets)
+			walletIndex[sum.Month][sum.WalletID] = wi
+			month.Wallets = append(month.Wallets, dto.MonthlySummaryWallet{WalletID: sum.WalletID, WalletCurrency: sum.Currency})
+		}
+		wallet := &month.Wallets[wi]
+		wallet.TransactionCount += sum.TransactionCount
+		wallet.OriginalTotalCost = wallet.OriginalTotalCost.Add(sum.TotalCost)
+		wallet.TotalCost = wallet.TotalCost.Add(converted)
+	}
+
+	return result, nil
+}
+
+// GetBalanceSummary returns the balance of every wallet of a user converted into targetCurrency
+// with the latest rates dated on or before date
+func (uc *DashboardUseCase) GetBalanceSummary(ctx context.Context, id uuid.UUID, targetCurrency string, date time.Time) (*dto.BalanceSummaryResponse, error) {
+	funcCtx := "GetBalanceSummary"
+
+	wallets, err := uc.dashboardRepo.GetWalletsByUser(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get wallets", err, logrus.Fields{"user_id": id.String()})
+		return nil, helpers.NewInternalError("failed to get wallets", err.Error())
+	}
+
+	currencies := []string{targetCurrency}
+	for _, wallet := range wallets {
+		currencies = append(currencies, currency.Normalize(wallet.Currency))
+	}
+
+	rates, err := loadRateTable(ctx, uc.exchangeRateRepo, currencies, date)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get exchange rates", err, logrus.Fields{"currency": targetCurrency})
+		return nil, helpers.NewInternalError("failed to get exchange rates", err.Error())
+	}
+
+	result := &dto.BalanceSummaryResponse{
+		Currency:     targetCurrency,
+		Date:         date.Format(currency.RateDateFormat),
+		TotalBalance: money.Zero,
+		Wallets:      make([]dto.BalanceSummaryWallet, len(wallets)),
+	}
+	for i, wallet := range wallets {
+		walletCurrency := currency.Normalize(wallet.Currency)
+		converted, err := rates.convert(wallet.Balance, walletCurrency, targetCurrency, date)
+		if err != nil {
+			logger.LogError(funcCtx, "failed to convert amount", err, logrus.Fields{"user_id": id.String()})
+			return nil, conversionError(err, "missing exchange rate")
+		}
+
+		result.TotalBalance = result.TotalBalance.Add(converted)
+		result.Wallets[i] = dto.BalanceSummaryWallet{
+			WalletID:         wallet.ID,
+			Name:             wallet.Name,
+			WalletCurrency:   walletCurrency,
+			Balance:          wallet.Balance,
+			ConvertedBalance: converted,
+		}
+	}
+
+	return result, nil
+}
+
+// GetTagSummary returns the income and expense of every tag of a user between from and to, both inclusive,
+// converted into targetCurrency with the rate of each transaction date
+func (uc *DashboardUseCase) GetTagSummary(ctx context.Context, id uuid.UUID, targetCurrency string, from, to time.Time) (*dto.TagSummaryResponse, error) {
+	funcCtx := "GetTagSummary"
+
+	if to.Before(from) {
+		return nil, helpers.NewBadRequestError("end_date must not be before start_date", "")
+	}
+
+	dailySums, err := uc.dashboardRepo.GetDailyTagSumByUser(ctx, id, from, to)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get tag summary", err, logrus.Fields{"user_id": id.String()})
+		return nil, helpers.NewInternalError("failed to get tag summary", err.Error())
+	}
+
+	currencies := []string{targetCurrency}
+	for _, sum := range dailySums {
+		sum.Currency = currency.Normalize(sum.Currency)
+		currencies = append(currencies, sum.Currency)
+	}
+
+	rates, err := loadRateTable(ctx, uc.exchangeRateRepo, currencies, to)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get exchange rates", err, logrus.Fields{"currency": targetCurrency})
+		return nil, helpers.NewInternalError("failed to get exchange rates", err.Error())
+	}
+
+	// Rows come ordered by tag name, so tags keep their first-seen order
+	result := &dto.TagSummaryResponse{
+		Currency:  targetCurrency,
+		StartDate: from.Format(currency.RateDateFormat),
+		EndDate:   to.Format(currency.RateDateFormat),
+		Tags:      []dto.TagSummaryTag{},
+	}
+	tagIndex := make(map[uuid.UUID]int)
+	for _, sum := range dailySums {
+		converted, err := rates.convert(sum.TotalCost, sum.Currency, targetCurrency, sum.Day)
+		if err != nil {
+			logger.LogError(funcCtx, "failed to convert amount", err, logrus.Fields{"user_id": id.String()})
+			return nil, conversionError(err, "missing exchange rate")
+		}
+
+		ti, ok := tagIndex[sum.TagID]
+		if !ok {
+			ti = len(result.Tags)
+			tagIndex[sum.TagID] = ti
+			result.Tags = append(result.Tags, dto.TagSummaryTag{TagID: sum.TagID, Name: sum.Name})
+		}
+		tag := &result.Tags[ti]
+		tag.TransactionCount += sum.TransactionCount
+		if sum.Type == entities.TransactionTypeIncome {
+			tag.Income = tag.Income.Add(converted)
+		} else {
+			tag.Expense = tag.Expense.Add(converted)
+		}
+		tag.Net = tag.Income.Sub(tag.Expense)
+	}
+
+	return result, nil
 }
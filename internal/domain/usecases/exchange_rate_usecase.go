@@ -0,0 +1,292 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/currency"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+	"github.com/sirupsen/logrus"
+)
+
+type ExchangeRateUseCaseInterface interface {
+	GetExchangeRates(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.ExchangeRateResponse], error)
+	UpsertExchangeRates(ctx context.Context, req *dto.UpsertExchangeRatesRequest) (*dto.ExchangeRateLoadResponse, error)
+	ImportExchangeRates(ctx context.Context, req *dto.ImportExchangeRatesRequest) (*dto.ExchangeRateLoadResponse, error)
+	LoadExchangeRatesFile(ctx context.Context, path string) (*dto.ExchangeRateLoadResponse, error)
+}
+
+type ExchangeRateUseCase struct {
+	exchangeRateRepo repositories.ExchangeRateRepository
+}
+
+func NewExchangeRateUseCase(exchangeRateRepo repositories.ExchangeRateRepository) ExchangeRateUseCaseInterface {
+	return &ExchangeRateUseCase{
+		exchangeRateRepo: exchangeRateRepo,
+	}
+}
+
+func (uc *ExchangeRateUseCase) GetExchangeRates(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.ExchangeRateResponse], error) {
+	funcCtx := "GetExchangeRates"
+
+	rates, err := uc.exchangeRateRepo.GetAll(ctx, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get exchange rates", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to get exchange rates", err.Error())
+	}
+
+	total, err := uc.exchangeRateRepo.CountWithFilters(ctx, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to count exchange rates", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to count exchange rates", err.Error())
+	}
+
+	rateResponses := make([]dto.ExchangeRateResponse, len(rates))
+	for i, rate := range rates {
+		rateResponses[i] = *dto.MapToExchangeRateResponse(rate)
+	}
+
+	paginationMeta := helpers.NewPaginationMeta(queryParams.Page, queryParams.Limit, total)
+
+	return &dto.PaginationData[dto.ExchangeRateResponse]{
+		Data: rateResponses,
+		Meta: paginationMeta,
+	}, nil
+}
+
+// UpsertExchangeRates stores rates sent by an admin, replacing rates already stored for the same day and pair
+func (uc *ExchangeRateUseCase) UpsertExchangeRates(ctx context.Context, req *dto.UpsertExchangeRatesRequest) (*dto.ExchangeRateLoadResponse, error) {
+	funcCtx := "UpsertExchangeRates"
+
+	rates := make([]*entities.ExchangeRate, len(req.Rates))
+	seen := make(map[currency.RateKey]int, len(req.Rates))
+	for i, input := range req.Rates {
+		if input.Rate.IsZero() {
+			return nil, helpers.NewValidationError("Validation failed", fmt.Sprintf("rates[%d]: rate must be a positive decimal", i))
+		}
+
+		date, err := time.Parse(currency.RateDateFormat, input.Date)
+		if err != nil {
+			return nil, helpers.NewValidationError("Validation failed", fmt.Sprintf("rates[%d]: invalid date", i))
+		}
+
+		rates[i] = &entities.ExchangeRate{
+			Date:   date,
+			Base:   currency.Normalize(input.Base),
+			Quote:  currency.Normalize(input.Quote),
+			Rate:   input.Rate,
+			Source: entities.ExchangeRateSourceAdmin,
+		}
+
+		// A pair can only be stored once per day, Postgres rejects an upsert touching the same row twice
+		key := currency.RateKey{Date: date.Format(currency.RateDateFormat), Base: rates[i].Base, Quote: rates[i].Quote}
+		if first, ok := seen[key]; ok {
+			return nil, helpers.NewValidationError("Validation failed", fmt.Sprintf("rates[%d]: duplicate %s/%s rate on %s, already given in rates[%d]", i, key.Base, key.Quote, key.Date, first))
+		}
+		seen[key] = i
+	}
+
+	return uc.storeRates(ctx, funcCtx, rates)
+}
+
+// ImportExchangeRates stores the rates of an uploaded CSV file, see currency.ParseRatesCSV for the format
+func (uc *ExchangeRateUseCase) ImportExchangeRates(ctx context.Context, req *dto.ImportExchangeRatesRequest) (*dto.ExchangeRateLoadResponse, error) {
+	funcCtx := "ImportExchangeRates"
+
+	file, err := req.File.Open()
+	if err != nil {
+		logger.LogError(funcCtx, "failed to open uploaded file", err, logrus.Fields{"filename": req.File.Filename})
+		return nil, helpers.NewBadRequestError("failed to read uploaded file", err.Error())
+	}
+	defer file.Close()
+
+	records, err := currency.ParseRatesCSV(file)
+	if err != nil {
+		logger.LogError(funcCtx, "invalid exchange rate file", err, logrus.Fields{"filename": req.File.Filename})
+		return nil, helpers.NewBadRequestError("invalid exchange rate file", err.Error())
+	}
+
+	return uc.storeRates(ctx, funcCtx, ratesFromRecords(records, entities.ExchangeRateSourceAdmin))
+}
+
+// LoadExchangeRatesFile stores the rates of a local CSV file, used to seed the table at startup
+func (uc *ExchangeRateUseCase) LoadExchangeRatesFile(ctx context.Context, path string) (*dto.ExchangeRateLoadResponse, error) {
+	funcCtx := "LoadExchangeRatesFile"
+
+	file, err := os.Open(path)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to open exchange rate file", err, logrus.Fields{"path": path})
+		return nil, helpers.NewInternalError("failed to open exchange rate file", err.Error())
+	}
+	defer file.Close()
+
+	records, err := currency.ParseRatesCSV(file)
+	if err != nil {
+		logger.LogError(funcCtx, "invalid exchange rate file", err, logrus.Fields{"path": path})
+		return nil, helpers.NewBadRequestError("invalid exchange rate file", err.Error())
+	}
+
+	return uc.storeRates(ctx, funcCtx, ratesFromRecords(records, entities.ExchangeRateSourceFile))
+}
+
+func (uc *ExchangeRateUseCase) storeRates(ctx context.Context, funcCtx string, rates []*entities.ExchangeRate) (*dto.ExchangeRateLoadResponse, error) {
+	if err := uc.exchangeRateRepo.Upsert(ctx, rates); err != nil {
+		logger.LogError(funcCtx, "failed to store exchange rates", err, logrus.Fields{"count": len(rates)})
+		return nil, helpers.NewInternalError("failed to store exchange rates", err.Error())
+	}
+
+	result := &dto.ExchangeRateLoadResponse{Loaded: len(rates)}
+	for i, rate := range rates {
+		date := rate.Date.Format(currency.RateDateFormat)
+		if i == 0 || date < result.DateFrom {
+			result.DateFrom = date
+		}
+		if date > result.DateTo {
+			result.DateTo = date
+		}
+	}
+	return result, nil
+}
+
+func ratesFromRecords(records []currency.RateRecord, source string) []*entities.ExchangeRate {
+	rates := make([]*entities.ExchangeRate, len(records))
+	for i, record := range records {
+		rates[i] = &entities.ExchangeRate{
+			Date:   record.Date,
+			Base:   record.Base,
+			Quote:  record.Quote,
+			Rate:   record.Rate,
+			Source: source,
+		}
+	}
+	return rates
+}
+
+// datedRate is a rate from one currency to another, valid from date until the next rate of the pair
+type datedRate struct {
+	date time.Time
+	rate money.Rate
+}
+
+// rateTable converts amounts between currencies with the rate in force on a given day.
+// Each stored rate is indexed in both directions, so USD->IDR also answers IDR->USD.
+type rateTable struct {
+	pairs map[[2]string][]datedRate
+}
+
+// loadRateTable loads every rate touching one of currencies dated on or before until
+func loadRateTable(ctx context.Context, exchangeRateRepo repositories.ExchangeRateRepository, currencies []string, until time.Time) (*rateTable, error) {
+	rates, err := exchangeRateRepo.GetUpTo(ctx, currencies, until)
+	if err != nil {
+		return nil, err
+	}
+	return newRateTable(rates), nil
+}
+
+func newRateTable(rates []*entities.ExchangeRate) *rateTable {
+	table := &rateTable{pairs: make(map[[2]string][]datedRate)}
+	for _, rate := range rates {
+		date := rateDay(rate.Date)
+		table.add(rate.Base, rate.Quote, datedRate{date: date, rate: rate.Rate})
+		table.add(rate.Quote, rate.Base, datedRate{date: date, rate: rate.Rate.Invert()})
+	}
+	for _, entries := range table.pairs {
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].date.Before(entries[j].date) })
+	}
+	return table
+}
+
+func (t *rateTable) add(from, to string, entry datedRate) {
+	key := [2]string{from, to}
+	t.pairs[key] = append(t.pairs[key], entry)
+}
+
+// latest returns the most recent rate of a pair dated on or before day
+func (t *rateTable) latest(from, to string, day time.Time) (datedRate, bool) {
+	entries := t.pairs[[2]string{from, to}]
+	i := sort.Search(len(entries), func(i int) bool { return entries[i].date.After(day) })
+	if i == 0 {
+		return datedRate{}, false
+	}
+	return entries[i-1], true
+}
+
+// rate returns the rate from one currency to another in force on the day of on. When no rate
+// links both currencies directly it is crossed through a third one, preferring the freshest legs.
+func (t *rateTable) rate(from, to string, on time.Time) (money.Rate, error) {
+	if from == to {
+		return money.MustParseRate("1"), nil
+	}
+
+	day := rateDay(on)
+	if direct, ok := t.latest(from, to, day); ok {
+		return direct.rate, nil
+	}
+
+	var (
+		best      money.Rate
+		bestDate  time.Time
+		crossings []string
+	)
+	for key := range t.pairs {
+		if key[0] == from && key[1] != to {
+			crossings = append(crossings, key[1])
+		}
+	}
+	sort.Strings(crossings)
+	for _, via := range crossings {
+		first, ok := t.latest(from, via, day)
+		if !ok {
+			continue
+		}
+		second, ok := t.latest(via, to, day)
+		if !ok {
+			continue
+		}
+
+		// A cross rate is only as fresh as its oldest leg
+		date := first.date
+		if second.date.Before(date) {
+			date = second.date
+		}
+		if best.IsZero() || date.After(bestDate) {
+			best, bestDate = first.rate.Mul(second.rate), date
+		}
+	}
+	if best.IsZero() {
+		return money.Rate{}, fmt.Errorf("no exchange rate for %s to %s on or before %s", from, to, day.Format(currency.RateDateFormat))
+	}
+	return best, nil
+}
+
+// convert converts amount from one currency to another at the rate in force on the day of on
+func (t *rateTable) convert(amount money.Money, from, to string, on time.Time) (money.Money, error) {
+	rate, err := t.rate(from, to, on)
+	if err != nil {
+		return money.Zero, err
+	}
+	return amount.Convert(rate)
+}
+
+// conversionError maps an error of rateTable.convert to a bad request: an amount too large once converted, or
+// missingMessage when no rate links both currencies
+func conversionError(err error, missingMessage string) error {
+	if errors.Is(err, money.ErrRange) {
+		return helpers.NewBadRequestError("converted amount is out of range", err.Error())
+	}
+	return helpers.NewBadRequestError(missingMessage, err.Error())
+}
+
+// rateDay truncates t to its calendar day, so rates and transactions compare by date whatever their time zone
+func rateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,99 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rateOn(day, base, quote, rate string) *entities.ExchangeRate {
+	date, _ := time.Parse("2006-01-02", day)
+	return &entities.ExchangeRate{Date: date, Base: base, Quote: quote, Rate: money.MustParseRate(rate)}
+}
+
+func TestRateTable_UsesRateOfTransactionDate(t *testing.T) {
+	table := newRateTable([]*entities.ExchangeRate{
+		rateOn("2024-01-01", "USD", "IDR", "15000"),
+		rateOn("2024-02-01", "USD", "IDR", "16000"),
+	})
+
+	january := time.Date(2024, 1, 20, 23, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+	converted, err := table.convert(money.MustParse("10"), "USD", "IDR", january)
+	require.NoError(t, err)
+	assert.Equal(t, "150000", converted.String())
+
+	converted, err = table.convert(money.MustParse("10"), "USD", "IDR", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "160000", converted.String())
+
+	// Inverse direction of a stored pair
+	converted, err = table.convert(money.MustParse("32000"), "IDR", "USD", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "2", converted.String())
+}
+
+func TestRateTable_CrossRate(t *testing.T) {
+	table := newRateTable([]*entities.ExchangeRate{
+		rateOn("2024-01-01", "USD", "IDR", "15000"),
+		rateOn("2024-01-01", "USD", "EUR", "0.9"),
+	})
+
+	converted, err := table.convert(money.MustParse("1"), "EUR", "IDR", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "16666.66666667", converted.String())
+
+	converted, err = table.convert(money.MustParse("42.5"), "IDR", "IDR", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "42.5", converted.String())
+}
+
+func TestRateTable_MissingRate(t *testing.T) {
+	table := newRateTable([]*entities.ExchangeRate{
+		rateOn("2024-01-05", "USD", "IDR", "15000"),
+	})
+
+	_, err := table.convert(money.MustParse("1"), "USD", "IDR", time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC))
+	require.Error(t, err)
+	assert.Equal(t, "no exchange rate for USD to IDR on or before 2024-01-04", err.Error())
+
+	_, err = table.convert(money.MustParse("1"), "USD", "JPY", time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC))
+	assert.Error(t, err)
+}
+
+func TestRateTable_ConvertedAmountOutOfRange(t *testing.T) {
+	table := newRateTable([]*entities.ExchangeRate{
+		rateOn("2024-01-01", "BTC", "IDR", "1000000000"),
+	})
+
+	_, err := table.convert(money.MustParse("999999999999999999999999"), "BTC", "IDR", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	require.ErrorIs(t, err, money.ErrRange)
+
+	var appErr *helpers.AppError
+	require.ErrorAs(t, conversionError(err, "missing exchange rate"), &appErr)
+	assert.Equal(t, helpers.ErrorTypeBadRequest, appErr.Type)
+	assert.Equal(t, "converted amount is out of range", appErr.Message)
+}
+
+func TestUpsertExchangeRates_RejectsDuplicatePair(t *testing.T) {
+	uc := NewExchangeRateUseCase(nil)
+
+	_, err := uc.UpsertExchangeRates(context.Background(), &dto.UpsertExchangeRatesRequest{
+		Rates: []dto.ExchangeRateInput{
+			{Date: "2024-01-05", Base: "USD", Quote: "IDR", Rate: money.MustParseRate("15500")},
+			{Date: "2024-01-05", Base: "USD", Quote: "EUR", Rate: money.MustParseRate("0.91")},
+			{Date: "2024-01-05", Base: "usd", Quote: "idr", Rate: money.MustParseRate("15600")},
+		},
+	})
+
+	var appErr *helpers.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, helpers.ErrorTypeValidation, appErr.Type)
+	assert.Equal(t, "rates[2]: duplicate USD/IDR rate on 2024-01-05, already given in rates[0]", appErr.Details)
+}
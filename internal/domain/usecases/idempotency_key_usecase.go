@@ -0,0 +1,40 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type IdempotencyKeyUseCaseInterface interface {
+	PurgeExpiredKeys(ctx context.Context, now time.Time) (*dto.IdempotencyKeyPurgeResponse, error)
+}
+
+type IdempotencyKeyUseCase struct {
+	idempotencyKeyRepo repositories.IdempotencyKeyRepository
+}
+
+func NewIdempotencyKeyUseCase(idempotencyKeyRepo repositories.IdempotencyKeyRepository) IdempotencyKeyUseCaseInterface {
+	return &IdempotencyKeyUseCase{
+		idempotencyKeyRepo: idempotencyKeyRepo,
+	}
+}
+
+// PurgeExpiredKeys deletes the idempotency keys stored in Postgres that expired at now. Keys kept in Redis expire on
+// their own.
+func (uc *IdempotencyKeyUseCase) PurgeExpiredKeys(ctx context.Context, now time.Time) (*dto.IdempotencyKeyPurgeResponse, error) {
+	funcCtx := "IdempotencyKeyUseCase.PurgeExpiredKeys"
+
+	deleted, err := uc.idempotencyKeyRepo.DeleteExpired(ctx, now)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to delete expired idempotency keys", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to delete expired idempotency keys", err.Error())
+	}
+
+	return &dto.IdempotencyKeyPurgeResponse{Deleted: deleted}, nil
+}
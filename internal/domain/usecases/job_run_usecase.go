@@ -0,0 +1,162 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type JobRunUseCaseInterface interface {
+	StartRun(ctx context.Context, jobName string, trigger entities.JobTrigger) (*entities.JobRun, error)
+	FinishRun(ctx context.Context, run *entities.JobRun, result interface{}, jobErr error) error
+	GetJobRun(ctx context.Context, id uuid.UUID) (*dto.JobRunResponse, error)
+	GetJobRuns(ctx context.Context, jobName string, queryParams *dto.QueryParams) (*dto.PaginationData[dto.JobRunResponse], error)
+	GetJobStatus(ctx context.Context, jobName string, schedule string, nextRunAt *time.Time) (*dto.JobStatusResponse, error)
+}
+
+type JobRunUseCase struct {
+	jobRunRepo repositories.JobRunRepository
+}
+
+func NewJobRunUseCase(jobRunRepo repositories.JobRunRepository) JobRunUseCaseInterface {
+	return &JobRunUseCase{
+		jobRunRepo: jobRunRepo,
+	}
+}
+
+// StartRun records that a job started
+func (uc *JobRunUseCase) StartRun(ctx context.Context, jobName string, trigger entities.JobTrigger) (*entities.JobRun, error) {
+	funcCtx := "JobRunUseCase.StartRun"
+
+	run := &entities.JobRun{
+		JobName:   jobName,
+		Trigger:   trigger,
+		Status:    entities.JobRunStatusRunning,
+		StartedAt: time.Now().UTC(),
+	}
+
+	if err := uc.jobRunRepo.Create(ctx, run); err != nil {
+		logger.LogError(funcCtx, "failed to record job run", err, logrus.Fields{
+			"job_name": jobName,
+			"trigger":  trigger,
+		})
+		return nil, helpers.NewInternalError("failed to record job run", err.Error())
+	}
+
+	return run, nil
+}
+
+// FinishRun records the outcome of a job, result is stored as the JSON summary of the run
+func (uc *JobRunUseCase) FinishRun(ctx context.Context, run *entities.JobRun, result interface{}, jobErr error) error {
+	funcCtx := "JobRunUseCase.FinishRun"
+
+	var summary json.RawMessage
+	if result != nil {
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			logger.LogError(funcCtx, "failed to encode job run summary", err, logrus.Fields{
+				"job_run_id": run.ID.String(),
+			})
+		} else if string(encoded) != "null" {
+			summary = encoded
+		}
+	}
+
+	run.Finish(summary, jobErr)
+
+	if err := uc.jobRunRepo.Update(ctx, run); err != nil {
+		logger.LogError(funcCtx, "failed to record job run outcome", err, logrus.Fields{
+			"job_run_id": run.ID.String(),
+			"job_name":   run.JobName,
+			"status":     run.Status,
+		})
+		return helpers.NewInternalError("failed to record job run outcome", err.Error())
+	}
+
+	return nil
+}
+
+// GetJobRun returns a run with its status and, once finished, its result
+func (uc *JobRunUseCase) GetJobRun(ctx context.Context, id uuid.UUID) (*dto.JobRunResponse, error) {
+	funcCtx := "JobRunUseCase.GetJobRun"
+
+	run, err := uc.jobRunRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, helpers.NewNotFoundError("job run not found", id.String())
+		}
+		logger.LogError(funcCtx, "failed to get job run", err, logrus.Fields{
+			"job_run_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to get job run", err.Error())
+	}
+
+	return dto.MapToJobRunResponse(run), nil
+}
+
+// GetJobRuns lists the runs of a job, newest first
+func (uc *JobRunUseCase) GetJobRuns(ctx context.Context, jobName string, queryParams *dto.QueryParams) (*dto.PaginationData[dto.JobRunResponse], error) {
+	funcCtx := "JobRunUseCase.GetJobRuns"
+
+	runs, err := uc.jobRunRepo.GetByJobName(ctx, jobName, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get job runs", err, logrus.Fields{
+			"job_name": jobName,
+		})
+		return nil, helpers.NewInternalError("failed to get job runs", err.Error())
+	}
+
+	total, err := uc.jobRunRepo.CountByJobName(ctx, jobName)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to count job runs", err, logrus.Fields{
+			"job_name": jobName,
+		})
+		return nil, helpers.NewInternalError("failed to count job runs", err.Error())
+	}
+
+	runResponses := make([]dto.JobRunResponse, len(runs))
+	for i, run := range runs {
+		runResponses[i] = *dto.MapToJobRunResponse(run)
+	}
+
+	return &dto.PaginationData[dto.JobRunResponse]{
+		Data: runResponses,
+		Meta: helpers.NewPaginationMeta(queryParams.Page, queryParams.Limit, total),
+	}, nil
+}
+
+// GetJobStatus returns the schedule of a job with its last run and current failure streak
+func (uc *JobRunUseCase) GetJobStatus(ctx context.Context, jobName string, schedule string, nextRunAt *time.Time) (*dto.JobStatusResponse, error) {
+	funcCtx := "JobRunUseCase.GetJobStatus"
+
+	stats, err := uc.jobRunRepo.GetStats(ctx, jobName)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get job run stats", err, logrus.Fields{
+			"job_name": jobName,
+		})
+		return nil, helpers.NewInternalError("failed to get job run stats", err.Error())
+	}
+
+	status := &dto.JobStatusResponse{
+		Name:                jobName,
+		Schedule:            schedule,
+		NextRunAt:           nextRunAt,
+		LastSuccessAt:       stats.LastSuccessAt,
+		ConsecutiveFailures: stats.ConsecutiveFailures,
+	}
+	if stats.LastRun != nil {
+		status.LastRun = dto.MapToJobRunResponse(stats.LastRun)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,96 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubJobRunRepository records the saved runs, other methods are not used by the tests
+type stubJobRunRepository struct {
+	repositories.JobRunRepository
+	created []*entities.JobRun
+	updated []*entities.JobRun
+}
+
+func (r *stubJobRunRepository) Create(ctx context.Context, run *entities.JobRun) error {
+	r.created = append(r.created, run)
+	return nil
+}
+
+func (r *stubJobRunRepository) Update(ctx context.Context, run *entities.JobRun) error {
+	r.updated = append(r.updated, run)
+	return nil
+}
+
+func (r *stubJobRunRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.JobRun, error) {
+	for _, run := range r.created {
+		if run.ID == id {
+			return run, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestFinishRun_StoresSummaryOfSuccessfulRun(t *testing.T) {
+	logger.Init("info")
+	repo := &stubJobRunRepository{}
+	useCase := NewJobRunUseCase(repo)
+
+	run, err := useCase.StartRun(context.Background(), "budget_alerts", entities.JobTriggerManual)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.JobRunStatusRunning, run.Status)
+
+	err = useCase.FinishRun(context.Background(), run, &dto.BudgetAlertRunResponse{Budgets: 3, AlertsSent: 1}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, entities.JobRunStatusSucceeded, run.Status)
+	assert.NotNil(t, run.FinishedAt)
+	assert.JSONEq(t, `{"budgets":3,"alerts_sent":1,"failed":0}`, string(run.Summary))
+	assert.Len(t, repo.updated, 1)
+}
+
+func TestFinishRun_RecordsFailureWithoutSummary(t *testing.T) {
+	logger.Init("info")
+	repo := &stubJobRunRepository{}
+	useCase := NewJobRunUseCase(repo)
+	run, _ := useCase.StartRun(context.Background(), "budget_alerts", entities.JobTriggerScheduled)
+
+	var result *dto.BudgetAlertRunResponse
+	err := useCase.FinishRun(context.Background(), run, result, errors.New("smtp unavailable"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, entities.JobRunStatusFailed, run.Status)
+	assert.Equal(t, "smtp unavailable", run.Error)
+	assert.Nil(t, run.Summary)
+}
+
+func TestGetJobRun_ReturnsQueuedRunAndNotFound(t *testing.T) {
+	logger.Init("info")
+	repo := &stubJobRunRepository{}
+	useCase := NewJobRunUseCase(repo)
+	run, _ := useCase.StartRun(context.Background(), "balance_sync", entities.JobTriggerManual)
+	run.ID = uuid.New()
+
+	found, err := useCase.GetJobRun(context.Background(), run.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, run.ID, found.ID)
+	assert.Equal(t, string(entities.JobRunStatusRunning), found.Status)
+	assert.Equal(t, string(entities.JobTriggerManual), found.Trigger)
+
+	_, err = useCase.GetJobRun(context.Background(), uuid.New())
+
+	var appErr *helpers.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, helpers.ErrorTypeNotFound, appErr.Type)
+}
@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type JobSettingUseCaseInterface interface {
+	GetJobSettings(ctx context.Context) ([]*entities.JobSetting, error)
+	SaveJobSetting(ctx context.Context, jobName string, schedule string, timezone string, enabled bool) (*entities.JobSetting, error)
+	DeleteJobSetting(ctx context.Context, jobName string) error
+}
+
+type JobSettingUseCase struct {
+	jobSettingRepo repositories.JobSettingRepository
+}
+
+func NewJobSettingUseCase(jobSettingRepo repositories.JobSettingRepository) JobSettingUseCaseInterface {
+	return &JobSettingUseCase{
+		jobSettingRepo: jobSettingRepo,
+	}
+}
+
+// GetJobSettings returns the job schedules changed at runtime
+func (uc *JobSettingUseCase) GetJobSettings(ctx context.Context) ([]*entities.JobSetting, error) {
+	funcCtx := "JobSettingUseCase.GetJobSettings"
+
+	settings, err := uc.jobSettingRepo.GetAll(ctx)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get job settings", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to get job settings", err.Error())
+	}
+
+	return settings, nil
+}
+
+// SaveJobSetting stores the schedule of a job so every replica, and later restarts, use it instead of the configured one
+func (uc *JobSettingUseCase) SaveJobSetting(ctx context.Context, jobName string, schedule string, timezone string, enabled bool) (*entities.JobSetting, error) {
+	funcCtx := "JobSettingUseCase.SaveJobSetting"
+
+	setting := &entities.JobSetting{
+		JobName:   jobName,
+		Schedule:  schedule,
+		Timezone:  timezone,
+		Enabled:   enabled,
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	if err := uc.jobSettingRepo.Upsert(ctx, setting); err != nil {
+		logger.LogError(funcCtx, "failed to save job setting", err, logrus.Fields{
+			"job_name": jobName,
+			"schedule": schedule,
+			"timezone": timezone,
+			"enabled":  enabled,
+		})
+		return nil, helpers.NewInternalError("failed to save job setting", err.Error())
+	}
+
+	return setting, nil
+}
+
+// DeleteJobSetting removes the stored schedule of a job, so every replica uses the configured one again
+func (uc *JobSettingUseCase) DeleteJobSetting(ctx context.Context, jobName string) error {
+	funcCtx := "JobSettingUseCase.DeleteJobSetting"
+
+	if err := uc.jobSettingRepo.Delete(ctx, jobName); err != nil {
+		logger.LogError(funcCtx, "failed to delete job setting", err, logrus.Fields{
+			"job_name": jobName,
+		})
+		return helpers.NewInternalError("failed to delete job setting", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,434 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// recurringDueBatchSize caps how many due templates a single run picks up
+	recurringDueBatchSize = 1000
+	// recurringMaxOccurrencesPerRun caps the catch-up per template, the rest is posted on the next run
+	recurringMaxOccurrencesPerRun = 366
+)
+
+type RecurringTransactionUseCaseInterface interface {
+	CreateRecurringTransaction(ctx context.Context, req *dto.CreateRecurringTransactionRequest) (*dto.RecurringTransactionResponse, error)
+	GetRecurringTransaction(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.RecurringTransactionResponse, error)
+	GetRecurringTransactions(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.RecurringTransactionResponse], error)
+	UpdateRecurringTransaction(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, req *dto.UpdateRecurringTransactionRequest) (*dto.RecurringTransactionResponse, error)
+	DeleteRecurringTransaction(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) error // Soft delete, posted transactions are kept
+	ProcessDueRecurringTransactions(ctx context.Context, now time.Time) (*dto.ProcessRecurringTransactionsResponse, error)
+}
+
+type RecurringTransactionUseCase struct {
+	recurringRepo repositories.RecurringTransactionRepository
+	walletRepo    repositories.WalletRepository
+	userRepo      repositories.UserRepository
+	db            *gorm.DB
+}
+
+func NewRecurringTransactionUseCase(
+	recurringRepo repositories.RecurringTransactionRepository,
+	walletRepo repositories.WalletRepository,
+	userRepo repositories.UserRepository,
+	db *gorm.DB,
+) RecurringTransactionUseCaseInterface {
+	return &RecurringTransactionUseCase{
+		recurringRepo: recurringRepo,
+		walletRepo:    walletRepo,
+		userRepo:      userRepo,
+		db:            db,
+	}
+}
+
+func (uc *RecurringTransactionUseCase) CreateRecurringTransaction(ctx context.Context, req *dto.CreateRecurringTransactionRequest) (*dto.RecurringTransactionResponse, error) {
+	funcCtx := "CreateRecurringTransaction"
+
+	if req.EndDate != nil && req.EndDate.Before(req.StartDate) {
+		return nil, helpers.NewBadRequestError("end_date must not be before start_date", "")
+	}
+
+	// Verify user exists
+	if _, err := uc.userRepo.GetByID(ctx, req.UserID); err != nil {
+		logger.LogError(funcCtx, "user not found", err, logrus.Fields{"user_id": req.UserID.String()})
+		return nil, helpers.NewNotFoundError("user not found", "")
+	}
+
+	if err := checkWalletOwnership(ctx, uc.walletRepo, funcCtx, req.WalletID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	interval := req.Interval
+	if interval == 0 {
+		interval = 1
+	}
+
+	recurring := &entities.RecurringTransaction{
+		Name:      req.Name,
+		Cost:      req.Cost,
+		Type:      entities.TransactionType(req.Type),
+		Note:      req.Note,
+		TCategory: req.TCategory,
+		UserID:    req.UserID,
+		WalletID:  req.WalletID,
+		Frequency: entities.RecurrenceFrequency(req.Frequency),
+		Interval:  interval,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		IsActive:  true,
+	}
+	recurring.ScheduleNext()
+
+	if err := uc.recurringRepo.Create(ctx, recurring); err != nil {
+		logger.LogError(funcCtx, "failed to create recurring transaction", err, logrus.Fields{
+			"name":      req.Name,
+			"user_id":   req.UserID.String(),
+			"wallet_id": req.WalletID.String(),
+		})
+		return nil, helpers.NewInternalError("failed to create recurring transaction", err.Error())
+	}
+
+	// Reload recurring transaction with relationships
+	createdRecurring, err := uc.recurringRepo.GetByID(ctx, recurring.ID)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to reload created recurring transaction", err, logrus.Fields{
+			"recurring_transaction_id": recurring.ID.String(),
+		})
+		return nil, helpers.NewInternalError("failed to reload created recurring transaction", err.Error())
+	}
+
+	return dto.MapToRecurringTransactionResponse(createdRecurring), nil
+}
+
+func (uc *RecurringTransactionUseCase) GetRecurringTransaction(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.RecurringTransactionResponse, error) {
+	recurring, err := uc.getOwnedRecurringTransaction(ctx, "GetRecurringTransaction", id, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.MapToRecurringTransactionResponse(recurring), nil
+}
+
+func (uc *RecurringTransactionUseCase) GetRecurringTransactions(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.RecurringTransactionResponse], error) {
+	funcCtx := "GetRecurringTransactions"
+
+	recurrings, err := uc.recurringRepo.GetAll(ctx, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get recurring transactions", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to get recurring transactions", err.Error())
+	}
+
+	total, err := uc.recurringRepo.CountWithFilters(ctx, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to count recurring transactions", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to count recurring transactions", err.Error())
+	}
+
+	recurringResponses := make([]dto.RecurringTransactionResponse, len(recurrings))
+	for i, recurring := range recurrings {
+		recurringResponses[i] = *dto.MapToRecurringTransactionResponse(recurring)
+	}
+
+	paginationMeta := helpers.NewPaginationMeta(queryParams.Page, queryParams.Limit, total)
+
+	return &dto.PaginationData[dto.RecurringTransactionResponse]{
+		Data: recurringResponses,
+		Meta: paginationMeta,
+	}, nil
+}
+
+func (uc *RecurringTransactionUseCase) UpdateRecurringTransaction(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, req *dto.UpdateRecurringTransactionRequest) (*dto.RecurringTransactionResponse, error) {
+	funcCtx := "UpdateRecurringTransaction"
+
+	recurring, err := uc.getOwnedRecurringTransaction(ctx, funcCtx, id, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.WalletID != uuid.Nil && req.WalletID != recurring.WalletID {
+		if err := checkWalletOwnership(ctx, uc.walletRepo, funcCtx, req.WalletID, recurring.UserID); err != nil {
+			return nil, err
+		}
+		recurring.WalletID = req.WalletID
+	}
+
+	// Update template fields
+	if req.Name != "" {
+		recurring.Name = req.Name
+	}
+	if !req.Cost.IsZero() {
+		recurring.Cost = req.Cost
+	}
+	if req.Type != "" {
+		recurring.Type = entities.TransactionType(req.Type)
+	}
+	if req.Note != "" {
+		recurring.Note = req.Note
+	}
+	if req.TCategory != "" {
+		recurring.TCategory = req.TCategory
+	}
+	if req.EndDate != nil {
+		recurring.EndDate = req.EndDate
+	}
+
+	// A schedule change re-anchors the series, on the requested start date or else on the upcoming occurrence
+	scheduleChanged := false
+	if req.Frequency != "" && entities.RecurrenceFrequency(req.Frequency) != recurring.Frequency {
+		recurring.Frequency = entities.RecurrenceFrequency(req.Frequency)
+		scheduleChanged = true
+	}
+	if req.Interval != 0 && req.Interval != recurring.Interval {
+		recurring.Interval = req.Interval
+		scheduleChanged = true
+	}
+	if req.StartDate != nil {
+		recurring.StartDate = *req.StartDate
+		scheduleChanged = true
+	} else if scheduleChanged && recurring.NextOccurrenceAt != nil {
+		recurring.StartDate = *recurring.NextOccurrenceAt
+	}
+	if scheduleChanged {
+		recurring.OccurrenceIndex = 0
+		// Never post an occurrence at or before one that was already posted
+		if recurring.LastOccurrenceAt != nil {
+			uc.skipOccurrencesUntil(recurring, *recurring.LastOccurrenceAt, true)
+		}
+	}
+
+	// Resuming a paused template skips the occurrences missed while it was paused
+	if req.IsActive != nil {
+		if *req.IsActive && !recurring.IsActive {
+			uc.skipOccurrencesUntil(recurring, time.Now(), false)
+		}
+		recurring.IsActive = *req.IsActive
+	}
+
+	if recurring.EndDate != nil && recurring.EndDate.Before(recurring.StartDate) {
+		return nil, helpers.NewBadRequestError("end_date must not be before start_date", "")
+	}
+
+	recurring.ScheduleNext()
+
+	// Clear preloaded relation so Save doesn't write back the stale wallet
+	recurring.Wallet = entities.Wallet{}
+
+	if err := uc.recurringRepo.Update(ctx, recurring); err != nil {
+		logger.LogError(funcCtx, "failed to update recurring transaction", err, logrus.Fields{
+			"recurring_transaction_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to update recurring transaction", err.Error())
+	}
+
+	// Reload recurring transaction with relationships
+	updatedRecurring, err := uc.recurringRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to reload updated recurring transaction", err, logrus.Fields{
+			"recurring_transaction_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to reload updated recurring transaction", err.Error())
+	}
+
+	return dto.MapToRecurringTransactionResponse(updatedRecurring), nil
+}
+
+func (uc *RecurringTransactionUseCase) DeleteRecurringTransaction(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) error {
+	funcCtx := "DeleteRecurringTransaction"
+
+	if _, err := uc.getOwnedRecurringTransaction(ctx, funcCtx, id, loggedUserID); err != nil {
+		return err
+	}
+
+	if err := uc.recurringRepo.SoftDelete(ctx, id); err != nil {
+		logger.LogError(funcCtx, "failed to delete recurring transaction", err, logrus.Fields{
+			"recurring_transaction_id": id.String(),
+		})
+		return helpers.NewInternalError("failed to delete recurring transaction", err.Error())
+	}
+
+	return nil
+}
+
+// ProcessDueRecurringTransactions posts every occurrence that is due at now.
+// Each template is processed in its own DB transaction so one failure doesn't block the others.
+func (uc *RecurringTransactionUseCase) ProcessDueRecurringTransactions(ctx context.Context, now time.Time) (*dto.ProcessRecurringTransactionsResponse, error) {
+	funcCtx := "ProcessDueRecurringTransactions"
+
+	ids, err := uc.recurringRepo.GetDueIDs(ctx, now, recurringDueBatchSize)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get due recurring transactions", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to get due recurring transactions", err.Error())
+	}
+
+	result := &dto.ProcessRecurringTransactionsResponse{}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			logger.LogError(funcCtx, "recurring transaction processing cancelled", err, logrus.Fields{
+				"processed": result.Templates,
+				"due":       len(ids),
+			})
+			return result, err
+		}
+
+		result.Templates++
+		posted, skipped, err := uc.materialiseOccurrences(ctx, id, now)
+		result.Posted += posted
+		result.Skipped += skipped
+		if err != nil {
+			result.Failed++
+			logger.LogError(funcCtx, "failed to materialise recurring transaction", err, logrus.Fields{
+				"recurring_transaction_id": id.String(),
+			})
+		}
+	}
+
+	logger.LogSuccess(funcCtx, "Processed due recurring transactions", logrus.Fields{
+		"templates": result.Templates,
+		"posted":    result.Posted,
+		"skipped":   result.Skipped,
+		"failed":    result.Failed,
+	})
+
+	return result, nil
+}
+
+// materialiseOccurrences posts the due occurrences of one template through createTransactionWithBalance.
+// The template row is locked for the duration, and occurrences that already exist are skipped,
+// so concurrent or repeated runs never post the same occurrence twice.
+func (uc *RecurringTransactionUseCase) materialiseOccurrences(ctx context.Context, id uuid.UUID, now time.Time) (int, int, error) {
+	funcCtx := "materialiseOccurrences"
+
+	tx := uc.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	recurringRepo := repositories.NewRecurringTransactionRepository(tx)
+	transactionRepo := repositories.NewTransactionRepository(tx)
+	walletRepo := repositories.NewWalletRepository(tx)
+
+	recurring, err := recurringRepo.GetByIDForUpdate(ctx, id)
+	if err != nil {
+		tx.Rollback()
+		return 0, 0, err
+	}
+
+	// Another run may have handled it since the due list was loaded
+	if !recurring.IsDue(now) {
+		tx.Rollback()
+		return 0, 0, nil
+	}
+
+	wallet, err := walletRepo.GetByID(ctx, recurring.WalletID)
+	if err != nil || wallet.UserID != recurring.UserID {
+		// The wallet is gone or changed hands, pause the template instead of failing every run
+		recurring.IsActive = false
+		if updateErr := recurringRepo.Update(ctx, recurring); updateErr != nil {
+			tx.Rollback()
+			return 0, 0, updateErr
+		}
+		if commitErr := tx.Commit().Error; commitErr != nil {
+			return 0, 0, commitErr
+		}
+		logger.LogError(funcCtx, "wallet unavailable, recurring transaction paused", err, logrus.Fields{
+			"recurring_transaction_id": id.String(),
+			"wallet_id":                recurring.WalletID.String(),
+		})
+		return 0, 0, helpers.NewNotFoundError("wallet not found", "")
+	}
+
+	posted, skipped := 0, 0
+	for i := 0; i < recurringMaxOccurrencesPerRun && recurring.IsDue(now); i++ {
+		occurrence := *recurring.NextOccurrenceAt
+
+		exists, err := transactionRepo.ExistsRecurringOccurrence(ctx, recurring.ID, occurrence)
+		if err != nil {
+			tx.Rollback()
+			return 0, 0, err
+		}
+
+		if exists {
+			skipped++
+		} else {
+			transaction := &entities.Transaction{
+				Name:                   recurring.Name,
+				Cost:                   recurring.Cost,
+				Type:                   recurring.Type,
+				Note:                   recurring.Note,
+				TCategory:              recurring.TCategory,
+				UserID:                 recurring.UserID,
+				WalletID:               recurring.WalletID,
+				RecurringTransactionID: &recurring.ID,
+				OccurrenceDate:         &occurrence,
+				OccurredAt:             occurrence,
+			}
+
+			if err := createTransactionWithBalance(ctx, tx, transaction, wallet); err != nil {
+				tx.Rollback()
+				return 0, 0, err
+			}
+			posted++
+		}
+
+		recurring.LastOccurrenceAt = &occurrence
+		recurring.OccurrenceIndex++
+		recurring.ScheduleNext()
+	}
+
+	if err := recurringRepo.Update(ctx, recurring); err != nil {
+		tx.Rollback()
+		return 0, 0, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, 0, err
+	}
+
+	return posted, skipped, nil
+}
+
+// skipOccurrencesUntil advances OccurrenceIndex past the occurrences before (or at, when inclusive) the given time
+func (uc *RecurringTransactionUseCase) skipOccurrencesUntil(recurring *entities.RecurringTransaction, until time.Time, inclusive bool) {
+	for {
+		occurrence := recurring.OccurrenceAt(recurring.OccurrenceIndex)
+		if occurrence.After(until) || (!inclusive && occurrence.Equal(until)) {
+			return
+		}
+		recurring.OccurrenceIndex++
+	}
+}
+
+// getOwnedRecurringTransaction loads a recurring transaction and hides it from non-admin users who don't own it
+func (uc *RecurringTransactionUseCase) getOwnedRecurringTransaction(ctx context.Context, funcCtx string, id uuid.UUID, loggedUserID uuid.UUID) (*entities.RecurringTransaction, error) {
+	recurring, err := uc.recurringRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get recurring transaction", err, logrus.Fields{
+			"recurring_transaction_id": id.String(),
+		})
+		return nil, helpers.NewNotFoundError("recurring transaction not found", "")
+	}
+
+	if loggedUserID != uuid.Nil && loggedUserID != recurring.UserID {
+		logger.LogError(funcCtx, "unauthorized access to recurring transaction", nil, logrus.Fields{
+			"recurring_transaction_id": id.String(),
+			"recurring_user_id":        recurring.UserID.String(),
+			"logged_user_id":           loggedUserID.String(),
+		})
+		return nil, helpers.NewNotFoundError("recurring transaction not found", "")
+	}
+
+	return recurring, nil
+}
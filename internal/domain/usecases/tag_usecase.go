@@ -0,0 +1,221 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type TagUseCaseInterface interface {
+	CreateTag(ctx context.Context, req *dto.CreateTagRequest) (*dto.TagResponse, error)
+	GetTag(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.TagResponse, error)
+	GetTags(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.TagResponse], error)
+	UpdateTag(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, req *dto.UpdateTagRequest) (*dto.TagResponse, error)
+	DeleteTag(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) error // Also removes the tag from its transactions
+}
+
+type TagUseCase struct {
+	tagRepo  repositories.TagRepository
+	userRepo repositories.UserRepository
+}
+
+func NewTagUseCase(
+	tagRepo repositories.TagRepository,
+	userRepo repositories.UserRepository,
+) TagUseCaseInterface {
+	return &TagUseCase{
+		tagRepo:  tagRepo,
+		userRepo: userRepo,
+	}
+}
+
+func (uc *TagUseCase) CreateTag(ctx context.Context, req *dto.CreateTagRequest) (*dto.TagResponse, error) {
+	funcCtx := "CreateTag"
+
+	// Verify user exists
+	if _, err := uc.userRepo.GetByID(ctx, req.UserID); err != nil {
+		logger.LogError(funcCtx, "user not found", err, logrus.Fields{"user_id": req.UserID.String()})
+		return nil, helpers.NewNotFoundError("user not found", "")
+	}
+
+	tag := &entities.Tag{
+		UserID: req.UserID,
+		Name:   entities.NormalizeTagName(req.Name),
+		Color:  strings.ToUpper(req.Color),
+	}
+	if tag.Name == "" {
+		return nil, helpers.NewBadRequestError("tag name is required", "")
+	}
+	if err := uc.checkUniqueName(ctx, funcCtx, tag); err != nil {
+		return nil, err
+	}
+
+	if err := uc.tagRepo.Create(ctx, tag); err != nil {
+		logger.LogError(funcCtx, "failed to create tag", err, logrus.Fields{
+			"user_id": req.UserID.String(),
+			"name":    tag.Name,
+		})
+		return nil, helpers.NewInternalError("failed to create tag", err.Error())
+	}
+
+	return dto.MapToTagResponse(tag), nil
+}
+
+func (uc *TagUseCase) GetTag(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.TagResponse, error) {
+	tag, err := uc.getOwnedTag(ctx, "GetTag", id, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.MapToTagResponse(tag), nil
+}
+
+func (uc *TagUseCase) GetTags(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.TagResponse], error) {
+	funcCtx := "GetTags"
+
+	tags, err := uc.tagRepo.GetAll(ctx, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get tags", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to get tags", err.Error())
+	}
+
+	total, err := uc.tagRepo.CountWithFilters(ctx, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to count tags", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to count tags", err.Error())
+	}
+
+	tagResponses := make([]dto.TagResponse, len(tags))
+	for i, tag := range tags {
+		tagResponses[i] = *dto.MapToTagResponse(tag)
+	}
+
+	paginationMeta := helpers.NewPaginationMeta(queryParams.Page, queryParams.Limit, total)
+
+	return &dto.PaginationData[dto.TagResponse]{
+		Data: tagResponses,
+		Meta: paginationMeta,
+	}, nil
+}
+
+func (uc *TagUseCase) UpdateTag(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, req *dto.UpdateTagRequest) (*dto.TagResponse, error) {
+	funcCtx := "UpdateTag"
+
+	tag, err := uc.getOwnedTag(ctx, funcCtx, id, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := entities.NormalizeTagName(req.Name); name != "" && name != tag.Name {
+		tag.Name = name
+		if err := uc.checkUniqueName(ctx, funcCtx, tag); err != nil {
+			return nil, err
+		}
+	}
+	if req.Color != "" {
+		tag.Color = strings.ToUpper(req.Color)
+	}
+
+	if err := uc.tagRepo.Update(ctx, tag); err != nil {
+		logger.LogError(funcCtx, "failed to update tag", err, logrus.Fields{
+			"tag_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to update tag", err.Error())
+	}
+
+	return dto.MapToTagResponse(tag), nil
+}
+
+func (uc *TagUseCase) DeleteTag(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) error {
+	funcCtx := "DeleteTag"
+
+	if _, err := uc.getOwnedTag(ctx, funcCtx, id, loggedUserID); err != nil {
+		return err
+	}
+
+	if err := uc.tagRepo.Delete(ctx, id); err != nil {
+		logger.LogError(funcCtx, "failed to delete tag", err, logrus.Fields{
+			"tag_id": id.String(),
+		})
+		return helpers.NewInternalError("failed to delete tag", err.Error())
+	}
+
+	return nil
+}
+
+// getOwnedTag loads a tag and hides it from non-admin users who don't own it
+func (uc *TagUseCase) getOwnedTag(ctx context.Context, funcCtx string, id uuid.UUID, loggedUserID uuid.UUID) (*entities.Tag, error) {
+	tag, err := uc.tagRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get tag", err, logrus.Fields{
+			"tag_id": id.String(),
+		})
+		return nil, helpers.NewNotFoundError("tag not found", "")
+	}
+
+	if loggedUserID != uuid.Nil && loggedUserID != tag.UserID {
+		logger.LogError(funcCtx, "unauthorized access to tag", nil, logrus.Fields{
+			"tag_id":         id.String(),
+			"tag_user_id":    tag.UserID.String(),
+			"logged_user_id": loggedUserID.String(),
+		})
+		return nil, helpers.NewNotFoundError("tag not found", "")
+	}
+
+	return tag, nil
+}
+
+// checkUniqueName rejects a tag whose name is already used by another tag of the same user
+func (uc *TagUseCase) checkUniqueName(ctx context.Context, funcCtx string, tag *entities.Tag) error {
+	existing, err := uc.tagRepo.GetByName(ctx, tag.UserID, tag.Name)
+	if err == nil && existing.ID != tag.ID {
+		logger.LogError(funcCtx, "tag name already used", nil, logrus.Fields{
+			"user_id":     tag.UserID.String(),
+			"name":        tag.Name,
+			"existing_id": existing.ID.String(),
+		})
+		return helpers.NewConflictError(fmt.Sprintf("tag %q already exists", tag.Name), "")
+	}
+	return nil
+}
+
+// resolveTags returns the tags of userID with the given names, creating the missing ones.
+// Names are normalized first, so "Trip Bali" and "trip-bali" resolve to the same tag.
+func resolveTags(ctx context.Context, tagRepo repositories.TagRepository, funcCtx string, userID uuid.UUID, names []string) ([]entities.Tag, error) {
+	names = entities.NormalizeTagNames(names)
+
+	existing, err := tagRepo.GetByNames(ctx, userID, names)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get tags", err, logrus.Fields{"user_id": userID.String()})
+		return nil, helpers.NewInternalError("failed to get tags", err.Error())
+	}
+	byName := make(map[string]entities.Tag, len(existing))
+	for _, tag := range existing {
+		byName[tag.Name] = tag
+	}
+
+	tags := make([]entities.Tag, 0, len(names))
+	for _, name := range names {
+		tag, ok := byName[name]
+		if !ok {
+			tag = entities.Tag{UserID: userID, Name: name}
+			if err := tagRepo.Create(ctx, &tag); err != nil {
+				logger.LogError(funcCtx, "failed to create tag", err, logrus.Fields{
+					"user_id": userID.String(),
+					"name":    name,
+				})
+				return nil, helpers.NewInternalError("failed to create tag", err.Error())
+			}
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
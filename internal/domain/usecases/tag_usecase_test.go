@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTagRepository keeps the tags created through it in memory
+type memoryTagRepository struct {
+	repositories.TagRepository
+	tags []entities.Tag
+}
+
+func (r *memoryTagRepository) Create(ctx context.Context, tag *entities.Tag) error {
+	tag.ID = uuid.New()
+	r.tags = append(r.tags, *tag)
+	return nil
+}
+
+func (r *memoryTagRepository) GetByNames(ctx context.Context, userID uuid.UUID, names []string) ([]entities.Tag, error) {
+	var tags []entities.Tag
+	for _, tag := range r.tags {
+		for _, name := range names {
+			if tag.UserID == userID && tag.Name == name {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags, nil
+}
+
+func TestResolveTags_NormalizesAndReusesExistingTags(t *testing.T) {
+	userID := uuid.New()
+	existing := entities.Tag{ID: uuid.New(), UserID: userID, Name: "reimbursable"}
+	repo := &memoryTagRepository{tags: []entities.Tag{existing}}
+
+	tags, err := resolveTags(context.Background(), repo, "test", userID, []string{" Trip  Bali 2026 ", "Reimbursable", "trip-bali-2026", ""})
+	require.NoError(t, err)
+
+	require.Len(t, tags, 2)
+	assert.Equal(t, "trip-bali-2026", tags[0].Name)
+	assert.NotEqual(t, uuid.Nil, tags[0].ID)
+	assert.Equal(t, existing.ID, tags[1].ID)
+	assert.Len(t, repo.tags, 2)
+}
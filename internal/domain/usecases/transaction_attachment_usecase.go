@@ -0,0 +1,193 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/minio"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxTransactionAttachments caps the number of files attached to one transaction
+	maxTransactionAttachments = 10
+	// attachmentURLExpiryMinutes is how long the signed URL of an attachment stays valid
+	attachmentURLExpiryMinutes = 15
+)
+
+type TransactionAttachmentUseCaseInterface interface {
+	UploadAttachment(ctx context.Context, transactionID uuid.UUID, loggedUserID uuid.UUID, file *multipart.FileHeader) (*dto.TransactionAttachmentResponse, error)
+	GetAttachments(ctx context.Context, transactionID uuid.UUID, loggedUserID uuid.UUID) ([]dto.TransactionAttachmentResponse, error)
+	DeleteAttachment(ctx context.Context, transactionID uuid.UUID, attachmentID uuid.UUID, loggedUserID uuid.UUID) error // Removes the file from storage as well
+}
+
+type TransactionAttachmentUseCase struct {
+	transactionRepo repositories.TransactionRepository
+	attachmentRepo  repositories.TransactionAttachmentRepository
+}
+
+func NewTransactionAttachmentUseCase(
+	transactionRepo repositories.TransactionRepository,
+	attachmentRepo repositories.TransactionAttachmentRepository,
+) TransactionAttachmentUseCaseInterface {
+	return &TransactionAttachmentUseCase{
+		transactionRepo: transactionRepo,
+		attachmentRepo:  attachmentRepo,
+	}
+}
+
+func (uc *TransactionAttachmentUseCase) UploadAttachment(ctx context.Context, transactionID uuid.UUID, loggedUserID uuid.UUID, file *multipart.FileHeader) (*dto.TransactionAttachmentResponse, error) {
+	funcCtx := "UploadAttachment"
+
+	transaction, err := uc.getOwnedTransaction(ctx, funcCtx, transactionID, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := uc.attachmentRepo.CountByTransactionID(ctx, transactionID)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to count attachments", err, logrus.Fields{"transaction_id": transactionID.String()})
+		return nil, helpers.NewInternalError("failed to count attachments", err.Error())
+	}
+	if count >= maxTransactionAttachments {
+		return nil, helpers.NewBadRequestError(fmt.Sprintf("a transaction can have at most %d attachments", maxTransactionAttachments), "")
+	}
+
+	// Attachments are private; the random prefix keeps uploads within the same second apart
+	uploadResult, err := minio.UploadPhotoMinio(ctx, minio.UploadPhotoDto{
+		FileHeader:   file,
+		FolderPrefix: "transaction-attachment",
+		FilePrefix:   "receipt_" + uuid.NewString(),
+		BucketType:   minio.BucketTypePrivate,
+	})
+	if err != nil {
+		logger.LogError(funcCtx, "failed to upload attachment", err, logrus.Fields{"transaction_id": transactionID.String()})
+		return nil, helpers.NewInternalError("failed to upload attachment", err.Error())
+	}
+
+	attachment := &entities.TransactionAttachment{
+		TransactionID: transaction.ID,
+		UserID:        transaction.UserID,
+		FileName:      filepath.Base(file.Filename),
+		Path:          uploadResult.Path,
+		ContentType:   uploadResult.ContentType,
+		Size:          uploadResult.FileSize,
+	}
+	if err := uc.attachmentRepo.Create(ctx, attachment); err != nil {
+		// Revert the upload so no orphaned object is left behind
+		removeAttachmentObjects(ctx, funcCtx, []*entities.TransactionAttachment{attachment})
+		logger.LogError(funcCtx, "failed to create attachment", err, logrus.Fields{"transaction_id": transactionID.String()})
+		return nil, helpers.NewInternalError("failed to create attachment", err.Error())
+	}
+
+	return uc.mapWithSignedURL(ctx, funcCtx, attachment)
+}
+
+func (uc *TransactionAttachmentUseCase) GetAttachments(ctx context.Context, transactionID uuid.UUID, loggedUserID uuid.UUID) ([]dto.TransactionAttachmentResponse, error) {
+	funcCtx := "GetAttachments"
+
+	if _, err := uc.getOwnedTransaction(ctx, funcCtx, transactionID, loggedUserID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := uc.attachmentRepo.GetByTransactionID(ctx, transactionID)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get attachments", err, logrus.Fields{"transaction_id": transactionID.String()})
+		return nil, helpers.NewInternalError("failed to get attachments", err.Error())
+	}
+
+	responses := make([]dto.TransactionAttachmentResponse, 0, len(attachments))
+	for _, attachment := range attachments {
+		response, err := uc.mapWithSignedURL(ctx, funcCtx, attachment)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *response)
+	}
+
+	return responses, nil
+}
+
+func (uc *TransactionAttachmentUseCase) DeleteAttachment(ctx context.Context, transactionID uuid.UUID, attachmentID uuid.UUID, loggedUserID uuid.UUID) error {
+	funcCtx := "DeleteAttachment"
+
+	if _, err := uc.getOwnedTransaction(ctx, funcCtx, transactionID, loggedUserID); err != nil {
+		return err
+	}
+
+	attachment, err := uc.attachmentRepo.GetByID(ctx, attachmentID)
+	if err != nil || attachment.TransactionID != transactionID {
+		logger.LogError(funcCtx, "attachment not found", err, logrus.Fields{
+			"transaction_id": transactionID.String(),
+			"attachment_id":  attachmentID.String(),
+		})
+		return helpers.NewNotFoundError("attachment not found", "")
+	}
+
+	if err := uc.attachmentRepo.Delete(ctx, attachmentID); err != nil {
+		logger.LogError(funcCtx, "failed to delete attachment", err, logrus.Fields{"attachment_id": attachmentID.String()})
+		return helpers.NewInternalError("failed to delete attachment", err.Error())
+	}
+
+	removeAttachmentObjects(ctx, funcCtx, []*entities.TransactionAttachment{attachment})
+	return nil
+}
+
+// getOwnedTransaction loads an active transaction and hides it from non-admin users who don't own it
+func (uc *TransactionAttachmentUseCase) getOwnedTransaction(ctx context.Context, funcCtx string, id uuid.UUID, loggedUserID uuid.UUID) (*entities.Transaction, error) {
+	transaction, err := uc.transactionRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get transaction", err, logrus.Fields{
+			"transaction_id": id.String(),
+		})
+		return nil, helpers.NewNotFoundError("transaction not found", "")
+	}
+
+	if loggedUserID != uuid.Nil && loggedUserID != transaction.UserID {
+		logger.LogError(funcCtx, "unauthorized access to transaction", nil, logrus.Fields{
+			"transaction_id":      id.String(),
+			"transaction_user_id": transaction.UserID.String(),
+			"logged_user_id":      loggedUserID.String(),
+		})
+		return nil, helpers.NewNotFoundError("transaction not found", "")
+	}
+
+	return transaction, nil
+}
+
+// mapWithSignedURL maps an attachment to its response with a freshly signed URL
+func (uc *TransactionAttachmentUseCase) mapWithSignedURL(ctx context.Context, funcCtx string, attachment *entities.TransactionAttachment) (*dto.TransactionAttachmentResponse, error) {
+	expiresAt := time.Now().Add(attachmentURLExpiryMinutes * time.Minute)
+	url, err := minio.GetSignedURL(ctx, attachment.Path, attachmentURLExpiryMinutes)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to sign attachment URL", err, logrus.Fields{"attachment_id": attachment.ID.String()})
+		return nil, helpers.NewInternalError("failed to sign attachment URL", err.Error())
+	}
+
+	return dto.MapToTransactionAttachmentResponse(attachment, url, expiresAt), nil
+}
+
+// removeAttachmentObjects deletes the stored files of attachments whose rows are gone.
+// Failures are only logged since the database no longer references the objects.
+func removeAttachmentObjects(ctx context.Context, funcCtx string, attachments []*entities.TransactionAttachment) {
+	for _, attachment := range attachments {
+		if err := minio.DeletePhotoMinio(ctx, minio.DeletePhotoDto{
+			PhotoPath:  attachment.Path,
+			BucketType: minio.BucketTypePrivate,
+		}); err != nil {
+			logger.LogError(funcCtx, "failed to remove attachment object", err, logrus.Fields{
+				"transaction_id": attachment.TransactionID.String(),
+				"path":           attachment.Path,
+			})
+		}
+	}
+}
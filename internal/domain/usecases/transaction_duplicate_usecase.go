@@ -0,0 +1,442 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/config"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// minNameSimilarity is how alike two transaction names must be, from 0 to 1, for the transactions to be likely duplicates
+const minNameSimilarity = 0.8
+
+const (
+	// duplicateScanBatchSize is how many candidate pairs are read per query while a page of duplicates is filled
+	duplicateScanBatchSize = 200
+	// duplicateMaxScanned bounds the candidate pairs compared per request, a page that reaches it ends early
+	// with a cursor after the last pair compared
+	duplicateMaxScanned = 5000
+)
+
+type TransactionDuplicateUseCaseInterface interface {
+	GetDuplicates(ctx context.Context, queryParams *dto.QueryParams, walletID *uuid.UUID, window time.Duration, cursor string) (*dto.CursorPaginationData[dto.TransactionDuplicateResponse], error)
+	MergeDuplicate(ctx context.Context, req *dto.TransactionDuplicatePairRequest, loggedUserID uuid.UUID) (*dto.TransactionResponse, error) // Keeps the original and soft deletes the duplicate
+	DismissDuplicate(ctx context.Context, req *dto.TransactionDuplicatePairRequest, loggedUserID uuid.UUID) error                           // Keeps both and stops reporting the pair
+}
+
+type TransactionDuplicateUseCase struct {
+	duplicateRepo   repositories.TransactionDuplicateRepository
+	transactionRepo repositories.TransactionRepository
+	db              *gorm.DB
+}
+
+func NewTransactionDuplicateUseCase(
+	duplicateRepo repositories.TransactionDuplicateRepository,
+	transactionRepo repositories.TransactionRepository,
+	db *gorm.DB,
+) TransactionDuplicateUseCaseInterface {
+	return &TransactionDuplicateUseCase{
+		duplicateRepo:   duplicateRepo,
+		transactionRepo: transactionRepo,
+		db:              db,
+	}
+}
+
+// GetDuplicates lists the likely duplicate pairs of the logged user, newest first, starting after cursor when it is set.
+// A zero window uses the configured one.
+func (uc *TransactionDuplicateUseCase) GetDuplicates(ctx context.Context, queryParams *dto.QueryParams, walletID *uuid.UUID, window time.Duration, cursor string) (*dto.CursorPaginationData[dto.TransactionDuplicateResponse], error) {
+	funcCtx := "GetDuplicates"
+
+	if window <= 0 {
+		window = duplicateWindow()
+	}
+
+	var after *repositories.DuplicatePairCursor
+	if cursor != "" {
+		position, err := decodeDuplicateCursor(cursor)
+		if err != nil {
+			return nil, helpers.NewBadRequestError("invalid cursor", err.Error())
+		}
+		after = &position
+	}
+
+	limit := queryParams.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	// Names are compared here, so candidate pairs are read in batches until the page is full. One more match
+	// than the page holds tells that there is a next page.
+	var pairs []repositories.DuplicatePair
+	var similarities []float64
+	var next *repositories.DuplicatePairCursor
+	scanned := 0
+scan:
+	for {
+		batch, err := uc.duplicateRepo.GetCandidatePairs(ctx, queryParams.LoggedUserID, walletID, window, after, duplicateScanBatchSize)
+		if err != nil {
+			logger.LogError(funcCtx, "failed to get duplicate transactions", err, logrus.Fields{})
+			return nil, helpers.NewInternalError("failed to get duplicate transactions", err.Error())
+		}
+
+		for _, pair := range batch {
+			similarity := nameSimilarity(pair.Original.Name, pair.Duplicate.Name)
+			if similarity >= minNameSimilarity {
+				if len(pairs) == limit {
+					last := pairs[len(pairs)-1].Cursor()
+					next = &last
+					break scan
+				}
+				pairs = append(pairs, pair)
+				similarities = append(similarities, similarity)
+			}
+			position := pair.Cursor()
+			after = &position
+			scanned++
+		}
+
+		if len(batch) < duplicateScanBatchSize {
+			break
+		}
+		if scanned >= duplicateMaxScanned {
+			// The pairs compared so far are skipped on the next page even when this one isn't full
+			next = after
+			break
+		}
+	}
+
+	duplicates, err := uc.loadDuplicatePairs(ctx, funcCtx, pairs, similarities)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &dto.CursorPaginationMeta{Limit: limit}
+	if next != nil {
+		meta.NextCursor = encodeDuplicateCursor(*next)
+	}
+
+	return &dto.CursorPaginationData[dto.TransactionDuplicateResponse]{
+		Data: duplicates,
+		Meta: meta,
+	}, nil
+}
+
+// loadDuplicatePairs loads the transactions of a page of pairs with their wallet and tags. A pair with a transaction
+// deleted since it was found is left out.
+func (uc *TransactionDuplicateUseCase) loadDuplicatePairs(ctx context.Context, funcCtx string, pairs []repositories.DuplicatePair, similarities []float64) ([]dto.TransactionDuplicateResponse, error) {
+	duplicates := []dto.TransactionDuplicateResponse{}
+	if len(pairs) == 0 {
+		return duplicates, nil
+	}
+
+	ids := make([]uuid.UUID, 0, 2*len(pairs))
+	for _, pair := range pairs {
+		ids = append(ids, pair.Original.ID, pair.Duplicate.ID)
+	}
+	transactions, err := uc.duplicateRepo.GetPairTransactions(ctx, ids)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get duplicate transactions", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to get duplicate transactions", err.Error())
+	}
+	byID := make(map[uuid.UUID]*entities.Transaction, len(transactions))
+	for _, transaction := range transactions {
+		byID[transaction.ID] = transaction
+	}
+
+	for i, pair := range pairs {
+		original, duplicate := byID[pair.Original.ID], byID[pair.Duplicate.ID]
+		if original == nil || duplicate == nil {
+			continue
+		}
+		duplicates = append(duplicates, dto.TransactionDuplicateResponse{
+			Original:       *dto.MapToTransactionResponse(original),
+			Duplicate:      *dto.MapToTransactionResponse(duplicate),
+			NameSimilarity: similarities[i],
+			TimeApart:      duplicate.OccurredAt.Sub(original.OccurredAt).String(),
+		})
+	}
+	return duplicates, nil
+}
+
+// encodeDuplicateCursor turns the position of a pair into the opaque cursor of the next page
+func encodeDuplicateCursor(position repositories.DuplicatePairCursor) string {
+	raw := fmt.Sprintf("%d:%s:%s", position.OccurredAt.UnixNano(), position.DuplicateID, position.OriginalID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeDuplicateCursor reads a cursor made by encodeDuplicateCursor
+func decodeDuplicateCursor(cursor string) (repositories.DuplicatePairCursor, error) {
+	var position repositories.DuplicatePairCursor
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return position, fmt.Errorf("cursor is not base64: %w", err)
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return position, errors.New("cursor must have 3 parts")
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return position, fmt.Errorf("cursor time: %w", err)
+	}
+	if position.DuplicateID, err = uuid.Parse(parts[1]); err != nil {
+		return position, fmt.Errorf("cursor duplicate id: %w", err)
+	}
+	if position.OriginalID, err = uuid.Parse(parts[2]); err != nil {
+		return position, fmt.Errorf("cursor original id: %w", err)
+	}
+	position.OccurredAt = time.Unix(0, nanos).UTC()
+	return position, nil
+}
+
+// MergeDuplicate soft deletes the duplicate and reverses its balance impact. Its tags and attachments move to
+// the original, which also takes its note when it has none.
+func (uc *TransactionDuplicateUseCase) MergeDuplicate(ctx context.Context, req *dto.TransactionDuplicatePairRequest, loggedUserID uuid.UUID) (*dto.TransactionResponse, error) {
+	funcCtx := "MergeDuplicate"
+
+	original, duplicate, err := uc.getPair(ctx, funcCtx, req, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+	if original.WalletID != duplicate.WalletID || original.Type != duplicate.Type || original.Cost != duplicate.Cost {
+		return nil, helpers.NewBadRequestError("only transactions with the same wallet, amount and type can be merged", "")
+	}
+
+	// Start transaction so the duplicate and its balance impact disappear together
+	tx := uc.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	transactionRepo := repositories.NewTransactionRepository(tx)
+
+	tags := append([]entities.Tag(nil), original.Tags...)
+	missing := missingTags(original, tagNames(duplicate.Tags))
+	for _, tag := range duplicate.Tags {
+		if slices.Contains(missing, tag.Name) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(missing) > 0 {
+		if err := transactionRepo.ReplaceTags(ctx, original, tags); err != nil {
+			tx.Rollback()
+			logger.LogError(funcCtx, "failed to merge tags", err, logrus.Fields{"transaction_id": original.ID.String()})
+			return nil, helpers.NewInternalError("failed to merge tags", err.Error())
+		}
+	}
+
+	if original.Note == "" && duplicate.Note != "" {
+		original.Note = duplicate.Note
+		if err := transactionRepo.Update(ctx, original); err != nil {
+			tx.Rollback()
+			logger.LogError(funcCtx, "failed to update transaction", err, logrus.Fields{"transaction_id": original.ID.String()})
+			if errors.Is(err, repositories.ErrVersionConflict) {
+				return nil, versionConflictError("transaction", 0)
+			}
+			return nil, helpers.NewInternalError("failed to update transaction", err.Error())
+		}
+	}
+
+	if err := repositories.NewTransactionAttachmentRepository(tx).MoveToTransaction(ctx, duplicate.ID, original.ID); err != nil {
+		tx.Rollback()
+		logger.LogError(funcCtx, "failed to move attachments", err, logrus.Fields{"transaction_id": duplicate.ID.String()})
+		return nil, helpers.NewInternalError("failed to move attachments", err.Error())
+	}
+
+	// Reverse the duplicate's impact from the wallet balance, then soft delete it
+	if err := repositories.NewWalletRepository(tx).AdjustBalance(ctx, entities.NewBalanceChange(duplicate.WalletID, duplicate.GetWalletImpact().Neg(), entities.BalanceCauseDuplicateMerged, &duplicate.ID)); err != nil {
+		tx.Rollback()
+		logger.LogError(funcCtx, "failed to reverse wallet balance", err, logrus.Fields{
+			"wallet_id":         duplicate.WalletID.String(),
+			"impact_to_reverse": duplicate.GetWalletImpact(),
+		})
+		return nil, helpers.NewInternalError("failed to reverse wallet balance", err.Error())
+	}
+
+	if err := transactionRepo.SoftDelete(ctx, duplicate.ID); err != nil {
+		tx.Rollback()
+		logger.LogError(funcCtx, "failed to delete duplicate transaction", err, logrus.Fields{"transaction_id": duplicate.ID.String()})
+		return nil, helpers.NewInternalError("failed to delete duplicate transaction", err.Error())
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		logger.LogError(funcCtx, "failed to commit duplicate merge", err, logrus.Fields{
+			"transaction_id": original.ID.String(),
+			"duplicate_id":   duplicate.ID.String(),
+		})
+		return nil, helpers.NewInternalError("failed to commit duplicate merge", err.Error())
+	}
+
+	// Reload transaction with relationships
+	mergedTransaction, err := uc.transactionRepo.GetByID(ctx, original.ID)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to reload merged transaction", err, logrus.Fields{
+			"transaction_id": original.ID.String(),
+		})
+		return nil, helpers.NewInternalError("failed to reload merged transaction", err.Error())
+	}
+
+	return dto.MapToTransactionResponse(mergedTransaction), nil
+}
+
+func (uc *TransactionDuplicateUseCase) DismissDuplicate(ctx context.Context, req *dto.TransactionDuplicatePairRequest, loggedUserID uuid.UUID) error {
+	funcCtx := "DismissDuplicate"
+
+	original, duplicate, err := uc.getPair(ctx, funcCtx, req, loggedUserID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.duplicateRepo.Dismiss(ctx, entities.NewDismissedDuplicate(original.UserID, original.ID, duplicate.ID)); err != nil {
+		logger.LogError(funcCtx, "failed to dismiss duplicate", err, logrus.Fields{
+			"transaction_id": original.ID.String(),
+			"duplicate_id":   duplicate.ID.String(),
+		})
+		return helpers.NewInternalError("failed to dismiss duplicate", err.Error())
+	}
+
+	return nil
+}
+
+// getPair loads the two transactions of a pair, which must be distinct active transactions of the same user,
+// hidden from non-admin users who don't own them, and not transfer legs
+func (uc *TransactionDuplicateUseCase) getPair(ctx context.Context, funcCtx string, req *dto.TransactionDuplicatePairRequest, loggedUserID uuid.UUID) (*entities.Transaction, *entities.Transaction, error) {
+	if req.TransactionID == req.DuplicateID {
+		return nil, nil, helpers.NewBadRequestError("a transaction can't be a duplicate of itself", "")
+	}
+
+	var pair [2]*entities.Transaction
+	for i, id := range []uuid.UUID{req.TransactionID, req.DuplicateID} {
+		transaction, err := uc.transactionRepo.GetByID(ctx, id)
+		if err != nil || (loggedUserID != uuid.Nil && loggedUserID != transaction.UserID) {
+			logger.LogError(funcCtx, "transaction not found", err, logrus.Fields{
+				"transaction_id": id.String(),
+				"logged_user_id": loggedUserID.String(),
+			})
+			return nil, nil, helpers.NewNotFoundError("transaction not found", "")
+		}
+		if transaction.IsTransferLeg() {
+			return nil, nil, helpers.NewBadRequestError("transaction is part of a transfer, delete the transfer instead", "")
+		}
+		pair[i] = transaction
+	}
+
+	if pair[0].UserID != pair[1].UserID {
+		return nil, nil, helpers.NewBadRequestError("transactions belong to different users", "")
+	}
+
+	return pair[0], pair[1], nil
+}
+
+// findDuplicates returns a warning for every existing transaction that is a likely duplicate of a new one
+func findDuplicates(ctx context.Context, duplicateRepo repositories.TransactionDuplicateRepository, funcCtx string, transaction *entities.Transaction) ([]dto.TransactionWarning, error) {
+	window := duplicateWindow()
+	candidates, err := duplicateRepo.GetCandidates(ctx, transaction, window)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to look for duplicate transactions", err, logrus.Fields{
+			"transaction_id": transaction.ID.String(),
+		})
+		return nil, helpers.NewInternalError("failed to look for duplicate transactions", err.Error())
+	}
+
+	var warnings []dto.TransactionWarning
+	for _, candidate := range candidates {
+		if nameSimilarity(transaction.Name, candidate.Name) < minNameSimilarity {
+			continue
+		}
+		candidateID := candidate.ID
+		warnings = append(warnings, dto.TransactionWarning{
+			Code: dto.WarningPossibleDuplicate,
+			Message: fmt.Sprintf("%q of %s with the same amount was recorded %s apart",
+				candidate.Name, candidate.OccurredAt.Format(time.RFC3339), transaction.OccurredAt.Sub(candidate.OccurredAt).Abs()),
+			TransactionID: &candidateID,
+		})
+	}
+	return warnings, nil
+}
+
+// duplicateWindow is how close in time two transactions must be to be likely duplicates
+func duplicateWindow() time.Duration {
+	if minutes := config.GetConfig().App.DuplicateWindow; minutes > 0 {
+		return time.Duration(minutes) * time.Minute
+	}
+	return 10 * time.Minute
+}
+
+// nameSimilarity compares two transaction names ignoring case, punctuation and spacing, from 0 (different) to 1 (same).
+// A name contained in the other, like "Netflix" in "NETFLIX.COM 123", counts as the same.
+func nameSimilarity(a, b string) float64 {
+	a, b = normalizeTransactionName(a), normalizeTransactionName(b)
+	if a == b {
+		return 1
+	}
+	if a == "" || b == "" {
+		return 0
+	}
+	if len(a) >= 4 && len(b) >= 4 && (strings.Contains(a, b) || strings.Contains(b, a)) {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	return 1 - float64(levenshtein(ra, rb))/float64(max(len(ra), len(rb)))
+}
+
+// normalizeTransactionName lowercases a name and keeps only its letters and digits
+func normalizeTransactionName(name string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// levenshtein counts the single rune edits that turn a into b
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// tagNames returns the names of tags
+func tagNames(tags []entities.Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
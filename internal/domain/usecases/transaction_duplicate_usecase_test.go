@@ -0,0 +1,152 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		similar bool
+	}{
+		{"same name in another case", "Grocery Shopping", "grocery shopping", true},
+		{"punctuation and spacing", "Starbucks - Kemang", "STARBUCKS KEMANG", true},
+		{"one name contains the other", "Netflix", "NETFLIX.COM 123", true},
+		{"typo", "Electricity bill", "Electricty bill", true},
+		{"different merchants", "Starbucks", "Spotify", false},
+		{"short names are not matched by containment", "Gas", "Gas station refund", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.similar, nameSimilarity(tt.a, tt.b) >= minNameSimilarity)
+		})
+	}
+}
+
+// memoryDuplicateRepository serves candidate pairs, newest first, and records what was read
+type memoryDuplicateRepository struct {
+	repositories.TransactionDuplicateRepository
+	pairs   []repositories.DuplicatePair
+	queries int
+	loaded  []uuid.UUID
+}
+
+func (r *memoryDuplicateRepository) GetCandidatePairs(ctx context.Context, userID uuid.UUID, walletID *uuid.UUID, window time.Duration, after *repositories.DuplicatePairCursor, limit int) ([]repositories.DuplicatePair, error) {
+	r.queries++
+	start := 0
+	if after != nil {
+		for start < len(r.pairs) && r.pairs[start].Cursor() != *after {
+			start++
+		}
+		start++
+	}
+	end := min(start+limit, len(r.pairs))
+	return r.pairs[min(start, end):end], nil
+}
+
+func (r *memoryDuplicateRepository) GetPairTransactions(ctx context.Context, ids []uuid.UUID) ([]*entities.Transaction, error) {
+	r.loaded = append(r.loaded, ids...)
+	var transactions []*entities.Transaction
+	for _, pair := range r.pairs {
+		for _, transaction := range []*entities.Transaction{pair.Original, pair.Duplicate} {
+			for _, id := range ids {
+				if transaction.ID == id {
+					transactions = append(transactions, transaction)
+				}
+			}
+		}
+	}
+	return transactions, nil
+}
+
+// newDuplicatePairs builds count pairs a minute apart, newest first, whose names match when similar returns true
+func newDuplicatePairs(count int, similar func(i int) bool) []repositories.DuplicatePair {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pairs := make([]repositories.DuplicatePair, count)
+	for i := range pairs {
+		occurredAt := start.Add(-time.Duration(i) * time.Minute)
+		name := "Coffee"
+		if !similar(i) {
+			name = fmt.Sprintf("Rent %d", i)
+		}
+		pairs[i] = repositories.DuplicatePair{
+			Original:  &entities.Transaction{ID: uuid.New(), Name: "Coffee", OccurredAt: occurredAt.Add(-time.Minute)},
+			Duplicate: &entities.Transaction{ID: uuid.New(), Name: name, OccurredAt: occurredAt},
+		}
+	}
+	return pairs
+}
+
+func TestGetDuplicates_Pages(t *testing.T) {
+	ctx := context.Background()
+	// Every third pair has matching names
+	repo := &memoryDuplicateRepository{pairs: newDuplicatePairs(500, func(i int) bool { return i%3 == 0 })}
+	uc := &TransactionDuplicateUseCase{duplicateRepo: repo}
+	queryParams := &dto.QueryParams{PaginationQuery: &dto.PaginationQuery{Page: 1, Limit: 100}}
+
+	var seen []uuid.UUID
+	cursor := ""
+	for page := 0; ; page++ {
+		require.Less(t, page, 10)
+		repo.loaded = nil
+		duplicates, err := uc.GetDuplicates(ctx, queryParams, nil, time.Minute, cursor)
+		require.NoError(t, err)
+
+		// Only the returned page is loaded
+		assert.Len(t, repo.loaded, 2*len(duplicates.Data))
+		for _, duplicate := range duplicates.Data {
+			assert.Equal(t, "Coffee", duplicate.Duplicate.Name)
+			seen = append(seen, duplicate.Duplicate.ID)
+		}
+
+		cursor = duplicates.Meta.NextCursor
+		if cursor == "" {
+			break
+		}
+		assert.Len(t, duplicates.Data, 100)
+	}
+
+	require.Len(t, seen, 167)
+	for i, id := range seen {
+		assert.Equal(t, repo.pairs[3*i].Duplicate.ID, id)
+	}
+}
+
+func TestGetDuplicates_StopsScanning(t *testing.T) {
+	ctx := context.Background()
+	// Only the last pair matches, past the pairs one request compares
+	pairs := newDuplicatePairs(duplicateMaxScanned+10, func(i int) bool { return i == duplicateMaxScanned+5 })
+	repo := &memoryDuplicateRepository{pairs: pairs}
+	uc := &TransactionDuplicateUseCase{duplicateRepo: repo}
+	queryParams := &dto.QueryParams{PaginationQuery: &dto.PaginationQuery{Page: 1, Limit: 10}}
+
+	duplicates, err := uc.GetDuplicates(ctx, queryParams, nil, time.Minute, "")
+	require.NoError(t, err)
+	assert.Empty(t, duplicates.Data)
+	assert.Equal(t, duplicateMaxScanned/duplicateScanBatchSize, repo.queries)
+	require.NotEmpty(t, duplicates.Meta.NextCursor)
+
+	duplicates, err = uc.GetDuplicates(ctx, queryParams, nil, time.Minute, duplicates.Meta.NextCursor)
+	require.NoError(t, err)
+	require.Len(t, duplicates.Data, 1)
+	assert.Equal(t, pairs[duplicateMaxScanned+5].Duplicate.ID, duplicates.Data[0].Duplicate.ID)
+	assert.Empty(t, duplicates.Meta.NextCursor)
+
+	_, err = uc.GetDuplicates(ctx, queryParams, nil, time.Minute, "not a cursor")
+	var appErr *helpers.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, helpers.ErrorTypeBadRequest, appErr.Type)
+}
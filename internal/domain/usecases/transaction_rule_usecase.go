@@ -0,0 +1,589 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type TransactionRuleUseCaseInterface interface {
+	CreateRule(ctx context.Context, req *dto.CreateTransactionRuleRequest) (*dto.TransactionRuleResponse, error)
+	GetRule(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.TransactionRuleResponse, error)
+	GetRules(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.TransactionRuleResponse], error)
+	UpdateRule(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, req *dto.UpdateTransactionRuleRequest) (*dto.TransactionRuleResponse, error)
+	DeleteRule(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) error
+	PreviewRule(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.TransactionRuleRunResponse, error) // Lists the existing transactions the rule would change
+	ApplyRule(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.TransactionRuleRunResponse, error)   // Changes the existing transactions the rule matches
+}
+
+type TransactionRuleUseCase struct {
+	ruleRepo     repositories.TransactionRuleRepository
+	categoryRepo repositories.CategoryRepository
+	walletRepo   repositories.WalletRepository
+	userRepo     repositories.UserRepository
+	db           *gorm.DB
+}
+
+func NewTransactionRuleUseCase(
+	ruleRepo repositories.TransactionRuleRepository,
+	categoryRepo repositories.CategoryRepository,
+	walletRepo repositories.WalletRepository,
+	userRepo repositories.UserRepository,
+	db *gorm.DB,
+) TransactionRuleUseCaseInterface {
+	return &TransactionRuleUseCase{
+		ruleRepo:     ruleRepo,
+		categoryRepo: categoryRepo,
+		walletRepo:   walletRepo,
+		userRepo:     userRepo,
+		db:           db,
+	}
+}
+
+func (uc *TransactionRuleUseCase) CreateRule(ctx context.Context, req *dto.CreateTransactionRuleRequest) (*dto.TransactionRuleResponse, error) {
+	funcCtx := "CreateRule"
+
+	// Verify user exists
+	if _, err := uc.userRepo.GetByID(ctx, req.UserID); err != nil {
+		logger.LogError(funcCtx, "user not found", err, logrus.Fields{"user_id": req.UserID.String()})
+		return nil, helpers.NewNotFoundError("user not found", "")
+	}
+
+	rule := &entities.TransactionRule{
+		UserID:         req.UserID,
+		Name:           strings.TrimSpace(req.Name),
+		Priority:       req.Priority,
+		IsActive:       true,
+		NamePattern:    req.NamePattern,
+		NotePattern:    req.NotePattern,
+		MinAmount:      req.MinAmount,
+		MaxAmount:      req.MaxAmount,
+		WalletID:       req.WalletID,
+		Type:           entities.TransactionType(req.Type),
+		CategoryID:     req.CategoryID,
+		Note:           req.Note,
+		StopProcessing: req.StopProcessing,
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	rule.SetTags(req.Tags)
+
+	if err := uc.validateRule(ctx, funcCtx, rule); err != nil {
+		return nil, err
+	}
+
+	if err := uc.ruleRepo.Create(ctx, rule); err != nil {
+		logger.LogError(funcCtx, "failed to create transaction rule", err, logrus.Fields{
+			"user_id": req.UserID.String(),
+			"name":    rule.Name,
+		})
+		return nil, helpers.NewInternalError("failed to create transaction rule", err.Error())
+	}
+
+	return uc.reloadRule(ctx, funcCtx, rule.ID)
+}
+
+func (uc *TransactionRuleUseCase) GetRule(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.TransactionRuleResponse, error) {
+	rule, err := uc.getOwnedRule(ctx, "GetRule", id, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.MapToTransactionRuleResponse(rule), nil
+}
+
+func (uc *TransactionRuleUseCase) GetRules(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.TransactionRuleResponse], error) {
+	funcCtx := "GetRules"
+
+	rules, err := uc.ruleRepo.GetAll(ctx, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get transaction rules", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to get transaction rules", err.Error())
+	}
+
+	total, err := uc.ruleRepo.CountWithFilters(ctx, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to count transaction rules", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to count transaction rules", err.Error())
+	}
+
+	ruleResponses := make([]dto.TransactionRuleResponse, len(rules))
+	for i, rule := range rules {
+		ruleResponses[i] = *dto.MapToTransactionRuleResponse(rule)
+	}
+
+	paginationMeta := helpers.NewPaginationMeta(queryParams.Page, queryParams.Limit, total)
+
+	return &dto.PaginationData[dto.TransactionRuleResponse]{
+		Data: ruleResponses,
+		Meta: paginationMeta,
+	}, nil
+}
+
+func (uc *TransactionRuleUseCase) UpdateRule(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, req *dto.UpdateTransactionRuleRequest) (*dto.TransactionRuleResponse, error) {
+	funcCtx := "UpdateRule"
+
+	rule, err := uc.getOwnedRule(ctx, funcCtx, id, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		rule.Name = name
+	}
+	if req.Priority != nil {
+		rule.Priority = *req.Priority
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	if req.NamePattern != nil {
+		rule.NamePattern = *req.NamePattern
+	}
+	if req.NotePattern != nil {
+		rule.NotePattern = *req.NotePattern
+	}
+	if req.ClearAmountRange {
+		rule.MinAmount = nil
+		rule.MaxAmount = nil
+	}
+	if req.MinAmount != nil {
+		rule.MinAmount = req.MinAmount
+	}
+	if req.MaxAmount != nil {
+		rule.MaxAmount = req.MaxAmount
+	}
+	if req.ClearWallet {
+		rule.WalletID = nil
+	} else if req.WalletID != nil {
+		rule.WalletID = req.WalletID
+	}
+	if req.ClearType {
+		rule.Type = ""
+	} else if req.Type != "" {
+		rule.Type = entities.TransactionType(req.Type)
+	}
+	if req.ClearCategory {
+		rule.CategoryID = nil
+	} else if req.CategoryID != nil {
+		rule.CategoryID = req.CategoryID
+	}
+	if req.ClearTags {
+		rule.Tags = ""
+	} else if len(req.Tags) > 0 {
+		rule.SetTags(req.Tags)
+	}
+	if req.Note != nil {
+		rule.Note = *req.Note
+	}
+	if req.StopProcessing != nil {
+		rule.StopProcessing = *req.StopProcessing
+	}
+
+	if err := uc.validateRule(ctx, funcCtx, rule); err != nil {
+		return nil, err
+	}
+
+	if err := uc.ruleRepo.Update(ctx, rule); err != nil {
+		logger.LogError(funcCtx, "failed to update transaction rule", err, logrus.Fields{
+			"rule_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to update transaction rule", err.Error())
+	}
+
+	return uc.reloadRule(ctx, funcCtx, rule.ID)
+}
+
+func (uc *TransactionRuleUseCase) DeleteRule(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) error {
+	funcCtx := "DeleteRule"
+
+	if _, err := uc.getOwnedRule(ctx, funcCtx, id, loggedUserID); err != nil {
+		return err
+	}
+
+	if err := uc.ruleRepo.SoftDelete(ctx, id); err != nil {
+		logger.LogError(funcCtx, "failed to delete transaction rule", err, logrus.Fields{
+			"rule_id": id.String(),
+		})
+		return helpers.NewInternalError("failed to delete transaction rule", err.Error())
+	}
+
+	return nil
+}
+
+func (uc *TransactionRuleUseCase) PreviewRule(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.TransactionRuleRunResponse, error) {
+	return uc.runRule(ctx, "PreviewRule", id, loggedUserID, true)
+}
+
+func (uc *TransactionRuleUseCase) ApplyRule(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.TransactionRuleRunResponse, error) {
+	return uc.runRule(ctx, "ApplyRule", id, loggedUserID, false)
+}
+
+const (
+	// ruleCandidateBatchSize is how many candidate transactions are loaded at a time when a rule is run
+	ruleCandidateBatchSize = 500
+	// ruleChangesListed caps the changes listed in the response of a rule run, the counts cover all of them
+	ruleChangesListed = 200
+)
+
+// runRule matches a rule, active or not, against the existing transactions of its user and, unless dryRun,
+// applies its actions to them in one DB transaction. The category is only set on unsplit transactions of its type.
+// Candidates are read in batches so a user with many transactions is never loaded in memory at once.
+func (uc *TransactionRuleUseCase) runRule(ctx context.Context, funcCtx string, id uuid.UUID, loggedUserID uuid.UUID, dryRun bool) (*dto.TransactionRuleRunResponse, error) {
+	rule, err := uc.getOwnedRule(ctx, funcCtx, id, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	matcher, err := compileRule(rule)
+	if err != nil {
+		return nil, helpers.NewBadRequestError(err.Error(), "")
+	}
+
+	var category *entities.Category
+	if rule.CategoryID != nil {
+		category, err = uc.categoryRepo.GetByID(ctx, *rule.CategoryID)
+		if err != nil {
+			logger.LogError(funcCtx, "rule category not found", err, logrus.Fields{"rule_id": id.String()})
+			return nil, helpers.NewBadRequestError("the category of the rule no longer exists", "")
+		}
+		if category.IsArchived {
+			return nil, helpers.NewBadRequestError(fmt.Sprintf("category %q of the rule is archived", category.Name), "")
+		}
+	}
+
+	ruleRepo := uc.ruleRepo
+	var tx *gorm.DB
+	if !dryRun {
+		// Start transaction so either every matching transaction changes or none does
+		tx = uc.db.Begin()
+		defer func() {
+			if r := recover(); r != nil {
+				tx.Rollback()
+				panic(r)
+			}
+		}()
+		ruleRepo = repositories.NewTransactionRuleRepository(tx)
+	}
+
+	result := &dto.TransactionRuleRunResponse{RuleID: rule.ID, DryRun: dryRun, Transactions: []dto.TransactionRuleChange{}}
+	var after *entities.Transaction
+	for {
+		candidates, err := ruleRepo.GetCandidateTransactions(ctx, rule, after, ruleCandidateBatchSize)
+		if err != nil {
+			if tx != nil {
+				tx.Rollback()
+			}
+			logger.LogError(funcCtx, "failed to get transactions", err, logrus.Fields{"rule_id": id.String()})
+			return nil, helpers.NewInternalError("failed to get transactions", err.Error())
+		}
+
+		for _, transaction := range candidates {
+			if !matcher.matches(transaction) {
+				continue
+			}
+			result.Matched++
+
+			change, modified := ruleChange(rule, category, transaction)
+			if !modified {
+				continue
+			}
+
+			result.Changed++
+			if len(result.Transactions) < ruleChangesListed {
+				result.Transactions = append(result.Transactions, change)
+			} else {
+				result.Truncated = true
+			}
+			if dryRun {
+				continue
+			}
+
+			if err := uc.applyRuleChange(ctx, tx, funcCtx, rule, transaction, change); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+
+		if len(candidates) < ruleCandidateBatchSize {
+			break
+		}
+		after = candidates[len(candidates)-1]
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		logger.LogError(funcCtx, "failed to commit rule apply", err, logrus.Fields{
+			"rule_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to commit rule apply", err.Error())
+	}
+
+	return result, nil
+}
+
+// ruleChange returns the difference the actions of a rule make to a matching transaction, and whether there is any
+func ruleChange(rule *entities.TransactionRule, category *entities.Category, transaction *entities.Transaction) (dto.TransactionRuleChange, bool) {
+	change := dto.TransactionRuleChange{
+		TransactionID: transaction.ID,
+		Name:          transaction.Name,
+		OccurredAt:    transaction.OccurredAt,
+		AddedTags:     missingTags(transaction, rule.GetTags()),
+	}
+	modified := len(change.AddedTags) > 0
+	if category != nil && !transaction.IsSplit() && transaction.Type == category.Type &&
+		(transaction.CategoryID == nil || *transaction.CategoryID != category.ID) {
+		change.FromTCategory = transaction.TCategory
+		change.ToTCategory = category.Name
+		change.ToCategoryID = &category.ID
+		modified = true
+	}
+	if rule.Note != "" && transaction.Note != rule.Note {
+		fromNote, toNote := transaction.Note, rule.Note
+		change.FromNote = &fromNote
+		change.ToNote = &toNote
+		modified = true
+	}
+	return change, modified
+}
+
+// applyRuleChange saves a change of runRule to a transaction within tx. The caller rolls tx back on error.
+func (uc *TransactionRuleUseCase) applyRuleChange(ctx context.Context, tx *gorm.DB, funcCtx string, rule *entities.TransactionRule, transaction *entities.Transaction, change dto.TransactionRuleChange) error {
+	transactionRepo := repositories.NewTransactionRepository(tx)
+
+	if change.ToCategoryID != nil {
+		transaction.CategoryID = change.ToCategoryID
+		transaction.TCategory = change.ToTCategory
+	}
+	if change.ToNote != nil {
+		transaction.Note = *change.ToNote
+	}
+
+	if err := transactionRepo.Update(ctx, transaction); err != nil {
+		logger.LogError(funcCtx, "failed to update transaction", err, logrus.Fields{
+			"rule_id":        rule.ID.String(),
+			"transaction_id": transaction.ID.String(),
+		})
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			return versionConflictError("transaction", 0)
+		}
+		return helpers.NewInternalError("failed to update transaction", err.Error())
+	}
+
+	if len(change.AddedTags) == 0 {
+		return nil
+	}
+	tags, err := resolveTags(ctx, repositories.NewTagRepository(tx), funcCtx, transaction.UserID, change.AddedTags)
+	if err != nil {
+		return err
+	}
+	if err := transactionRepo.ReplaceTags(ctx, transaction, append(transaction.Tags, tags...)); err != nil {
+		logger.LogError(funcCtx, "failed to update transaction tags", err, logrus.Fields{
+			"rule_id":        rule.ID.String(),
+			"transaction_id": transaction.ID.String(),
+		})
+		return helpers.NewInternalError("failed to update transaction tags", err.Error())
+	}
+	return nil
+}
+
+// getOwnedRule loads a rule and hides it from non-admin users who don't own it
+func (uc *TransactionRuleUseCase) getOwnedRule(ctx context.Context, funcCtx string, id uuid.UUID, loggedUserID uuid.UUID) (*entities.TransactionRule, error) {
+	rule, err := uc.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get transaction rule", err, logrus.Fields{
+			"rule_id": id.String(),
+		})
+		return nil, helpers.NewNotFoundError("transaction rule not found", "")
+	}
+
+	if loggedUserID != uuid.Nil && loggedUserID != rule.UserID {
+		logger.LogError(funcCtx, "unauthorized access to transaction rule", nil, logrus.Fields{
+			"rule_id":        id.String(),
+			"rule_user_id":   rule.UserID.String(),
+			"logged_user_id": loggedUserID.String(),
+		})
+		return nil, helpers.NewNotFoundError("transaction rule not found", "")
+	}
+
+	return rule, nil
+}
+
+// reloadRule returns a saved rule with its category
+func (uc *TransactionRuleUseCase) reloadRule(ctx context.Context, funcCtx string, id uuid.UUID) (*dto.TransactionRuleResponse, error) {
+	rule, err := uc.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to reload transaction rule", err, logrus.Fields{
+			"rule_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to reload transaction rule", err.Error())
+	}
+
+	return dto.MapToTransactionRuleResponse(rule), nil
+}
+
+// validateRule checks a rule has a condition and an action, that its patterns compile and
+// that its wallet and category belong to the rule user
+func (uc *TransactionRuleUseCase) validateRule(ctx context.Context, funcCtx string, rule *entities.TransactionRule) error {
+	if rule.NamePattern == "" && rule.NotePattern == "" && rule.MinAmount == nil && rule.MaxAmount == nil &&
+		rule.WalletID == nil && rule.Type == "" {
+		return helpers.NewBadRequestError("a rule needs at least one condition", "")
+	}
+	if !rule.HasActions() {
+		return helpers.NewBadRequestError("a rule needs a category, tags or a note to set", "")
+	}
+	if _, err := compileRule(rule); err != nil {
+		return helpers.NewBadRequestError(err.Error(), "")
+	}
+	if rule.MinAmount != nil && rule.MaxAmount != nil && rule.MinAmount.Cmp(*rule.MaxAmount) > 0 {
+		return helpers.NewBadRequestError("min_amount must not be greater than max_amount", "")
+	}
+
+	if rule.WalletID != nil {
+		if err := checkWalletOwnership(ctx, uc.walletRepo, funcCtx, *rule.WalletID, rule.UserID); err != nil {
+			return err
+		}
+	}
+
+	if rule.CategoryID != nil {
+		category, err := uc.categoryRepo.GetByID(ctx, *rule.CategoryID)
+		if err != nil || category.UserID != rule.UserID {
+			logger.LogError(funcCtx, "category not found", err, logrus.Fields{
+				"category_id": rule.CategoryID.String(),
+				"user_id":     rule.UserID.String(),
+			})
+			return helpers.NewNotFoundError("category not found", "")
+		}
+		if category.IsArchived {
+			return helpers.NewBadRequestError(fmt.Sprintf("category %q is archived", category.Name), "")
+		}
+		if rule.Type != "" && category.Type != rule.Type {
+			return helpers.NewBadRequestError(
+				fmt.Sprintf("category %q is an %s category but the rule matches %s transactions", category.Name, category.Type, rule.Type), "")
+		}
+	}
+
+	return nil
+}
+
+// ruleMatcher is a transaction rule with its patterns compiled
+type ruleMatcher struct {
+	rule *entities.TransactionRule
+	name *regexp.Regexp
+	note *regexp.Regexp
+}
+
+// compileRule compiles the name and note patterns of a rule as case-insensitive regular expressions
+func compileRule(rule *entities.TransactionRule) (*ruleMatcher, error) {
+	matcher := &ruleMatcher{rule: rule}
+
+	var err error
+	if rule.NamePattern != "" {
+		if matcher.name, err = regexp.Compile("(?i)" + rule.NamePattern); err != nil {
+			return nil, fmt.Errorf("invalid name_pattern: %w", err)
+		}
+	}
+	if rule.NotePattern != "" {
+		if matcher.note, err = regexp.Compile("(?i)" + rule.NotePattern); err != nil {
+			return nil, fmt.Errorf("invalid note_pattern: %w", err)
+		}
+	}
+
+	return matcher, nil
+}
+
+// matches checks every condition of the rule against a transaction
+func (m *ruleMatcher) matches(transaction *entities.Transaction) bool {
+	rule := m.rule
+	if rule.WalletID != nil && *rule.WalletID != transaction.WalletID {
+		return false
+	}
+	if rule.Type != "" && rule.Type != transaction.Type {
+		return false
+	}
+
+	amount := transaction.GetAbsoluteCost()
+	if rule.MinAmount != nil && amount.Cmp(*rule.MinAmount) < 0 {
+		return false
+	}
+	if rule.MaxAmount != nil && amount.Cmp(*rule.MaxAmount) > 0 {
+		return false
+	}
+
+	if m.name != nil && !m.name.MatchString(transaction.Name) {
+		return false
+	}
+	if m.note != nil && !m.note.MatchString(transaction.Note) {
+		return false
+	}
+
+	return true
+}
+
+// ruleActions are the combined actions of the rules matching a new transaction
+type ruleActions struct {
+	categoryID *uuid.UUID // From the first matching rule that sets a category
+	tags       []string   // From every matching rule
+	note       string     // From the first matching rule that sets a note
+}
+
+// evaluateRules runs rules, already in priority order, against a transaction and combines the actions of
+// the matching ones until a rule that stops processing matches. Rules that no longer compile are skipped.
+func evaluateRules(funcCtx string, rules []*entities.TransactionRule, transaction *entities.Transaction) ruleActions {
+	var actions ruleActions
+	for _, rule := range rules {
+		matcher, err := compileRule(rule)
+		if err != nil {
+			logger.LogError(funcCtx, "skipping invalid transaction rule", err, logrus.Fields{"rule_id": rule.ID.String()})
+			continue
+		}
+		if !matcher.matches(transaction) {
+			continue
+		}
+
+		if actions.categoryID == nil && rule.CategoryID != nil {
+			actions.categoryID = rule.CategoryID
+		}
+		actions.tags = append(actions.tags, rule.GetTags()...)
+		if actions.note == "" {
+			actions.note = rule.Note
+		}
+
+		if rule.StopProcessing {
+			break
+		}
+	}
+	return actions
+}
+
+// missingTags returns the tag names a transaction doesn't carry yet
+func missingTags(transaction *entities.Transaction, names []string) []string {
+	existing := make(map[string]bool, len(transaction.Tags))
+	for _, tag := range transaction.Tags {
+		existing[tag.Name] = true
+	}
+
+	var missing []string
+	for _, name := range names {
+		if !existing[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
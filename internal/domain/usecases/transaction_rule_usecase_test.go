@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateRules_CombinesMatchingRulesInOrder(t *testing.T) {
+	logger.Init("info")
+
+	groceries, dining := uuid.New(), uuid.New()
+	maxAmount := money.MustParse("500000")
+	rules := []*entities.TransactionRule{
+		{ID: uuid.New(), NamePattern: "superindo|alfamart", Type: entities.TransactionTypeExpense, MaxAmount: &maxAmount, CategoryID: &groceries, Tags: "groceries"},
+		{ID: uuid.New(), NamePattern: "[invalid", CategoryID: &dining},
+		{ID: uuid.New(), NamePattern: "kemang", CategoryID: &dining, Tags: "south-jakarta", Note: "Kemang branch", StopProcessing: true},
+		{ID: uuid.New(), NamePattern: "superindo", Tags: "never-reached"},
+	}
+	transaction := &entities.Transaction{Name: "SUPERINDO KEMANG", Type: entities.TransactionTypeExpense, Cost: money.MustParse("125000")}
+
+	actions := evaluateRules("test", rules, transaction)
+
+	assert.Equal(t, &groceries, actions.categoryID)
+	assert.Equal(t, []string{"groceries", "south-jakarta"}, actions.tags)
+	assert.Equal(t, "Kemang branch", actions.note)
+}
+
+func TestRuleMatcher_AmountRangeUsesAbsoluteCost(t *testing.T) {
+	minAmount, maxAmount := money.MustParse("100"), money.MustParse("200")
+	matcher, err := compileRule(&entities.TransactionRule{MinAmount: &minAmount, MaxAmount: &maxAmount})
+	assert.NoError(t, err)
+
+	assert.True(t, matcher.matches(&entities.Transaction{Cost: money.MustParse("-150")}))
+	assert.True(t, matcher.matches(&entities.Transaction{Cost: money.MustParse("200")}))
+	assert.False(t, matcher.matches(&entities.Transaction{Cost: money.MustParse("99.99")}))
+}
+
+// pagedRuleRepository serves one rule and pages through its candidate transactions like the keyset query does
+type pagedRuleRepository struct {
+	repositories.TransactionRuleRepository
+	rule         *entities.TransactionRule
+	transactions []*entities.Transaction // Sorted by occurred_at
+	batches      int
+}
+
+func (r *pagedRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.TransactionRule, error) {
+	return r.rule, nil
+}
+
+func (r *pagedRuleRepository) GetCandidateTransactions(ctx context.Context, rule *entities.TransactionRule, after *entities.Transaction, limit int) ([]*entities.Transaction, error) {
+	r.batches++
+	start := 0
+	if after != nil {
+		for i, transaction := range r.transactions {
+			if transaction.ID == after.ID {
+				start = i + 1
+			}
+		}
+	}
+	end := min(start+limit, len(r.transactions))
+	return r.transactions[start:end], nil
+}
+
+func TestPreviewRule_ReadsCandidatesInBatchesAndCapsTheList(t *testing.T) {
+	logger.Init("info")
+
+	rule := &entities.TransactionRule{ID: uuid.New(), UserID: uuid.New(), NamePattern: "coffee", Note: "Daily coffee"}
+	repo := &pagedRuleRepository{rule: rule}
+	day := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	total := ruleCandidateBatchSize*2 + 10
+	for i := 0; i < total; i++ {
+		name := fmt.Sprintf("Coffee #%d", i)
+		if i%2 == 1 {
+			name = fmt.Sprintf("Lunch #%d", i)
+		}
+		repo.transactions = append(repo.transactions, &entities.Transaction{ID: uuid.New(), Name: name, OccurredAt: day.Add(time.Duration(i) * time.Hour)})
+	}
+
+	uc := &TransactionRuleUseCase{ruleRepo: repo}
+	result, err := uc.PreviewRule(context.Background(), rule.ID, rule.UserID)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, repo.batches)
+	assert.Equal(t, total/2, result.Matched)
+	assert.Equal(t, total/2, result.Changed)
+	assert.True(t, result.Truncated)
+	require.Len(t, result.Transactions, ruleChangesListed)
+	assert.Equal(t, "Coffee #0", result.Transactions[0].Name)
+	assert.Equal(t, "Daily coffee", *result.Transactions[0].ToNote)
+}
@@ -1,14 +1,23 @@
 package usecases
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
 	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/exporter"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/importer"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -17,14 +26,19 @@ type TransactionUseCaseInterface interface {
 	CreateTransaction(ctx context.Context, req *dto.CreateTransactionRequest) (*dto.TransactionResponse, error)
 	GetTransaction(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.TransactionResponse, error)
 	GetTransactions(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.TransactionResponse], error)
-	UpdateTransaction(ctx context.Context, id uuid.UUID, req *dto.UpdateTransactionRequest) (*dto.TransactionResponse, error)
-	DeleteTransaction(ctx context.Context, id uuid.UUID) error // This now does soft delete
+	UpdateTransaction(ctx context.Context, id uuid.UUID, req *dto.UpdateTransactionRequest, expectedVersion int64) (*dto.TransactionResponse, error)
+	DeleteTransaction(ctx context.Context, id uuid.UUID, expectedVersion int64) error // This now does soft delete
+	HardDeleteTransaction(ctx context.Context, id uuid.UUID) error                    // Also removes the attachment files from storage
+	ImportTransactions(ctx context.Context, req *dto.ImportTransactionsRequest, loggedUserID uuid.UUID, dryRun bool) (*dto.ImportTransactionsResponse, error)
+	ImportStatement(ctx context.Context, walletID uuid.UUID, req *dto.ImportStatementRequest, loggedUserID uuid.UUID, dryRun bool) (*dto.ImportTransactionsResponse, error)
+	ExportTransactions(ctx context.Context, queryParams *dto.QueryParams, format string, w io.Writer) error
 }
 
 type TransactionUseCase struct {
 	transactionRepo repositories.TransactionRepository
 	walletRepo      repositories.WalletRepository
 	userRepo        repositories.UserRepository
+	attachmentRepo  repositories.TransactionAttachmentRepository
 	db              *gorm.DB
 }
 
@@ -32,12 +46,14 @@ func NewTransactionUseCase(
 	transactionRepo repositories.TransactionRepository,
 	walletRepo repositories.WalletRepository,
 	userRepo repositories.UserRepository,
+	attachmentRepo repositories.TransactionAttachmentRepository,
 	db *gorm.DB,
 ) TransactionUseCaseInterface {
 	return &TransactionUseCase{
 		transactionRepo: transactionRepo,
 		walletRepo:      walletRepo,
 		userRepo:        userRepo,
+		attachmentRepo:  attachmentRepo,
 		db:              db,
 	}
 }
@@ -50,6 +66,7 @@ func (uc *TransactionUseCase) CreateTransaction(ctx context.Context, req *dto.Cr
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
+			panic(r)
 		}
 	}()
 
@@ -90,29 +107,62 @@ func (uc *TransactionUseCase) CreateTransaction(ctx context.Context, req *dto.Cr
 		UserID:    req.UserID,
 		WalletID:  req.WalletID,
 	}
+	if req.OccurredAt != nil {
+		transaction.OccurredAt = *req.OccurredAt
+	}
 
-	// Save transaction within transaction
-	transactionRepo := repositories.NewTransactionRepository(tx)
-	if err := transactionRepo.Create(ctx, transaction); err != nil {
+	// Categorisation rules fill in what the request leaves open
+	rules, err := repositories.NewTransactionRuleRepository(tx).GetActiveByUser(ctx, req.UserID)
+	if err != nil {
 		tx.Rollback()
-		logger.LogError(funcCtx, "failed to create transaction", err, logrus.Fields{
-			"name":      req.Name,
-			"user_id":   req.UserID.String(),
-			"wallet_id": req.WalletID.String(),
-		})
-		return nil, helpers.NewInternalError("failed to create transaction", err.Error())
+		logger.LogError(funcCtx, "failed to get transaction rules", err, logrus.Fields{"user_id": req.UserID.String()})
+		return nil, helpers.NewInternalError("failed to get transaction rules", err.Error())
+	}
+	ruled := evaluateRules(funcCtx, rules, transaction)
+	if transaction.Note == "" {
+		transaction.Note = ruled.note
 	}
 
-	// Update wallet balance using the GetWalletImpact method
-	wallet.Balance += transaction.GetWalletImpact()
-	walletRepo := repositories.NewWalletRepository(tx)
-	if err := walletRepo.Update(ctx, wallet); err != nil {
+	// Splits set the category from their largest line, otherwise an explicit category wins over a rule category,
+	// which wins over the category name
+	if len(req.Splits) > 0 {
+		if err := assignSplits(ctx, repositories.NewCategoryRepository(tx), funcCtx, transaction, req.Splits); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	} else if req.CategoryID != nil {
+		if err := assignCategory(ctx, repositories.NewCategoryRepository(tx), funcCtx, transaction, req.CategoryID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	} else if ruled.categoryID != nil {
+		// A rule whose category was archived or doesn't fit the transaction type falls back to the category name
+		if err := assignCategory(ctx, repositories.NewCategoryRepository(tx), funcCtx, transaction, ruled.categoryID); err != nil {
+			logger.LogError(funcCtx, "rule category not assigned", err, logrus.Fields{
+				"category_id": ruled.categoryID.String(),
+			})
+		}
+	}
+
+	if tagNames := append(req.Tags, ruled.tags...); len(tagNames) > 0 {
+		tags, err := resolveTags(ctx, repositories.NewTagRepository(tx), funcCtx, transaction.UserID, tagNames)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		transaction.Tags = tags
+	}
+
+	// Save transaction and update wallet balance within transaction
+	if err := createTransactionWithBalance(ctx, tx, transaction, wallet); err != nil {
 		tx.Rollback()
-		logger.LogError(funcCtx, "failed to update wallet balance", err, logrus.Fields{
+		logger.LogError(funcCtx, "failed to create transaction", err, logrus.Fields{
+			"name":          req.Name,
+			"user_id":       req.UserID.String(),
 			"wallet_id":     req.WalletID.String(),
 			"wallet_impact": transaction.GetWalletImpact(),
 		})
-		return nil, helpers.NewInternalError("failed to update wallet balance", err.Error())
+		return nil, err
 	}
 
 	// Commit transaction
@@ -132,7 +182,14 @@ func (uc *TransactionUseCase) CreateTransaction(ctx context.Context, req *dto.Cr
 		return nil, helpers.NewInternalError("failed to reload created transaction", err.Error())
 	}
 
-	return dto.MapToTransactionResponse(createdTransaction), nil
+	response := dto.MapToTransactionResponse(createdTransaction)
+
+	// Likely duplicates are only reported, the transaction is kept either way
+	if warnings, err := findDuplicates(ctx, repositories.NewTransactionDuplicateRepository(uc.db), funcCtx, createdTransaction); err == nil {
+		response.Warnings = warnings
+	}
+
+	return response, nil
 }
 
 func (uc *TransactionUseCase) GetTransaction(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.TransactionResponse, error) {
@@ -187,7 +244,9 @@ func (uc *TransactionUseCase) GetTransactions(ctx context.Context, queryParams *
 	}, nil
 }
 
-func (uc *TransactionUseCase) UpdateTransaction(ctx context.Context, id uuid.UUID, req *dto.UpdateTransactionRequest) (*dto.TransactionResponse, error) {
+// UpdateTransaction updates a transaction and moves its balance impact. A non-zero expectedVersion (from If-Match)
+// must match the stored version.
+func (uc *TransactionUseCase) UpdateTransaction(ctx context.Context, id uuid.UUID, req *dto.UpdateTransactionRequest, expectedVersion int64) (*dto.TransactionResponse, error) {
 	funcCtx := "UpdateTransaction"
 
 	// Start transaction to ensure consistency
@@ -195,6 +254,7 @@ func (uc *TransactionUseCase) UpdateTransaction(ctx context.Context, id uuid.UUI
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
+			panic(r)
 		}
 	}()
 
@@ -208,10 +268,26 @@ func (uc *TransactionUseCase) UpdateTransaction(ctx context.Context, id uuid.UUI
 		return nil, helpers.NewNotFoundError("transaction not found", "")
 	}
 
+	if err := checkVersion(funcCtx, "transaction", id, expectedVersion, transaction.Version); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Transfer legs must be changed through the transfer so both wallets stay in sync
+	if transaction.IsTransferLeg() {
+		tx.Rollback()
+		logger.LogError(funcCtx, "cannot update transfer leg directly", nil, logrus.Fields{
+			"transaction_id": id.String(),
+			"transfer_id":    transaction.TransferID.String(),
+		})
+		return nil, helpers.NewBadRequestError("transaction is part of a transfer, update the transfer instead", "")
+	}
+
 	// Store original values for balance calculation
 	originalCost := transaction.Cost
 	originalType := transaction.Type
 	originalWalletID := transaction.WalletID
+	originalUserID := transaction.UserID
 
 	// Update transaction fields
 	if req.Name != "" {
@@ -220,13 +296,18 @@ func (uc *TransactionUseCase) UpdateTransaction(ctx context.Context, id uuid.UUI
 	if req.Note != "" {
 		transaction.Note = req.Note
 	}
-	if req.TCategory != "" {
+	categoryChanged := false
+	if req.TCategory != "" && req.TCategory != transaction.TCategory {
 		transaction.TCategory = req.TCategory
+		categoryChanged = true
+	}
+	if req.OccurredAt != nil {
+		transaction.OccurredAt = *req.OccurredAt
 	}
 
 	// Handle cost update
 	costChanged := false
-	if req.Cost > 0 && req.Cost != originalCost {
+	if req.Cost.IsPositive() && req.Cost != originalCost {
 		transaction.Cost = req.Cost
 		costChanged = true
 	}
@@ -275,47 +356,76 @@ func (uc *TransactionUseCase) UpdateTransaction(ctx context.Context, id uuid.UUI
 		transaction.UserID = req.UserID
 	}
 
-	// Update wallet balances if needed
-	walletRepo := repositories.NewWalletRepository(tx)
+	// Re-resolve the category when it, the type or the owner changed, since categories are per user and type.
+	// Split lines are replaced when new ones are sent, and re-resolved by name or checked against a new cost otherwise.
+	categoryRepo := repositories.NewCategoryRepository(tx)
+	userChanged := transaction.UserID != originalUserID
+	splits := req.Splits
+	if len(splits) == 0 && !req.ClearSplits && transaction.IsSplit() && (costChanged || typeChanged || userChanged) {
+		for _, split := range transaction.Splits {
+			splitReq := dto.TransactionSplitRequest{Amount: split.Amount, TCategory: split.TCategory, Note: split.Note}
+			if !typeChanged && !userChanged {
+				splitReq.CategoryID = split.CategoryID
+			}
+			splits = append(splits, splitReq)
+		}
+	}
+	splitsChanged := len(splits) > 0 || (req.ClearSplits && transaction.IsSplit())
 
-	if walletChanged {
-		// Wallet changed: reverse from old wallet, apply to new wallet
+	if len(splits) > 0 {
+		if err := assignSplits(ctx, categoryRepo, funcCtx, transaction, splits); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	} else if req.CategoryID != nil || categoryChanged || typeChanged || userChanged || splitsChanged {
+		transaction.Splits = nil
+		transaction.CategoryID = nil
+		if err := assignCategory(ctx, categoryRepo, funcCtx, transaction, req.CategoryID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
 
-		// Calculate original wallet impact
-		originalTransaction := &entities.Transaction{
-			Cost: originalCost,
-			Type: originalType,
+	// Tags are per user too, so a new owner gets tags with the same names
+	tagNames := req.Tags
+	if len(tagNames) == 0 && !req.ClearTags && userChanged {
+		for _, tag := range transaction.Tags {
+			tagNames = append(tagNames, tag.Name)
 		}
+	}
+	tagsChanged := len(tagNames) > 0 || (req.ClearTags && len(transaction.Tags) > 0)
 
-		// Get original wallet and reverse the original transaction
-		originalWallet, err := uc.walletRepo.GetByID(ctx, originalWalletID)
-		if err != nil {
-			tx.Rollback()
-			logger.LogError(funcCtx, "failed to get original wallet", err, logrus.Fields{"wallet_id": originalWalletID.String()})
-			return nil, helpers.NewInternalError("failed to get original wallet", err.Error())
+	if tagsChanged {
+		transaction.Tags = nil
+		if len(tagNames) > 0 {
+			tags, err := resolveTags(ctx, repositories.NewTagRepository(tx), funcCtx, transaction.UserID, tagNames)
+			if err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			transaction.Tags = tags
 		}
+	}
 
-		// Reverse original impact from original wallet
-		originalWallet.Balance -= originalTransaction.GetWalletImpact()
-		if err := walletRepo.Update(ctx, originalWallet); err != nil {
+	// Update wallet balances if needed, as atomic increments so concurrent transactions on the wallets aren't lost
+	walletRepo := repositories.NewWalletRepository(tx)
+	originalTransaction := &entities.Transaction{
+		Cost: originalCost,
+		Type: originalType,
+	}
+
+	if walletChanged {
+		// Wallet changed: reverse from old wallet, apply to new wallet
+		if err := walletRepo.AdjustBalance(ctx, entities.NewBalanceChange(originalWalletID, originalTransaction.GetWalletImpact().Neg(), entities.BalanceCauseTransactionUpdated, &transaction.ID)); err != nil {
 			tx.Rollback()
 			logger.LogError(funcCtx, "failed to reverse balance from original wallet", err, logrus.Fields{
 				"wallet_id":      originalWalletID.String(),
-				"impact_reverse": -originalTransaction.GetWalletImpact(),
+				"impact_reverse": originalTransaction.GetWalletImpact().Neg(),
 			})
 			return nil, helpers.NewInternalError("failed to reverse balance from original wallet", err.Error())
 		}
 
-		// Apply new impact to new wallet
-		newWallet, err := uc.walletRepo.GetByID(ctx, transaction.WalletID)
-		if err != nil {
-			tx.Rollback()
-			logger.LogError(funcCtx, "failed to get new wallet", err, logrus.Fields{"wallet_id": transaction.WalletID.String()})
-			return nil, helpers.NewInternalError("failed to get new wallet", err.Error())
-		}
-
-		newWallet.Balance += transaction.GetWalletImpact()
-		if err := walletRepo.Update(ctx, newWallet); err != nil {
+		if err := walletRepo.AdjustBalance(ctx, entities.NewBalanceChange(transaction.WalletID, transaction.GetWalletImpact(), entities.BalanceCauseTransactionUpdated, &transaction.ID)); err != nil {
 			tx.Rollback()
 			logger.LogError(funcCtx, "failed to apply balance to new wallet", err, logrus.Fields{
 				"wallet_id":    transaction.WalletID.String(),
@@ -326,22 +436,9 @@ func (uc *TransactionUseCase) UpdateTransaction(ctx context.Context, id uuid.UUI
 
 	} else if costChanged || typeChanged {
 		// Same wallet, but cost or type changed: calculate difference and apply
-		originalTransaction := &entities.Transaction{
-			Cost: originalCost,
-			Type: originalType,
-		}
-
-		impactDifference := transaction.GetWalletImpact() - originalTransaction.GetWalletImpact()
-
-		wallet, err := uc.walletRepo.GetByID(ctx, transaction.WalletID)
-		if err != nil {
-			tx.Rollback()
-			logger.LogError(funcCtx, "failed to get wallet for update", err, logrus.Fields{"wallet_id": transaction.WalletID.String()})
-			return nil, helpers.NewInternalError("failed to get wallet for update", err.Error())
-		}
+		impactDifference := transaction.GetWalletImpact().Sub(originalTransaction.GetWalletImpact())
 
-		wallet.Balance += impactDifference
-		if err := walletRepo.Update(ctx, wallet); err != nil {
+		if err := walletRepo.AdjustBalance(ctx, entities.NewBalanceChange(transaction.WalletID, impactDifference, entities.BalanceCauseTransactionUpdated, &transaction.ID)); err != nil {
 			tx.Rollback()
 			logger.LogError(funcCtx, "failed to update wallet balance", err, logrus.Fields{
 				"wallet_id":         transaction.WalletID.String(),
@@ -358,9 +455,32 @@ func (uc *TransactionUseCase) UpdateTransaction(ctx context.Context, id uuid.UUI
 		logger.LogError(funcCtx, "failed to update transaction", err, logrus.Fields{
 			"transaction_id": id.String(),
 		})
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			return nil, versionConflictError("transaction", expectedVersion)
+		}
 		return nil, helpers.NewInternalError("failed to update transaction", err.Error())
 	}
 
+	if splitsChanged {
+		if err := transactionRepo.ReplaceSplits(ctx, transaction.ID, transaction.Splits); err != nil {
+			tx.Rollback()
+			logger.LogError(funcCtx, "failed to update transaction splits", err, logrus.Fields{
+				"transaction_id": id.String(),
+			})
+			return nil, helpers.NewInternalError("failed to update transaction splits", err.Error())
+		}
+	}
+
+	if tagsChanged {
+		if err := transactionRepo.ReplaceTags(ctx, transaction, transaction.Tags); err != nil {
+			tx.Rollback()
+			logger.LogError(funcCtx, "failed to update transaction tags", err, logrus.Fields{
+				"transaction_id": id.String(),
+			})
+			return nil, helpers.NewInternalError("failed to update transaction tags", err.Error())
+		}
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		logger.LogError(funcCtx, "failed to commit transaction update", err, logrus.Fields{
@@ -381,7 +501,7 @@ func (uc *TransactionUseCase) UpdateTransaction(ctx context.Context, id uuid.UUI
 	return dto.MapToTransactionResponse(updatedTransaction), nil
 }
 
-func (uc *TransactionUseCase) DeleteTransaction(ctx context.Context, id uuid.UUID) error {
+func (uc *TransactionUseCase) DeleteTransaction(ctx context.Context, id uuid.UUID, expectedVersion int64) error {
 	funcCtx := "DeleteTransaction"
 
 	// Start transaction to ensure consistency
@@ -389,6 +509,7 @@ func (uc *TransactionUseCase) DeleteTransaction(ctx context.Context, id uuid.UUI
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
+			panic(r)
 		}
 	}()
 
@@ -402,20 +523,24 @@ func (uc *TransactionUseCase) DeleteTransaction(ctx context.Context, id uuid.UUI
 		return helpers.NewNotFoundError("transaction not found", "")
 	}
 
-	// Get wallet and reverse the transaction cost
-	wallet, err := uc.walletRepo.GetByID(ctx, transaction.WalletID)
-	if err != nil {
+	if err := checkVersion(funcCtx, "transaction", id, expectedVersion, transaction.Version); err != nil {
 		tx.Rollback()
-		logger.LogError(funcCtx, "failed to get wallet", err, logrus.Fields{
-			"wallet_id": transaction.WalletID.String(),
+		return err
+	}
+
+	// Transfer legs must be deleted through the transfer so both wallets stay in sync
+	if transaction.IsTransferLeg() {
+		tx.Rollback()
+		logger.LogError(funcCtx, "cannot delete transfer leg directly", nil, logrus.Fields{
+			"transaction_id": id.String(),
+			"transfer_id":    transaction.TransferID.String(),
 		})
-		return helpers.NewInternalError("failed to get wallet", err.Error())
+		return helpers.NewBadRequestError("transaction is part of a transfer, delete the transfer instead", "")
 	}
 
 	// Reverse the transaction impact from wallet balance
-	wallet.Balance -= transaction.GetWalletImpact()
 	walletRepo := repositories.NewWalletRepository(tx)
-	if err := walletRepo.Update(ctx, wallet); err != nil {
+	if err := walletRepo.AdjustBalance(ctx, entities.NewBalanceChange(transaction.WalletID, transaction.GetWalletImpact().Neg(), entities.BalanceCauseTransactionDeleted, &transaction.ID)); err != nil {
 		tx.Rollback()
 		logger.LogError(funcCtx, "failed to reverse wallet balance", err, logrus.Fields{
 			"wallet_id":         transaction.WalletID.String(),
@@ -444,3 +569,532 @@ func (uc *TransactionUseCase) DeleteTransaction(ctx context.Context, id uuid.UUI
 
 	return nil
 }
+
+// HardDeleteTransaction permanently deletes a transaction, soft deleted or not, with its splits, tags and attachments.
+// The balance impact of an active transaction is reversed; the attachment files are removed once the deletion is committed.
+func (uc *TransactionUseCase) HardDeleteTransaction(ctx context.Context, id uuid.UUID) error {
+	funcCtx := "HardDeleteTransaction"
+
+	transaction, err := uc.transactionRepo.GetByIDWithDeleted(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get transaction", err, logrus.Fields{
+			"transaction_id": id.String(),
+		})
+		return helpers.NewNotFoundError("transaction not found", "")
+	}
+
+	// Transfer legs must be deleted through the transfer so both wallets stay in sync
+	if transaction.IsTransferLeg() {
+		logger.LogError(funcCtx, "cannot delete transfer leg directly", nil, logrus.Fields{
+			"transaction_id": id.String(),
+			"transfer_id":    transaction.TransferID.String(),
+		})
+		return helpers.NewBadRequestError("transaction is part of a transfer, delete the transfer instead", "")
+	}
+
+	attachments, err := uc.attachmentRepo.GetByTransactionID(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get attachments", err, logrus.Fields{
+			"transaction_id": id.String(),
+		})
+		return helpers.NewInternalError("failed to get attachments", err.Error())
+	}
+
+	// Start transaction to ensure consistency
+	tx := uc.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	// A soft deleted transaction no longer counts towards the wallet balance
+	if transaction.IsActive() {
+		if err := repositories.NewWalletRepository(tx).AdjustBalance(ctx, entities.NewBalanceChange(transaction.WalletID, transaction.GetWalletImpact().Neg(), entities.BalanceCauseTransactionDeleted, &transaction.ID)); err != nil {
+			tx.Rollback()
+			logger.LogError(funcCtx, "failed to reverse wallet balance", err, logrus.Fields{
+				"wallet_id":         transaction.WalletID.String(),
+				"impact_to_reverse": transaction.GetWalletImpact(),
+			})
+			return helpers.NewInternalError("failed to reverse wallet balance", err.Error())
+		}
+	}
+
+	if err := repositories.NewTransactionAttachmentRepository(tx).DeleteByTransactionID(ctx, id); err != nil {
+		tx.Rollback()
+		logger.LogError(funcCtx, "failed to delete attachments", err, logrus.Fields{
+			"transaction_id": id.String(),
+		})
+		return helpers.NewInternalError("failed to delete attachments", err.Error())
+	}
+
+	transactionRepo := repositories.NewTransactionRepository(tx)
+	if err := transactionRepo.ReplaceSplits(ctx, id, nil); err != nil {
+		tx.Rollback()
+		logger.LogError(funcCtx, "failed to delete transaction splits", err, logrus.Fields{
+			"transaction_id": id.String(),
+		})
+		return helpers.NewInternalError("failed to delete transaction splits", err.Error())
+	}
+
+	if err := transactionRepo.ReplaceTags(ctx, &entities.Transaction{ID: id}, nil); err != nil {
+		tx.Rollback()
+		logger.LogError(funcCtx, "failed to delete transaction tags", err, logrus.Fields{
+			"transaction_id": id.String(),
+		})
+		return helpers.NewInternalError("failed to delete transaction tags", err.Error())
+	}
+
+	if err := transactionRepo.HardDelete(ctx, id); err != nil {
+		tx.Rollback()
+		logger.LogError(funcCtx, "failed to hard delete transaction", err, logrus.Fields{
+			"transaction_id": id.String(),
+		})
+		return helpers.NewInternalError("failed to hard delete transaction", err.Error())
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		logger.LogError(funcCtx, "failed to commit transaction deletion", err, logrus.Fields{
+			"transaction_id": id.String(),
+		})
+		return helpers.NewInternalError("failed to commit transaction deletion", err.Error())
+	}
+
+	removeAttachmentObjects(ctx, funcCtx, attachments)
+	return nil
+}
+
+// transactionExportColumns are the columns of a transaction export, in order
+var transactionExportColumns = []string{
+	"id", "occurred_at", "name", "type", "t_category", "cost", "note",
+	"wallet_id", "wallet_name", "wallet_currency", "transfer_id", "created_at",
+}
+
+// exportWalletCacheSize caps the wallets an export keeps in memory for their name and currency columns
+const exportWalletCacheSize = 1000
+
+// ExportTransactions writes every transaction matching the filters and sort of queryParams to w in the
+// given format, ignoring pagination. Rows are streamed from the database, so once writing has started
+// an error can only be logged and returned; the caller cannot turn it into an error response anymore.
+func (uc *TransactionUseCase) ExportTransactions(ctx context.Context, queryParams *dto.QueryParams, format string, w io.Writer) error {
+	funcCtx := "ExportTransactions"
+
+	writer, err := exporter.NewRowWriter(format, w, transactionExportColumns)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to start export", err, logrus.Fields{"format": format})
+		return helpers.NewBadRequestError("failed to start export", err.Error())
+	}
+
+	// Wallets are looked up once and cached. Admin exports span the wallets of every user, so the cache is
+	// bounded and simply starts over when full.
+	wallets := make(map[uuid.UUID]*entities.Wallet)
+	rows := 0
+
+	err = uc.transactionRepo.StreamWithFilters(ctx, queryParams, func(transaction *entities.Transaction) error {
+		wallet, ok := wallets[transaction.WalletID]
+		if !ok {
+			if len(wallets) >= exportWalletCacheSize {
+				clear(wallets)
+			}
+			if wallet, err = uc.walletRepo.GetByID(ctx, transaction.WalletID); err != nil {
+				wallet = &entities.Wallet{} // Deleted wallet, leave its columns empty
+			}
+			wallets[transaction.WalletID] = wallet
+		}
+
+		var transferID any
+		if transaction.TransferID != nil {
+			transferID = transaction.TransferID.String()
+		}
+
+		rows++
+		return writer.WriteRow([]any{
+			transaction.ID.String(),
+			transaction.OccurredAt,
+			transaction.Name,
+			string(transaction.Type),
+			transaction.TCategory,
+			transaction.Cost,
+			transaction.Note,
+			transaction.WalletID.String(),
+			wallet.Name,
+			wallet.Currency,
+			transferID,
+			transaction.CreatedAt,
+		})
+	})
+	if err != nil {
+		logger.LogError(funcCtx, "failed to export transactions", err, logrus.Fields{
+			"format":         format,
+			"rows_written":   rows,
+			"logged_user_id": queryParams.LoggedUserID.String(),
+		})
+		return helpers.NewInternalError("failed to export transactions", err.Error())
+	}
+
+	if err := writer.Close(); err != nil {
+		logger.LogError(funcCtx, "failed to finish export", err, logrus.Fields{"format": format, "rows_written": rows})
+		return helpers.NewInternalError("failed to finish export", err.Error())
+	}
+
+	return nil
+}
+
+// importMaxRows caps the rows of a single CSV or statement import so it fits in one DB transaction
+const importMaxRows = 5000
+
+// ImportTransactions parses a CSV file with the requested column mapping and creates a transaction for
+// every valid row. Invalid rows are reported and skipped; all valid rows and the resulting wallet balance
+// change are committed in a single DB transaction. With dryRun nothing is written.
+func (uc *TransactionUseCase) ImportTransactions(ctx context.Context, req *dto.ImportTransactionsRequest, loggedUserID uuid.UUID, dryRun bool) (*dto.ImportTransactionsResponse, error) {
+	funcCtx := "ImportTransactions"
+
+	wallet, err := uc.getImportWallet(ctx, funcCtx, req.WalletID, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := req.File.Open()
+	if err != nil {
+		logger.LogError(funcCtx, "failed to open uploaded file", err, logrus.Fields{"filename": req.File.Filename})
+		return nil, helpers.NewBadRequestError("failed to read uploaded file", err.Error())
+	}
+	defer file.Close()
+
+	records, rowErrors, err := importer.ParseCSV(file, csvMappingFromRequest(req))
+	if err != nil {
+		logger.LogError(funcCtx, "invalid CSV file", err, logrus.Fields{"filename": req.File.Filename})
+		return nil, helpers.NewBadRequestError("invalid CSV file", err.Error())
+	}
+
+	return uc.importRecords(ctx, funcCtx, wallet, records, rowErrors, dryRun)
+}
+
+// ImportStatement parses an OFX or QIF bank statement and posts its entries to the wallet the same way
+// ImportTransactions does. Entries whose FITID (or, for QIF, content fingerprint) was already imported
+// into the wallet are skipped, so downloading and uploading overlapping statements is safe.
+func (uc *TransactionUseCase) ImportStatement(ctx context.Context, walletID uuid.UUID, req *dto.ImportStatementRequest, loggedUserID uuid.UUID, dryRun bool) (*dto.ImportTransactionsResponse, error) {
+	funcCtx := "ImportStatement"
+
+	wallet, err := uc.getImportWallet(ctx, funcCtx, walletID, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := req.File.Open()
+	if err != nil {
+		logger.LogError(funcCtx, "failed to open uploaded file", err, logrus.Fields{"filename": req.File.Filename})
+		return nil, helpers.NewBadRequestError("failed to read uploaded file", err.Error())
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to read uploaded file", err, logrus.Fields{"filename": req.File.Filename})
+		return nil, helpers.NewBadRequestError("failed to read uploaded file", err.Error())
+	}
+
+	format := req.Format
+	if format == "" {
+		format = importer.DetectStatementFormat(req.File.Filename, content)
+	}
+	if format == "" {
+		return nil, helpers.NewBadRequestError("unknown statement format", "upload an OFX or QIF file or set the format field")
+	}
+
+	statement, rowErrors, err := importer.ParseStatement(bytes.NewReader(content), format, importer.StatementOptions{
+		DefaultCategory: req.DefaultCategory,
+		DayFirst:        req.DayFirst,
+		DecimalComma:    req.DecimalComma,
+		MaxEntries:      importMaxRows,
+	})
+	if err != nil {
+		logger.LogError(funcCtx, "invalid statement file", err, logrus.Fields{"filename": req.File.Filename, "format": format})
+		return nil, helpers.NewBadRequestError("invalid statement file", err.Error())
+	}
+
+	if statement.Currency != "" && !strings.EqualFold(statement.Currency, wallet.Currency) {
+		logger.LogError(funcCtx, "statement currency does not match wallet", nil, logrus.Fields{
+			"wallet_id":          wallet.ID.String(),
+			"wallet_currency":    wallet.Currency,
+			"statement_currency": statement.Currency,
+		})
+		return nil, helpers.NewBadRequestError(
+			fmt.Sprintf("statement currency %s does not match wallet currency %s", statement.Currency, wallet.Currency), "")
+	}
+
+	return uc.importRecords(ctx, funcCtx, wallet, statement.Records, rowErrors, dryRun)
+}
+
+// getImportWallet loads the wallet an import posts to and checks the logged user owns it (uuid.Nil means admin)
+func (uc *TransactionUseCase) getImportWallet(ctx context.Context, funcCtx string, walletID, loggedUserID uuid.UUID) (*entities.Wallet, error) {
+	wallet, err := uc.walletRepo.GetByID(ctx, walletID)
+	if err != nil || (loggedUserID != uuid.Nil && wallet.UserID != loggedUserID) {
+		logger.LogError(funcCtx, "wallet not found", err, logrus.Fields{
+			"wallet_id":      walletID.String(),
+			"logged_user_id": loggedUserID.String(),
+		})
+		return nil, helpers.NewNotFoundError("wallet not found", "")
+	}
+	return wallet, nil
+}
+
+// importRecords turns parsed records into transactions of the wallet, skipping entries whose external ID
+// was already imported, and commits them together with the balance change unless dryRun is set
+func (uc *TransactionUseCase) importRecords(ctx context.Context, funcCtx string, wallet *entities.Wallet, records []importer.Record, rowErrors []importer.RowError, dryRun bool) (*dto.ImportTransactionsResponse, error) {
+	response := &dto.ImportTransactionsResponse{
+		DryRun:       dryRun,
+		ValidRows:    len(records),
+		Errors:       make([]dto.ImportRowError, 0, len(rowErrors)),
+		Transactions: make([]dto.TransactionResponse, 0, len(records)),
+	}
+	invalidRows := make(map[int]bool)
+	for _, rowErr := range rowErrors {
+		invalidRows[rowErr.Row] = true
+		response.Errors = append(response.Errors, dto.ImportRowError{Row: rowErr.Row, Column: rowErr.Column, Message: rowErr.Message})
+	}
+	response.InvalidRows = len(invalidRows)
+	response.TotalRows = response.ValidRows + response.InvalidRows
+
+	externalIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		if record.ExternalID != "" {
+			externalIDs = append(externalIDs, record.ExternalID)
+		}
+	}
+	imported, err := uc.transactionRepo.GetExistingExternalIDs(ctx, wallet.ID, externalIDs)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to check already imported entries", err, logrus.Fields{"wallet_id": wallet.ID.String()})
+		return nil, helpers.NewInternalError("failed to check already imported entries", err.Error())
+	}
+
+	transactions := make([]*entities.Transaction, 0, len(records))
+	rows := make([]int, 0, len(records))
+	for _, record := range records {
+		transaction := &entities.Transaction{
+			Name:       record.Name,
+			Cost:       record.Amount,
+			Type:       entities.TransactionType(record.Type),
+			Note:       record.Note,
+			TCategory:  record.Category,
+			UserID:     wallet.UserID,
+			WalletID:   wallet.ID,
+			OccurredAt: record.Date,
+		}
+		if record.ExternalID != "" {
+			// Skip entries imported before as well as repeats within the same file
+			if imported[record.ExternalID] {
+				response.SkippedRows++
+				continue
+			}
+			imported[record.ExternalID] = true
+			externalID := record.ExternalID
+			transaction.ExternalID = &externalID
+		}
+		transactions = append(transactions, transaction)
+		rows = append(rows, record.Row)
+	}
+
+	// Categories are checked in dry runs too, so a dry run reports the rows the import would reject
+	transactions, rows, categoryErrors, err := resolveImportCategories(ctx, repositories.NewCategoryRepository(uc.db), funcCtx, transactions, rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(categoryErrors) > 0 {
+		response.Errors = append(response.Errors, categoryErrors...)
+		sort.SliceStable(response.Errors, func(i, j int) bool { return response.Errors[i].Row < response.Errors[j].Row })
+		response.ValidRows -= len(categoryErrors)
+		response.InvalidRows += len(categoryErrors)
+	}
+
+	if !dryRun && len(transactions) > 0 {
+		// Start transaction so either every valid row is imported or none is
+		tx := uc.db.Begin()
+		defer func() {
+			if r := recover(); r != nil {
+				tx.Rollback()
+				panic(r)
+			}
+		}()
+
+		for i, transaction := range transactions {
+			if err := createTransactionWithBalance(ctx, tx, transaction, wallet); err != nil {
+				tx.Rollback()
+				logger.LogError(funcCtx, "failed to import transaction", err, logrus.Fields{
+					"wallet_id": wallet.ID.String(),
+					"row":       rows[i],
+				})
+				return nil, err
+			}
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			logger.LogError(funcCtx, "failed to commit import", err, logrus.Fields{
+				"wallet_id": wallet.ID.String(),
+				"rows":      len(transactions),
+			})
+			return nil, helpers.NewInternalError("failed to commit transaction", err.Error())
+		}
+		response.ImportedRows = len(transactions)
+	}
+
+	for _, transaction := range transactions {
+		response.Transactions = append(response.Transactions, *dto.MapToTransactionResponse(transaction))
+	}
+
+	return response, nil
+}
+
+// resolveImportCategories links imported transactions to the existing categories named in their t_category before
+// anything is written. A row naming an archived category is dropped and returned as a row error rather than failing
+// the whole import. Categories that don't exist yet are left to createTransactionWithBalance, which creates them.
+func resolveImportCategories(ctx context.Context, categoryRepo repositories.CategoryRepository, funcCtx string, transactions []*entities.Transaction, rows []int) ([]*entities.Transaction, []int, []dto.ImportRowError, error) {
+	type categoryKey struct {
+		categoryType entities.TransactionType
+		name         string
+	}
+	type resolution struct {
+		category *entities.Category
+		err      error
+	}
+	resolved := make(map[categoryKey]resolution)
+
+	kept := make([]*entities.Transaction, 0, len(transactions))
+	keptRows := make([]int, 0, len(rows))
+	var rowErrors []dto.ImportRowError
+	for i, transaction := range transactions {
+		name := strings.TrimSpace(transaction.TCategory)
+		key := categoryKey{categoryType: transaction.Type, name: strings.ToLower(name)}
+		result, ok := resolved[key]
+		if !ok {
+			result.category, result.err = findCategoryByName(ctx, categoryRepo, funcCtx, transaction.UserID, transaction.Type, name)
+			resolved[key] = result
+		}
+
+		if result.err != nil {
+			var appErr *helpers.AppError
+			if !errors.As(result.err, &appErr) || appErr.Type != helpers.ErrorTypeBadRequest {
+				return nil, nil, nil, result.err
+			}
+			rowErrors = append(rowErrors, dto.ImportRowError{Row: rows[i], Column: "category", Message: appErr.Message})
+			continue
+		}
+		if result.category != nil {
+			transaction.CategoryID = &result.category.ID
+			transaction.TCategory = result.category.Name
+		}
+		kept = append(kept, transaction)
+		keptRows = append(keptRows, rows[i])
+	}
+
+	return kept, keptRows, rowErrors, nil
+}
+
+// csvMappingFromRequest converts the column mapping of an import request to the importer format
+func csvMappingFromRequest(req *dto.ImportTransactionsRequest) importer.CSVMapping {
+	mapping := importer.CSVMapping{
+		DateColumn:      req.DateColumn,
+		DateFormat:      req.DateFormat,
+		AmountColumn:    req.AmountColumn,
+		SignConvention:  importer.SignConvention(req.SignConvention),
+		TypeColumn:      req.TypeColumn,
+		NameColumn:      req.NameColumn,
+		CategoryColumn:  req.CategoryColumn,
+		DefaultCategory: req.DefaultCategory,
+		NoteColumn:      req.NoteColumn,
+		DecimalComma:    req.DecimalComma,
+		HasHeader:       req.HasHeader == nil || *req.HasHeader,
+		MaxRows:         importMaxRows,
+	}
+
+	switch req.Delimiter {
+	case "semicolon":
+		mapping.Delimiter = ';'
+	case "tab":
+		mapping.Delimiter = '\t'
+	case "pipe":
+		mapping.Delimiter = '|'
+	default:
+		mapping.Delimiter = ','
+	}
+
+	return mapping
+}
+
+// createTransactionWithBalance saves the transaction and applies its impact to the wallet balance using the given DB transaction.
+// It is the single balance-updating path shared by CreateTransaction, the importers and the recurring transaction job,
+// so transactions that only carry a category name are linked to the matching category here.
+func createTransactionWithBalance(ctx context.Context, tx *gorm.DB, transaction *entities.Transaction, wallet *entities.Wallet) error {
+	if transaction.CategoryID == nil {
+		if err := assignCategory(ctx, repositories.NewCategoryRepository(tx), "createTransactionWithBalance", transaction, nil); err != nil {
+			return err
+		}
+	}
+
+	if err := repositories.NewTransactionRepository(tx).Create(ctx, transaction); err != nil {
+		return helpers.NewInternalError("failed to create transaction", err.Error())
+	}
+
+	// Update wallet balance using the GetWalletImpact method, atomically so concurrent transactions aren't lost
+	if err := repositories.NewWalletRepository(tx).AdjustBalance(ctx, entities.NewBalanceChange(wallet.ID, transaction.GetWalletImpact(), entities.BalanceCauseTransactionCreated, &transaction.ID)); err != nil {
+		return helpers.NewInternalError("failed to update wallet balance", err.Error())
+	}
+
+	return nil
+}
+
+// assignSplits resolves the categories of the split lines and attaches them to the transaction, largest first.
+// The amounts must add up to the transaction cost, and the largest split also becomes the transaction category.
+func assignSplits(ctx context.Context, categoryRepo repositories.CategoryRepository, funcCtx string, transaction *entities.Transaction, splits []dto.TransactionSplitRequest) error {
+	var total money.Money
+	for _, split := range splits {
+		total = total.Add(split.Amount)
+	}
+	if total != transaction.GetAbsoluteCost() {
+		return helpers.NewBadRequestError(
+			fmt.Sprintf("splits add up to %s but the transaction cost is %s", total, transaction.GetAbsoluteCost()), "")
+	}
+
+	transaction.Splits = make([]entities.TransactionSplit, 0, len(splits))
+	for _, split := range splits {
+		category, err := resolveCategory(ctx, categoryRepo, funcCtx, transaction.UserID, transaction.Type, split.CategoryID, split.TCategory)
+		if err != nil {
+			return err
+		}
+		transaction.Splits = append(transaction.Splits, entities.TransactionSplit{
+			CategoryID: &category.ID,
+			TCategory:  category.Name,
+			Amount:     split.Amount,
+			Note:       split.Note,
+		})
+	}
+
+	sort.SliceStable(transaction.Splits, func(i, j int) bool {
+		return transaction.Splits[i].Amount.Cmp(transaction.Splits[j].Amount) > 0
+	})
+	transaction.CategoryID = transaction.Splits[0].CategoryID
+	transaction.TCategory = transaction.Splits[0].TCategory
+	return nil
+}
+
+// checkWalletOwnership verifies the wallet exists and belongs to userID
+func checkWalletOwnership(ctx context.Context, walletRepo repositories.WalletRepository, funcCtx string, walletID, userID uuid.UUID) error {
+	wallet, err := walletRepo.GetByID(ctx, walletID)
+	if err != nil {
+		logger.LogError(funcCtx, "wallet not found", err, logrus.Fields{"wallet_id": walletID.String()})
+		return helpers.NewNotFoundError("wallet not found", "")
+	}
+
+	if wallet.UserID != userID {
+		logger.LogError(funcCtx, "wallet does not belong to user", nil, logrus.Fields{
+			"wallet_id":       walletID.String(),
+			"wallet_user_id":  wallet.UserID.String(),
+			"request_user_id": userID.String(),
+		})
+		return helpers.NewForbiddenError("wallet does not belong to the specified user", "")
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// namedCategoryRepository resolves every category name to an existing category
+type namedCategoryRepository struct {
+	repositories.CategoryRepository
+}
+
+func (r *namedCategoryRepository) GetByName(ctx context.Context, userID uuid.UUID, categoryType entities.TransactionType, name string) (*entities.Category, error) {
+	return &entities.Category{ID: uuid.New(), UserID: userID, Name: name, Type: categoryType}, nil
+}
+
+func TestAssignSplits_LargestSplitSetsCategory(t *testing.T) {
+	transaction := &entities.Transaction{UserID: uuid.New(), Type: entities.TransactionTypeExpense, Cost: money.MustParse("100")}
+
+	err := assignSplits(context.Background(), &namedCategoryRepository{}, "test", transaction, []dto.TransactionSplitRequest{
+		{Amount: money.MustParse("20"), TCategory: "Gifts"},
+		{Amount: money.MustParse("65.5"), TCategory: "Groceries"},
+		{Amount: money.MustParse("14.5"), TCategory: "Household"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, transaction.Splits, 3)
+	assert.Equal(t, "Groceries", transaction.Splits[0].TCategory)
+	assert.Equal(t, "Gifts", transaction.Splits[1].TCategory)
+	assert.Equal(t, "Household", transaction.Splits[2].TCategory)
+	assert.Equal(t, "Groceries", transaction.TCategory)
+	assert.Equal(t, transaction.Splits[0].CategoryID, transaction.CategoryID)
+	assert.Equal(t, money.MustParse("-100"), transaction.GetWalletImpact())
+}
+
+func TestAssignSplits_MustAddUpToCost(t *testing.T) {
+	transaction := &entities.Transaction{UserID: uuid.New(), Type: entities.TransactionTypeExpense, Cost: money.MustParse("100")}
+
+	err := assignSplits(context.Background(), &namedCategoryRepository{}, "test", transaction, []dto.TransactionSplitRequest{
+		{Amount: money.MustParse("60"), TCategory: "Groceries"},
+		{Amount: money.MustParse("30"), TCategory: "Household"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "splits add up to 90 but the transaction cost is 100")
+	assert.Empty(t, transaction.Splits)
+}
+
+func TestResolveImportCategories(t *testing.T) {
+	logger.Init("info")
+	userID := uuid.New()
+	groceries := &entities.Category{ID: uuid.New(), UserID: userID, Name: "Groceries", Type: entities.TransactionTypeExpense}
+	archived := &entities.Category{ID: uuid.New(), UserID: userID, Name: "Old Gym", Type: entities.TransactionTypeExpense, IsArchived: true}
+	repo := &memoryCategoryRepository{categories: []*entities.Category{groceries, archived}}
+
+	imported := func(category string) *entities.Transaction {
+		return &entities.Transaction{UserID: userID, Type: entities.TransactionTypeExpense, Cost: money.FromInt(10), TCategory: category}
+	}
+	transactions := []*entities.Transaction{imported("groceries"), imported("Old Gym"), imported("Coffee"), imported("old gym ")}
+
+	kept, rows, rowErrors, err := resolveImportCategories(context.Background(), repo, "test", transactions, []int{2, 3, 4, 5})
+	require.NoError(t, err)
+
+	// Existing categories are linked, missing ones are left to be created on commit
+	require.Len(t, kept, 2)
+	assert.Equal(t, []int{2, 4}, rows)
+	assert.Equal(t, &groceries.ID, kept[0].CategoryID)
+	assert.Equal(t, "Groceries", kept[0].TCategory)
+	assert.Nil(t, kept[1].CategoryID)
+	assert.Zero(t, repo.created)
+
+	// Archived categories are row errors instead of failing the import
+	assert.Equal(t, []dto.ImportRowError{
+		{Row: 3, Column: "category", Message: `category "Old Gym" is archived`},
+		{Row: 5, Column: "category", Message: `category "Old Gym" is archived`},
+	}, rowErrors)
+
+	// A failed lookup still fails the import
+	_, _, _, err = resolveImportCategories(context.Background(), &memoryCategoryRepository{getErr: errors.New("connection refused")}, "test", transactions, []int{2, 3, 4, 5})
+	var appErr *helpers.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, helpers.ErrorTypeInternal, appErr.Type)
+}
@@ -0,0 +1,550 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/currency"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type TransferUseCaseInterface interface {
+	CreateTransfer(ctx context.Context, req *dto.CreateTransferRequest) (*dto.TransferResponse, error)
+	GetTransfer(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.TransferResponse, error)
+	GetTransfers(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.TransferResponse], error)
+	UpdateTransfer(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, req *dto.UpdateTransferRequest) (*dto.TransferResponse, error)
+	DeleteTransfer(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) error // Soft deletes the transfer and both legs
+}
+
+type TransferUseCase struct {
+	transferRepo     repositories.TransferRepository
+	userRepo         repositories.UserRepository
+	exchangeRateRepo repositories.ExchangeRateRepository
+	db               *gorm.DB
+}
+
+func NewTransferUseCase(
+	transferRepo repositories.TransferRepository,
+	userRepo repositories.UserRepository,
+	exchangeRateRepo repositories.ExchangeRateRepository,
+	db *gorm.DB,
+) TransferUseCaseInterface {
+	return &TransferUseCase{
+		transferRepo:     transferRepo,
+		userRepo:         userRepo,
+		exchangeRateRepo: exchangeRateRepo,
+		db:               db,
+	}
+}
+
+func (uc *TransferUseCase) CreateTransfer(ctx context.Context, req *dto.CreateTransferRequest) (*dto.TransferResponse, error) {
+	funcCtx := "CreateTransfer"
+
+	// Verify user exists
+	if _, err := uc.userRepo.GetByID(ctx, req.UserID); err != nil {
+		logger.LogError(funcCtx, "user not found", err, logrus.Fields{"user_id": req.UserID.String()})
+		return nil, helpers.NewNotFoundError("user not found", "")
+	}
+
+	// Start transaction so both legs and both balances are committed together
+	tx := uc.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	walletRepo := repositories.NewWalletRepository(tx)
+	transactionRepo := repositories.NewTransactionRepository(tx)
+	transferRepo := repositories.NewTransferRepository(tx)
+
+	fromWallet, toWallet, err := uc.getTransferWallets(ctx, walletRepo, req.UserID, req.FromWalletID, req.ToWalletID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	receivedAmount, err := uc.resolveReceivedAmount(ctx, funcCtx, req.Amount, req.ReceivedAmount, fromWallet, toWallet, time.Now())
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	transfer := &entities.Transfer{
+		Amount:         req.Amount,
+		ReceivedAmount: receivedAmount,
+		Note:           req.Note,
+		UserID:         req.UserID,
+		FromWalletID:   fromWallet.ID,
+		ToWalletID:     toWallet.ID,
+	}
+
+	if err := transferRepo.Create(ctx, transfer); err != nil {
+		tx.Rollback()
+		logger.LogError(funcCtx, "failed to create transfer", err, logrus.Fields{
+			"user_id":        req.UserID.String(),
+			"from_wallet_id": req.FromWalletID.String(),
+			"to_wallet_id":   req.ToWalletID.String(),
+		})
+		return nil, helpers.NewInternalError("failed to create transfer", err.Error())
+	}
+
+	// Create the outgoing (expense) and incoming (income) legs linked to the transfer
+	outgoing := &entities.Transaction{
+		Name:       fmt.Sprintf("Transfer to %s", toWallet.Name),
+		Cost:       transfer.GetAbsoluteAmount(),
+		Type:       entities.TransactionTypeExpense,
+		Note:       req.Note,
+		TCategory:  entities.TransferCategory,
+		UserID:     req.UserID,
+		WalletID:   fromWallet.ID,
+		TransferID: &transfer.ID,
+	}
+	incoming := &entities.Transaction{
+		Name:       fmt.Sprintf("Transfer from %s", fromWallet.Name),
+		Cost:       transfer.GetReceivedAmount(),
+		Type:       entities.TransactionTypeIncome,
+		Note:       req.Note,
+		TCategory:  entities.TransferCategory,
+		UserID:     req.UserID,
+		WalletID:   toWallet.ID,
+		TransferID: &transfer.ID,
+	}
+
+	for _, leg := range []*entities.Transaction{outgoing, incoming} {
+		if err := transactionRepo.Create(ctx, leg); err != nil {
+			tx.Rollback()
+			logger.LogError(funcCtx, "failed to create transfer leg", err, logrus.Fields{
+				"transfer_id": transfer.ID.String(),
+				"wallet_id":   leg.WalletID.String(),
+				"type":        string(leg.Type),
+			})
+			return nil, helpers.NewInternalError("failed to create transfer leg", err.Error())
+		}
+
+		if err := uc.adjustWalletBalance(ctx, walletRepo, leg, leg.GetWalletImpact(), entities.BalanceCauseTransferCreated); err != nil {
+			tx.Rollback()
+			logger.LogError(funcCtx, "failed to update wallet balance", err, logrus.Fields{
+				"transfer_id":   transfer.ID.String(),
+				"wallet_id":     leg.WalletID.String(),
+				"wallet_impact": leg.GetWalletImpact(),
+			})
+			return nil, helpers.NewInternalError("failed to update wallet balance", err.Error())
+		}
+	}
+
+	// Link both legs back to the transfer
+	transfer.OutgoingTransactionID = &outgoing.ID
+	transfer.IncomingTransactionID = &incoming.ID
+	if err := transferRepo.Update(ctx, transfer); err != nil {
+		tx.Rollback()
+		logger.LogError(funcCtx, "failed to link transfer legs", err, logrus.Fields{
+			"transfer_id": transfer.ID.String(),
+		})
+		return nil, helpers.NewInternalError("failed to link transfer legs", err.Error())
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		logger.LogError(funcCtx, "failed to commit transfer", err, logrus.Fields{
+			"transfer_id": transfer.ID.String(),
+		})
+		return nil, helpers.NewInternalError("failed to commit transfer", err.Error())
+	}
+
+	// Reload transfer with relationships
+	createdTransfer, err := uc.transferRepo.GetByID(ctx, transfer.ID)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to reload created transfer", err, logrus.Fields{
+			"transfer_id": transfer.ID.String(),
+		})
+		return nil, helpers.NewInternalError("failed to reload created transfer", err.Error())
+	}
+
+	return dto.MapToTransferResponse(createdTransfer), nil
+}
+
+func (uc *TransferUseCase) GetTransfer(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.TransferResponse, error) {
+	transfer, err := uc.getOwnedTransfer(ctx, "GetTransfer", id, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.MapToTransferResponse(transfer), nil
+}
+
+func (uc *TransferUseCase) GetTransfers(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.TransferResponse], error) {
+	funcCtx := "GetTransfers"
+
+	transfers, err := uc.transferRepo.GetAll(ctx, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get transfers", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to get transfers", err.Error())
+	}
+
+	total, err := uc.transferRepo.CountWithFilters(ctx, queryParams)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to count transfers", err, logrus.Fields{})
+		return nil, helpers.NewInternalError("failed to count transfers", err.Error())
+	}
+
+	transferResponses := make([]dto.TransferResponse, len(transfers))
+	for i, transfer := range transfers {
+		transferResponses[i] = *dto.MapToTransferResponse(transfer)
+	}
+
+	paginationMeta := helpers.NewPaginationMeta(queryParams.Page, queryParams.Limit, total)
+
+	return &dto.PaginationData[dto.TransferResponse]{
+		Data: transferResponses,
+		Meta: paginationMeta,
+	}, nil
+}
+
+func (uc *TransferUseCase) UpdateTransfer(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, req *dto.UpdateTransferRequest) (*dto.TransferResponse, error) {
+	funcCtx := "UpdateTransfer"
+
+	transfer, err := uc.getOwnedTransfer(ctx, funcCtx, id, loggedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start transaction to ensure consistency
+	tx := uc.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	walletRepo := repositories.NewWalletRepository(tx)
+	transactionRepo := repositories.NewTransactionRepository(tx)
+	transferRepo := repositories.NewTransferRepository(tx)
+
+	outgoing, incoming, err := uc.getTransferLegs(ctx, transactionRepo, transfer)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Resolve new values, falling back to the current ones
+	newFromWalletID := transfer.FromWalletID
+	if req.FromWalletID != uuid.Nil {
+		newFromWalletID = req.FromWalletID
+	}
+	newToWalletID := transfer.ToWalletID
+	if req.ToWalletID != uuid.Nil {
+		newToWalletID = req.ToWalletID
+	}
+
+	fromWallet, toWallet, err := uc.getTransferWallets(ctx, walletRepo, transfer.UserID, newFromWalletID, newToWalletID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Reconvert the received amount when anything it was derived from changes
+	if req.Amount.IsPositive() || req.ReceivedAmount.IsPositive() || fromWallet.ID != transfer.FromWalletID || toWallet.ID != transfer.ToWalletID {
+		if req.Amount.IsPositive() {
+			transfer.Amount = req.Amount
+		}
+
+		receivedAmount, err := uc.resolveReceivedAmount(ctx, funcCtx, transfer.GetAbsoluteAmount(), req.ReceivedAmount, fromWallet, toWallet, transfer.CreatedAt)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		transfer.ReceivedAmount = receivedAmount
+	}
+	if req.Note != "" {
+		transfer.Note = req.Note
+	}
+	transfer.FromWalletID = fromWallet.ID
+	transfer.ToWalletID = toWallet.ID
+
+	// Reverse each leg from its original wallet, then apply it to its (possibly new) wallet
+	legUpdates := []struct {
+		leg    *entities.Transaction
+		wallet *entities.Wallet
+		name   string
+		cost   money.Money
+	}{
+		{leg: outgoing, wallet: fromWallet, name: fmt.Sprintf("Transfer to %s", toWallet.Name), cost: transfer.GetAbsoluteAmount()},
+		{leg: incoming, wallet: toWallet, name: fmt.Sprintf("Transfer from %s", fromWallet.Name), cost: transfer.GetReceivedAmount()},
+	}
+
+	for _, update := range legUpdates {
+		leg := update.leg
+
+		if err := uc.adjustWalletBalance(ctx, walletRepo, leg, leg.GetWalletImpact().Neg(), entities.BalanceCauseTransferUpdated); err != nil {
+			tx.Rollback()
+			logger.LogError(funcCtx, "failed to reverse balance from original wallet", err, logrus.Fields{
+				"transfer_id":    id.String(),
+				"wallet_id":      leg.WalletID.String(),
+				"impact_reverse": leg.GetWalletImpact().Neg(),
+			})
+			return nil, helpers.NewInternalError("failed to reverse balance from original wallet", err.Error())
+		}
+
+		leg.Name = update.name
+		leg.Cost = update.cost
+		leg.Note = transfer.Note
+		leg.WalletID = update.wallet.ID
+		// Drop preloaded relations so saving the leg doesn't overwrite WalletID from the stale association
+		leg.Wallet = entities.Wallet{}
+		leg.User = entities.User{}
+
+		if err := uc.adjustWalletBalance(ctx, walletRepo, leg, leg.GetWalletImpact(), entities.BalanceCauseTransferUpdated); err != nil {
+			tx.Rollback()
+			logger.LogError(funcCtx, "failed to apply balance to wallet", err, logrus.Fields{
+				"transfer_id":  id.String(),
+				"wallet_id":    leg.WalletID.String(),
+				"impact_apply": leg.GetWalletImpact(),
+			})
+			return nil, helpers.NewInternalError("failed to apply balance to wallet", err.Error())
+		}
+
+		if err := transactionRepo.Update(ctx, leg); err != nil {
+			tx.Rollback()
+			logger.LogError(funcCtx, "failed to update transfer leg", err, logrus.Fields{
+				"transfer_id":    id.String(),
+				"transaction_id": leg.ID.String(),
+			})
+			return nil, helpers.NewInternalError("failed to update transfer leg", err.Error())
+		}
+	}
+
+	// Drop preloaded wallets for the same reason as the legs above
+	transfer.FromWallet = entities.Wallet{}
+	transfer.ToWallet = entities.Wallet{}
+	if err := transferRepo.Update(ctx, transfer); err != nil {
+		tx.Rollback()
+		logger.LogError(funcCtx, "failed to update transfer", err, logrus.Fields{
+			"transfer_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to update transfer", err.Error())
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		logger.LogError(funcCtx, "failed to commit transfer update", err, logrus.Fields{
+			"transfer_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to commit transfer update", err.Error())
+	}
+
+	// Reload transfer with relationships
+	updatedTransfer, err := uc.transferRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to reload updated transfer", err, logrus.Fields{
+			"transfer_id": id.String(),
+		})
+		return nil, helpers.NewInternalError("failed to reload updated transfer", err.Error())
+	}
+
+	return dto.MapToTransferResponse(updatedTransfer), nil
+}
+
+func (uc *TransferUseCase) DeleteTransfer(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) error {
+	funcCtx := "DeleteTransfer"
+
+	transfer, err := uc.getOwnedTransfer(ctx, funcCtx, id, loggedUserID)
+	if err != nil {
+		return err
+	}
+
+	// Start transaction to ensure consistency
+	tx := uc.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	walletRepo := repositories.NewWalletRepository(tx)
+	transactionRepo := repositories.NewTransactionRepository(tx)
+	transferRepo := repositories.NewTransferRepository(tx)
+
+	outgoing, incoming, err := uc.getTransferLegs(ctx, transactionRepo, transfer)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Reverse both legs from their wallets and soft delete them
+	for _, leg := range []*entities.Transaction{outgoing, incoming} {
+		if err := uc.adjustWalletBalance(ctx, walletRepo, leg, leg.GetWalletImpact().Neg(), entities.BalanceCauseTransferDeleted); err != nil {
+			tx.Rollback()
+			logger.LogError(funcCtx, "failed to reverse wallet balance", err, logrus.Fields{
+				"transfer_id":       id.String(),
+				"wallet_id":         leg.WalletID.String(),
+				"impact_to_reverse": leg.GetWalletImpact(),
+			})
+			return helpers.NewInternalError("failed to reverse wallet balance", err.Error())
+		}
+
+		if err := transactionRepo.SoftDelete(ctx, leg.ID); err != nil {
+			tx.Rollback()
+			logger.LogError(funcCtx, "failed to delete transfer leg", err, logrus.Fields{
+				"transfer_id":    id.String(),
+				"transaction_id": leg.ID.String(),
+			})
+			return helpers.NewInternalError("failed to delete transfer leg", err.Error())
+		}
+	}
+
+	if err := transferRepo.SoftDelete(ctx, id); err != nil {
+		tx.Rollback()
+		logger.LogError(funcCtx, "failed to delete transfer", err, logrus.Fields{
+			"transfer_id": id.String(),
+		})
+		return helpers.NewInternalError("failed to delete transfer", err.Error())
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		logger.LogError(funcCtx, "failed to commit transfer deletion", err, logrus.Fields{
+			"transfer_id": id.String(),
+		})
+		return helpers.NewInternalError("failed to commit transfer deletion", err.Error())
+	}
+
+	return nil
+}
+
+// getOwnedTransfer loads a transfer and hides it from non-admin users who don't own it
+func (uc *TransferUseCase) getOwnedTransfer(ctx context.Context, funcCtx string, id uuid.UUID, loggedUserID uuid.UUID) (*entities.Transfer, error) {
+	transfer, err := uc.transferRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get transfer", err, logrus.Fields{
+			"transfer_id": id.String(),
+		})
+		return nil, helpers.NewNotFoundError("transfer not found", "")
+	}
+
+	if loggedUserID != uuid.Nil && loggedUserID != transfer.UserID {
+		logger.LogError(funcCtx, "unauthorized access to transfer", nil, logrus.Fields{
+			"transfer_id":      id.String(),
+			"transfer_user_id": transfer.UserID.String(),
+			"logged_user_id":   loggedUserID.String(),
+		})
+		return nil, helpers.NewNotFoundError("transfer not found", "")
+	}
+
+	return transfer, nil
+}
+
+// getTransferWallets loads the source and destination wallets and checks they are distinct and owned by userID
+func (uc *TransferUseCase) getTransferWallets(ctx context.Context, walletRepo repositories.WalletRepository, userID, fromWalletID, toWalletID uuid.UUID) (*entities.Wallet, *entities.Wallet, error) {
+	funcCtx := "getTransferWallets"
+
+	if fromWalletID == toWalletID {
+		return nil, nil, helpers.NewBadRequestError("source and destination wallets must be different", "")
+	}
+
+	fromWallet, err := walletRepo.GetByID(ctx, fromWalletID)
+	if err != nil {
+		logger.LogError(funcCtx, "source wallet not found", err, logrus.Fields{"wallet_id": fromWalletID.String()})
+		return nil, nil, helpers.NewNotFoundError("source wallet not found", "")
+	}
+
+	toWallet, err := walletRepo.GetByID(ctx, toWalletID)
+	if err != nil {
+		logger.LogError(funcCtx, "destination wallet not found", err, logrus.Fields{"wallet_id": toWalletID.String()})
+		return nil, nil, helpers.NewNotFoundError("destination wallet not found", "")
+	}
+
+	if fromWallet.UserID != userID || toWallet.UserID != userID {
+		logger.LogError(funcCtx, "wallet does not belong to user", nil, logrus.Fields{
+			"from_wallet_id":      fromWalletID.String(),
+			"from_wallet_user_id": fromWallet.UserID.String(),
+			"to_wallet_id":        toWalletID.String(),
+			"to_wallet_user_id":   toWallet.UserID.String(),
+			"request_user_id":     userID.String(),
+		})
+		return nil, nil, helpers.NewForbiddenError("both wallets must belong to the specified user", "")
+	}
+
+	return fromWallet, toWallet, nil
+}
+
+// resolveReceivedAmount returns the amount credited to the destination wallet. Between wallets of the same
+// currency it is the amount itself; otherwise it is the received amount given by the user or, when omitted,
+// the amount converted at the latest exchange rate dated on or before on.
+func (uc *TransferUseCase) resolveReceivedAmount(ctx context.Context, funcCtx string, amount, receivedAmount money.Money, fromWallet, toWallet *entities.Wallet, on time.Time) (money.Money, error) {
+	fromCurrency := currency.Normalize(fromWallet.Currency)
+	toCurrency := currency.Normalize(toWallet.Currency)
+
+	if fromCurrency == toCurrency {
+		if receivedAmount.IsPositive() && receivedAmount != amount {
+			return money.Zero, helpers.NewBadRequestError("received amount must equal amount between wallets of the same currency", "")
+		}
+		return amount, nil
+	}
+	if receivedAmount.IsPositive() {
+		return receivedAmount, nil
+	}
+
+	rates, err := loadRateTable(ctx, uc.exchangeRateRepo, []string{fromCurrency, toCurrency}, on)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get exchange rates", err, logrus.Fields{
+			"from_currency": fromCurrency,
+			"to_currency":   toCurrency,
+		})
+		return money.Zero, helpers.NewInternalError("failed to get exchange rates", err.Error())
+	}
+
+	converted, err := rates.convert(amount, fromCurrency, toCurrency, on)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to convert amount", err, logrus.Fields{
+			"from_wallet_id": fromWallet.ID.String(),
+			"to_wallet_id":   toWallet.ID.String(),
+		})
+		return money.Zero, conversionError(err, "missing exchange rate, set received_amount or load the rate")
+	}
+	if !converted.IsPositive() {
+		return money.Zero, helpers.NewBadRequestError("converted amount is too small to transfer", "")
+	}
+	return converted, nil
+}
+
+// getTransferLegs loads the outgoing and incoming transactions of a transfer
+func (uc *TransferUseCase) getTransferLegs(ctx context.Context, transactionRepo repositories.TransactionRepository, transfer *entities.Transfer) (*entities.Transaction, *entities.Transaction, error) {
+	funcCtx := "getTransferLegs"
+
+	if transfer.OutgoingTransactionID == nil || transfer.IncomingTransactionID == nil {
+		logger.LogError(funcCtx, "transfer legs are not linked", nil, logrus.Fields{"transfer_id": transfer.ID.String()})
+		return nil, nil, helpers.NewInternalError("transfer legs are not linked", "")
+	}
+
+	outgoing, err := transactionRepo.GetByID(ctx, *transfer.OutgoingTransactionID)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get outgoing leg", err, logrus.Fields{"transfer_id": transfer.ID.String()})
+		return nil, nil, helpers.NewInternalError("failed to get outgoing leg", err.Error())
+	}
+
+	incoming, err := transactionRepo.GetByID(ctx, *transfer.IncomingTransactionID)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get incoming leg", err, logrus.Fields{"transfer_id": transfer.ID.String()})
+		return nil, nil, helpers.NewInternalError("failed to get incoming leg", err.Error())
+	}
+
+	return outgoing, incoming, nil
+}
+
+// adjustWalletBalance adds delta to the balance of the leg's wallet in a single statement and records it in the ledger
+func (uc *TransferUseCase) adjustWalletBalance(ctx context.Context, walletRepo repositories.WalletRepository, leg *entities.Transaction, delta money.Money, cause entities.BalanceChangeCause) error {
+	return walletRepo.AdjustBalance(ctx, entities.NewBalanceChange(leg.WalletID, delta, cause, &leg.ID))
+}
@@ -2,11 +2,15 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
 	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/currency"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
 	"github.com/sirupsen/logrus"
@@ -16,19 +20,22 @@ type WalletUseCaseInterface interface {
 	CreateWallet(ctx context.Context, req *dto.CreateWalletRequest) (*dto.WalletResponse, error)
 	GetWallet(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID) (*dto.WalletResponse, error)
 	GetWallets(ctx context.Context, queryParams *dto.QueryParams) (*dto.PaginationData[dto.WalletResponse], error)
-	UpdateWallet(ctx context.Context, id uuid.UUID, req *dto.UpdateWalletRequest) (*dto.WalletResponse, error)
-	DeleteWallet(ctx context.Context, id uuid.UUID) error // This now does soft delete
+	UpdateWallet(ctx context.Context, id uuid.UUID, req *dto.UpdateWalletRequest, expectedVersion int64) (*dto.WalletResponse, error)
+	DeleteWallet(ctx context.Context, id uuid.UUID, expectedVersion int64) error // This now does soft delete
+	GetBalanceHistory(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, from, to time.Time) (*dto.WalletBalanceHistoryResponse, error)
 }
 
 type WalletUseCase struct {
-	walletRepo repositories.WalletRepository
-	userRepo   repositories.UserRepository
+	walletRepo       repositories.WalletRepository
+	userRepo         repositories.UserRepository
+	balanceEntryRepo repositories.WalletBalanceEntryRepository
 }
 
-func NewWalletUseCase(walletRepo repositories.WalletRepository, userRepo repositories.UserRepository) WalletUseCaseInterface {
+func NewWalletUseCase(walletRepo repositories.WalletRepository, userRepo repositories.UserRepository, balanceEntryRepo repositories.WalletBalanceEntryRepository) WalletUseCaseInterface {
 	return &WalletUseCase{
-		walletRepo: walletRepo,
-		userRepo:   userRepo,
+		walletRepo:       walletRepo,
+		userRepo:         userRepo,
+		balanceEntryRepo: balanceEntryRepo,
 	}
 }
 
@@ -43,13 +50,18 @@ func (uc *WalletUseCase) CreateWallet(ctx context.Context, req *dto.CreateWallet
 		return nil, helpers.NewConflictError("wallet with this name already exists for this user", "")
 	}
 
+	walletCurrency := currency.Default
+	if req.Currency != "" {
+		walletCurrency = currency.Normalize(req.Currency)
+	}
+
 	// Create wallet entity
 	wallet := &entities.Wallet{
 		Name:     req.Name,
 		Type:     req.Type,
 		Category: req.Category,
 		Balance:  req.Balance,
-		Currency: req.Currency,
+		Currency: walletCurrency,
 		UserID:   req.UserID,
 	}
 
@@ -114,7 +126,8 @@ func (uc *WalletUseCase) GetWallets(ctx context.Context, queryParams *dto.QueryP
 	}, nil
 }
 
-func (uc *WalletUseCase) UpdateWallet(ctx context.Context, id uuid.UUID, req *dto.UpdateWalletRequest) (*dto.WalletResponse, error) {
+// UpdateWallet updates a wallet. A non-zero expectedVersion (from If-Match) must match the stored version.
+func (uc *WalletUseCase) UpdateWallet(ctx context.Context, id uuid.UUID, req *dto.UpdateWalletRequest, expectedVersion int64) (*dto.WalletResponse, error) {
 	funcCtx := "UpdateWallet"
 
 	// Get existing wallet
@@ -126,6 +139,10 @@ func (uc *WalletUseCase) UpdateWallet(ctx context.Context, id uuid.UUID, req *dt
 		return nil, helpers.NewNotFoundError("wallet not found", "")
 	}
 
+	if err := checkVersion(funcCtx, "wallet", id, expectedVersion, wallet.Version); err != nil {
+		return nil, err
+	}
+
 	if req.Name != "" {
 		wallet.Name = req.Name
 	}
@@ -135,11 +152,11 @@ func (uc *WalletUseCase) UpdateWallet(ctx context.Context, id uuid.UUID, req *dt
 	if req.Category != "" {
 		wallet.Category = req.Category
 	}
-	if req.Balance >= 0 {
+	if !req.Balance.IsNegative() {
 		wallet.Balance = req.Balance
 	}
 	if req.Currency != "" {
-		wallet.Currency = req.Currency
+		wallet.Currency = currency.Normalize(req.Currency)
 	}
 
 	if req.UserID != uuid.Nil {
@@ -154,21 +171,24 @@ func (uc *WalletUseCase) UpdateWallet(ctx context.Context, id uuid.UUID, req *dt
 	}
 
 	// Save updated wallet
-	if err := uc.walletRepo.Update(ctx, wallet); err != nil {
+	if err := uc.walletRepo.Update(ctx, wallet, entities.BalanceCauseWalletUpdated); err != nil {
 		logger.LogError(funcCtx, "failed to update wallet", err, logrus.Fields{
 			"wallet_id": id.String(),
 		})
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			return nil, versionConflictError("wallet", expectedVersion)
+		}
 		return nil, helpers.NewInternalError("failed to update wallet", err.Error())
 	}
 
 	return dto.MapToWalletResponse(wallet), nil
 }
 
-func (uc *WalletUseCase) DeleteWallet(ctx context.Context, id uuid.UUID) error {
+func (uc *WalletUseCase) DeleteWallet(ctx context.Context, id uuid.UUID, expectedVersion int64) error {
 	funcCtx := "DeleteWallet"
 
 	// Check if wallet exists
-	_, err := uc.walletRepo.GetByID(ctx, id)
+	wallet, err := uc.walletRepo.GetByID(ctx, id)
 	if err != nil {
 		logger.LogError(funcCtx, "failed to get wallet", err, logrus.Fields{
 			"wallet_id": id.String(),
@@ -176,6 +196,10 @@ func (uc *WalletUseCase) DeleteWallet(ctx context.Context, id uuid.UUID) error {
 		return helpers.NewNotFoundError("wallet not found", "")
 	}
 
+	if err := checkVersion(funcCtx, "wallet", id, expectedVersion, wallet.Version); err != nil {
+		return err
+	}
+
 	// Use soft delete for default delete operation
 	if err := uc.walletRepo.SoftDelete(ctx, id); err != nil {
 		logger.LogError(funcCtx, "failed to delete wallet", err, logrus.Fields{
@@ -186,3 +210,98 @@ func (uc *WalletUseCase) DeleteWallet(ctx context.Context, id uuid.UUID) error {
 
 	return nil
 }
+
+// maxBalanceHistoryDays is the longest range of a balance history, one point is returned per day
+const maxBalanceHistoryDays = 366
+
+// GetBalanceHistory rebuilds the end of day balance of a wallet from the balance ledger, walking back from the
+// current balance so wallets created before the ledger existed still get a history from then on.
+// Days are UTC calendar days and the range covers at most maxBalanceHistoryDays.
+func (uc *WalletUseCase) GetBalanceHistory(ctx context.Context, id uuid.UUID, loggedUserID uuid.UUID, from, to time.Time) (*dto.WalletBalanceHistoryResponse, error) {
+	funcCtx := "GetBalanceHistory"
+
+	if to.Before(from) {
+		return nil, helpers.NewBadRequestError("end_date must not be before start_date", "")
+	}
+	if to.After(from.AddDate(0, 0, maxBalanceHistoryDays-1)) {
+		return nil, helpers.NewBadRequestError(fmt.Sprintf("the range from start_date to end_date must not exceed %d days", maxBalanceHistoryDays), "")
+	}
+
+	wallet, err := uc.walletRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get wallet", err, logrus.Fields{
+			"wallet_id": id.String(),
+		})
+		return nil, helpers.NewNotFoundError("wallet not found", "")
+	}
+	if loggedUserID != uuid.Nil && loggedUserID != wallet.UserID {
+		logger.LogError(funcCtx, "unauthorized access to wallet", nil, logrus.Fields{
+			"wallet_id":      id.String(),
+			"logged_user_id": loggedUserID.String(),
+		})
+		return nil, helpers.NewNotFoundError("wallet not found", "")
+	}
+
+	// Everything recorded after the range is undone from the current balance to get the closing balance
+	laterChanges, err := uc.balanceEntryRepo.SumSince(ctx, id, to.AddDate(0, 0, 1))
+	if err != nil {
+		logger.LogError(funcCtx, "failed to sum later balance changes", err, logrus.Fields{"wallet_id": id.String()})
+		return nil, helpers.NewInternalError("failed to get balance history", err.Error())
+	}
+
+	changes, err := uc.balanceEntryRepo.GetDailyChanges(ctx, id, from, to)
+	if err != nil {
+		logger.LogError(funcCtx, "failed to get daily balance changes", err, logrus.Fields{"wallet_id": id.String()})
+		return nil, helpers.NewInternalError("failed to get balance history", err.Error())
+	}
+
+	changesByDay := make(map[string]*repositories.DailyBalanceChange, len(changes))
+	balance := wallet.Balance.Sub(laterChanges)
+	for _, change := range changes {
+		changesByDay[change.Day.Format(currency.RateDateFormat)] = change
+		balance = balance.Sub(change.Amount)
+	}
+
+	response := &dto.WalletBalanceHistoryResponse{
+		WalletID:       wallet.ID,
+		Currency:       wallet.Currency,
+		StartDate:      from.Format(currency.RateDateFormat),
+		EndDate:        to.Format(currency.RateDateFormat),
+		OpeningBalance: balance,
+		Points:         []dto.WalletBalancePoint{},
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		point := dto.WalletBalancePoint{Date: day.Format(currency.RateDateFormat)}
+		if change, ok := changesByDay[point.Date]; ok {
+			point.Change = change.Amount
+			point.EntryCount = change.EntryCount
+			balance = balance.Add(change.Amount)
+		}
+		point.Balance = balance
+		response.Points = append(response.Points, point)
+	}
+
+	return response, nil
+}
+
+// checkVersion fails with 412 when the client sent an If-Match version (expectedVersion) that is no longer current
+func checkVersion(funcCtx, resource string, id uuid.UUID, expectedVersion, currentVersion int64) error {
+	if expectedVersion == 0 || expectedVersion == currentVersion {
+		return nil
+	}
+	logger.LogError(funcCtx, resource+" version mismatch", nil, logrus.Fields{
+		"id":               id.String(),
+		"expected_version": expectedVersion,
+		"current_version":  currentVersion,
+	})
+	return helpers.NewPreconditionFailedError(resource+" was changed by another request", fmt.Sprintf("current version is %d", currentVersion))
+}
+
+// versionConflictError reports a row that changed between reading and writing it: 412 when the client asked for a
+// specific version, 409 otherwise so the client knows to reload and retry
+func versionConflictError(resource string, expectedVersion int64) error {
+	if expectedVersion != 0 {
+		return helpers.NewPreconditionFailedError(resource+" was changed by another request", "")
+	}
+	return helpers.NewConflictError(resource+" was changed by another request, reload it and try again", "")
+}
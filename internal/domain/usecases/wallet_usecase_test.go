@@ -8,8 +8,11 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/repositories"
 	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -43,8 +46,18 @@ func (m *MockWalletRepository) GetAll(ctx context.Context, queryParams *dto.Quer
 	return args.Get(0).([]*entities.Wallet), args.Error(1)
 }
 
-func (m *MockWalletRepository) Update(ctx context.Context, wallet *entities.Wallet) error {
-	args := m.Called(ctx, wallet)
+func (m *MockWalletRepository) GetBatchAfter(ctx context.Context, afterID uuid.UUID, limit int) ([]*entities.Wallet, error) {
+	args := m.Called(ctx, afterID, limit)
+	return args.Get(0).([]*entities.Wallet), args.Error(1)
+}
+
+func (m *MockWalletRepository) Update(ctx context.Context, wallet *entities.Wallet, cause entities.BalanceChangeCause) error {
+	args := m.Called(ctx, wallet, cause)
+	return args.Error(0)
+}
+
+func (m *MockWalletRepository) AdjustBalance(ctx context.Context, change *entities.WalletBalanceEntry) error {
+	args := m.Called(ctx, change)
 	return args.Error(0)
 }
 
@@ -203,7 +216,7 @@ func (suite *WalletUseCaseTestSuite) SetupTest() {
 
 	suite.walletRepo = new(MockWalletRepository)
 	suite.userRepo = new(MockUserRepository)
-	suite.useCase = NewWalletUseCase(suite.walletRepo, suite.userRepo)
+	suite.useCase = NewWalletUseCase(suite.walletRepo, suite.userRepo, nil)
 	suite.ctx = context.Background()
 }
 
@@ -220,7 +233,7 @@ func (suite *WalletUseCaseTestSuite) TestCreateWallet_Success() {
 		Name:     "Test Wallet",
 		Type:     "personal",
 		Category: "income",
-		Balance:  1000.0,
+		Balance:  money.FromInt(1000),
 		Currency: "IDR",
 		UserID:   userID,
 	}
@@ -256,7 +269,7 @@ func (suite *WalletUseCaseTestSuite) TestCreateWallet_WalletExists() {
 		Name:     "Test Wallet",
 		Type:     "personal",
 		Category: "income",
-		Balance:  1000.0,
+		Balance:  money.FromInt(1000),
 		Currency: "IDR",
 		UserID:   userID,
 	}
@@ -290,7 +303,7 @@ func (suite *WalletUseCaseTestSuite) TestCreateWallet_CreateFails() {
 		Name:     "Test Wallet",
 		Type:     "personal",
 		Category: "income",
-		Balance:  1000.0,
+		Balance:  money.FromInt(1000),
 		Currency: "IDR",
 		UserID:   userID,
 	}
@@ -323,7 +336,7 @@ func (suite *WalletUseCaseTestSuite) TestGetWallet_Success() {
 		Name:     "Test Wallet",
 		Type:     "personal",
 		Category: "income",
-		Balance:  1000.0,
+		Balance:  money.FromInt(1000),
 		Currency: "IDR",
 		UserID:   userID,
 		User: entities.User{
@@ -401,7 +414,7 @@ func (suite *WalletUseCaseTestSuite) TestGetWallets_Success() {
 			Name:      "Wallet 1",
 			Type:      "personal",
 			Category:  "income",
-			Balance:   1000.0,
+			Balance:   money.FromInt(1000),
 			Currency:  "IDR",
 			UserID:    queryParams.LoggedUserID,
 			CreatedAt: time.Now(),
@@ -412,7 +425,7 @@ func (suite *WalletUseCaseTestSuite) TestGetWallets_Success() {
 			Name:      "Wallet 2",
 			Type:      "business",
 			Category:  "expense",
-			Balance:   500.0,
+			Balance:   money.FromInt(500),
 			Currency:  "USD",
 			UserID:    queryParams.LoggedUserID,
 			CreatedAt: time.Now(),
@@ -489,7 +502,7 @@ func (suite *WalletUseCaseTestSuite) TestUpdateWallet_Success() {
 		Name:     "Old Wallet",
 		Type:     "personal",
 		Category: "income",
-		Balance:  1000.0,
+		Balance:  money.FromInt(1000),
 		Currency: "IDR",
 		UserID:   userID,
 	}
@@ -498,7 +511,7 @@ func (suite *WalletUseCaseTestSuite) TestUpdateWallet_Success() {
 		Name:     "Updated Wallet",
 		Type:     "business",
 		Category: "expense",
-		Balance:  2000.0,
+		Balance:  money.FromInt(2000),
 		Currency: "USD",
 		UserID:   newUserID,
 	}
@@ -522,10 +535,10 @@ func (suite *WalletUseCaseTestSuite) TestUpdateWallet_Success() {
 			wallet.Balance == req.Balance &&
 			wallet.Currency == req.Currency &&
 			wallet.UserID == req.UserID
-	})).Return(nil)
+	}), entities.BalanceCauseWalletUpdated).Return(nil)
 
 	// Act
-	result, err := suite.useCase.UpdateWallet(suite.ctx, walletID, req)
+	result, err := suite.useCase.UpdateWallet(suite.ctx, walletID, req, 0)
 
 	// Assert
 	assert.NoError(suite.T(), err)
@@ -540,7 +553,7 @@ func (suite *WalletUseCaseTestSuite) TestUpdateWallet_PartialUpdate() {
 		Name:     "Old Wallet",
 		Type:     "personal",
 		Category: "income",
-		Balance:  1000.0,
+		Balance:  money.FromInt(1000),
 		Currency: "IDR",
 		UserID:   uuid.New(),
 	}
@@ -561,10 +574,10 @@ func (suite *WalletUseCaseTestSuite) TestUpdateWallet_PartialUpdate() {
 			wallet.Balance == existingWallet.Balance &&
 			wallet.Currency == existingWallet.Currency &&
 			wallet.UserID == existingWallet.UserID
-	})).Return(nil)
+	}), entities.BalanceCauseWalletUpdated).Return(nil)
 
 	// Act
-	result, err := suite.useCase.UpdateWallet(suite.ctx, walletID, req)
+	result, err := suite.useCase.UpdateWallet(suite.ctx, walletID, req, 0)
 
 	// Assert
 	assert.NoError(suite.T(), err)
@@ -583,7 +596,7 @@ func (suite *WalletUseCaseTestSuite) TestUpdateWallet_WalletNotFound() {
 		Return((*entities.Wallet)(nil), errors.New("wallet not found"))
 
 	// Act
-	result, err := suite.useCase.UpdateWallet(suite.ctx, walletID, req)
+	result, err := suite.useCase.UpdateWallet(suite.ctx, walletID, req, 0)
 
 	// Assert
 	assert.Error(suite.T(), err)
@@ -612,7 +625,7 @@ func (suite *WalletUseCaseTestSuite) TestUpdateWallet_UserNotFound() {
 		Return((*entities.User)(nil), errors.New("user not found"))
 
 	// Act
-	result, err := suite.useCase.UpdateWallet(suite.ctx, walletID, req)
+	result, err := suite.useCase.UpdateWallet(suite.ctx, walletID, req, 0)
 
 	// Assert
 	assert.Error(suite.T(), err)
@@ -636,11 +649,11 @@ func (suite *WalletUseCaseTestSuite) TestUpdateWallet_UpdateFails() {
 	suite.walletRepo.On("GetByID", suite.ctx, walletID).Return(existingWallet, nil)
 
 	// Mock: update fails
-	suite.walletRepo.On("Update", suite.ctx, mock.AnythingOfType("*entities.Wallet")).
+	suite.walletRepo.On("Update", suite.ctx, mock.AnythingOfType("*entities.Wallet"), entities.BalanceCauseWalletUpdated).
 		Return(errors.New("database error"))
 
 	// Act
-	result, err := suite.useCase.UpdateWallet(suite.ctx, walletID, req)
+	result, err := suite.useCase.UpdateWallet(suite.ctx, walletID, req, 0)
 
 	// Assert
 	assert.Error(suite.T(), err)
@@ -663,7 +676,7 @@ func (suite *WalletUseCaseTestSuite) TestDeleteWallet_Success() {
 	suite.walletRepo.On("SoftDelete", suite.ctx, walletID).Return(nil)
 
 	// Act
-	err := suite.useCase.DeleteWallet(suite.ctx, walletID)
+	err := suite.useCase.DeleteWallet(suite.ctx, walletID, 0)
 
 	// Assert
 	assert.NoError(suite.T(), err)
@@ -678,7 +691,7 @@ func (suite *WalletUseCaseTestSuite) TestDeleteWallet_WalletNotFound() {
 		Return((*entities.Wallet)(nil), errors.New("wallet not found"))
 
 	// Act
-	err := suite.useCase.DeleteWallet(suite.ctx, walletID)
+	err := suite.useCase.DeleteWallet(suite.ctx, walletID, 0)
 
 	// Assert
 	assert.Error(suite.T(), err)
@@ -700,7 +713,7 @@ func (suite *WalletUseCaseTestSuite) TestDeleteWallet_SoftDeleteFails() {
 		Return(errors.New("database error"))
 
 	// Act
-	err := suite.useCase.DeleteWallet(suite.ctx, walletID)
+	err := suite.useCase.DeleteWallet(suite.ctx, walletID, 0)
 
 	// Assert
 	assert.Error(suite.T(), err)
@@ -715,7 +728,7 @@ func (suite *WalletUseCaseTestSuite) TestUpdateWallet_UserIDIsNil() {
 		Name:     "Old Wallet",
 		Type:     "personal",
 		Category: "income",
-		Balance:  1000.0,
+		Balance:  money.FromInt(1000),
 		Currency: "IDR",
 		UserID:   uuid.New(),
 	}
@@ -733,17 +746,103 @@ func (suite *WalletUseCaseTestSuite) TestUpdateWallet_UserIDIsNil() {
 		return wallet.ID == walletID &&
 			wallet.Name == req.Name &&
 			wallet.UserID == existingWallet.UserID // Should remain unchanged
-	})).Return(nil)
+	}), entities.BalanceCauseWalletUpdated).Return(nil)
 
 	// Act
-	result, err := suite.useCase.UpdateWallet(suite.ctx, walletID, req)
+	result, err := suite.useCase.UpdateWallet(suite.ctx, walletID, req, 0)
 
 	// Assert
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), result)
 }
 
+func (suite *WalletUseCaseTestSuite) TestUpdateWallet_StaleIfMatchVersion() {
+	// Arrange
+	walletID := uuid.New()
+	existingWallet := &entities.Wallet{ID: walletID, Name: "Old Wallet", Version: 3}
+	suite.walletRepo.On("GetByID", suite.ctx, walletID).Return(existingWallet, nil)
+
+	// Act
+	result, err := suite.useCase.UpdateWallet(suite.ctx, walletID, &dto.UpdateWalletRequest{Name: "Updated Wallet"}, 2)
+
+	// Assert
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), helpers.ErrorTypePreconditionFailed, helpers.GetErrorType(err))
+	suite.walletRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *WalletUseCaseTestSuite) TestUpdateWallet_ChangedWhileUpdating() {
+	// Arrange
+	walletID := uuid.New()
+	existingWallet := &entities.Wallet{ID: walletID, Name: "Old Wallet", Version: 3}
+	suite.walletRepo.On("GetByID", suite.ctx, walletID).Return(existingWallet, nil)
+	suite.walletRepo.On("Update", suite.ctx, existingWallet, entities.BalanceCauseWalletUpdated).Return(repositories.ErrVersionConflict)
+
+	// Act
+	_, errWithIfMatch := suite.useCase.UpdateWallet(suite.ctx, walletID, &dto.UpdateWalletRequest{Name: "Updated Wallet"}, 3)
+	_, errWithoutIfMatch := suite.useCase.UpdateWallet(suite.ctx, walletID, &dto.UpdateWalletRequest{Name: "Updated Wallet"}, 0)
+
+	// Assert
+	assert.Equal(suite.T(), helpers.ErrorTypePreconditionFailed, helpers.GetErrorType(errWithIfMatch))
+	assert.Equal(suite.T(), helpers.ErrorTypeConflict, helpers.GetErrorType(errWithoutIfMatch))
+}
+
 // Run the test suite
 func TestWalletUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(WalletUseCaseTestSuite))
 }
+
+// stubBalanceEntryRepository serves fixed ledger sums, the way the SQL groups them by day
+type stubBalanceEntryRepository struct {
+	sumSince money.Money
+	changes  []*repositories.DailyBalanceChange
+}
+
+func (r *stubBalanceEntryRepository) SumSince(ctx context.Context, walletID uuid.UUID, since time.Time) (money.Money, error) {
+	return r.sumSince, nil
+}
+
+func (r *stubBalanceEntryRepository) GetDailyChanges(ctx context.Context, walletID uuid.UUID, from, to time.Time) ([]*repositories.DailyBalanceChange, error) {
+	return r.changes, nil
+}
+
+func TestGetBalanceHistory_WalksBackFromCurrentBalance(t *testing.T) {
+	logger.Init("info")
+	ctx := context.Background()
+	userID := uuid.New()
+	wallet := &entities.Wallet{ID: uuid.New(), UserID: userID, Balance: money.FromInt(1000), Currency: "IDR"}
+	walletRepo := new(MockWalletRepository)
+	walletRepo.On("GetByID", ctx, wallet.ID).Return(wallet, nil)
+	day := func(d int) time.Time { return time.Date(2024, time.March, d, 0, 0, 0, 0, time.UTC) }
+	balanceEntryRepo := &stubBalanceEntryRepository{
+		sumSince: money.FromInt(100), // Recorded after March 4th
+		changes: []*repositories.DailyBalanceChange{
+			{Day: day(2), Amount: money.FromInt(-50), EntryCount: 1},
+			{Day: day(3), Amount: money.FromInt(200), EntryCount: 2},
+		},
+	}
+	useCase := NewWalletUseCase(walletRepo, nil, balanceEntryRepo)
+
+	history, err := useCase.GetBalanceHistory(ctx, wallet.ID, userID, day(1), day(4))
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromInt(750), history.OpeningBalance)
+	var balances []money.Money
+	for _, point := range history.Points {
+		balances = append(balances, point.Balance)
+	}
+	assert.Equal(t, []money.Money{money.FromInt(750), money.FromInt(700), money.FromInt(900), money.FromInt(900)}, balances)
+	assert.Equal(t, "2024-03-03", history.Points[2].Date)
+	assert.Equal(t, int64(2), history.Points[2].EntryCount)
+
+	_, err = useCase.GetBalanceHistory(ctx, wallet.ID, uuid.New(), day(1), day(4))
+	assert.Equal(t, helpers.ErrorTypeNotFound, helpers.GetErrorType(err))
+
+	// A year of points is the most one request returns
+	history, err = useCase.GetBalanceHistory(ctx, wallet.ID, userID, day(1), day(1).AddDate(0, 0, maxBalanceHistoryDays-1))
+	assert.NoError(t, err)
+	assert.Len(t, history.Points, maxBalanceHistoryDays)
+
+	_, err = useCase.GetBalanceHistory(ctx, wallet.ID, userID, day(1), day(1).AddDate(0, 0, maxBalanceHistoryDays))
+	assert.Equal(t, helpers.ErrorTypeBadRequest, helpers.GetErrorType(err))
+}
@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+)
+
+// Response DTOs
+type BalanceSyncReportResponse struct {
+	ID         *uuid.UUID                       `json:"id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"` // Empty for dry runs, which are not stored
+	DryRun     bool                             `json:"dry_run" example:"false"`
+	Wallets    int                              `json:"wallets" example:"120"`     // Number of wallets checked
+	Drifted    int                              `json:"drifted" example:"2"`       // Wallets whose stored balance differs from their transactions
+	Corrected  int                              `json:"corrected" example:"2"`     // Drifted wallets whose balance was written, always 0 for dry runs
+	Failed     int                              `json:"failed" example:"0"`        // Wallets that could not be checked or corrected
+	Cancelled  bool                             `json:"cancelled" example:"false"` // The run stopped before checking every wallet
+	StartedAt  time.Time                        `json:"started_at" example:"2024-01-01T00:00:00Z"`
+	FinishedAt time.Time                        `json:"finished_at" example:"2024-01-01T00:00:05Z"`
+	Entries    []BalanceSyncReportEntryResponse `json:"entries,omitempty"` // Drifted and failed wallets, left out of report lists
+}
+
+type BalanceSyncReportEntryResponse struct {
+	WalletID          uuid.UUID   `json:"wallet_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	StoredBalance     money.Money `json:"stored_balance" swaggertype:"number" example:"1500000.00"`
+	RecomputedBalance money.Money `json:"recomputed_balance" swaggertype:"number" example:"1450000.00"`
+	Difference        money.Money `json:"difference" swaggertype:"number" example:"-50000.00"` // Recomputed minus stored balance
+	TransactionCount  int         `json:"transaction_count" example:"42"`
+	Corrected         bool        `json:"corrected" example:"true"`
+	Error             string      `json:"error,omitempty" example:""`
+}
+
+// BalanceSyncProgressResponse is the progress of the running or last finished balance sync
+type BalanceSyncProgressResponse struct {
+	Running   bool      `json:"running" example:"true"`
+	DryRun    bool      `json:"dry_run" example:"false"`
+	Total     int64     `json:"total" example:"12000"`    // Wallets when the run started
+	Processed int       `json:"processed" example:"4500"` // Wallets checked so far
+	Drifted   int       `json:"drifted" example:"2"`
+	Corrected int       `json:"corrected" example:"2"`
+	Failed    int       `json:"failed" example:"0"`
+	StartedAt time.Time `json:"started_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2024-01-01T00:00:03Z"`
+}
+
+// MapToBalanceSyncReportResponse converts a BalanceSyncReport entity to BalanceSyncReportResponse DTO
+func MapToBalanceSyncReportResponse(report *entities.BalanceSyncReport, dryRun bool) *BalanceSyncReportResponse {
+	response := &BalanceSyncReportResponse{
+		DryRun:     dryRun,
+		Wallets:    report.Wallets,
+		Drifted:    report.Drifted,
+		Corrected:  report.Corrected,
+		Failed:     report.Failed,
+		Cancelled:  report.Cancelled,
+		StartedAt:  report.StartedAt,
+		FinishedAt: report.FinishedAt,
+	}
+	if !dryRun {
+		id := report.ID
+		response.ID = &id
+	}
+
+	for _, entry := range report.Entries {
+		response.Entries = append(response.Entries, BalanceSyncReportEntryResponse{
+			WalletID:          entry.WalletID,
+			StoredBalance:     entry.StoredBalance,
+			RecomputedBalance: entry.RecomputedBalance,
+			Difference:        entry.Difference,
+			TransactionCount:  entry.TransactionCount,
+			Corrected:         entry.Corrected,
+			Error:             entry.Error,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+)
+
+// Request DTOs
+type CreateBudgetRequest struct {
+	Name            string      `json:"name" validate:"required,min=2,max=255" example:"Food budget"`
+	UserID          uuid.UUID   `json:"user_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WalletID        *uuid.UUID  `json:"wallet_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	TCategory       string      `json:"t_category" validate:"required,min=2,max=100" example:"food"`
+	Period          string      `json:"period" validate:"required,oneof=weekly monthly yearly" example:"monthly"`
+	Amount          money.Money `json:"amount" swaggertype:"number" validate:"required,gt=0,money" example:"2000000.00"`
+	Rollover        bool        `json:"rollover" example:"false"`
+	AlertsEnabled   *bool       `json:"alerts_enabled" example:"true"`                                                    // Defaults to true
+	AlertThresholds []int       `json:"alert_thresholds" validate:"omitempty,max=5,dive,min=1,max=1000" example:"80,100"` // Percentages of the limit, defaults to 80 and 100
+}
+
+type UpdateBudgetRequest struct {
+	Name            string      `json:"name" validate:"omitempty,min=2,max=255" example:"Food budget"`
+	WalletID        *uuid.UUID  `json:"wallet_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	ClearWallet     bool        `json:"clear_wallet" example:"false"` // Remove the wallet scope so the budget covers all wallets
+	TCategory       string      `json:"t_category" validate:"omitempty,min=2,max=100" example:"food"`
+	Period          string      `json:"period" validate:"omitempty,oneof=weekly monthly yearly" example:"monthly"`
+	Amount          money.Money `json:"amount" swaggertype:"number" validate:"omitempty,gt=0,money" example:"2500000.00"`
+	Rollover        *bool       `json:"rollover" example:"true"`
+	AlertsEnabled   *bool       `json:"alerts_enabled" example:"true"`
+	AlertThresholds []int       `json:"alert_thresholds" validate:"omitempty,max=5,dive,min=1,max=1000" example:"50,90,100"`
+}
+
+// Response DTOs
+type BudgetResponse struct {
+	ID              uuid.UUID       `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name            string          `json:"name" example:"Food budget"`
+	UserID          uuid.UUID       `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WalletID        *uuid.UUID      `json:"wallet_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	TCategory       string          `json:"t_category" example:"food"`
+	Period          string          `json:"period" example:"monthly"`
+	Amount          money.Money     `json:"amount" swaggertype:"number" example:"2000000.00"`
+	Rollover        bool            `json:"rollover" example:"false"`
+	AlertsEnabled   bool            `json:"alerts_enabled" example:"true"`
+	AlertThresholds []int           `json:"alert_thresholds" example:"80,100"`
+	Wallet          *WalletResponse `json:"wallet,omitempty"`
+	CreatedAt       time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt       time.Time       `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+type BudgetProgressResponse struct {
+	BudgetID         uuid.UUID   `json:"budget_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	TCategory        string      `json:"t_category" example:"food"`
+	Period           string      `json:"period" example:"monthly"`
+	PeriodStart      time.Time   `json:"period_start" example:"2024-01-01T00:00:00Z"`
+	PeriodEnd        time.Time   `json:"period_end" example:"2024-01-31T23:59:59.999999Z"`
+	Amount           money.Money `json:"amount" swaggertype:"number" example:"2000000.00"`         // Configured limit per period
+	RolloverAmount   money.Money `json:"rollover_amount" swaggertype:"number" example:"150000.00"` // Carried over from past periods, negative when overspent
+	Limit            money.Money `json:"limit" swaggertype:"number" example:"2150000.00"`          // Amount plus rollover
+	Spent            money.Money `json:"spent" swaggertype:"number" example:"1250000.00"`
+	Remaining        money.Money `json:"remaining" swaggertype:"number" example:"900000.00"`
+	PercentUsed      float64     `json:"percent_used" example:"58.14"`
+	TransactionCount int64       `json:"transaction_count" example:"12"`
+	IsExceeded       bool        `json:"is_exceeded" example:"false"`
+}
+
+type BudgetAlertRunResponse struct {
+	Budgets    int `json:"budgets" example:"25"`    // Number of budgets evaluated
+	AlertsSent int `json:"alerts_sent" example:"2"` // Emails sent
+	Failed     int `json:"failed" example:"0"`      // Budgets that failed and will be retried on the next run
+}
+
+// MapToBudgetResponse converts a Budget entity to BudgetResponse DTO
+func MapToBudgetResponse(budget *entities.Budget) *BudgetResponse {
+	response := &BudgetResponse{
+		ID:              budget.ID,
+		Name:            budget.Name,
+		UserID:          budget.UserID,
+		WalletID:        budget.WalletID,
+		TCategory:       budget.TCategory,
+		Period:          string(budget.Period),
+		Amount:          budget.Amount,
+		Rollover:        budget.Rollover,
+		AlertsEnabled:   budget.AlertsEnabled,
+		AlertThresholds: budget.GetAlertThresholds(),
+		CreatedAt:       budget.CreatedAt,
+		UpdatedAt:       budget.UpdatedAt,
+	}
+
+	// Include wallet data if it's preloaded
+	if budget.Wallet != nil && budget.Wallet.ID != uuid.Nil {
+		response.Wallet = MapToWalletResponse(budget.Wallet)
+	}
+
+	return response
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+)
+
+// Request DTOs
+type CreateCategoryRequest struct {
+	Name     string     `json:"name" validate:"required,min=2,max=100" example:"Restaurants"`
+	Type     string     `json:"type" validate:"required,oneof=income expense" example:"expense"`
+	ParentID *uuid.UUID `json:"parent_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174001"` // Must have the same type
+	Icon     string     `json:"icon" validate:"omitempty,max=50" example:"utensils"`
+	Color    string     `json:"color" validate:"omitempty,hexcolor,len=7" example:"#F97316"`
+	UserID   uuid.UUID  `json:"user_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+type UpdateCategoryRequest struct {
+	Name        string     `json:"name" validate:"omitempty,min=2,max=100" example:"Dining out"`
+	ParentID    *uuid.UUID `json:"parent_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	ClearParent bool       `json:"clear_parent" example:"false"` // Move the category to the top level
+	Icon        string     `json:"icon" validate:"omitempty,max=50" example:"utensils"`
+	Color       string     `json:"color" validate:"omitempty,hexcolor,len=7" example:"#F97316"`
+	IsArchived  *bool      `json:"is_archived" example:"true"` // Archived categories can't be assigned to new transactions
+}
+
+// Response DTOs
+type CategoryResponse struct {
+	ID         uuid.UUID  `json:"id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	UserID     uuid.UUID  `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ParentID   *uuid.UUID `json:"parent_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Name       string     `json:"name" example:"Restaurants"`
+	Type       string     `json:"type" example:"expense"`
+	Icon       string     `json:"icon" example:"utensils"`
+	Color      string     `json:"color" example:"#F97316"`
+	IsArchived bool       `json:"is_archived" example:"false"`
+	CreatedAt  time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt  time.Time  `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// MapToCategoryResponse converts a Category entity to CategoryResponse DTO
+func MapToCategoryResponse(category *entities.Category) *CategoryResponse {
+	return &CategoryResponse{
+		ID:         category.ID,
+		UserID:     category.UserID,
+		ParentID:   category.ParentID,
+		Name:       category.Name,
+		Type:       string(category.Type),
+		Icon:       category.Icon,
+		Color:      category.Color,
+		IsArchived: category.IsArchived,
+		CreatedAt:  category.CreatedAt,
+		UpdatedAt:  category.UpdatedAt,
+	}
+}
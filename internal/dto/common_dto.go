@@ -19,6 +19,12 @@ type PaginationMeta struct {
 	TotalPages int   `json:"total_pages" example:"10"`
 }
 
+// CursorPaginationMeta describes a page of a listing that continues after an opaque cursor instead of an offset
+type CursorPaginationMeta struct {
+	Limit      int    `json:"limit" example:"10"`
+	NextCursor string `json:"next_cursor,omitempty" example:"MTcwNDA2NzIwMDAwMDAwMDAwMA"` // Passed as ?cursor= for the next page, empty on the last one
+}
+
 // Filter DTOs
 type FilterQuery struct {
 	Search   string            `json:"search" query:"search" validate:"omitempty,max=255"`
@@ -52,6 +58,11 @@ type PaginationData[T any] struct {
 	Meta *PaginationMeta `json:"meta"`
 }
 
+type CursorPaginationData[T any] struct {
+	Data []T                   `json:"data"`
+	Meta *CursorPaginationMeta `json:"meta"`
+}
+
 // GetOffset calculates the offset for database queries
 func (p *PaginationQuery) GetOffset() int {
 	return (p.Page - 1) * p.Limit
@@ -104,3 +115,20 @@ func (f *FilterQuery) GetPreloadRelations() []string {
 	}
 	return relations
 }
+
+// Clone returns a deep copy of the filter query. Fiber query strings point into the request buffer,
+// so a copy is needed when the query outlives the handler (e.g. in a streamed response).
+func (f *FilterQuery) Clone() *FilterQuery {
+	filters := make(map[string]string, len(f.Filters))
+	for key, value := range f.Filters {
+		filters[strings.Clone(key)] = strings.Clone(value)
+	}
+
+	return &FilterQuery{
+		Search:   strings.Clone(f.Search),
+		SortBy:   strings.Clone(f.SortBy),
+		SortType: strings.Clone(f.SortType),
+		Filters:  filters,
+		Preload:  strings.Clone(f.Preload),
+	}
+}
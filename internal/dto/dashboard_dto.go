@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+)
+
+// Response DTOs
+
+// MonthlySummaryResponse reports the monthly totals of every wallet converted to one currency
+type MonthlySummaryResponse struct {
+	Currency string                `json:"currency" example:"USD"`
+	Months   []MonthlySummaryMonth `json:"months"`
+}
+
+type MonthlySummaryMonth struct {
+	Month            string                 `json:"month" example:"2024-01"`
+	TransactionCount int64                  `json:"transaction_count" example:"42"`
+	TotalCost        money.Money            `json:"total_cost" swaggertype:"number" example:"1250.75"` // In the requested currency
+	Wallets          []MonthlySummaryWallet `json:"wallets"`
+}
+
+type MonthlySummaryWallet struct {
+	WalletID          uuid.UUID   `json:"wallet_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WalletCurrency    string      `json:"wallet_currency" example:"IDR"`
+	TransactionCount  int64       `json:"transaction_count" example:"30"`
+	OriginalTotalCost money.Money `json:"original_total_cost" swaggertype:"number" example:"15500000"` // In the wallet currency
+	TotalCost         money.Money `json:"total_cost" swaggertype:"number" example:"1000"`              // In the requested currency
+}
+
+// BalanceSummaryResponse reports the total balance of a user's wallets in one currency
+type BalanceSummaryResponse struct {
+	Currency     string                 `json:"currency" example:"USD"`
+	Date         string                 `json:"date" example:"2024-01-31"` // Rates dated on or before this day are used
+	TotalBalance money.Money            `json:"total_balance" swaggertype:"number" example:"2500.5"`
+	Wallets      []BalanceSummaryWallet `json:"wallets"`
+}
+
+type BalanceSummaryWallet struct {
+	WalletID         uuid.UUID   `json:"wallet_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name             string      `json:"name" example:"Main account"`
+	WalletCurrency   string      `json:"wallet_currency" example:"IDR"`
+	Balance          money.Money `json:"balance" swaggertype:"number" example:"15500000"`       // In the wallet currency
+	ConvertedBalance money.Money `json:"converted_balance" swaggertype:"number" example:"1000"` // In the requested currency
+}
+
+// TagSummaryResponse reports the income and expense of each tag over a date range in one currency
+type TagSummaryResponse struct {
+	Currency  string          `json:"currency" example:"IDR"`
+	StartDate string          `json:"start_date" example:"2024-01-01"`
+	EndDate   string          `json:"end_date" example:"2024-01-31"`
+	Tags      []TagSummaryTag `json:"tags"`
+}
+
+type TagSummaryTag struct {
+	TagID            uuid.UUID   `json:"tag_id" example:"123e4567-e89b-12d3-a456-426614174007"`
+	Name             string      `json:"name" example:"trip-bali-2026"`
+	TransactionCount int64       `json:"transaction_count" example:"12"`
+	Income           money.Money `json:"income" swaggertype:"number" example:"0"`
+	Expense          money.Money `json:"expense" swaggertype:"number" example:"4250000"`
+	Net              money.Money `json:"net" swaggertype:"number" example:"-4250000"` // Income minus expense
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/currency"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+)
+
+// Request DTOs
+type ExchangeRateInput struct {
+	Date  string     `json:"date" validate:"required,datetime=2006-01-02" example:"2024-01-05"`
+	Base  string     `json:"base" validate:"required,currency" example:"USD"`
+	Quote string     `json:"quote" validate:"required,currency,nefield=Base" example:"IDR"`
+	Rate  money.Rate `json:"rate" swaggertype:"number" example:"15500.25"` // Price of 1 base unit in the quote currency
+}
+
+type UpsertExchangeRatesRequest struct {
+	Rates []ExchangeRateInput `json:"rates" validate:"required,min=1,max=1000,dive"`
+}
+
+type ImportExchangeRatesRequest struct {
+	File *multipart.FileHeader `json:"-" form:"file" validate:"omitempty" swaggerignore:"true"`
+}
+
+// Response DTOs
+type ExchangeRateResponse struct {
+	ID        uuid.UUID  `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Date      string     `json:"date" example:"2024-01-05"`
+	Base      string     `json:"base" example:"USD"`
+	Quote     string     `json:"quote" example:"IDR"`
+	Rate      money.Rate `json:"rate" swaggertype:"number" example:"15500.25"`
+	Source    string     `json:"source" example:"admin"`
+	CreatedAt time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt time.Time  `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+type ExchangeRateLoadResponse struct {
+	Loaded   int    `json:"loaded" example:"365"`
+	DateFrom string `json:"date_from" example:"2024-01-01"`
+	DateTo   string `json:"date_to" example:"2024-12-31"`
+}
+
+// MapToExchangeRateResponse converts an ExchangeRate entity to ExchangeRateResponse DTO
+func MapToExchangeRateResponse(rate *entities.ExchangeRate) *ExchangeRateResponse {
+	return &ExchangeRateResponse{
+		ID:        rate.ID,
+		Date:      rate.Date.Format(currency.RateDateFormat),
+		Base:      rate.Base,
+		Quote:     rate.Quote,
+		Rate:      rate.Rate,
+		Source:    rate.Source,
+		CreatedAt: rate.CreatedAt,
+		UpdatedAt: rate.UpdatedAt,
+	}
+}
@@ -0,0 +1,6 @@
+package dto
+
+// IdempotencyKeyPurgeResponse is the outcome of a run of the expired idempotency key cleanup
+type IdempotencyKeyPurgeResponse struct {
+	Deleted int64 `json:"deleted" example:"120"`
+}
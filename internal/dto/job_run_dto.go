@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+)
+
+// Response DTOs
+type JobRunResponse struct {
+	ID         uuid.UUID       `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	JobName    string          `json:"job_name" example:"balance_sync"`
+	Trigger    string          `json:"trigger" example:"scheduled"` // scheduled or manual
+	Status     string          `json:"status" example:"succeeded"`  // running, succeeded or failed
+	StartedAt  time.Time       `json:"started_at" example:"2024-01-01T00:00:00Z"`
+	FinishedAt *time.Time      `json:"finished_at" example:"2024-01-01T00:00:05Z"`
+	DurationMs int64           `json:"duration_ms" example:"5000"`
+	Error      string          `json:"error,omitempty" example:""`
+	Summary    json.RawMessage `json:"summary,omitempty" swaggertype:"object"` // Result of the job
+}
+
+// Request DTOs
+type RescheduleJobRequest struct {
+	Schedule string `json:"schedule" validate:"required,max=100" example:"0 2 * * *"`    // Standard 5-field cron expression
+	Timezone string `json:"timezone" validate:"omitempty,max=64" example:"Asia/Jakarta"` // IANA time zone, keeps the current one when empty
+}
+
+// JobStatusResponse is a registered job with its schedule and recent history
+type JobStatusResponse struct {
+	Name                string          `json:"name" example:"balance_sync"`
+	Schedule            string          `json:"schedule" example:"0 0 * * *"`
+	Timezone            string          `json:"timezone" example:"UTC"`
+	Enabled             bool            `json:"enabled" example:"true"`                     // Paused jobs are not scheduled but can still be triggered manually
+	NextRunAt           *time.Time      `json:"next_run_at" example:"2024-01-02T00:00:00Z"` // Empty while the worker is stopped
+	LastRun             *JobRunResponse `json:"last_run"`
+	LastSuccessAt       *time.Time      `json:"last_success_at" example:"2024-01-01T00:00:05Z"`
+	ConsecutiveFailures int64           `json:"consecutive_failures" example:"0"`          // Failed runs since the last successful one
+	RunningOn           string          `json:"running_on,omitempty" example:"api-7f9c-1"` // Instance ID of the replica holding the job lock
+}
+
+// MapToJobRunResponse converts a JobRun entity to JobRunResponse DTO
+func MapToJobRunResponse(run *entities.JobRun) *JobRunResponse {
+	return &JobRunResponse{
+		ID:         run.ID,
+		JobName:    run.JobName,
+		Trigger:    string(run.Trigger),
+		Status:     string(run.Status),
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		DurationMs: run.DurationMs,
+		Error:      run.Error,
+		Summary:    run.Summary,
+	}
+}
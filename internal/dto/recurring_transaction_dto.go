@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+)
+
+// Request DTOs
+type CreateRecurringTransactionRequest struct {
+	Name      string      `json:"name" validate:"required,min=2,max=255" example:"Monthly Rent"`
+	Cost      money.Money `json:"cost" swaggertype:"number" validate:"required,gt=0,money" example:"3500000.00"`
+	Type      string      `json:"type" validate:"required,oneof=income expense" example:"expense"`
+	Note      string      `json:"note" validate:"omitempty,max=1000" example:"Apartment rent"`
+	TCategory string      `json:"t_category" validate:"required,min=2,max=100" example:"housing"`
+	UserID    uuid.UUID   `json:"user_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WalletID  uuid.UUID   `json:"wallet_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Frequency string      `json:"frequency" validate:"required,oneof=daily weekly monthly yearly" example:"monthly"`
+	Interval  int         `json:"interval" validate:"omitempty,min=1,max=365" example:"1"`
+	StartDate time.Time   `json:"start_date" validate:"required" example:"2024-01-01T00:00:00Z"`
+	EndDate   *time.Time  `json:"end_date" validate:"omitempty" example:"2024-12-31T00:00:00Z"`
+}
+
+type UpdateRecurringTransactionRequest struct {
+	Name      string      `json:"name" validate:"omitempty,min=2,max=255" example:"Monthly Rent"`
+	Cost      money.Money `json:"cost" swaggertype:"number" validate:"omitempty,gt=0,money" example:"3750000.00"`
+	Type      string      `json:"type" validate:"omitempty,oneof=income expense" example:"expense"`
+	Note      string      `json:"note" validate:"omitempty,max=1000" example:"Apartment rent after renewal"`
+	TCategory string      `json:"t_category" validate:"omitempty,min=2,max=100" example:"housing"`
+	WalletID  uuid.UUID   `json:"wallet_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Frequency string      `json:"frequency" validate:"omitempty,oneof=daily weekly monthly yearly" example:"monthly"`
+	Interval  int         `json:"interval" validate:"omitempty,min=1,max=365" example:"1"`
+	StartDate *time.Time  `json:"start_date" validate:"omitempty" example:"2024-02-01T00:00:00Z"`
+	EndDate   *time.Time  `json:"end_date" validate:"omitempty" example:"2025-12-31T00:00:00Z"`
+	IsActive  *bool       `json:"is_active" validate:"omitempty" example:"true"`
+}
+
+// Response DTOs
+type RecurringTransactionResponse struct {
+	ID               uuid.UUID       `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name             string          `json:"name" example:"Monthly Rent"`
+	Cost             money.Money     `json:"cost" swaggertype:"number" example:"3500000.00"`
+	Type             string          `json:"type" example:"expense"`
+	Note             string          `json:"note" example:"Apartment rent"`
+	TCategory        string          `json:"t_category" example:"housing"`
+	UserID           uuid.UUID       `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WalletID         uuid.UUID       `json:"wallet_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Frequency        string          `json:"frequency" example:"monthly"`
+	Interval         int             `json:"interval" example:"1"`
+	StartDate        time.Time       `json:"start_date" example:"2024-01-01T00:00:00Z"`
+	EndDate          *time.Time      `json:"end_date" example:"2024-12-31T00:00:00Z"`
+	OccurrenceIndex  int             `json:"occurrence_index" example:"3"`
+	NextOccurrenceAt *time.Time      `json:"next_occurrence_at" example:"2024-04-01T00:00:00Z"`
+	LastOccurrenceAt *time.Time      `json:"last_occurrence_at" example:"2024-03-01T00:00:00Z"`
+	IsActive         bool            `json:"is_active" example:"true"`
+	Wallet           *WalletResponse `json:"wallet,omitempty"`
+	CreatedAt        time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt        time.Time       `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+type ProcessRecurringTransactionsResponse struct {
+	Templates int `json:"templates" example:"4"` // Number of due templates processed
+	Posted    int `json:"posted" example:"5"`    // Number of transactions created
+	Skipped   int `json:"skipped" example:"0"`   // Occurrences already posted earlier
+	Failed    int `json:"failed" example:"0"`    // Templates that failed and will be retried on the next run
+}
+
+// MapToRecurringTransactionResponse converts a RecurringTransaction entity to RecurringTransactionResponse DTO
+func MapToRecurringTransactionResponse(recurring *entities.RecurringTransaction) *RecurringTransactionResponse {
+	response := &RecurringTransactionResponse{
+		ID:               recurring.ID,
+		Name:             recurring.Name,
+		Cost:             recurring.Cost,
+		Type:             string(recurring.Type),
+		Note:             recurring.Note,
+		TCategory:        recurring.TCategory,
+		UserID:           recurring.UserID,
+		WalletID:         recurring.WalletID,
+		Frequency:        string(recurring.Frequency),
+		Interval:         recurring.Interval,
+		StartDate:        recurring.StartDate,
+		EndDate:          recurring.EndDate,
+		OccurrenceIndex:  recurring.OccurrenceIndex,
+		NextOccurrenceAt: recurring.NextOccurrenceAt,
+		LastOccurrenceAt: recurring.LastOccurrenceAt,
+		IsActive:         recurring.IsActive,
+		CreatedAt:        recurring.CreatedAt,
+		UpdatedAt:        recurring.UpdatedAt,
+	}
+
+	// Include wallet data if it's preloaded
+	if recurring.Wallet.ID != uuid.Nil {
+		response.Wallet = MapToWalletResponse(&recurring.Wallet)
+	}
+
+	return response
+}
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+)
+
+// Request DTOs
+type CreateTagRequest struct {
+	Name   string    `json:"name" validate:"required,min=1,max=50" example:"Trip Bali 2026"` // Stored lowercased with dashes, e.g. trip-bali-2026
+	Color  string    `json:"color" validate:"omitempty,hexcolor,len=7" example:"#0EA5E9"`
+	UserID uuid.UUID `json:"user_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+type UpdateTagRequest struct {
+	Name  string `json:"name" validate:"omitempty,min=1,max=50" example:"reimbursable"`
+	Color string `json:"color" validate:"omitempty,hexcolor,len=7" example:"#0EA5E9"`
+}
+
+// Response DTOs
+type TagResponse struct {
+	ID        uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174007"`
+	UserID    uuid.UUID `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name      string    `json:"name" example:"trip-bali-2026"`
+	Color     string    `json:"color" example:"#0EA5E9"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// MapToTagResponse converts a Tag entity to TagResponse DTO
+func MapToTagResponse(tag *entities.Tag) *TagResponse {
+	return &TagResponse{
+		ID:        tag.ID,
+		UserID:    tag.UserID,
+		Name:      tag.Name,
+		Color:     tag.Color,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+}
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+)
+
+// Request DTOs
+type UploadTransactionAttachmentRequest struct {
+	File *multipart.FileHeader `json:"-" form:"file" validate:"omitempty" swaggerignore:"true"`
+}
+
+// Response DTOs
+type TransactionAttachmentResponse struct {
+	ID            uuid.UUID `json:"id" example:"123e4567-e89b-12d3-a456-426614174007"`
+	TransactionID uuid.UUID `json:"transaction_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	FileName      string    `json:"file_name" example:"receipt.pdf"`
+	ContentType   string    `json:"content_type" example:"application/pdf"`
+	Size          int64     `json:"size" example:"183422"`
+	URL           string    `json:"url" example:"https://minio.example.com/private/transaction-attachment/2024/01/receipt_1704067200.pdf?X-Amz-Signature=..."` // Signed URL, only valid until url_expires_at
+	URLExpiresAt  time.Time `json:"url_expires_at" example:"2024-01-01T00:15:00Z"`
+	CreatedAt     time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// MapToTransactionAttachmentResponse converts a TransactionAttachment entity and its signed URL to TransactionAttachmentResponse DTO
+func MapToTransactionAttachmentResponse(attachment *entities.TransactionAttachment, url string, urlExpiresAt time.Time) *TransactionAttachmentResponse {
+	return &TransactionAttachmentResponse{
+		ID:            attachment.ID,
+		TransactionID: attachment.TransactionID,
+		FileName:      attachment.FileName,
+		ContentType:   attachment.ContentType,
+		Size:          attachment.Size,
+		URL:           url,
+		URLExpiresAt:  urlExpiresAt,
+		CreatedAt:     attachment.CreatedAt,
+	}
+}
@@ -1,62 +1,176 @@
 package dto
 
 import (
+	"mime/multipart"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
 )
 
 // Request DTOs
 type CreateTransactionRequest struct {
-	Name      string    `json:"name" validate:"required,min=2,max=255" example:"Grocery Shopping"`
-	Cost      float64   `json:"cost" validate:"required,min=0" example:"50000.00"`
-	Type      string    `json:"type" validate:"required,oneof=income expense" example:"expense"`
-	Note      string    `json:"note" validate:"omitempty,max=1000" example:"Weekly grocery shopping at supermarket"`
-	TCategory string    `json:"t_category" validate:"required,min=2,max=100" example:"food"`
-	UserID    uuid.UUID `json:"user_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
-	WalletID  uuid.UUID `json:"wallet_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Name       string                    `json:"name" validate:"required,min=2,max=255" example:"Grocery Shopping"`
+	Cost       money.Money               `json:"cost" swaggertype:"number" validate:"required,min=0,money" example:"50000.00"`
+	Type       string                    `json:"type" validate:"required,oneof=income expense" example:"expense"`
+	Note       string                    `json:"note" validate:"omitempty,max=1000" example:"Weekly grocery shopping at supermarket"`
+	TCategory  string                    `json:"t_category" validate:"required_without_all=CategoryID Splits,omitempty,min=2,max=100" example:"food"` // Top-level category name, created when missing; ignored when category_id, splits or a matching rule set the category
+	CategoryID *uuid.UUID                `json:"category_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174005"`
+	UserID     uuid.UUID                 `json:"user_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WalletID   uuid.UUID                 `json:"wallet_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	OccurredAt *time.Time                `json:"occurred_at" validate:"omitempty" example:"2023-01-01T09:30:00Z"`                          // Defaults to now
+	Splits     []TransactionSplitRequest `json:"splits" validate:"omitempty,min=2,max=50,dive"`                                            // Amounts must add up to cost; the largest split sets the transaction category
+	Tags       []string                  `json:"tags" validate:"omitempty,max=20,dive,min=1,max=50" example:"trip-bali-2026,reimbursable"` // Tag names, created when missing
+}
+
+// TransactionSplitRequest is one category line of a split transaction
+type TransactionSplitRequest struct {
+	Amount     money.Money `json:"amount" swaggertype:"number" validate:"required,gt=0,money" example:"30000.00"`
+	TCategory  string      `json:"t_category" validate:"required_without=CategoryID,omitempty,min=2,max=100" example:"groceries"` // Category name, created when missing; ignored when category_id is set
+	CategoryID *uuid.UUID  `json:"category_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174005"`
+	Note       string      `json:"note" validate:"omitempty,max=1000" example:"Vegetables and fruit"`
 }
 
 type UpdateTransactionRequest struct {
-	Name      string    `json:"name" validate:"omitempty,min=2,max=255" example:"Updated Grocery Shopping"`
-	Cost      float64   `json:"cost" validate:"omitempty,min=0" example:"45000.00"`
-	Type      string    `json:"type" validate:"omitempty,oneof=income expense" example:"expense"`
-	Note      string    `json:"note" validate:"omitempty,max=1000" example:"Updated note for grocery shopping"`
-	TCategory string    `json:"t_category" validate:"omitempty,min=2,max=100" example:"food"`
-	UserID    uuid.UUID `json:"user_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
-	WalletID  uuid.UUID `json:"wallet_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Name        string                    `json:"name" validate:"omitempty,min=2,max=255" example:"Updated Grocery Shopping"`
+	Cost        money.Money               `json:"cost" swaggertype:"number" validate:"omitempty,min=0,money" example:"45000.00"`
+	Type        string                    `json:"type" validate:"omitempty,oneof=income expense" example:"expense"`
+	Note        string                    `json:"note" validate:"omitempty,max=1000" example:"Updated note for grocery shopping"`
+	TCategory   string                    `json:"t_category" validate:"omitempty,min=2,max=100" example:"food"` // Top-level category name, created when missing; ignored when category_id is set
+	CategoryID  *uuid.UUID                `json:"category_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174005"`
+	UserID      uuid.UUID                 `json:"user_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WalletID    uuid.UUID                 `json:"wallet_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	OccurredAt  *time.Time                `json:"occurred_at" validate:"omitempty" example:"2023-01-01T09:30:00Z"`
+	Splits      []TransactionSplitRequest `json:"splits" validate:"omitempty,min=2,max=50,dive"`                             // Replaces the current splits
+	ClearSplits bool                      `json:"clear_splits" example:"false"`                                              // Turn a split transaction back into a single-category one
+	Tags        []string                  `json:"tags" validate:"omitempty,max=20,dive,min=1,max=50" example:"reimbursable"` // Replaces the current tags
+	ClearTags   bool                      `json:"clear_tags" example:"false"`                                                // Remove all tags
+}
+
+// ImportTransactionsRequest is a multipart CSV upload plus the mapping of its columns.
+// Columns are referenced by header name (case-insensitive) or by 1-based position.
+type ImportTransactionsRequest struct {
+	WalletID        uuid.UUID             `json:"wallet_id" form:"wallet_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174001"`
+	DateColumn      string                `json:"date_column" form:"date_column" validate:"required,max=100" example:"Date"`
+	DateFormat      string                `json:"date_format" form:"date_format" validate:"omitempty,max=50" example:"2006-01-02"` // Go time layout, defaults to 2006-01-02
+	AmountColumn    string                `json:"amount_column" form:"amount_column" validate:"required,max=100" example:"Amount"`
+	SignConvention  string                `json:"sign_convention" form:"sign_convention" validate:"omitempty,oneof=negative_expense positive_expense type_column" example:"negative_expense"` // Defaults to negative_expense
+	TypeColumn      string                `json:"type_column" form:"type_column" validate:"required_if=SignConvention type_column,max=100" example:"Type"`                                    // income/expense or credit/debit values
+	NameColumn      string                `json:"name_column" form:"name_column" validate:"required,max=100" example:"Description"`
+	CategoryColumn  string                `json:"category_column" form:"category_column" validate:"omitempty,max=100" example:"Category"`
+	DefaultCategory string                `json:"default_category" form:"default_category" validate:"omitempty,min=2,max=100" example:"uncategorized"` // Used when the category column is absent or empty
+	NoteColumn      string                `json:"note_column" form:"note_column" validate:"omitempty,max=100" example:"Memo"`
+	Delimiter       string                `json:"delimiter" form:"delimiter" validate:"omitempty,oneof=comma semicolon tab pipe" example:"comma"` // Defaults to comma
+	DecimalComma    bool                  `json:"decimal_comma" form:"decimal_comma" example:"false"`                                             // Amounts are written like 1.234,56
+	HasHeader       *bool                 `json:"has_header" form:"has_header" example:"true"`                                                    // Defaults to true
+	File            *multipart.FileHeader `json:"-" form:"file" validate:"omitempty" swaggerignore:"true"`
+}
+
+// ImportStatementRequest is a multipart OFX or QIF bank statement upload
+type ImportStatementRequest struct {
+	Format          string                `json:"format" form:"format" validate:"omitempty,oneof=ofx qif" example:"ofx"`                               // Detected from the file when empty
+	DefaultCategory string                `json:"default_category" form:"default_category" validate:"omitempty,min=2,max=100" example:"uncategorized"` // For entries without a category, defaults to uncategorized
+	DayFirst        bool                  `json:"day_first" form:"day_first" example:"false"`                                                          // QIF dates are written DD/MM/YYYY
+	DecimalComma    bool                  `json:"decimal_comma" form:"decimal_comma" example:"false"`                                                  // QIF amounts are written like 1.234,56
+	File            *multipart.FileHeader `json:"-" form:"file" validate:"omitempty" swaggerignore:"true"`
 }
 
 // Response DTOs
 type TransactionResponse struct {
-	ID        uuid.UUID       `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Name      string          `json:"name" example:"Grocery Shopping"`
-	Cost      float64         `json:"cost" example:"50000.00"`
-	Type      string          `json:"type" example:"expense"`
-	Note      string          `json:"note" example:"Weekly grocery shopping at supermarket"`
-	TCategory string          `json:"t_category" example:"food"`
-	UserID    uuid.UUID       `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	WalletID  uuid.UUID       `json:"wallet_id" example:"123e4567-e89b-12d3-a456-426614174001"`
-	User      *UserResponse   `json:"user,omitempty"`
-	Wallet    *WalletResponse `json:"wallet,omitempty"`
-	CreatedAt time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
-	UpdatedAt time.Time       `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+	ID                     uuid.UUID                  `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name                   string                     `json:"name" example:"Grocery Shopping"`
+	Cost                   money.Money                `json:"cost" swaggertype:"number" example:"50000.00"`
+	Type                   string                     `json:"type" example:"expense"`
+	Note                   string                     `json:"note" example:"Weekly grocery shopping at supermarket"`
+	TCategory              string                     `json:"t_category" example:"food"`
+	CategoryID             *uuid.UUID                 `json:"category_id" example:"123e4567-e89b-12d3-a456-426614174005"`
+	UserID                 uuid.UUID                  `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WalletID               uuid.UUID                  `json:"wallet_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	OccurredAt             time.Time                  `json:"occurred_at" example:"2023-01-01T09:30:00Z"`
+	TransferID             *uuid.UUID                 `json:"transfer_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174002"`
+	RecurringTransactionID *uuid.UUID                 `json:"recurring_transaction_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174003"`
+	OccurrenceDate         *time.Time                 `json:"occurrence_date,omitempty" example:"2024-01-01T00:00:00Z"`
+	ExternalID             *string                    `json:"external_id,omitempty" example:"20240105001"`
+	Version                int64                      `json:"version" example:"1"` // Also sent as the ETag, send it back in If-Match to update or delete
+	User                   *UserResponse              `json:"user,omitempty"`
+	Wallet                 *WalletResponse            `json:"wallet,omitempty"`
+	Splits                 []TransactionSplitResponse `json:"splits,omitempty"`
+	Tags                   []TagResponse              `json:"tags,omitempty"`
+	Warnings               []TransactionWarning       `json:"warnings,omitempty"` // Only set when creating a transaction
+	CreatedAt              time.Time                  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt              time.Time                  `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+type TransactionSplitResponse struct {
+	ID         uuid.UUID   `json:"id" example:"123e4567-e89b-12d3-a456-426614174006"`
+	Amount     money.Money `json:"amount" swaggertype:"number" example:"30000.00"`
+	TCategory  string      `json:"t_category" example:"groceries"`
+	CategoryID *uuid.UUID  `json:"category_id" example:"123e4567-e89b-12d3-a456-426614174005"`
+	Note       string      `json:"note" example:"Vegetables and fruit"`
+}
+
+// WarningPossibleDuplicate flags a new transaction that looks like one already recorded
+const WarningPossibleDuplicate = "possible_duplicate"
+
+// TransactionWarning is a non-blocking problem found with a transaction that was saved anyway
+type TransactionWarning struct {
+	Code          string     `json:"code" example:"possible_duplicate"`
+	Message       string     `json:"message" example:"\"Grocery Shopping\" of 2024-01-05T10:00:00Z with the same amount was recorded 2m0s apart"`
+	TransactionID *uuid.UUID `json:"transaction_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174002"` // The transaction it may duplicate
+}
+
+// TransactionDuplicatePairRequest names a likely duplicate pair to merge or dismiss
+type TransactionDuplicatePairRequest struct {
+	TransactionID uuid.UUID `json:"transaction_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174002"` // Kept on a merge
+	DuplicateID   uuid.UUID `json:"duplicate_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174003"`   // Deleted on a merge
+}
+
+// TransactionDuplicateResponse is a pair of transactions that are likely the same one recorded twice
+type TransactionDuplicateResponse struct {
+	Original       TransactionResponse `json:"original"`                       // Recorded first
+	Duplicate      TransactionResponse `json:"duplicate"`                      // Recorded last
+	NameSimilarity float64             `json:"name_similarity" example:"0.92"` // From 0 to 1
+	TimeApart      string              `json:"time_apart" example:"2m0s"`
+}
+
+type ImportRowError struct {
+	Row     int    `json:"row" example:"3"` // Line number in the uploaded file
+	Column  string `json:"column,omitempty" example:"amount"`
+	Message string `json:"message" example:"invalid amount \"abc\""`
+}
+
+type ImportTransactionsResponse struct {
+	DryRun       bool                  `json:"dry_run" example:"false"`
+	TotalRows    int                   `json:"total_rows" example:"120"`
+	ValidRows    int                   `json:"valid_rows" example:"118"`
+	InvalidRows  int                   `json:"invalid_rows" example:"2"`
+	SkippedRows  int                   `json:"skipped_rows" example:"0"`    // Valid statement entries already imported into the wallet
+	ImportedRows int                   `json:"imported_rows" example:"118"` // Zero on a dry run
+	Errors       []ImportRowError      `json:"errors"`
+	Transactions []TransactionResponse `json:"transactions"` // Transactions that were (or, on a dry run, would be) created
 }
 
 // MapToTransactionResponse converts a Transaction entity to TransactionResponse DTO
 func MapToTransactionResponse(transaction *entities.Transaction) *TransactionResponse {
 	response := &TransactionResponse{
-		ID:        transaction.ID,
-		Name:      transaction.Name,
-		Cost:      transaction.Cost,
-		Type:      string(transaction.Type),
-		Note:      transaction.Note,
-		TCategory: transaction.TCategory,
-		UserID:    transaction.UserID,
-		WalletID:  transaction.WalletID,
-		CreatedAt: transaction.CreatedAt,
-		UpdatedAt: transaction.UpdatedAt,
+		ID:                     transaction.ID,
+		Name:                   transaction.Name,
+		Cost:                   transaction.Cost,
+		Type:                   string(transaction.Type),
+		Note:                   transaction.Note,
+		TCategory:              transaction.TCategory,
+		CategoryID:             transaction.CategoryID,
+		UserID:                 transaction.UserID,
+		WalletID:               transaction.WalletID,
+		OccurredAt:             transaction.OccurredAt,
+		TransferID:             transaction.TransferID,
+		RecurringTransactionID: transaction.RecurringTransactionID,
+		OccurrenceDate:         transaction.OccurrenceDate,
+		ExternalID:             transaction.ExternalID,
+		Version:                transaction.Version,
+		CreatedAt:              transaction.CreatedAt,
+		UpdatedAt:              transaction.UpdatedAt,
 	}
 
 	// Include user data if it's preloaded
@@ -69,5 +183,19 @@ func MapToTransactionResponse(transaction *entities.Transaction) *TransactionRes
 		response.Wallet = MapToWalletResponse(&transaction.Wallet)
 	}
 
+	for _, split := range transaction.Splits {
+		response.Splits = append(response.Splits, TransactionSplitResponse{
+			ID:         split.ID,
+			Amount:     split.Amount,
+			TCategory:  split.TCategory,
+			CategoryID: split.CategoryID,
+			Note:       split.Note,
+		})
+	}
+
+	for i := range transaction.Tags {
+		response.Tags = append(response.Tags, *MapToTagResponse(&transaction.Tags[i]))
+	}
+
 	return response
 }
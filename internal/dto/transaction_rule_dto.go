@@ -0,0 +1,127 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+)
+
+// Request DTOs
+type CreateTransactionRuleRequest struct {
+	Name           string       `json:"name" validate:"required,min=2,max=255" example:"Supermarket purchases"`
+	UserID         uuid.UUID    `json:"user_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Priority       int          `json:"priority" validate:"min=0,max=10000" example:"10"`                                 // Lower runs first
+	IsActive       *bool        `json:"is_active" example:"true"`                                                         // Defaults to true
+	NamePattern    string       `json:"name_pattern" validate:"omitempty,max=255" example:"superindo|alfamart|indomaret"` // Case-insensitive regular expression
+	NotePattern    string       `json:"note_pattern" validate:"omitempty,max=255" example:"groceries"`                    // Case-insensitive regular expression
+	MinAmount      *money.Money `json:"min_amount" swaggertype:"number" validate:"omitempty,min=0,money" example:"10000"`
+	MaxAmount      *money.Money `json:"max_amount" swaggertype:"number" validate:"omitempty,min=0,money" example:"2000000"`
+	WalletID       *uuid.UUID   `json:"wallet_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Type           string       `json:"type" validate:"omitempty,oneof=income expense" example:"expense"` // Empty matches both
+	CategoryID     *uuid.UUID   `json:"category_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174005"`
+	Tags           []string     `json:"tags" validate:"omitempty,max=20,dive,min=1,max=50" example:"groceries"`
+	Note           string       `json:"note" validate:"omitempty,max=1000" example:"Weekly groceries"`
+	StopProcessing bool         `json:"stop_processing" example:"false"` // Skip lower priority rules when this one matches
+}
+
+type UpdateTransactionRuleRequest struct {
+	Name             string       `json:"name" validate:"omitempty,min=2,max=255" example:"Supermarket purchases"`
+	Priority         *int         `json:"priority" validate:"omitempty,min=0,max=10000" example:"20"`
+	IsActive         *bool        `json:"is_active" example:"false"`
+	NamePattern      *string      `json:"name_pattern" validate:"omitempty,max=255" example:"superindo|alfamart"` // Empty string removes the condition
+	NotePattern      *string      `json:"note_pattern" validate:"omitempty,max=255" example:""`                   // Empty string removes the condition
+	MinAmount        *money.Money `json:"min_amount" swaggertype:"number" validate:"omitempty,min=0,money" example:"10000"`
+	MaxAmount        *money.Money `json:"max_amount" swaggertype:"number" validate:"omitempty,min=0,money" example:"2000000"`
+	ClearAmountRange bool         `json:"clear_amount_range" example:"false"` // Remove both amount conditions
+	WalletID         *uuid.UUID   `json:"wallet_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	ClearWallet      bool         `json:"clear_wallet" example:"false"` // Match transactions of every wallet
+	Type             string       `json:"type" validate:"omitempty,oneof=income expense" example:"expense"`
+	ClearType        bool         `json:"clear_type" example:"false"` // Match income and expense
+	CategoryID       *uuid.UUID   `json:"category_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174005"`
+	ClearCategory    bool         `json:"clear_category" example:"false"`
+	Tags             []string     `json:"tags" validate:"omitempty,max=20,dive,min=1,max=50" example:"groceries"` // Replaces the current tags
+	ClearTags        bool         `json:"clear_tags" example:"false"`
+	Note             *string      `json:"note" validate:"omitempty,max=1000" example:"Weekly groceries"` // Empty string removes the action
+	StopProcessing   *bool        `json:"stop_processing" example:"true"`
+}
+
+// Response DTOs
+type TransactionRuleResponse struct {
+	ID             uuid.UUID         `json:"id" example:"123e4567-e89b-12d3-a456-426614174008"`
+	UserID         uuid.UUID         `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name           string            `json:"name" example:"Supermarket purchases"`
+	Priority       int               `json:"priority" example:"10"`
+	IsActive       bool              `json:"is_active" example:"true"`
+	NamePattern    string            `json:"name_pattern" example:"superindo|alfamart|indomaret"`
+	NotePattern    string            `json:"note_pattern" example:""`
+	MinAmount      *money.Money      `json:"min_amount" swaggertype:"number" example:"10000"`
+	MaxAmount      *money.Money      `json:"max_amount" swaggertype:"number" example:"2000000"`
+	WalletID       *uuid.UUID        `json:"wallet_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Type           string            `json:"type" example:"expense"`
+	CategoryID     *uuid.UUID        `json:"category_id" example:"123e4567-e89b-12d3-a456-426614174005"`
+	Tags           []string          `json:"tags" example:"groceries"`
+	Note           string            `json:"note" example:"Weekly groceries"`
+	StopProcessing bool              `json:"stop_processing" example:"false"`
+	Category       *CategoryResponse `json:"category,omitempty"`
+	CreatedAt      time.Time         `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt      time.Time         `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// TransactionRuleRunResponse lists the transactions a rule changed, or would change on a preview.
+// Only the first changes are listed, Truncated tells when Changed is larger than the list.
+type TransactionRuleRunResponse struct {
+	RuleID       uuid.UUID               `json:"rule_id" example:"123e4567-e89b-12d3-a456-426614174008"`
+	DryRun       bool                    `json:"dry_run" example:"true"`
+	Matched      int                     `json:"matched" example:"25"` // Transactions matching every condition
+	Changed      int                     `json:"changed" example:"18"` // Matching transactions the actions change
+	Truncated    bool                    `json:"truncated" example:"false"`
+	Transactions []TransactionRuleChange `json:"transactions"`
+}
+
+// TransactionRuleChange is the difference a rule makes to one transaction
+type TransactionRuleChange struct {
+	TransactionID uuid.UUID  `json:"transaction_id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	Name          string     `json:"name" example:"SUPERINDO KEMANG"`
+	OccurredAt    time.Time  `json:"occurred_at" example:"2024-01-05T10:00:00Z"`
+	FromTCategory string     `json:"from_t_category,omitempty" example:"uncategorized"`
+	ToTCategory   string     `json:"to_t_category,omitempty" example:"groceries"`
+	ToCategoryID  *uuid.UUID `json:"to_category_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174005"`
+	AddedTags     []string   `json:"added_tags,omitempty" example:"groceries"`
+	FromNote      *string    `json:"from_note,omitempty" example:""`
+	ToNote        *string    `json:"to_note,omitempty" example:"Weekly groceries"`
+}
+
+// MapToTransactionRuleResponse converts a TransactionRule entity to TransactionRuleResponse DTO
+func MapToTransactionRuleResponse(rule *entities.TransactionRule) *TransactionRuleResponse {
+	response := &TransactionRuleResponse{
+		ID:             rule.ID,
+		UserID:         rule.UserID,
+		Name:           rule.Name,
+		Priority:       rule.Priority,
+		IsActive:       rule.IsActive,
+		NamePattern:    rule.NamePattern,
+		NotePattern:    rule.NotePattern,
+		MinAmount:      rule.MinAmount,
+		MaxAmount:      rule.MaxAmount,
+		WalletID:       rule.WalletID,
+		Type:           string(rule.Type),
+		CategoryID:     rule.CategoryID,
+		Tags:           rule.GetTags(),
+		Note:           rule.Note,
+		StopProcessing: rule.StopProcessing,
+		CreatedAt:      rule.CreatedAt,
+		UpdatedAt:      rule.UpdatedAt,
+	}
+	if response.Tags == nil {
+		response.Tags = []string{}
+	}
+
+	// Include category data if it's preloaded
+	if rule.Category != nil {
+		response.Category = MapToCategoryResponse(rule.Category)
+	}
+
+	return response
+}
@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+)
+
+// Request DTOs
+type CreateTransferRequest struct {
+	Amount         money.Money `json:"amount" swaggertype:"number" validate:"required,gt=0,money" example:"250000.00"`
+	ReceivedAmount money.Money `json:"received_amount" swaggertype:"number" validate:"omitempty,gt=0,money" example:"16.05"` // In the destination wallet currency, converted at the latest exchange rate when omitted
+	Note           string      `json:"note" validate:"omitempty,max=1000" example:"Move savings to e-wallet"`
+	UserID         uuid.UUID   `json:"user_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	FromWalletID   uuid.UUID   `json:"from_wallet_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	ToWalletID     uuid.UUID   `json:"to_wallet_id" validate:"required,uuid,nefield=FromWalletID" example:"123e4567-e89b-12d3-a456-426614174002"`
+}
+
+type UpdateTransferRequest struct {
+	Amount         money.Money `json:"amount" swaggertype:"number" validate:"omitempty,gt=0,money" example:"300000.00"`
+	ReceivedAmount money.Money `json:"received_amount" swaggertype:"number" validate:"omitempty,gt=0,money" example:"19.25"` // In the destination wallet currency, reconverted when omitted and the amount or wallets change
+	Note           string      `json:"note" validate:"omitempty,max=1000" example:"Updated note for transfer"`
+	FromWalletID   uuid.UUID   `json:"from_wallet_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	ToWalletID     uuid.UUID   `json:"to_wallet_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174002"`
+}
+
+// Response DTOs
+type TransferResponse struct {
+	ID                    uuid.UUID       `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Amount                money.Money     `json:"amount" swaggertype:"number" example:"250000.00"`
+	ReceivedAmount        money.Money     `json:"received_amount" swaggertype:"number" example:"250000.00"`
+	Note                  string          `json:"note" example:"Move savings to e-wallet"`
+	UserID                uuid.UUID       `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	FromWalletID          uuid.UUID       `json:"from_wallet_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	ToWalletID            uuid.UUID       `json:"to_wallet_id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	OutgoingTransactionID *uuid.UUID      `json:"outgoing_transaction_id" example:"123e4567-e89b-12d3-a456-426614174003"`
+	IncomingTransactionID *uuid.UUID      `json:"incoming_transaction_id" example:"123e4567-e89b-12d3-a456-426614174004"`
+	FromWallet            *WalletResponse `json:"from_wallet,omitempty"`
+	ToWallet              *WalletResponse `json:"to_wallet,omitempty"`
+	CreatedAt             time.Time       `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt             time.Time       `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// MapToTransferResponse converts a Transfer entity to TransferResponse DTO
+func MapToTransferResponse(transfer *entities.Transfer) *TransferResponse {
+	response := &TransferResponse{
+		ID:                    transfer.ID,
+		Amount:                transfer.Amount,
+		ReceivedAmount:        transfer.GetReceivedAmount(),
+		Note:                  transfer.Note,
+		UserID:                transfer.UserID,
+		FromWalletID:          transfer.FromWalletID,
+		ToWalletID:            transfer.ToWalletID,
+		OutgoingTransactionID: transfer.OutgoingTransactionID,
+		IncomingTransactionID: transfer.IncomingTransactionID,
+		CreatedAt:             transfer.CreatedAt,
+		UpdatedAt:             transfer.UpdatedAt,
+	}
+
+	// Include wallet data if it's preloaded
+	if transfer.FromWallet.ID != uuid.Nil {
+		response.FromWallet = MapToWalletResponse(&transfer.FromWallet)
+	}
+	if transfer.ToWallet.ID != uuid.Nil {
+		response.ToWallet = MapToWalletResponse(&transfer.ToWallet)
+	}
+
+	return response
+}
@@ -5,25 +5,26 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
 )
 
 // Request DTOs
 type CreateWalletRequest struct {
-	Name     string    `json:"name" validate:"required,min=2,max=100" example:"John Doe"`
-	Type     string    `json:"type" validate:"required" example:"personal"`
-	Category string    `json:"category" validate:"required" example:"income"`
-	Balance  float64   `json:"balance" validate:"omitempty,min=0" example:"1000.50"`
-	Currency string    `json:"currency" validate:"omitempty" example:"IDR"`
-	UserID   uuid.UUID `json:"user_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name     string      `json:"name" validate:"required,min=2,max=100" example:"John Doe"`
+	Type     string      `json:"type" validate:"required" example:"personal"`
+	Category string      `json:"category" validate:"required" example:"income"`
+	Balance  money.Money `json:"balance" swaggertype:"number" validate:"omitempty,min=0,money" example:"1000.50"`
+	Currency string      `json:"currency" validate:"omitempty,currency" example:"IDR"`
+	UserID   uuid.UUID   `json:"user_id" validate:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
 }
 
 type UpdateWalletRequest struct {
-	Name     string    `json:"name" validate:"omitempty,min=2,max=100" example:"John Doe Updated"`
-	Type     string    `json:"type" validate:"omitempty" example:"personal"`
-	Category string    `json:"category" validate:"omitempty" example:"income"`
-	Balance  float64   `json:"balance" validate:"omitempty,min=0" example:"1000.50"`
-	Currency string    `json:"currency" validate:"omitempty" example:"IDR"`
-	UserID   uuid.UUID `json:"user_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name     string      `json:"name" validate:"omitempty,min=2,max=100" example:"John Doe Updated"`
+	Type     string      `json:"type" validate:"omitempty" example:"personal"`
+	Category string      `json:"category" validate:"omitempty" example:"income"`
+	Balance  money.Money `json:"balance" swaggertype:"number" validate:"omitempty,min=0,money" example:"1000.50"`
+	Currency string      `json:"currency" validate:"omitempty,currency" example:"IDR"`
+	UserID   uuid.UUID   `json:"user_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
 }
 
 // Response DTOs
@@ -32,14 +33,32 @@ type WalletResponse struct {
 	Name      string        `json:"name" example:"John Doe"`
 	Type      string        `json:"type" example:"personal"`
 	Category  string        `json:"category" example:"income"`
-	Balance   float64       `json:"balance" example:"1000.50"`
+	Balance   money.Money   `json:"balance" swaggertype:"number" example:"1000.50"`
 	Currency  string        `json:"currency" example:"IDR"`
 	UserID    uuid.UUID     `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Version   int64         `json:"version" example:"1"` // Also sent as the ETag, send it back in If-Match to update or delete
 	User      *UserResponse `json:"user,omitempty"`
 	CreatedAt time.Time     `json:"created_at" example:"2023-01-01T00:00:00Z"`
 	UpdatedAt time.Time     `json:"updated_at" example:"2023-01-01T00:00:00Z"`
 }
 
+// WalletBalanceHistoryResponse is the end of day balance of a wallet for every day of a date range
+type WalletBalanceHistoryResponse struct {
+	WalletID       uuid.UUID            `json:"wallet_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Currency       string               `json:"currency" example:"IDR"`
+	StartDate      string               `json:"start_date" example:"2024-03-01"`
+	EndDate        string               `json:"end_date" example:"2024-03-31"`
+	OpeningBalance money.Money          `json:"opening_balance" swaggertype:"number" example:"1000.50"` // Balance at the start of start_date
+	Points         []WalletBalancePoint `json:"points"`
+}
+
+type WalletBalancePoint struct {
+	Date       string      `json:"date" example:"2024-03-03"`
+	Balance    money.Money `json:"balance" swaggertype:"number" example:"950.50"` // Balance at the end of the day
+	Change     money.Money `json:"change" swaggertype:"number" example:"-50"`
+	EntryCount int64       `json:"entry_count" example:"2"`
+}
+
 // MapToWalletResponse converts a Wallet entity to WalletResponse DTO
 func MapToWalletResponse(wallet *entities.Wallet) *WalletResponse {
 	response := &WalletResponse{
@@ -50,6 +69,7 @@ func MapToWalletResponse(wallet *entities.Wallet) *WalletResponse {
 		Balance:   wallet.Balance,
 		Currency:  wallet.Currency,
 		UserID:    wallet.UserID,
+		Version:   wallet.Version,
 		CreatedAt: wallet.CreatedAt,
 		UpdatedAt: wallet.UpdatedAt,
 	}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+)
+
+const (
+	IdempotencyCacheKeyPrefix  = "idempotency:"
+	DefaultIdempotencyCacheTTL = 24 * time.Hour
+	// IdempotencyClaimTTL bounds how long a request can hold its key, so a claim left by a crashed replica expires
+	IdempotencyClaimTTL = 5 * time.Minute
+)
+
+func idempotencyCacheKey(userID uuid.UUID, key string) string {
+	return fmt.Sprintf("%s%s:%s", IdempotencyCacheKeyPrefix, userID.String(), HashToken(key))
+}
+
+// SetIdempotencyKey stores the response of an idempotency key until it expires
+func SetIdempotencyKey(ctx context.Context, idempotencyKey *entities.IdempotencyKey) error {
+	ttl := time.Until(idempotencyKey.ExpiresAt)
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyCacheTTL
+	}
+	return RedisSetData(ctx, idempotencyCacheKey(idempotencyKey.UserID, idempotencyKey.Key), idempotencyKey, ttl)
+}
+
+// ClaimIdempotencyKey stores a processing idempotency key until it expires unless the key is already stored,
+// and reports whether it did
+func ClaimIdempotencyKey(ctx context.Context, idempotencyKey *entities.IdempotencyKey) (bool, error) {
+	ttl := time.Until(idempotencyKey.ExpiresAt)
+	if ttl <= 0 {
+		ttl = IdempotencyClaimTTL
+	}
+	return RedisSetDataNX(ctx, idempotencyCacheKey(idempotencyKey.UserID, idempotencyKey.Key), idempotencyKey, ttl)
+}
+
+// DeleteIdempotencyKey removes an idempotency key, releasing the claim of a request that failed
+func DeleteIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) error {
+	return RedisDeleteData(ctx, idempotencyCacheKey(userID, key))
+}
+
+// GetIdempotencyKey returns the stored response of a user's idempotency key
+func GetIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*entities.IdempotencyKey, error) {
+	var idempotencyKey entities.IdempotencyKey
+	if err := RedisGetData(ctx, idempotencyCacheKey(userID, key), &idempotencyKey); err != nil {
+		return nil, err
+	}
+	return &idempotencyKey, nil
+}
@@ -95,6 +95,26 @@ func RedisSetData(ctx context.Context, key string, value interface{}, ttl time.D
 	return nil
 }
 
+// RedisSetDataNX sets a value in Redis for a given key and TTL only if the key does not exist yet,
+// and reports whether it was set
+func RedisSetDataNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	client := GetRedisClient()
+	if !IsRedisAvailable() {
+		return false, fmt.Errorf("redis not available")
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	set, err := client.SetNX(ctx, key, data, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to set data in redis: %w", err)
+	}
+	return set, nil
+}
+
 // RedisGetData gets a value from Redis for a given key and unmarshals into dest
 func RedisGetData(ctx context.Context, key string, dest interface{}) error {
 	client := GetRedisClient()
@@ -58,6 +58,11 @@ func NewPostgresDB(dbConfig config.DatabaseConfig) *gorm.DB {
 		log.Fatal("Failed to create monthly_transaction_summary view:", err)
 	}
 
+	// Seed default categories and link transactions to categories built from their t_category
+	if err := entities.MigrateTransactionCategories(db); err != nil {
+		log.Fatal("Failed to migrate transaction categories:", err)
+	}
+
 	log.Printf("Database connected successfully with connection pool (MaxOpen: %d, MaxIdle: %d, MaxLifetime: %dm)",
 		dbConfig.MaxOpenConns, dbConfig.MaxIdleConns, dbConfig.ConnMaxLifetime)
 	return db
@@ -142,10 +147,33 @@ func migrateWithTimeout(db *gorm.DB, timeout time.Duration) error {
 	// Run migration in goroutine with timeout
 	migrationChan := make(chan error, 1)
 	go func() {
+		// Backfill columns that can't be added by AutoMigrate alone
+		if err := entities.MigrateTransactionOccurredAt(db); err != nil {
+			migrationChan <- err
+			return
+		}
+
 		err := db.AutoMigrate(
 			&entities.User{},
 			&entities.Wallet{},
 			&entities.Transaction{},
+			&entities.Transfer{},
+			&entities.RecurringTransaction{},
+			&entities.Budget{},
+			&entities.BudgetAlert{},
+			&entities.ExchangeRate{},
+			&entities.Category{},
+			&entities.TransactionSplit{},
+			&entities.TransactionAttachment{},
+			&entities.Tag{},
+			&entities.TransactionRule{},
+			&entities.DismissedDuplicate{},
+			&entities.IdempotencyKey{},
+			&entities.WalletBalanceEntry{},
+			&entities.BalanceSyncReport{},
+			&entities.BalanceSyncReportEntry{},
+			&entities.JobRun{},
+			&entities.JobSetting{},
 			// Add other entities here as your project grows
 		)
 		migrationChan <- err
@@ -2,9 +2,16 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
 	"github.com/naufalfazanadi/finance-manager-go/internal/domain/usecases"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/config"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
 	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
 
 	"github.com/robfig/cron/v3"
@@ -12,56 +19,164 @@ import (
 	"gorm.io/gorm"
 )
 
+// Job names, as recorded in the job run history
+const (
+	JobBalanceSync           = "balance_sync"
+	JobRecurringTransactions = "recurring_transactions"
+	JobBudgetAlerts          = "budget_alerts"
+	JobIdempotencyCleanup    = "idempotency_key_cleanup"
+)
+
+const (
+	// jobTimeout is the time budget of a run, scheduled or manual
+	jobTimeout = 10 * time.Minute
+	// jobStopTimeout is how long Stop waits for the running jobs before cancelling them
+	jobStopTimeout = 30 * time.Second
+	// jobCancelTimeout is how long Stop then waits for the cancelled jobs to record their outcome
+	jobCancelTimeout = 5 * time.Second
+)
+
+// jobFunc executes a job, its result is recorded as the summary of the run
+type jobFunc func(ctx context.Context) (interface{}, error)
+
+// scheduledJob is a job registered with the cron scheduler
+type scheduledJob struct {
+	name      string
+	config    config.JobConfig // Configured schedule, used while no setting is stored for the job
+	schedule  string           // Cron expression
+	timezone  string           // Time zone the schedule is read in
+	enabled   bool
+	stored    bool      // Whether the schedule comes from a stored setting rather than the config
+	changedAt time.Time // When the schedule in effect was stored or reset, settings read from before are stale
+	run       func()
+	entryID   cron.EntryID // Zero while the job is not scheduled
+}
+
 type CronWorker struct {
 	cron          *cron.Cron
 	balanceSyncUC usecases.BalanceSyncUseCaseInterface
+	recurringUC   usecases.RecurringTransactionUseCaseInterface
+	budgetUC      usecases.BudgetUseCaseInterface
+	jobRunUC      usecases.JobRunUseCaseInterface
+	jobSettingUC  usecases.JobSettingUseCaseInterface
+	idempotencyUC usecases.IdempotencyKeyUseCaseInterface
 	db            *gorm.DB
+	locker        *JobLocker
+	mu            sync.RWMutex // Guards jobs and isRunning, schedules change at runtime
+	jobs          []*scheduledJob
+	manualRuns    sync.WaitGroup     // Manual runs executing in the background
+	runCtx        context.Context    // Parent context of every run, cancelled when Stop gives up waiting for them
+	cancelRuns    context.CancelFunc // Cancels runCtx
+	stopTimeout   time.Duration      // How long Stop waits for the running jobs before cancelling them
+	stopWatch     chan struct{}      // Stops watching the stored job settings
 	isRunning     bool
 }
 
-func NewCronWorker(balanceSyncUC usecases.BalanceSyncUseCaseInterface, db *gorm.DB) *CronWorker {
-	// Create cron with logger and timezone
+func NewCronWorker(
+	balanceSyncUC usecases.BalanceSyncUseCaseInterface,
+	recurringUC usecases.RecurringTransactionUseCaseInterface,
+	budgetUC usecases.BudgetUseCaseInterface,
+	jobRunUC usecases.JobRunUseCaseInterface,
+	jobSettingUC usecases.JobSettingUseCaseInterface,
+	idempotencyUC usecases.IdempotencyKeyUseCaseInterface,
+	db *gorm.DB,
+) *CronWorker {
+	// Create cron with logger, schedules without their own time zone run in UTC
 	c := cron.New(
 		cron.WithLogger(cron.VerbosePrintfLogger(logger.Logger)),
 		cron.WithLocation(time.UTC),
 		cron.WithChain(cron.Recover(cron.VerbosePrintfLogger(logger.Logger))),
 	)
 
-	return &CronWorker{
+	w := &CronWorker{
 		cron:          c,
 		balanceSyncUC: balanceSyncUC,
+		recurringUC:   recurringUC,
+		budgetUC:      budgetUC,
+		jobRunUC:      jobRunUC,
+		jobSettingUC:  jobSettingUC,
+		idempotencyUC: idempotencyUC,
 		db:            db,
+		locker:        NewJobLocker(db, config.GetConfig().App.InstanceID),
+		stopTimeout:   jobStopTimeout,
 		isRunning:     false,
 	}
+	w.runCtx, w.cancelRuns = context.WithCancel(context.Background())
+
+	// Schedules come from the worker config, see pkg/config for their defaults.
+	// Recurring transactions run away from the midnight balance sync so the two never recompute the same wallet at
+	// once, and budget alerts run after recurring transactions are posted.
+	jobsConfig := config.GetConfig().Worker
+	w.jobs = []*scheduledJob{
+		newScheduledJob(JobBalanceSync, jobsConfig.BalanceSync, w.syncWalletBalances),
+		newScheduledJob(JobRecurringTransactions, jobsConfig.RecurringTransactions, w.postRecurringTransactions),
+		newScheduledJob(JobBudgetAlerts, jobsConfig.BudgetAlerts, w.sendBudgetAlerts),
+		newScheduledJob(JobIdempotencyCleanup, jobsConfig.IdempotencyKeyCleanup, w.purgeIdempotencyKeys),
+	}
+
+	return w
+}
+
+func newScheduledJob(name string, jobConfig config.JobConfig, run func()) *scheduledJob {
+	return &scheduledJob{
+		name:     name,
+		config:   jobConfig,
+		schedule: jobConfig.Schedule,
+		timezone: jobConfig.Timezone,
+		enabled:  jobConfig.Enabled,
+		run:      run,
+	}
 }
 
-// Start starts the cron worker and schedules jobs
+// Start starts the cron worker and schedules the enabled jobs
 func (w *CronWorker) Start() error {
 	funcCtx := "CronWorker.Start"
 
+	// A configured schedule must be valid even while a stored setting overrides it, resetting the job restores it
+	for _, job := range w.jobs {
+		if err := validateSchedule(job.config.Schedule, job.config.Timezone); err != nil {
+			logger.LogError(funcCtx, "invalid job schedule", err, logrus.Fields{
+				"job_name": job.name,
+				"schedule": job.config.Schedule,
+				"timezone": job.config.Timezone,
+			})
+			return fmt.Errorf("job %s: %w", job.name, err)
+		}
+	}
+
+	// Schedules changed at runtime before the last restart take precedence over the config
+	w.refreshJobSettings()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
 	if w.isRunning {
 		logger.LogSuccess(funcCtx, "Cron worker is already running", logrus.Fields{})
 		return nil
 	}
 
-	// Schedule balance sync job to run every day at 00:00 AM UTC
-	// Cron expression: "0 0 * * *" means minute=0, hour=0, every day, every month, every day of week
-	_, err := w.cron.AddFunc("0 0 * * *", w.syncWalletBalances)
-	if err != nil {
-		logger.LogError(funcCtx, "failed to add balance sync cron job", err, logrus.Fields{})
-		return err
+	// Runs cancelled by the previous Stop are over, the new ones get a fresh context
+	if w.runCtx.Err() != nil {
+		w.runCtx, w.cancelRuns = context.WithCancel(context.Background())
 	}
 
-	// Optional: Add a test job that runs every minute for debugging (comment out in production)
-	// _, err = w.cron.AddFunc("* * * * *", w.syncWalletBalances)
-	// if err != nil {
-	// 	logger.LogError(funcCtx, "failed to add test balance sync cron job", err, logrus.Fields{})
-	// 	return err
-	// }
+	w.isRunning = true
+	for _, job := range w.jobs {
+		if err := w.applySchedule(job); err != nil {
+			logger.LogError(funcCtx, "failed to add cron job", err, logrus.Fields{
+				"job_name": job.name,
+				"schedule": job.schedule,
+			})
+			w.isRunning = false
+			return err
+		}
+	}
 
 	// Start the cron scheduler
 	w.cron.Start()
-	w.isRunning = true
+
+	w.stopWatch = make(chan struct{})
+	go w.watchJobSettings(w.stopWatch)
 
 	logger.LogSuccess(funcCtx, "Cron worker started successfully", logrus.Fields{
 		"scheduled_jobs": len(w.cron.Entries()),
@@ -71,35 +186,71 @@ func (w *CronWorker) Start() error {
 	return nil
 }
 
-// Stop stops the cron worker
+// Stop stops the cron worker and waits for the running jobs, scheduled and manual. Jobs still running after the stop
+// timeout are cancelled, their runs are recorded as failed.
 func (w *CronWorker) Stop() {
 	funcCtx := "CronWorker.Stop"
 
+	w.mu.Lock()
 	if !w.isRunning {
+		w.mu.Unlock()
 		logger.LogSuccess(funcCtx, "Cron worker is not running", logrus.Fields{})
 		return
 	}
 
 	// Stop the cron scheduler
+	close(w.stopWatch)
 	ctx := w.cron.Stop()
 	w.isRunning = false
+	cancelRuns := w.cancelRuns
+	w.mu.Unlock()
+
+	// Wait for all running jobs, scheduled and manual, to complete (with timeout)
+	done := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		w.manualRuns.Wait()
+		close(done)
+	}()
 
-	// Wait for all running jobs to complete (with timeout)
 	select {
-	case <-ctx.Done():
+	case <-done:
 		logger.LogSuccess(funcCtx, "All cron jobs completed gracefully", logrus.Fields{})
-	case <-time.After(30 * time.Second):
-		logger.LogSuccess(funcCtx, "Cron worker stopped (timeout waiting for jobs)", logrus.Fields{})
+		return
+	case <-time.After(w.stopTimeout):
+	}
+
+	// The jobs still running are cancelled so they stop before the database they use is closed
+	cancelRuns()
+	select {
+	case <-done:
+		logger.LogSuccess(funcCtx, "Cron worker stopped, cancelled the jobs still running", logrus.Fields{})
+	case <-time.After(jobCancelTimeout):
+		logger.LogSuccess(funcCtx, "Cron worker stopped (timeout waiting for cancelled jobs)", logrus.Fields{})
 	}
 }
 
+// jobContext returns the context of a run, cancelled once it exceeds jobTimeout or when Stop cancels the running jobs
+func (w *CronWorker) jobContext() (context.Context, context.CancelFunc) {
+	w.mu.RLock()
+	parent := w.runCtx
+	w.mu.RUnlock()
+	return context.WithTimeout(parent, jobTimeout)
+}
+
 // IsRunning returns whether the cron worker is running
 func (w *CronWorker) IsRunning() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
 	return w.isRunning
 }
 
-// GetStatus returns the status of the cron worker
-func (w *CronWorker) GetStatus() map[string]interface{} {
+// GetStatus returns the status of the cron worker. With includeReplicas it also tells this replica's instance ID and
+// which replica runs each running job, which name hosts and processes and are only meant for admins.
+func (w *CronWorker) GetStatus(ctx context.Context, includeReplicas bool) map[string]interface{} {
+	funcCtx := "CronWorker.GetStatus"
+
+	w.mu.RLock()
 	status := map[string]interface{}{
 		"is_running":     w.isRunning,
 		"scheduled_jobs": len(w.cron.Entries()),
@@ -109,9 +260,203 @@ func (w *CronWorker) GetStatus() map[string]interface{} {
 		status["next_runs"] = w.getNextRunTimes()
 	}
 
+	jobNames := make([]string, len(w.jobs))
+	for i, job := range w.jobs {
+		jobNames[i] = job.name
+	}
+	w.mu.RUnlock()
+
+	if progress := w.balanceSyncUC.GetSyncProgress(); progress != nil {
+		status["balance_sync"] = progress
+	}
+
+	if !includeReplicas {
+		return status
+	}
+
+	status["instance_id"] = w.locker.InstanceID()
+	jobLocks := make(map[string]*JobLockOwner)
+	for _, name := range jobNames {
+		owner, err := w.locker.Owner(ctx, name)
+		if err != nil {
+			logger.LogError(funcCtx, "failed to get job lock owner", err, logrus.Fields{
+				"job_name": name,
+			})
+			continue
+		}
+		if owner != nil {
+			jobLocks[name] = owner
+		}
+	}
+	status["job_locks"] = jobLocks
+
 	return status
 }
 
+// HasJob reports whether name is a registered job
+func (w *CronWorker) HasJob(name string) bool {
+	return w.findJob(name) != nil
+}
+
+// findJob returns the registered job called name, nil when there is none. The job list itself never changes.
+func (w *CronWorker) findJob(name string) *scheduledJob {
+	for _, job := range w.jobs {
+		if job.name == name {
+			return job
+		}
+	}
+	return nil
+}
+
+// GetJobs returns every registered job with its schedule, last run and failure streak
+func (w *CronWorker) GetJobs(ctx context.Context) ([]*dto.JobStatusResponse, error) {
+	jobs := make([]*dto.JobStatusResponse, 0, len(w.jobs))
+	for _, job := range w.jobs {
+		status, err := w.jobStatus(ctx, job)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, status)
+	}
+	return jobs, nil
+}
+
+// jobStatus returns the current schedule of a job with its history and the replica running it
+func (w *CronWorker) jobStatus(ctx context.Context, job *scheduledJob) (*dto.JobStatusResponse, error) {
+	w.mu.RLock()
+	schedule, timezone, enabled := job.schedule, job.timezone, job.enabled
+	var nextRunAt *time.Time
+	if w.isRunning && job.entryID != 0 {
+		if next := w.cron.Entry(job.entryID).Next; !next.IsZero() {
+			nextRunAt = &next
+		}
+	}
+	w.mu.RUnlock()
+
+	status, err := w.jobRunUC.GetJobStatus(ctx, job.name, schedule, nextRunAt)
+	if err != nil {
+		return nil, err
+	}
+	status.Timezone = timezone
+	status.Enabled = enabled
+
+	owner, err := w.locker.Owner(ctx, job.name)
+	if err != nil {
+		return nil, err
+	}
+	if owner != nil {
+		status.RunningOn = owner.InstanceID
+	}
+
+	return status, nil
+}
+
+// runJob executes a job on this replica only if no other run of it holds its lock, and records the run in the job
+// history. It returns ErrJobLocked without running the job otherwise. Failing to record the run is logged but never
+// stops the job itself.
+func (w *CronWorker) runJob(ctx context.Context, name string, trigger entities.JobTrigger, job jobFunc) error {
+	unlock, run, err := w.startJob(ctx, name, trigger)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return w.executeJob(ctx, run, job)
+}
+
+// enqueueJob takes the lock of a job and records a manual run of it, then executes the job in the background and
+// returns the run right away so its outcome can be polled. It returns ErrJobLocked while the job is already running.
+func (w *CronWorker) enqueueJob(name string, job jobFunc) (*dto.JobRunResponse, error) {
+	funcCtx := "CronWorker.enqueueJob"
+
+	unlock, run, err := w.startJob(context.Background(), name, entities.JobTriggerManual)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		// A manual run is only reachable through its record, do not start one that cannot be polled
+		unlock()
+		return nil, helpers.NewInternalError("failed to record job run", name)
+	}
+
+	// Mapped before the job starts, the job updates run once it finishes
+	queued := dto.MapToJobRunResponse(run)
+
+	w.manualRuns.Add(1)
+	go func() {
+		defer w.manualRuns.Done()
+		defer unlock()
+
+		// The request that queued the run is gone, the job gets the same time budget as a scheduled run
+		ctx, cancel := w.jobContext()
+		defer cancel()
+
+		jobStart := time.Now()
+		if err := w.executeJob(ctx, run, job); err != nil {
+			logger.LogError(funcCtx, "Manual job run failed", err, logrus.Fields{
+				"job_name":     name,
+				"job_run_id":   run.ID.String(),
+				"job_duration": time.Since(jobStart).String(),
+			})
+			return
+		}
+
+		logger.LogSuccess(funcCtx, "Manual job run completed successfully", logrus.Fields{
+			"job_name":     name,
+			"job_run_id":   run.ID.String(),
+			"job_duration": time.Since(jobStart).String(),
+		})
+	}()
+
+	return queued, nil
+}
+
+// startJob takes the lock of a job and records the start of its run. The returned function releases the lock.
+// The run is nil when it could not be recorded, the lock is held either way.
+func (w *CronWorker) startJob(ctx context.Context, name string, trigger entities.JobTrigger) (func(), *entities.JobRun, error) {
+	funcCtx := "CronWorker.startJob"
+
+	unlock, err := w.locker.TryLock(ctx, name)
+	if err != nil {
+		if !errors.Is(err, ErrJobLocked) {
+			logger.LogError(funcCtx, "failed to take job lock", err, logrus.Fields{
+				"job_name": name,
+				"trigger":  trigger,
+			})
+		}
+		return nil, nil, err
+	}
+
+	run, err := w.jobRunUC.StartRun(ctx, name, trigger)
+	if err != nil {
+		logger.LogError(funcCtx, "Job runs without being recorded", err, logrus.Fields{
+			"job_name": name,
+			"trigger":  trigger,
+		})
+		return unlock, nil, nil
+	}
+
+	return unlock, run, nil
+}
+
+// executeJob runs a job and records its outcome on run when there is one. A panic is recovered and recorded as a
+// failed run, so a run never stays running after its job is gone.
+func (w *CronWorker) executeJob(ctx context.Context, run *entities.JobRun, job jobFunc) (jobErr error) {
+	var result interface{}
+	defer func() {
+		if r := recover(); r != nil {
+			jobErr = fmt.Errorf("job panicked: %v", r)
+		}
+		if run != nil {
+			// The job context may have timed out, the outcome must still be recorded
+			_ = w.jobRunUC.FinishRun(context.WithoutCancel(ctx), run, result, jobErr)
+		}
+	}()
+
+	result, jobErr = job(ctx)
+	return jobErr
+}
+
 // syncWalletBalances is the job function that gets executed by cron
 func (w *CronWorker) syncWalletBalances() {
 	funcCtx := "CronWorker.syncWalletBalances"
@@ -122,14 +467,26 @@ func (w *CronWorker) syncWalletBalances() {
 	})
 
 	// Create context with timeout for the sync job
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	ctx, cancel := w.jobContext()
 	defer cancel()
 
-	// Execute the balance sync using usecase
-	err := w.balanceSyncUC.SyncAllWalletBalances(ctx)
+	// Execute the balance sync using usecase, the report is stored for auditing
+	var report *dto.BalanceSyncReportResponse
+	err := w.runJob(ctx, JobBalanceSync, entities.JobTriggerScheduled, func(ctx context.Context) (interface{}, error) {
+		var err error
+		report, err = w.balanceSyncUC.SyncAllWalletBalances(ctx, false)
+		return balanceSyncSummary(report), err
+	})
 
 	jobDuration := time.Since(jobStart)
 
+	if errors.Is(err, ErrJobLocked) {
+		logger.LogSuccess(funcCtx, "Scheduled wallet balance sync job skipped, it is already running", logrus.Fields{
+			"scheduled_time": jobStart.Format(time.RFC3339),
+		})
+		return
+	}
+
 	if err != nil {
 		logger.LogError(funcCtx, "Scheduled wallet balance sync job failed", err, logrus.Fields{
 			"job_duration":   jobDuration.String(),
@@ -141,27 +498,188 @@ func (w *CronWorker) syncWalletBalances() {
 	logger.LogSuccess(funcCtx, "Scheduled wallet balance sync job completed successfully", logrus.Fields{
 		"job_duration":   jobDuration.String(),
 		"scheduled_time": jobStart.Format(time.RFC3339),
+		"report_id":      report.ID,
+		"drifted":        report.Drifted,
+		"corrected":      report.Corrected,
+		"failed":         report.Failed,
 	})
 }
 
-// TriggerSync manually triggers balance sync for all wallets
-func (w *CronWorker) TriggerSync(ctx context.Context) error {
-	funcCtx := "CronWorker.TriggerSync"
+// balanceSyncSummary is the report without its entries, entries are stored with the report itself
+func balanceSyncSummary(report *dto.BalanceSyncReportResponse) interface{} {
+	if report == nil {
+		return nil
+	}
+	summary := *report
+	summary.Entries = nil
+	return summary
+}
+
+// postRecurringTransactions is the job function that materialises due recurring transactions
+func (w *CronWorker) postRecurringTransactions() {
+	funcCtx := "CronWorker.postRecurringTransactions"
+	jobStart := time.Now()
 
-	logger.LogSuccess(funcCtx, "Manual wallet balance sync triggered", logrus.Fields{})
+	logger.LogSuccess(funcCtx, "Starting scheduled recurring transaction job", logrus.Fields{
+		"scheduled_time": jobStart.Format(time.RFC3339),
+	})
 
-	// Create context with timeout for manual sync
-	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	// Create context with timeout for the job
+	ctx, cancel := w.jobContext()
 	defer cancel()
 
-	err := w.balanceSyncUC.SyncAllWalletBalances(timeoutCtx)
+	var result *dto.ProcessRecurringTransactionsResponse
+	err := w.runJob(ctx, JobRecurringTransactions, entities.JobTriggerScheduled, func(ctx context.Context) (interface{}, error) {
+		var err error
+		result, err = w.recurringUC.ProcessDueRecurringTransactions(ctx, jobStart.UTC())
+		return result, err
+	})
+
+	jobDuration := time.Since(jobStart)
+
+	if errors.Is(err, ErrJobLocked) {
+		logger.LogSuccess(funcCtx, "Scheduled recurring transaction job skipped, it is already running", logrus.Fields{
+			"scheduled_time": jobStart.Format(time.RFC3339),
+		})
+		return
+	}
+
 	if err != nil {
-		logger.LogError(funcCtx, "Manual wallet balance sync failed", err, logrus.Fields{})
-		return err
+		logger.LogError(funcCtx, "Scheduled recurring transaction job failed", err, logrus.Fields{
+			"job_duration":   jobDuration.String(),
+			"scheduled_time": jobStart.Format(time.RFC3339),
+		})
+		return
 	}
 
-	logger.LogSuccess(funcCtx, "Manual wallet balance sync completed successfully", logrus.Fields{})
-	return nil
+	logger.LogSuccess(funcCtx, "Scheduled recurring transaction job completed successfully", logrus.Fields{
+		"job_duration":   jobDuration.String(),
+		"scheduled_time": jobStart.Format(time.RFC3339),
+		"posted":         result.Posted,
+		"failed":         result.Failed,
+	})
+}
+
+// TriggerRecurringTransactions queues a manual run posting all due recurring transactions and returns the run to poll.
+// Already posted occurrences are skipped, so the run is safe whenever it executes.
+func (w *CronWorker) TriggerRecurringTransactions() (*dto.JobRunResponse, error) {
+	funcCtx := "CronWorker.TriggerRecurringTransactions"
+
+	logger.LogSuccess(funcCtx, "Manual recurring transaction run triggered", logrus.Fields{})
+
+	return w.enqueueJob(JobRecurringTransactions, func(ctx context.Context) (interface{}, error) {
+		return w.recurringUC.ProcessDueRecurringTransactions(ctx, time.Now().UTC())
+	})
+}
+
+// sendBudgetAlerts is the job function that emails budget threshold alerts
+func (w *CronWorker) sendBudgetAlerts() {
+	funcCtx := "CronWorker.sendBudgetAlerts"
+	jobStart := time.Now()
+
+	logger.LogSuccess(funcCtx, "Starting scheduled budget alert job", logrus.Fields{
+		"scheduled_time": jobStart.Format(time.RFC3339),
+	})
+
+	// Create context with timeout for the job
+	ctx, cancel := w.jobContext()
+	defer cancel()
+
+	var result *dto.BudgetAlertRunResponse
+	err := w.runJob(ctx, JobBudgetAlerts, entities.JobTriggerScheduled, func(ctx context.Context) (interface{}, error) {
+		var err error
+		result, err = w.budgetUC.EvaluateBudgetAlerts(ctx, jobStart.UTC())
+		return result, err
+	})
+
+	jobDuration := time.Since(jobStart)
+
+	if errors.Is(err, ErrJobLocked) {
+		logger.LogSuccess(funcCtx, "Scheduled budget alert job skipped, it is already running", logrus.Fields{
+			"scheduled_time": jobStart.Format(time.RFC3339),
+		})
+		return
+	}
+
+	if err != nil {
+		logger.LogError(funcCtx, "Scheduled budget alert job failed", err, logrus.Fields{
+			"job_duration":   jobDuration.String(),
+			"scheduled_time": jobStart.Format(time.RFC3339),
+		})
+		return
+	}
+
+	logger.LogSuccess(funcCtx, "Scheduled budget alert job completed successfully", logrus.Fields{
+		"job_duration":   jobDuration.String(),
+		"scheduled_time": jobStart.Format(time.RFC3339),
+		"alerts_sent":    result.AlertsSent,
+		"failed":         result.Failed,
+	})
+}
+
+// TriggerBudgetAlerts queues a manual budget alert run and returns the run to poll.
+// Thresholds already alerted this period are not sent again.
+func (w *CronWorker) TriggerBudgetAlerts() (*dto.JobRunResponse, error) {
+	funcCtx := "CronWorker.TriggerBudgetAlerts"
+
+	logger.LogSuccess(funcCtx, "Manual budget alert run triggered", logrus.Fields{})
+
+	return w.enqueueJob(JobBudgetAlerts, func(ctx context.Context) (interface{}, error) {
+		return w.budgetUC.EvaluateBudgetAlerts(ctx, time.Now().UTC())
+	})
+}
+
+// purgeIdempotencyKeys is the job function that deletes the expired idempotency keys stored in Postgres
+func (w *CronWorker) purgeIdempotencyKeys() {
+	funcCtx := "CronWorker.purgeIdempotencyKeys"
+	jobStart := time.Now()
+
+	// Create context with timeout for the job
+	ctx, cancel := w.jobContext()
+	defer cancel()
+
+	var result *dto.IdempotencyKeyPurgeResponse
+	err := w.runJob(ctx, JobIdempotencyCleanup, entities.JobTriggerScheduled, func(ctx context.Context) (interface{}, error) {
+		var err error
+		result, err = w.idempotencyUC.PurgeExpiredKeys(ctx, jobStart)
+		return result, err
+	})
+
+	if errors.Is(err, ErrJobLocked) {
+		return
+	}
+
+	if err != nil {
+		logger.LogError(funcCtx, "Scheduled idempotency key cleanup failed", err, logrus.Fields{
+			"job_duration":   time.Since(jobStart).String(),
+			"scheduled_time": jobStart.Format(time.RFC3339),
+		})
+		return
+	}
+
+	logger.LogSuccess(funcCtx, "Scheduled idempotency key cleanup completed successfully", logrus.Fields{
+		"job_duration":   time.Since(jobStart).String(),
+		"scheduled_time": jobStart.Format(time.RFC3339),
+		"deleted":        result.Deleted,
+	})
+}
+
+// TriggerSync queues a manual balance sync for all wallets and returns the run to poll. A dry run only reports the
+// drifted balances, its report is kept whole in the run summary since it is not stored anywhere else.
+func (w *CronWorker) TriggerSync(dryRun bool) (*dto.JobRunResponse, error) {
+	funcCtx := "CronWorker.TriggerSync"
+
+	logger.LogSuccess(funcCtx, "Manual wallet balance sync triggered", logrus.Fields{
+		"dry_run": dryRun,
+	})
+
+	return w.enqueueJob(JobBalanceSync, func(ctx context.Context) (interface{}, error) {
+		report, err := w.balanceSyncUC.SyncAllWalletBalances(ctx, dryRun)
+		if dryRun {
+			return report, err
+		}
+		return balanceSyncSummary(report), err
+	})
 }
 
 // getNextRunTimes returns the next scheduled run times for debugging
@@ -0,0 +1,170 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/usecases"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryJobRunUseCase records runs in memory and sends each finished run on finished
+type memoryJobRunUseCase struct {
+	usecases.JobRunUseCaseInterface
+	finished chan *entities.JobRun
+}
+
+func (uc *memoryJobRunUseCase) StartRun(ctx context.Context, jobName string, trigger entities.JobTrigger) (*entities.JobRun, error) {
+	return &entities.JobRun{
+		ID:        uuid.New(),
+		JobName:   jobName,
+		Trigger:   trigger,
+		Status:    entities.JobRunStatusRunning,
+		StartedAt: time.Now().UTC(),
+	}, nil
+}
+
+func (uc *memoryJobRunUseCase) GetJobStatus(ctx context.Context, jobName string, schedule string, nextRunAt *time.Time) (*dto.JobStatusResponse, error) {
+	return &dto.JobStatusResponse{Name: jobName, Schedule: schedule, NextRunAt: nextRunAt}, nil
+}
+
+func (uc *memoryJobRunUseCase) FinishRun(ctx context.Context, run *entities.JobRun, result interface{}, jobErr error) error {
+	run.Finish(nil, jobErr)
+	uc.finished <- run
+	return nil
+}
+
+// blockingBalanceSyncUseCase syncs once release is closed, or gives up when its context is cancelled
+type blockingBalanceSyncUseCase struct {
+	usecases.BalanceSyncUseCaseInterface
+	release chan struct{}
+}
+
+func (uc *blockingBalanceSyncUseCase) SyncAllWalletBalances(ctx context.Context, dryRun bool) (*dto.BalanceSyncReportResponse, error) {
+	select {
+	case <-uc.release:
+		return &dto.BalanceSyncReportResponse{DryRun: dryRun}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (uc *blockingBalanceSyncUseCase) GetSyncProgress() *dto.BalanceSyncProgressResponse {
+	return nil
+}
+
+// memoryJobSettingUseCase keeps job settings in memory
+type memoryJobSettingUseCase struct {
+	mu       sync.Mutex
+	settings map[string]*entities.JobSetting
+}
+
+func (uc *memoryJobSettingUseCase) GetJobSettings(ctx context.Context) ([]*entities.JobSetting, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	settings := make([]*entities.JobSetting, 0, len(uc.settings))
+	for _, setting := range uc.settings {
+		stored := *setting
+		settings = append(settings, &stored)
+	}
+	return settings, nil
+}
+
+func (uc *memoryJobSettingUseCase) SaveJobSetting(ctx context.Context, jobName string, schedule string, timezone string, enabled bool) (*entities.JobSetting, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if uc.settings == nil {
+		uc.settings = make(map[string]*entities.JobSetting)
+	}
+	setting := &entities.JobSetting{JobName: jobName, Schedule: schedule, Timezone: timezone, Enabled: enabled, UpdatedAt: time.Now().UTC()}
+	uc.settings[jobName] = setting
+	stored := *setting
+	return &stored, nil
+}
+
+func (uc *memoryJobSettingUseCase) DeleteJobSetting(ctx context.Context, jobName string) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	delete(uc.settings, jobName)
+	return nil
+}
+
+// newTestWorker builds a worker whose job locks live in a fake database
+func newTestWorker(t *testing.T, balanceSyncUC usecases.BalanceSyncUseCaseInterface, jobRunUC usecases.JobRunUseCaseInterface, jobSettingUC usecases.JobSettingUseCaseInterface) *CronWorker {
+	logger.Init("info")
+	return NewCronWorker(balanceSyncUC, nil, nil, jobRunUC, jobSettingUC, nil, newFakeLockDB(t))
+}
+
+func TestTriggerSync(t *testing.T) {
+	runs := &memoryJobRunUseCase{finished: make(chan *entities.JobRun, 1)}
+	balanceSync := &blockingBalanceSyncUseCase{release: make(chan struct{})}
+	w := newTestWorker(t, balanceSync, runs, &memoryJobSettingUseCase{})
+
+	// The run is returned before the sync executes, so it can be polled
+	queued, err := w.TriggerSync(false)
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, queued.ID)
+	assert.Equal(t, JobBalanceSync, queued.JobName)
+	assert.Equal(t, string(entities.JobTriggerManual), queued.Trigger)
+	assert.Equal(t, string(entities.JobRunStatusRunning), queued.Status)
+
+	// A second trigger while the first run holds the lock is refused, the handler answers it with a conflict
+	_, err = w.TriggerSync(true)
+	assert.ErrorIs(t, err, ErrJobLocked)
+
+	close(balanceSync.release)
+	select {
+	case run := <-runs.finished:
+		assert.Equal(t, queued.ID, run.ID)
+		assert.Equal(t, entities.JobRunStatusSucceeded, run.Status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the queued run did not finish")
+	}
+
+	// Once the run is over the job can be triggered again
+	w.manualRuns.Wait()
+	_, err = w.TriggerSync(true)
+	require.NoError(t, err)
+	<-runs.finished
+	w.manualRuns.Wait()
+}
+
+func TestStop_CancelsManualRuns(t *testing.T) {
+	runs := &memoryJobRunUseCase{finished: make(chan *entities.JobRun, 1)}
+	balanceSync := &blockingBalanceSyncUseCase{release: make(chan struct{})}
+	w := newTestWorker(t, balanceSync, runs, &memoryJobSettingUseCase{})
+	w.stopTimeout = 10 * time.Millisecond
+
+	require.NoError(t, w.Start())
+	_, err := w.TriggerSync(false)
+	require.NoError(t, err)
+
+	// The sync never finishes on its own, Stop cancels it once it stops waiting
+	stopped := time.Now()
+	w.Stop()
+	assert.Less(t, time.Since(stopped), jobCancelTimeout)
+
+	select {
+	case run := <-runs.finished:
+		assert.Equal(t, entities.JobRunStatusFailed, run.Status)
+		assert.Equal(t, context.Canceled.Error(), run.Error)
+	default:
+		t.Fatal("the cancelled run was not recorded")
+	}
+
+	// A restarted worker runs jobs again
+	require.NoError(t, w.Start())
+	defer w.Stop()
+	_, err = w.TriggerSync(false)
+	require.NoError(t, err)
+	close(balanceSync.release)
+	run := <-runs.finished
+	assert.Equal(t, entities.JobRunStatusSucceeded, run.Status)
+}
@@ -0,0 +1,187 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ErrJobLocked is returned when a job is already running, on this or another replica
+var ErrJobLocked = errors.New("job is already running")
+
+// JobLockOwner is the replica holding the lock of a job
+type JobLockOwner struct {
+	InstanceID string    `json:"instance_id"`
+	Since      time.Time `json:"since"`
+	IsSelf     bool      `json:"is_self"` // Held by the replica answering the request
+}
+
+// JobLocker makes sure a job runs on one replica at a time with Postgres session advisory locks. The lock is held on
+// a dedicated connection for the whole run, so it is released when the run ends and also when the replica dies.
+// The connection carries the instance ID as its application name, which is how other replicas see the owner.
+type JobLocker struct {
+	db         *gorm.DB
+	instanceID string
+	mu         sync.Mutex
+	held       map[string]time.Time // Jobs locked by this instance and since when
+}
+
+func NewJobLocker(db *gorm.DB, instanceID string) *JobLocker {
+	if instanceID == "" {
+		instanceID = defaultInstanceID()
+	}
+	return &JobLocker{
+		db:         db,
+		instanceID: instanceID,
+		held:       make(map[string]time.Time),
+	}
+}
+
+// defaultInstanceID identifies this process when INSTANCE_ID is not set
+func defaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// InstanceID returns the ID this replica locks jobs with
+func (l *JobLocker) InstanceID() string {
+	return l.instanceID
+}
+
+// jobLockKey maps a job name to its advisory lock key
+func jobLockKey(jobName string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("finance-manager-job:" + jobName))
+	return int64(h.Sum64())
+}
+
+// jobLockKeyParts returns how pg_locks shows the advisory lock of a job: a bigint key is stored as its high and low
+// 32 bits in the classid and objid oid columns
+func jobLockKeyParts(jobName string) (classID int64, objID int64) {
+	key := uint64(jobLockKey(jobName))
+	return int64(key >> 32), int64(key & 0xffffffff)
+}
+
+// TryLock takes the lock of a job without waiting. It returns ErrJobLocked when another run holds it, otherwise
+// the returned function releases the lock and must be called once the job is done.
+func (l *JobLocker) TryLock(ctx context.Context, jobName string) (func(), error) {
+	funcCtx := "JobLocker.TryLock"
+
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database handle: %w", err)
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lock connection: %w", err)
+	}
+
+	key := jobLockKey(jobName)
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked); err != nil {
+		// The lock may have been taken before the error, drop the session instead of pooling it
+		discardConn(conn)
+		return nil, fmt.Errorf("failed to take job lock: %w", err)
+	}
+	if !locked {
+		conn.Close()
+		return nil, ErrJobLocked
+	}
+
+	if _, err := conn.ExecContext(ctx, "SELECT set_config('application_name', $1, false)", l.instanceID); err != nil {
+		// Ownership is only informational, the lock itself is held
+		logger.LogError(funcCtx, "failed to tag job lock connection", err, logrus.Fields{
+			"job_name": jobName,
+		})
+	}
+
+	l.mu.Lock()
+	l.held[jobName] = time.Now().UTC()
+	l.mu.Unlock()
+
+	var once sync.Once
+	unlock := func() {
+		once.Do(func() {
+			l.mu.Lock()
+			delete(l.held, jobName)
+			l.mu.Unlock()
+
+			// The job context may be done, releasing must not depend on it
+			releaseCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			var released bool
+			err := conn.QueryRowContext(releaseCtx, "SELECT pg_advisory_unlock($1)", key).Scan(&released)
+			if err == nil && !released {
+				err = errors.New("the lock connection no longer held the lock")
+			}
+			if err == nil {
+				_, err = conn.ExecContext(releaseCtx, "RESET application_name")
+			}
+			if err != nil {
+				logger.LogError(funcCtx, "failed to release job lock, dropping its connection", err, logrus.Fields{
+					"job_name": jobName,
+				})
+				// Closing the session releases the lock, never hand it back to the pool still locked
+				discardConn(conn)
+				return
+			}
+			conn.Close()
+		})
+	}
+
+	return unlock, nil
+}
+
+// discardConn closes the session of conn instead of returning it to the pool
+func discardConn(conn *sql.Conn) {
+	_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+	conn.Close()
+}
+
+// Owner returns which replica holds the lock of a job, nil when the job is not running anywhere
+func (l *JobLocker) Owner(ctx context.Context, jobName string) (*JobLockOwner, error) {
+	l.mu.Lock()
+	since, ok := l.held[jobName]
+	l.mu.Unlock()
+	if ok {
+		return &JobLockOwner{InstanceID: l.instanceID, Since: since, IsSelf: true}, nil
+	}
+
+	classID, objID := jobLockKeyParts(jobName)
+	var owners []struct {
+		ApplicationName string
+		StateChange     *time.Time
+	}
+	if err := l.db.WithContext(ctx).Raw(`SELECT a.application_name, a.state_change
+		FROM pg_locks l JOIN pg_stat_activity a ON a.pid = l.pid
+		WHERE l.locktype = 'advisory' AND l.granted AND l.objsubid = 1 AND l.classid::bigint = ? AND l.objid::bigint = ?`,
+		classID, objID).Scan(&owners).Error; err != nil {
+		return nil, err
+	}
+	if len(owners) == 0 {
+		return nil, nil
+	}
+
+	// The lock connection stays idle once the lock is taken, so its last state change is when it was taken
+	owner := &JobLockOwner{InstanceID: owners[0].ApplicationName}
+	if owners[0].StateChange != nil {
+		owner.Since = owners[0].StateChange.UTC()
+	}
+	return owner, nil
+}
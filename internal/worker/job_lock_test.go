@@ -0,0 +1,199 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// fakeAdvisoryLocks stands in for the advisory locks of a Postgres server. Locks belong to the session, that is the
+// connection, that took them, as pg_try_advisory_lock and pg_advisory_unlock do, and are listed in pg_locks with
+// their key split into classid and objid.
+type fakeAdvisoryLocks struct {
+	mu    sync.Mutex
+	locks map[int64]*fakeSession
+}
+
+type fakeSession struct {
+	server          *fakeAdvisoryLocks
+	applicationName string
+	lockedAt        time.Time
+}
+
+func (s *fakeAdvisoryLocks) Open(string) (driver.Conn, error) {
+	return &fakeSession{server: s}, nil
+}
+
+func (c *fakeSession) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *fakeSession) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+// Close ends the session, which releases its locks
+func (c *fakeSession) Close() error {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	for key, owner := range c.server.locks {
+		if owner == c {
+			delete(c.server.locks, key)
+		}
+	}
+	return nil
+}
+
+func (c *fakeSession) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	switch {
+	case strings.Contains(query, "set_config('application_name'"):
+		c.applicationName = args[0].Value.(string)
+	case strings.Contains(query, "RESET application_name"):
+		c.applicationName = ""
+	default:
+		return nil, fmt.Errorf("unexpected exec %q", query)
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeSession) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	switch {
+	case strings.Contains(query, "pg_try_advisory_lock"):
+		key := args[0].Value.(int64)
+		owner, held := c.server.locks[key]
+		if held && owner != c {
+			return &fakeRows{columns: []string{"pg_try_advisory_lock"}, values: [][]driver.Value{{false}}}, nil
+		}
+		c.server.locks[key] = c
+		c.lockedAt = time.Now()
+		return &fakeRows{columns: []string{"pg_try_advisory_lock"}, values: [][]driver.Value{{true}}}, nil
+
+	case strings.Contains(query, "pg_advisory_unlock"):
+		key := args[0].Value.(int64)
+		released := c.server.locks[key] == c
+		if released {
+			delete(c.server.locks, key)
+		}
+		return &fakeRows{columns: []string{"pg_advisory_unlock"}, values: [][]driver.Value{{released}}}, nil
+
+	case strings.Contains(query, "pg_locks"):
+		classID, objID := args[0].Value.(int64), args[1].Value.(int64)
+		rows := &fakeRows{columns: []string{"application_name", "state_change"}}
+		for key, owner := range c.server.locks {
+			if int64(uint64(key)>>32) == classID && int64(uint64(key)&0xffffffff) == objID {
+				rows.values = append(rows.values, []driver.Value{owner.applicationName, owner.lockedAt})
+			}
+		}
+		return rows, nil
+	}
+	return nil, fmt.Errorf("unexpected query %q", query)
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+var registerFakeAdvisoryLocks sync.Once
+
+// newFakeLockDB opens a gorm DB whose connections share one set of advisory locks
+func newFakeLockDB(t *testing.T) *gorm.DB {
+	registerFakeAdvisoryLocks.Do(func() {
+		sql.Register("fake-advisory-locks", &fakeAdvisoryLocks{locks: make(map[int64]*fakeSession)})
+	})
+
+	sqlDB, err := sql.Open("fake-advisory-locks", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	require.NoError(t, err)
+	return db
+}
+
+func TestJobLockKeyParts(t *testing.T) {
+	for _, name := range []string{JobBalanceSync, JobRecurringTransactions, JobBudgetAlerts, JobIdempotencyCleanup} {
+		classID, objID := jobLockKeyParts(name)
+
+		// pg_locks shows both halves as unsigned 32-bit oids
+		assert.GreaterOrEqual(t, classID, int64(0), name)
+		assert.Less(t, classID, int64(1)<<32, name)
+		assert.GreaterOrEqual(t, objID, int64(0), name)
+		assert.Less(t, objID, int64(1)<<32, name)
+		assert.Equal(t, jobLockKey(name), int64(uint64(classID)<<32|uint64(objID)), name)
+	}
+
+	assert.NotEqual(t, jobLockKey(JobBalanceSync), jobLockKey(JobBudgetAlerts))
+}
+
+func TestJobLocker_TryLock(t *testing.T) {
+	logger.Init("info")
+	ctx := context.Background()
+	db := newFakeLockDB(t)
+	replicaA, replicaB := NewJobLocker(db, "replica-a"), NewJobLocker(db, "replica-b")
+
+	owner, err := replicaB.Owner(ctx, JobBalanceSync)
+	require.NoError(t, err)
+	assert.Nil(t, owner, "nobody runs the job yet")
+
+	unlock, err := replicaA.TryLock(ctx, JobBalanceSync)
+	require.NoError(t, err)
+
+	// The other replica can't take the lock, and sees who holds it
+	_, err = replicaB.TryLock(ctx, JobBalanceSync)
+	assert.ErrorIs(t, err, ErrJobLocked)
+	owner, err = replicaB.Owner(ctx, JobBalanceSync)
+	require.NoError(t, err)
+	require.NotNil(t, owner)
+	assert.Equal(t, "replica-a", owner.InstanceID)
+	assert.False(t, owner.IsSelf)
+
+	owner, err = replicaA.Owner(ctx, JobBalanceSync)
+	require.NoError(t, err)
+	require.NotNil(t, owner)
+	assert.True(t, owner.IsSelf)
+
+	// Other jobs are not blocked
+	unlockAlerts, err := replicaB.TryLock(ctx, JobBudgetAlerts)
+	require.NoError(t, err)
+	unlockAlerts()
+
+	// Unlocking runs on the connection holding the lock, so the job is free again
+	unlock()
+	unlock() // Releasing twice is harmless
+	owner, err = replicaB.Owner(ctx, JobBalanceSync)
+	require.NoError(t, err)
+	assert.Nil(t, owner)
+
+	unlock, err = replicaB.TryLock(ctx, JobBalanceSync)
+	require.NoError(t, err)
+	unlock()
+}
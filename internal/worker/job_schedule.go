@@ -0,0 +1,292 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/internal/dto"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/logger"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// jobSettingsRefreshInterval is how often a replica picks up the schedule changes made through another replica
+const jobSettingsRefreshInterval = time.Minute
+
+// cronSpec is the cron expression of a schedule read in timezone
+func cronSpec(schedule, timezone string) string {
+	if timezone == "" {
+		return schedule
+	}
+	return fmt.Sprintf("CRON_TZ=%s %s", timezone, schedule)
+}
+
+// validateSchedule checks that schedule is a standard cron expression and timezone a known time zone
+func validateSchedule(schedule, timezone string) error {
+	if _, err := cron.ParseStandard(cronSpec(schedule, timezone)); err != nil {
+		return fmt.Errorf("invalid schedule %q in time zone %q: %w", schedule, timezone, err)
+	}
+	return nil
+}
+
+// applySchedule registers job with the scheduler as its settings say, replacing its previous entry.
+// Nothing is registered while the worker is stopped or the job is paused. Callers hold w.mu.
+func (w *CronWorker) applySchedule(job *scheduledJob) error {
+	if job.entryID != 0 {
+		w.cron.Remove(job.entryID)
+		job.entryID = 0
+	}
+
+	if !w.isRunning || !job.enabled {
+		return nil
+	}
+
+	entryID, err := w.cron.AddFunc(cronSpec(job.schedule, job.timezone), job.run)
+	if err != nil {
+		return err
+	}
+	job.entryID = entryID
+	return nil
+}
+
+// GetJob returns a registered job with its schedule, last run and failure streak
+func (w *CronWorker) GetJob(ctx context.Context, name string) (*dto.JobStatusResponse, error) {
+	job := w.findJob(name)
+	if job == nil {
+		return nil, helpers.NewNotFoundError("job not found", name)
+	}
+	return w.jobStatus(ctx, job)
+}
+
+// PauseJob stops scheduling a job on every replica until it is resumed. A run in progress is not interrupted and the
+// job can still be triggered manually.
+func (w *CronWorker) PauseJob(ctx context.Context, name string) (*dto.JobStatusResponse, error) {
+	return w.updateJob(ctx, name, func(job *scheduledJob) (string, string, bool) {
+		return job.schedule, job.timezone, false
+	})
+}
+
+// ResumeJob schedules a paused job again on every replica
+func (w *CronWorker) ResumeJob(ctx context.Context, name string) (*dto.JobStatusResponse, error) {
+	return w.updateJob(ctx, name, func(job *scheduledJob) (string, string, bool) {
+		return job.schedule, job.timezone, true
+	})
+}
+
+// RescheduleJob changes the cron expression of a job on every replica, and its time zone unless timezone is empty.
+// A paused job stays paused with its new schedule.
+func (w *CronWorker) RescheduleJob(ctx context.Context, name string, schedule string, timezone string) (*dto.JobStatusResponse, error) {
+	return w.updateJob(ctx, name, func(job *scheduledJob) (string, string, bool) {
+		if timezone == "" {
+			timezone = job.timezone
+		}
+		return schedule, timezone, job.enabled
+	})
+}
+
+// ResetJob deletes the stored setting of a job, so every replica schedules it from the config again
+func (w *CronWorker) ResetJob(ctx context.Context, name string) (*dto.JobStatusResponse, error) {
+	funcCtx := "CronWorker.ResetJob"
+
+	job := w.findJob(name)
+	if job == nil {
+		return nil, helpers.NewNotFoundError("job not found", name)
+	}
+
+	if err := w.jobSettingUC.DeleteJobSetting(ctx, name); err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	err := w.resetJobSchedule(job, time.Now().UTC())
+	w.mu.Unlock()
+	if err != nil {
+		logger.LogError(funcCtx, "failed to reschedule job", err, logrus.Fields{
+			"job_name": name,
+		})
+		return nil, helpers.NewInternalError("failed to reschedule job", err.Error())
+	}
+
+	logger.LogSuccess(funcCtx, "Job settings reset to the configured schedule", logrus.Fields{
+		"job_name": name,
+		"schedule": job.config.Schedule,
+		"timezone": job.config.Timezone,
+		"enabled":  job.config.Enabled,
+	})
+
+	return w.jobStatus(ctx, job)
+}
+
+// updateJob stores the settings change returns for a job, so other replicas and later restarts use them, then applies
+// them to this replica right away. w.mu is only held to read and apply the settings, never while they are stored.
+func (w *CronWorker) updateJob(ctx context.Context, name string, change func(job *scheduledJob) (string, string, bool)) (*dto.JobStatusResponse, error) {
+	funcCtx := "CronWorker.updateJob"
+
+	job := w.findJob(name)
+	if job == nil {
+		return nil, helpers.NewNotFoundError("job not found", name)
+	}
+
+	w.mu.RLock()
+	schedule, timezone, enabled := change(job)
+	w.mu.RUnlock()
+	if err := validateSchedule(schedule, timezone); err != nil {
+		return nil, helpers.NewValidationError("invalid job schedule", err.Error())
+	}
+
+	setting, err := w.jobSettingUC.SaveJobSetting(ctx, name, schedule, timezone, enabled)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	_, err = w.applyJobSetting(job, setting)
+	w.mu.Unlock()
+	if err != nil {
+		logger.LogError(funcCtx, "failed to reschedule job", err, logrus.Fields{
+			"job_name": name,
+		})
+		return nil, helpers.NewInternalError("failed to reschedule job", err.Error())
+	}
+
+	logger.LogSuccess(funcCtx, "Job settings updated", logrus.Fields{
+		"job_name": name,
+		"schedule": schedule,
+		"timezone": timezone,
+		"enabled":  enabled,
+	})
+
+	return w.jobStatus(ctx, job)
+}
+
+// applyJobSetting updates a job to a stored setting and reschedules it if it changed. A setting stored before the one
+// in effect is stale and ignored, it reports whether the schedule changed. Callers hold w.mu.
+func (w *CronWorker) applyJobSetting(job *scheduledJob, setting *entities.JobSetting) (bool, error) {
+	if setting.UpdatedAt.Before(job.changedAt) {
+		return false, nil
+	}
+	job.stored = true
+	job.changedAt = setting.UpdatedAt
+
+	if job.schedule == setting.Schedule && job.timezone == setting.Timezone && job.enabled == setting.Enabled {
+		return false, nil
+	}
+	job.schedule = setting.Schedule
+	job.timezone = setting.Timezone
+	job.enabled = setting.Enabled
+	return true, w.applySchedule(job)
+}
+
+// resetJobSchedule puts a job back on its configured schedule, resetAt is when its stored setting was deleted.
+// Callers hold w.mu.
+func (w *CronWorker) resetJobSchedule(job *scheduledJob, resetAt time.Time) error {
+	job.schedule = job.config.Schedule
+	job.timezone = job.config.Timezone
+	job.enabled = job.config.Enabled
+	job.stored = false
+	job.changedAt = resetAt
+	return w.applySchedule(job)
+}
+
+// refreshJobSettings applies the stored job settings to this replica. They are read before taking w.mu, so schedule
+// changes and the status endpoints never wait on the database.
+func (w *CronWorker) refreshJobSettings() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Settings stored or deleted after this are newer than the ones read
+	readAt := time.Now().UTC()
+	settings, err := w.jobSettingUC.GetJobSettings(ctx)
+	if err != nil {
+		// Already logged by the usecase, keep the current schedules until the next refresh
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.applyJobSettings(settings, readAt)
+}
+
+// applyJobSettings applies the stored settings, read at readAt, that differ from the current schedule of a job. A
+// stored setting that is no longer valid is skipped, the job keeps its current schedule. A job whose setting was
+// deleted on another replica goes back to its configured schedule. Callers hold w.mu.
+func (w *CronWorker) applyJobSettings(settings []*entities.JobSetting, readAt time.Time) {
+	funcCtx := "CronWorker.applyJobSettings"
+
+	storedJobs := make(map[string]bool, len(settings))
+	for _, setting := range settings {
+		job := w.findJob(setting.JobName)
+		if job == nil {
+			continue
+		}
+		storedJobs[job.name] = true
+
+		if err := validateSchedule(setting.Schedule, setting.Timezone); err != nil {
+			logger.LogError(funcCtx, "ignoring invalid stored job setting", err, logrus.Fields{
+				"job_name": setting.JobName,
+			})
+			continue
+		}
+
+		changed, err := w.applyJobSetting(job, setting)
+		if err != nil {
+			logger.LogError(funcCtx, "failed to apply stored job setting", err, logrus.Fields{
+				"job_name": setting.JobName,
+			})
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		// The stored setting wins over the config until the job is reset, say so since the config alone looks wrong
+		logger.LogSuccess(funcCtx, "Applied stored job setting, it overrides the configured schedule until the job is reset", logrus.Fields{
+			"job_name":            setting.JobName,
+			"schedule":            setting.Schedule,
+			"timezone":            setting.Timezone,
+			"enabled":             setting.Enabled,
+			"configured_schedule": job.config.Schedule,
+			"configured_timezone": job.config.Timezone,
+			"configured_enabled":  job.config.Enabled,
+		})
+	}
+
+	for _, job := range w.jobs {
+		if !job.stored || storedJobs[job.name] || !job.changedAt.Before(readAt) {
+			continue
+		}
+
+		if err := w.resetJobSchedule(job, readAt); err != nil {
+			logger.LogError(funcCtx, "failed to reset job schedule", err, logrus.Fields{
+				"job_name": job.name,
+			})
+			continue
+		}
+
+		logger.LogSuccess(funcCtx, "Job setting was reset, using the configured schedule", logrus.Fields{
+			"job_name": job.name,
+			"schedule": job.schedule,
+			"timezone": job.timezone,
+			"enabled":  job.enabled,
+		})
+	}
+}
+
+// watchJobSettings periodically applies the settings stored by other replicas until stop is closed
+func (w *CronWorker) watchJobSettings(stop <-chan struct{}) {
+	ticker := time.NewTicker(jobSettingsRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			w.refreshJobSettings()
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/internal/domain/entities"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	assert.NoError(t, validateSchedule("0 0 * * *", "UTC"))
+	assert.NoError(t, validateSchedule("30 2 * * 1-5", "Asia/Jakarta"))
+	assert.NoError(t, validateSchedule("@hourly", ""))
+
+	assert.Error(t, validateSchedule("0 0 * *", "UTC"))
+	assert.Error(t, validateSchedule("0 0 * * *", "Mars/Olympus_Mons"))
+}
+
+// newScheduleTestWorker builds a worker whose job settings are stored in settings
+func newScheduleTestWorker(t *testing.T, settings *memoryJobSettingUseCase) *CronWorker {
+	runs := &memoryJobRunUseCase{finished: make(chan *entities.JobRun, 1)}
+	return newTestWorker(t, &blockingBalanceSyncUseCase{}, runs, settings)
+}
+
+// assertScheduled checks that a job has a cron entry exactly when it is expected to be scheduled
+func assertScheduled(t *testing.T, w *CronWorker, name string, scheduled bool) {
+	t.Helper()
+	job := w.findJob(name)
+	require.NotNil(t, job)
+	if !scheduled {
+		assert.Zero(t, job.entryID, name)
+		return
+	}
+	require.NotZero(t, job.entryID, name)
+	assert.True(t, w.cron.Entry(job.entryID).Valid(), name)
+}
+
+func assertErrorType(t *testing.T, err error, want helpers.ErrorType) {
+	t.Helper()
+	var appErr *helpers.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, want, appErr.Type)
+}
+
+func TestStart_AppliesStoredSettings(t *testing.T) {
+	settings := &memoryJobSettingUseCase{settings: map[string]*entities.JobSetting{
+		JobBalanceSync:  {JobName: JobBalanceSync, Schedule: "0 0 * * *", Timezone: "UTC", Enabled: false, UpdatedAt: time.Now().UTC()},
+		JobBudgetAlerts: {JobName: JobBudgetAlerts, Schedule: "0 6 * * *", Timezone: "Asia/Jakarta", Enabled: true, UpdatedAt: time.Now().UTC()},
+		"removed_job":   {JobName: "removed_job", Schedule: "* * * * *", Timezone: "UTC", Enabled: true, UpdatedAt: time.Now().UTC()},
+	}}
+	w := newScheduleTestWorker(t, settings)
+
+	require.NoError(t, w.Start())
+	defer w.Stop()
+
+	assert.Len(t, w.cron.Entries(), len(w.jobs)-1)
+	assertScheduled(t, w, JobBalanceSync, false)
+	assertScheduled(t, w, JobRecurringTransactions, true)
+	assertScheduled(t, w, JobBudgetAlerts, true)
+
+	alerts := w.findJob(JobBudgetAlerts)
+	assert.Equal(t, "0 6 * * *", alerts.schedule)
+	assert.Equal(t, "Asia/Jakarta", alerts.timezone)
+	assert.True(t, alerts.stored)
+	assert.False(t, w.findJob(JobRecurringTransactions).stored)
+}
+
+func TestStart_RejectsInvalidConfiguredSchedule(t *testing.T) {
+	w := newScheduleTestWorker(t, &memoryJobSettingUseCase{})
+	w.findJob(JobBudgetAlerts).config.Schedule = "every hour"
+
+	err := w.Start()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), JobBudgetAlerts)
+	assert.False(t, w.IsRunning())
+	assert.Empty(t, w.cron.Entries())
+}
+
+func TestPauseResumeAndRescheduleJob(t *testing.T) {
+	ctx := context.Background()
+	settings := &memoryJobSettingUseCase{}
+	w := newScheduleTestWorker(t, settings)
+	require.NoError(t, w.Start())
+	defer w.Stop()
+
+	jobs := len(w.jobs)
+	require.Len(t, w.cron.Entries(), jobs)
+
+	// Pausing removes the cron entry and stores the change
+	status, err := w.PauseJob(ctx, JobBalanceSync)
+	require.NoError(t, err)
+	assert.False(t, status.Enabled)
+	assert.Len(t, w.cron.Entries(), jobs-1)
+	assertScheduled(t, w, JobBalanceSync, false)
+	assert.False(t, settings.settings[JobBalanceSync].Enabled)
+
+	// A paused job keeps its new schedule paused, the time zone is kept when none is given
+	status, err = w.RescheduleJob(ctx, JobBalanceSync, "30 1 * * *", "")
+	require.NoError(t, err)
+	assert.Equal(t, "30 1 * * *", status.Schedule)
+	assert.Equal(t, "UTC", status.Timezone)
+	assert.False(t, status.Enabled)
+	assertScheduled(t, w, JobBalanceSync, false)
+
+	// Resuming adds an entry with the new schedule
+	status, err = w.ResumeJob(ctx, JobBalanceSync)
+	require.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.Len(t, w.cron.Entries(), jobs)
+	assertScheduled(t, w, JobBalanceSync, true)
+	next := w.cron.Entry(w.findJob(JobBalanceSync).entryID).Next
+	assert.Equal(t, 1, next.Hour())
+	assert.Equal(t, 30, next.Minute())
+
+	// Rescheduling a running job replaces its entry
+	previous := w.findJob(JobBalanceSync).entryID
+	status, err = w.RescheduleJob(ctx, JobBalanceSync, "0 7 * * *", "Asia/Jakarta")
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Jakarta", status.Timezone)
+	assert.Len(t, w.cron.Entries(), jobs)
+	assert.False(t, w.cron.Entry(previous).Valid())
+	assertScheduled(t, w, JobBalanceSync, true)
+
+	// Invalid schedules and unknown jobs change nothing
+	_, err = w.RescheduleJob(ctx, JobBalanceSync, "0 7 * *", "")
+	assertErrorType(t, err, helpers.ErrorTypeValidation)
+	_, err = w.PauseJob(ctx, "unknown_job")
+	assertErrorType(t, err, helpers.ErrorTypeNotFound)
+	assert.Equal(t, "0 7 * * *", settings.settings[JobBalanceSync].Schedule)
+	assert.Len(t, settings.settings, 1)
+}
+
+func TestResetJob(t *testing.T) {
+	ctx := context.Background()
+	settings := &memoryJobSettingUseCase{}
+	w := newScheduleTestWorker(t, settings)
+	require.NoError(t, w.Start())
+	defer w.Stop()
+
+	_, err := w.RescheduleJob(ctx, JobRecurringTransactions, "0 */2 * * *", "Asia/Jakarta")
+	require.NoError(t, err)
+	_, err = w.PauseJob(ctx, JobRecurringTransactions)
+	require.NoError(t, err)
+
+	status, err := w.ResetJob(ctx, JobRecurringTransactions)
+	require.NoError(t, err)
+
+	job := w.findJob(JobRecurringTransactions)
+	assert.Equal(t, job.config.Schedule, status.Schedule)
+	assert.Equal(t, job.config.Timezone, status.Timezone)
+	assert.Equal(t, job.config.Enabled, status.Enabled)
+	assert.False(t, job.stored)
+	assert.Empty(t, settings.settings)
+	assertScheduled(t, w, JobRecurringTransactions, true)
+
+	_, err = w.ResetJob(ctx, "unknown_job")
+	assertErrorType(t, err, helpers.ErrorTypeNotFound)
+}
+
+func TestRefreshJobSettings(t *testing.T) {
+	ctx := context.Background()
+	settings := &memoryJobSettingUseCase{}
+	w := newScheduleTestWorker(t, settings)
+	require.NoError(t, w.Start())
+	defer w.Stop()
+
+	// Another replica pauses a job
+	_, err := settings.SaveJobSetting(ctx, JobBudgetAlerts, "30 * * * *", "UTC", false)
+	require.NoError(t, err)
+	w.refreshJobSettings()
+	assertScheduled(t, w, JobBudgetAlerts, false)
+
+	// A setting read before the one in effect was stored is stale
+	stale := &entities.JobSetting{JobName: JobBudgetAlerts, Schedule: "30 * * * *", Timezone: "UTC", Enabled: true, UpdatedAt: time.Now().UTC().Add(-time.Hour)}
+	w.mu.Lock()
+	w.applyJobSettings([]*entities.JobSetting{stale}, stale.UpdatedAt)
+	w.mu.Unlock()
+	assertScheduled(t, w, JobBudgetAlerts, false)
+
+	// A stored setting that is not valid anymore is ignored
+	settings.settings[JobBudgetAlerts].Schedule = "every hour"
+	settings.settings[JobBudgetAlerts].UpdatedAt = time.Now().UTC()
+	w.refreshJobSettings()
+	assert.Equal(t, "30 * * * *", w.findJob(JobBudgetAlerts).schedule)
+
+	// Another replica resets the job
+	require.NoError(t, settings.DeleteJobSetting(ctx, JobBudgetAlerts))
+	w.refreshJobSettings()
+	assertScheduled(t, w, JobBudgetAlerts, true)
+	assert.False(t, w.findJob(JobBudgetAlerts).stored)
+}
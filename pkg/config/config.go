@@ -37,6 +37,7 @@ type Config struct {
 	Minio    MinioConfig
 	Redis    RedisConfig
 	SMTP     SMTPConfig
+	Worker   WorkerConfig
 }
 
 type ServerConfig struct {
@@ -45,8 +46,11 @@ type ServerConfig struct {
 }
 
 type AppConfig struct {
-	Env      string
-	LogLevel string
+	Env               string
+	LogLevel          string
+	ExchangeRatesFile string // CSV of exchange rates loaded at startup, skipped when empty
+	DuplicateWindow   int    // Minutes between two transactions for them to be reported as likely duplicates
+	InstanceID        string // Identifies this replica in job locks, defaults to hostname and process ID
 }
 
 type JWTConfig struct {
@@ -92,6 +96,20 @@ type SMTPConfig struct {
 	FromName  string
 }
 
+// WorkerConfig holds the schedule of every background job
+type WorkerConfig struct {
+	BalanceSync           JobConfig
+	RecurringTransactions JobConfig
+	BudgetAlerts          JobConfig
+	IdempotencyKeyCleanup JobConfig
+}
+
+type JobConfig struct {
+	Schedule string // Standard 5-field cron expression
+	Timezone string // IANA time zone the schedule is read in
+	Enabled  bool   // Disabled jobs are not scheduled, they can still be triggered manually
+}
+
 var globalConfig *Config
 
 func LoadConfig() *Config {
@@ -125,8 +143,11 @@ func LoadConfig() *Config {
 			Port: getEnv("SERVER_PORT", "8080"),
 		},
 		App: AppConfig{
-			Env:      getEnv("APP_ENV", "development"),
-			LogLevel: getEnv("LOG_LEVEL", "debug"),
+			Env:               getEnv("APP_ENV", "development"),
+			LogLevel:          getEnv("LOG_LEVEL", "debug"),
+			ExchangeRatesFile: getEnv("EXCHANGE_RATES_FILE", ""),
+			DuplicateWindow:   getEnvAsInt("DUPLICATE_WINDOW_MINUTES", 10),
+			InstanceID:        getEnv("INSTANCE_ID", ""),
 		},
 		JWT: JWTConfig{
 			Secret:    getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
@@ -166,6 +187,12 @@ func LoadConfig() *Config {
 			FromEmail: getEnv("SMTP_FROM_EMAIL", ""),
 			FromName:  getEnv("SMTP_FROM_NAME", "Finance Manager"),
 		},
+		Worker: WorkerConfig{
+			BalanceSync:           getJobConfig("JOB_BALANCE_SYNC", "0 0 * * *"),             // Every day at midnight
+			RecurringTransactions: getJobConfig("JOB_RECURRING_TRANSACTIONS", "15 * * * *"),  // Every hour at minute 15
+			BudgetAlerts:          getJobConfig("JOB_BUDGET_ALERTS", "30 * * * *"),           // Every hour at minute 30
+			IdempotencyKeyCleanup: getJobConfig("JOB_IDEMPOTENCY_KEY_CLEANUP", "45 3 * * *"), // Every day at 03:45
+		},
 	}
 
 	return globalConfig
@@ -204,3 +231,12 @@ func getEnvAsBool(key string, fallback bool) bool {
 	}
 	return fallback
 }
+
+// getJobConfig reads the <prefix>_SCHEDULE, <prefix>_TIMEZONE and <prefix>_ENABLED settings of a job
+func getJobConfig(prefix, defaultSchedule string) JobConfig {
+	return JobConfig{
+		Schedule: getEnv(prefix+"_SCHEDULE", defaultSchedule),
+		Timezone: getEnv(prefix+"_TIMEZONE", "UTC"),
+		Enabled:  getEnvAsBool(prefix+"_ENABLED", true),
+	}
+}
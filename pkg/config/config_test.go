@@ -0,0 +1,52 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetJobConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want JobConfig
+	}{
+		{
+			name: "defaults",
+			want: JobConfig{Schedule: "0 0 * * *", Timezone: "UTC", Enabled: true},
+		},
+		{
+			name: "configured",
+			env: map[string]string{
+				"JOB_TEST_SCHEDULE": "30 2 * * 1-5",
+				"JOB_TEST_TIMEZONE": "Asia/Jakarta",
+				"JOB_TEST_ENABLED":  "false",
+			},
+			want: JobConfig{Schedule: "30 2 * * 1-5", Timezone: "Asia/Jakarta", Enabled: false},
+		},
+		{
+			name: "invalid enabled flag falls back to enabled",
+			env:  map[string]string{"JOB_TEST_ENABLED": "sometimes"},
+			want: JobConfig{Schedule: "0 0 * * *", Timezone: "UTC", Enabled: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			assert.Equal(t, tt.want, getJobConfig("JOB_TEST", "0 0 * * *"))
+		})
+	}
+}
+
+func TestLoadConfig_WorkerDefaults(t *testing.T) {
+	worker := LoadConfig().Worker
+
+	assert.Equal(t, JobConfig{Schedule: "0 0 * * *", Timezone: "UTC", Enabled: true}, worker.BalanceSync)
+	assert.Equal(t, JobConfig{Schedule: "15 * * * *", Timezone: "UTC", Enabled: true}, worker.RecurringTransactions)
+	assert.Equal(t, JobConfig{Schedule: "30 * * * *", Timezone: "UTC", Enabled: true}, worker.BudgetAlerts)
+	assert.Equal(t, JobConfig{Schedule: "45 3 * * *", Timezone: "UTC", Enabled: true}, worker.IdempotencyKeyCleanup)
+}
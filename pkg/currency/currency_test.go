@@ -0,0 +1,54 @@
+package currency
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValid(t *testing.T) {
+	assert.True(t, IsValid("IDR"))
+	assert.True(t, IsValid(" usd "))
+	assert.False(t, IsValid("RUP"))
+	assert.False(t, IsValid("XXX"))
+	assert.False(t, IsValid(""))
+	assert.Equal(t, "EUR", Normalize(" eur"))
+}
+
+func TestParseRatesCSV(t *testing.T) {
+	input := "\ufeffdate,base,quote,rate\n" +
+		"2024-01-05,USD,IDR,15500\n" +
+		"\n" +
+		"2024-01-05, usd, eur, 0.915\n"
+
+	records, err := ParseRatesCSV(strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), records[0].Date)
+	assert.Equal(t, "USD", records[0].Base)
+	assert.Equal(t, "IDR", records[0].Quote)
+	assert.Equal(t, "15500", records[0].Rate.String())
+	assert.Equal(t, "EUR", records[1].Quote)
+	assert.Equal(t, 4, records[1].Line)
+}
+
+func TestParseRatesCSV_Rejects(t *testing.T) {
+	cases := map[string]string{
+		"2024-01-05,USD,IDR,15500\n2024-13-01,USD,IDR,1\n":     "line 2: invalid date",
+		"2024-01-05,USD,ABC,1\n":                               "line 1: invalid currency code \"ABC\"",
+		"2024-01-05,USD,USD,1\n":                               "line 1: base and quote currency are both USD",
+		"2024-01-05,USD,IDR,-3\n":                              "line 1: exchange rate must be a positive decimal",
+		"2024-01-05,USD,IDR\n":                                 "line 1: expected 4 columns",
+		"date,base,quote,rate\n":                               "file contains no exchange rates",
+		"2024-01-05,USD,IDR,15500\n2024-01-05,usd,idr,15600\n": "line 2: duplicate USD/IDR rate on 2024-01-05, already given on line 1",
+	}
+	for input, message := range cases {
+		_, err := ParseRatesCSV(strings.NewReader(input))
+		require.Error(t, err, input)
+		assert.Contains(t, err.Error(), message, input)
+	}
+}
@@ -0,0 +1,112 @@
+package currency
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+)
+
+// RateDateFormat is the date layout of exchange rate files
+const RateDateFormat = "2006-01-02"
+
+// RateRecord is one line of an exchange rate file: on Date, 1 Base = Rate Quote
+type RateRecord struct {
+	Line  int
+	Date  time.Time
+	Base  string
+	Quote string
+	Rate  money.Rate
+}
+
+// ParseRatesCSV reads exchange rates from CSV with the columns date,base,quote,rate (e.g.
+// "2024-01-05,USD,IDR,15500"). A header row is optional and blank lines are skipped.
+// The whole file is rejected on the first invalid line so a partial upload is never stored,
+// including a line repeating the date, base and quote of an earlier one.
+func ParseRatesCSV(r io.Reader) ([]RateRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var records []RateRecord
+	seen := make(map[RateKey]int)
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(row) == 1 && strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+		if len(records) == 0 && line == 1 && strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(row[0], "\ufeff")), "date") {
+			continue
+		}
+
+		record, err := parseRateRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if first, ok := seen[record.Key()]; ok {
+			return nil, fmt.Errorf("line %d: duplicate %s/%s rate on %s, already given on line %d",
+				line, record.Base, record.Quote, record.Date.Format(RateDateFormat), first)
+		}
+		seen[record.Key()] = line
+
+		record.Line = line
+		records = append(records, record)
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("file contains no exchange rates")
+	}
+	return records, nil
+}
+
+// RateKey identifies the rate of a currency pair on a day, only one rate per key can be stored
+type RateKey struct {
+	Date  string
+	Base  string
+	Quote string
+}
+
+// Key returns the key of the rate of r
+func (r RateRecord) Key() RateKey {
+	return RateKey{Date: r.Date.Format(RateDateFormat), Base: r.Base, Quote: r.Quote}
+}
+
+func parseRateRow(row []string) (RateRecord, error) {
+	if len(row) != 4 {
+		return RateRecord{}, fmt.Errorf("expected 4 columns (date,base,quote,rate), got %d", len(row))
+	}
+
+	date, err := time.Parse(RateDateFormat, strings.TrimSpace(strings.TrimPrefix(row[0], "\ufeff")))
+	if err != nil {
+		return RateRecord{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", row[0])
+	}
+
+	base, quote := Normalize(row[1]), Normalize(row[2])
+	for _, code := range []string{base, quote} {
+		if !IsValid(code) {
+			return RateRecord{}, fmt.Errorf("invalid currency code %q", code)
+		}
+	}
+	if base == quote {
+		return RateRecord{}, fmt.Errorf("base and quote currency are both %s", base)
+	}
+
+	rate, err := money.ParseRate(row[3])
+	if err != nil {
+		return RateRecord{}, err
+	}
+
+	return RateRecord{Date: date, Base: base, Quote: quote, Rate: rate}, nil
+}
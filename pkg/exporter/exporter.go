@@ -0,0 +1,185 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported export formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatJSON = "json"
+)
+
+// RowWriter streams rows of a table to an underlying writer in one of the export formats.
+// Values may be string, money.Money, float64, int64, int, bool, time.Time, *time.Time, fmt.Stringer or nil (an empty cell).
+type RowWriter interface {
+	WriteRow(values []any) error
+	// Close writes the format trailer and flushes buffered data; it does not close the underlying writer
+	Close() error
+}
+
+// IsSupported reports whether format is one of the export formats
+func IsSupported(format string) bool {
+	switch format {
+	case FormatCSV, FormatXLSX, FormatJSON:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type of an export format
+func ContentType(format string) string {
+	switch format {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatJSON:
+		return "application/json"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// NewRowWriter creates a writer for format and writes the header (CSV and XLSX) right away
+func NewRowWriter(format string, w io.Writer, columns []string) (RowWriter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatXLSX:
+		return newXLSXWriter(w, columns)
+	case FormatJSON:
+		return &jsonWriter{w: w, columns: columns}, nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// csvWriter writes RFC 4180 CSV, times in RFC 3339. Text cells that a spreadsheet would read as a formula are
+// escaped, since exported names and notes come from users and bank imports.
+type csvWriter struct {
+	writer *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: writer, record: make([]string, len(columns))}, nil
+}
+
+func (cw *csvWriter) WriteRow(values []any) error {
+	for i := range cw.record {
+		cw.record[i] = ""
+		if i < len(values) {
+			cw.record[i] = formatText(values[i])
+			if _, isText := values[i].(string); isText {
+				cw.record[i] = escapeFormula(cw.record[i])
+			}
+		}
+	}
+	return cw.writer.Write(cw.record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+// jsonWriter writes a JSON array of objects keyed by column, keeping the column order
+type jsonWriter struct {
+	w       io.Writer
+	columns []string
+	rows    int
+}
+
+func (jw *jsonWriter) WriteRow(values []any) error {
+	buf := []byte("[\n")
+	if jw.rows > 0 {
+		buf = []byte(",\n")
+	}
+	buf = append(buf, '{')
+
+	for i, column := range jw.columns {
+		var value any
+		if i < len(values) {
+			value = values[i]
+		}
+		if t, ok := value.(*time.Time); ok && t == nil {
+			value = nil
+		}
+
+		key, err := json.Marshal(column)
+		if err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, key...)
+		buf = append(buf, ':')
+		buf = append(buf, encoded...)
+	}
+
+	buf = append(buf, '}')
+	if _, err := jw.w.Write(buf); err != nil {
+		return err
+	}
+	jw.rows++
+	return nil
+}
+
+func (jw *jsonWriter) Close() error {
+	trailer := "\n]\n"
+	if jw.rows == 0 {
+		trailer = "[]\n"
+	}
+	_, err := io.WriteString(jw.w, trailer)
+	return err
+}
+
+// escapeFormula prefixes text starting like a spreadsheet formula with a quote, so it is shown instead of evaluated
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+// formatText renders a value for text formats
+func formatText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(value)
+}
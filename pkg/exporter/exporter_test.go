@@ -0,0 +1,129 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testColumns = []string{"name", "cost", "occurred_at", "note"}
+	testRows    = [][]any{
+		{"Groceries, weekly", money.MustParse("150000.5"), time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC), nil},
+		{"Salary", money.FromInt(5000000), time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), "January <bonus> & more"},
+	}
+)
+
+func writeAll(t *testing.T, format string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer, err := NewRowWriter(format, &buf, testColumns)
+	require.NoError(t, err)
+	for _, row := range testRows {
+		require.NoError(t, writer.WriteRow(row))
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestCSVWriter(t *testing.T) {
+	output := string(writeAll(t, FormatCSV))
+
+	assert.Equal(t, "name,cost,occurred_at,note\n"+
+		"\"Groceries, weekly\",150000.5,2024-01-06T12:00:00Z,\n"+
+		"Salary,5000000,2024-01-05T00:00:00Z,January <bonus> & more\n", output)
+}
+
+func TestCSVWriter_EscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewRowWriter(FormatCSV, &buf, []string{"name", "note", "cost"})
+	require.NoError(t, err)
+
+	require.NoError(t, writer.WriteRow([]any{"=HYPERLINK(\"http://evil\")", "@SUM(A1)", money.MustParse("-12.5")}))
+	require.NoError(t, writer.WriteRow([]any{"+62 transfer", "-refund", "plain"}))
+	require.NoError(t, writer.Close())
+
+	assert.Equal(t, "name,note,cost\n"+
+		"\"'=HYPERLINK(\"\"http://evil\"\")\",'@SUM(A1),-12.5\n"+
+		"'+62 transfer,'-refund,plain\n", buf.String())
+}
+
+func TestJSONWriter(t *testing.T) {
+	var rows []map[string]any
+	require.NoError(t, json.Unmarshal(writeAll(t, FormatJSON), &rows))
+
+	require.Len(t, rows, 2)
+	assert.Equal(t, "Groceries, weekly", rows[0]["name"])
+	assert.Equal(t, 150000.5, rows[0]["cost"])
+	assert.Equal(t, "2024-01-06T12:00:00Z", rows[0]["occurred_at"])
+	assert.Nil(t, rows[0]["note"])
+
+	var buf bytes.Buffer
+	writer, err := NewRowWriter(FormatJSON, &buf, testColumns)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	assert.JSONEq(t, "[]", buf.String())
+}
+
+func TestXLSXWriter(t *testing.T) {
+	output := writeAll(t, FormatXLSX)
+
+	archive, err := zip.NewReader(bytes.NewReader(output), int64(len(output)))
+	require.NoError(t, err)
+
+	parts := make(map[string]string)
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		reader.Close()
+		parts[file.Name] = string(content)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		assert.Contains(t, parts, name)
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="A1" t="inlineStr" s="2"><is><t xml:space="preserve">name</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="B2"><v>150000.5</v></c>`)
+	assert.Contains(t, sheet, `<c r="C3" s="1"><v>45296</v></c>`) // 2024-01-05 as an Excel serial date
+	assert.Contains(t, sheet, `January &lt;bonus&gt; &amp; more`)
+	assert.NotContains(t, sheet, `r="D2"`) // nil values leave the cell empty
+
+	// Every part must be well-formed XML
+	for name, content := range parts {
+		decoder := xml.NewDecoder(strings.NewReader(content))
+		for {
+			_, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err, name)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "BA", columnName(52))
+}
+
+func TestNewRowWriter_UnsupportedFormat(t *testing.T) {
+	_, err := NewRowWriter("pdf", io.Discard, testColumns)
+	assert.Error(t, err)
+	assert.False(t, IsSupported("pdf"))
+	assert.True(t, strings.HasPrefix(ContentType(FormatCSV), "text/csv"))
+}
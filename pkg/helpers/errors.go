@@ -9,13 +9,14 @@ import (
 type ErrorType string
 
 const (
-	ErrorTypeValidation   ErrorType = "VALIDATION_ERROR"
-	ErrorTypeNotFound     ErrorType = "NOT_FOUND_ERROR"
-	ErrorTypeConflict     ErrorType = "CONFLICT_ERROR"
-	ErrorTypeBadRequest   ErrorType = "BAD_REQUEST_ERROR"
-	ErrorTypeUnauthorized ErrorType = "UNAUTHORIZED_ERROR"
-	ErrorTypeInternal     ErrorType = "INTERNAL_ERROR"
-	ErrorTypeForbidden    ErrorType = "FORBIDDEN_ERROR"
+	ErrorTypeValidation         ErrorType = "VALIDATION_ERROR"
+	ErrorTypeNotFound           ErrorType = "NOT_FOUND_ERROR"
+	ErrorTypeConflict           ErrorType = "CONFLICT_ERROR"
+	ErrorTypeBadRequest         ErrorType = "BAD_REQUEST_ERROR"
+	ErrorTypeUnauthorized       ErrorType = "UNAUTHORIZED_ERROR"
+	ErrorTypeInternal           ErrorType = "INTERNAL_ERROR"
+	ErrorTypeForbidden          ErrorType = "FORBIDDEN_ERROR"
+	ErrorTypePreconditionFailed ErrorType = "PRECONDITION_FAILED_ERROR"
 )
 
 // AppError represents a custom application error
@@ -89,6 +90,14 @@ func NewForbiddenError(message, details string) *AppError {
 	}
 }
 
+func NewPreconditionFailedError(message, details string) *AppError {
+	return &AppError{
+		Type:    ErrorTypePreconditionFailed,
+		Message: message,
+		Details: details,
+	}
+}
+
 // GetErrorType returns the ErrorType from an error if it's an AppError
 func GetErrorType(err error) ErrorType {
 	var appErr *AppError
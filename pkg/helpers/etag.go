@@ -0,0 +1,33 @@
+package helpers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetETag exposes the version of a resource as its ETag
+func SetETag(c *fiber.Ctx, version int64) {
+	c.Set(fiber.HeaderETag, strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// ParseIfMatch returns the version sent in the If-Match header, or 0 when the header is missing or "*".
+// Weak tags (W/"3") are accepted since the version is the only thing compared.
+func ParseIfMatch(c *fiber.Ctx) (int64, error) {
+	ifMatch := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, nil
+	}
+
+	tag := strings.TrimPrefix(ifMatch, "W/")
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		unquoted = tag
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, NewBadRequestError("Invalid If-Match header", "If-Match must be a single ETag returned by this API")
+	}
+	return version, nil
+}
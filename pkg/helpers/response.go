@@ -49,6 +49,15 @@ func CreatedResponse(c *fiber.Ctx, message string, data interface{}) error {
 	})
 }
 
+// AcceptedResponse sends an accepted response for work that continues in the background
+func AcceptedResponse(c *fiber.Ctx, message string, data interface{}) error {
+	return c.Status(fiber.StatusAccepted).JSON(Response{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+}
+
 // ErrorResponse sends an error response with consistent structure
 func ErrorResponse(c *fiber.Ctx, statusCode int, message string, details interface{}) error {
 	return c.Status(statusCode).JSON(Response{
@@ -65,13 +74,14 @@ func HandleErrorResponse(c *fiber.Ctx, err error, defaultMessage string) error {
 
 		// Map error types to HTTP status codes
 		statusCodeMap := map[ErrorType]int{
-			ErrorTypeValidation:   fiber.StatusBadRequest,
-			ErrorTypeNotFound:     fiber.StatusNotFound,
-			ErrorTypeConflict:     fiber.StatusConflict,
-			ErrorTypeBadRequest:   fiber.StatusBadRequest,
-			ErrorTypeUnauthorized: fiber.StatusUnauthorized,
-			ErrorTypeForbidden:    fiber.StatusForbidden,
-			ErrorTypeInternal:     fiber.StatusInternalServerError,
+			ErrorTypeValidation:         fiber.StatusBadRequest,
+			ErrorTypeNotFound:           fiber.StatusNotFound,
+			ErrorTypeConflict:           fiber.StatusConflict,
+			ErrorTypeBadRequest:         fiber.StatusBadRequest,
+			ErrorTypeUnauthorized:       fiber.StatusUnauthorized,
+			ErrorTypeForbidden:          fiber.StatusForbidden,
+			ErrorTypeInternal:           fiber.StatusInternalServerError,
+			ErrorTypePreconditionFailed: fiber.StatusPreconditionFailed,
 		}
 
 		statusCode, exists := statusCodeMap[appErr.Type]
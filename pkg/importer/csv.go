@@ -0,0 +1,359 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+)
+
+// SignConvention tells how the amount column encodes whether a row is income or an expense
+type SignConvention string
+
+const (
+	SignNegativeExpense SignConvention = "negative_expense" // Negative amounts are expenses, positive amounts are income
+	SignPositiveExpense SignConvention = "positive_expense" // Positive amounts are expenses, as in most credit card exports
+	SignTypeColumn      SignConvention = "type_column"      // Amounts are absolute, a separate column holds income/expense or credit/debit
+)
+
+// DefaultDateFormat is the Go layout used when the mapping does not specify one
+const DefaultDateFormat = "2006-01-02"
+
+// CSVMapping describes which columns of a CSV file hold the transaction fields.
+// Columns are referenced by header name (case-insensitive) or by 1-based position.
+type CSVMapping struct {
+	DateColumn      string
+	DateFormat      string // Go time layout, defaults to DefaultDateFormat
+	AmountColumn    string
+	SignConvention  SignConvention // Defaults to SignNegativeExpense
+	TypeColumn      string         // Required with SignTypeColumn
+	NameColumn      string
+	CategoryColumn  string // Optional when DefaultCategory is set
+	DefaultCategory string // Used when the category column is missing or empty
+	NoteColumn      string // Optional
+	Delimiter       rune   // Defaults to ','
+	DecimalComma    bool   // Amounts use ',' as decimal separator (e.g. 1.234,56)
+	HasHeader       bool
+	MaxRows         int // Zero means unlimited
+}
+
+// Record is a validated statement line, ready to be turned into a transaction
+type Record struct {
+	Row        int // Line number in the source file
+	Date       time.Time
+	Amount     money.Money // Always positive, the direction is in Type
+	Type       string      // "income" or "expense"
+	Name       string
+	Category   string
+	Note       string
+	ExternalID string // Bank identifier of the entry (OFX FITID or a content fingerprint), empty for CSV rows
+}
+
+// RowError describes why a field of a source row was rejected
+type RowError struct {
+	Row     int
+	Column  string
+	Message string
+}
+
+// ParseCSV reads every row of r according to mapping. Rows that fail validation are reported as
+// RowErrors and skipped; the returned error is reserved for problems that invalidate the whole file,
+// such as an invalid mapping, an unreadable header or too many rows.
+func ParseCSV(r io.Reader, mapping CSVMapping) ([]Record, []RowError, error) {
+	mapping = mapping.withDefaults()
+
+	text, err := readText(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = mapping.Delimiter
+	reader.FieldsPerRecord = -1 // Short rows are reported per field below
+	reader.TrimLeadingSpace = true
+
+	var header []string
+	if mapping.HasHeader {
+		row, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, nil, errors.New("file is empty")
+			}
+			return nil, nil, fmt.Errorf("failed to read header: %w", err)
+		}
+		header = row
+	}
+
+	columns, err := mapping.resolveColumns(header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var records []Record
+	var rowErrors []RowError
+	dataRows := 0
+
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		dataRows++
+		if mapping.MaxRows > 0 && dataRows > mapping.MaxRows {
+			return nil, nil, fmt.Errorf("file has more than %d rows", mapping.MaxRows)
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rowErrors = append(rowErrors, RowError{Row: parseErr.StartLine, Message: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to read file: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		record, errs := mapping.parseRow(line, row, columns)
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, errs...)
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, rowErrors, nil
+}
+
+// csvColumns holds the resolved 0-based column indexes, -1 when a column is not mapped
+type csvColumns struct {
+	date, amount, kind, name, category, note int
+}
+
+func (m CSVMapping) withDefaults() CSVMapping {
+	if m.DateFormat == "" {
+		m.DateFormat = DefaultDateFormat
+	}
+	if m.SignConvention == "" {
+		m.SignConvention = SignNegativeExpense
+	}
+	if m.Delimiter == 0 {
+		m.Delimiter = ','
+	}
+	m.DefaultCategory = strings.TrimSpace(m.DefaultCategory)
+	return m
+}
+
+func (m CSVMapping) resolveColumns(header []string) (csvColumns, error) {
+	switch m.SignConvention {
+	case SignNegativeExpense, SignPositiveExpense:
+	case SignTypeColumn:
+		if m.TypeColumn == "" {
+			return csvColumns{}, errors.New("type column is required when the sign convention is type_column")
+		}
+	default:
+		return csvColumns{}, fmt.Errorf("unknown sign convention %q", m.SignConvention)
+	}
+	if m.CategoryColumn == "" && m.DefaultCategory == "" {
+		return csvColumns{}, errors.New("either a category column or a default category is required")
+	}
+
+	var cols csvColumns
+	var err error
+	if cols.date, err = resolveColumn("date", m.DateColumn, header, true); err != nil {
+		return cols, err
+	}
+	if cols.amount, err = resolveColumn("amount", m.AmountColumn, header, true); err != nil {
+		return cols, err
+	}
+	if cols.name, err = resolveColumn("name", m.NameColumn, header, true); err != nil {
+		return cols, err
+	}
+	if cols.kind, err = resolveColumn("type", m.TypeColumn, header, m.SignConvention == SignTypeColumn); err != nil {
+		return cols, err
+	}
+	if cols.category, err = resolveColumn("category", m.CategoryColumn, header, false); err != nil {
+		return cols, err
+	}
+	if cols.note, err = resolveColumn("note", m.NoteColumn, header, false); err != nil {
+		return cols, err
+	}
+	return cols, nil
+}
+
+// resolveColumn maps a header name or 1-based position to a 0-based index
+func resolveColumn(field, ref string, header []string, required bool) (int, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		if required {
+			return -1, fmt.Errorf("%s column is required", field)
+		}
+		return -1, nil
+	}
+
+	if position, err := strconv.Atoi(ref); err == nil {
+		if position < 1 {
+			return -1, fmt.Errorf("%s column position must be at least 1", field)
+		}
+		return position - 1, nil
+	}
+
+	if header == nil {
+		return -1, fmt.Errorf("%s column must be a position when the file has no header", field)
+	}
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), ref) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("%s column %q not found in header", field, ref)
+}
+
+func (m CSVMapping) parseRow(line int, row []string, cols csvColumns) (Record, []RowError) {
+	record := Record{Row: line}
+	var errs []RowError
+	fail := func(column, message string) {
+		errs = append(errs, RowError{Row: line, Column: column, Message: message})
+	}
+	cell := func(index int) string {
+		if index < 0 || index >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[index])
+	}
+
+	if value := cell(cols.date); value == "" {
+		fail("date", "date is required")
+	} else if date, err := time.Parse(m.DateFormat, value); err != nil {
+		fail("date", fmt.Sprintf("invalid date %q, expected format %s", value, m.DateFormat))
+	} else {
+		record.Date = date
+	}
+
+	amount, err := ParseAmount(cell(cols.amount), m.DecimalComma)
+	switch {
+	case err != nil:
+		fail("amount", err.Error())
+	case amount.IsZero():
+		fail("amount", "amount must not be zero")
+	}
+
+	switch m.SignConvention {
+	case SignNegativeExpense:
+		record.Type = "income"
+		if amount.IsNegative() {
+			record.Type = "expense"
+		}
+	case SignPositiveExpense:
+		record.Type = "expense"
+		if amount.IsNegative() {
+			record.Type = "income"
+		}
+	case SignTypeColumn:
+		kind, ok := ParseTransactionType(cell(cols.kind))
+		if !ok {
+			fail("type", fmt.Sprintf("invalid type %q, expected income, expense, credit or debit", cell(cols.kind)))
+		}
+		record.Type = kind
+	}
+	if amount.IsNegative() {
+		amount = amount.Abs()
+	}
+	record.Amount = amount
+
+	record.Name = cell(cols.name)
+	if length := utf8.RuneCountInString(record.Name); length < 2 || length > 255 {
+		fail("name", "name must be between 2 and 255 characters")
+	}
+
+	record.Category = cell(cols.category)
+	if record.Category == "" {
+		record.Category = m.DefaultCategory
+	}
+	if length := utf8.RuneCountInString(record.Category); length < 2 || length > 100 {
+		fail("category", "category must be between 2 and 100 characters")
+	}
+
+	record.Note = cell(cols.note)
+	if utf8.RuneCountInString(record.Note) > 1000 {
+		fail("note", "note must not exceed 1000 characters")
+	}
+
+	return record, errs
+}
+
+// ParseAmount parses a formatted amount such as "-1,234.50", "(75.00)", "Rp 50.000" or "1.234,50" (with decimalComma).
+// Currency symbols, letters and thousands separators are ignored; parentheses and a minus sign mean negative.
+func ParseAmount(raw string, decimalComma bool) (money.Money, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return money.Zero, errors.New("amount is required")
+	}
+
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+
+	decimalSeparator := '.'
+	if decimalComma {
+		decimalSeparator = ','
+	}
+
+	// Letters are currency markers ("Rp", "IDR") only around the number, never inside it ("12abc34", "1e5")
+	firstDigit := strings.IndexFunc(value, unicode.IsDigit)
+	lastDigit := strings.LastIndexFunc(value, unicode.IsDigit)
+	if firstDigit >= 0 && strings.IndexFunc(value[firstDigit:lastDigit+1], unicode.IsLetter) >= 0 {
+		return money.Zero, fmt.Errorf("invalid amount %q", raw)
+	}
+
+	var digits strings.Builder
+	for _, ch := range value {
+		switch {
+		case ch >= '0' && ch <= '9':
+			digits.WriteRune(ch)
+		case ch == decimalSeparator:
+			digits.WriteRune('.')
+		case ch == '-':
+			negative = !negative
+		case ch == '+', ch == '.', ch == ',', ch == '\'', unicode.IsSpace(ch), unicode.IsLetter(ch), unicode.Is(unicode.Sc, ch):
+			// Thousands separators and currency markers
+		default:
+			return money.Zero, fmt.Errorf("invalid amount %q", raw)
+		}
+	}
+
+	if digits.Len() == 0 {
+		return money.Zero, fmt.Errorf("invalid amount %q", raw)
+	}
+	amount, err := money.Parse(digits.String())
+	if err != nil {
+		return money.Zero, fmt.Errorf("invalid amount %q", raw)
+	}
+	if !amount.Storable() {
+		return money.Zero, fmt.Errorf("amount %q exceeds %s", raw, money.MaxStored)
+	}
+	if negative {
+		amount = amount.Neg()
+	}
+	return amount, nil
+}
+
+// ParseTransactionType maps the type labels used by banks to "income" or "expense"
+func ParseTransactionType(raw string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "income", "credit", "cr", "in", "deposit":
+		return "income", true
+	case "expense", "debit", "dr", "out", "withdrawal":
+		return "expense", true
+	}
+	return "", false
+}
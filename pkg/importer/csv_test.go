@@ -0,0 +1,167 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV_SignedAmountsWithHeader(t *testing.T) {
+	input := "\ufeffDate,Description,Amount,Category\n" +
+		"2024-01-05,Salary,\"5,000,000.00\",salary\n" +
+		"2024-01-06,Groceries,-150000,\n" +
+		"2024-01-07,Refund,(20.50),food\n"
+
+	records, rowErrors, err := ParseCSV(strings.NewReader(input), CSVMapping{
+		DateColumn:      "date",
+		AmountColumn:    "Amount",
+		NameColumn:      "Description",
+		CategoryColumn:  "Category",
+		DefaultCategory: "uncategorized",
+		HasHeader:       true,
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, 2, records[0].Row)
+	assert.Equal(t, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), records[0].Date)
+	assert.Equal(t, money.MustParse("5000000"), records[0].Amount)
+	assert.Equal(t, "income", records[0].Type)
+
+	assert.Equal(t, money.MustParse("150000"), records[1].Amount)
+	assert.Equal(t, "expense", records[1].Type)
+	assert.Equal(t, "uncategorized", records[1].Category)
+
+	assert.Equal(t, money.MustParse("20.5"), records[2].Amount)
+	assert.Equal(t, "expense", records[2].Type)
+}
+
+func TestParseCSV_TypeColumnByPosition(t *testing.T) {
+	input := "05/01/2024;Coffee;4,50;DR\n" +
+		"06/01/2024;Transfer in;1.000,00;CR\n"
+
+	records, rowErrors, err := ParseCSV(strings.NewReader(input), CSVMapping{
+		DateColumn:      "1",
+		DateFormat:      "02/01/2006",
+		NameColumn:      "2",
+		AmountColumn:    "3",
+		SignConvention:  SignTypeColumn,
+		TypeColumn:      "4",
+		DefaultCategory: "general",
+		Delimiter:       ';',
+		DecimalComma:    true,
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	require.Len(t, records, 2)
+	assert.Equal(t, money.MustParse("4.5"), records[0].Amount)
+	assert.Equal(t, "expense", records[0].Type)
+	assert.Equal(t, money.MustParse("1000"), records[1].Amount)
+	assert.Equal(t, "income", records[1].Type)
+}
+
+func TestParseCSV_ReportsRowErrors(t *testing.T) {
+	input := "date,name,amount\n" +
+		"2024-13-01,Bad date,10\n" +
+		"2024-01-02,X,abc\n" +
+		"2024-01-03,Valid row,-10\n"
+
+	records, rowErrors, err := ParseCSV(strings.NewReader(input), CSVMapping{
+		DateColumn:      "date",
+		AmountColumn:    "amount",
+		NameColumn:      "name",
+		DefaultCategory: "general",
+		HasHeader:       true,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, 4, records[0].Row)
+
+	require.Len(t, rowErrors, 3)
+	assert.Equal(t, RowError{Row: 2, Column: "date", Message: `invalid date "2024-13-01", expected format 2006-01-02`}, rowErrors[0])
+	assert.Equal(t, 3, rowErrors[1].Row)
+	assert.Equal(t, "amount", rowErrors[1].Column)
+	assert.Equal(t, 3, rowErrors[2].Row)
+	assert.Equal(t, "name", rowErrors[2].Column)
+}
+
+func TestParseCSV_InvalidMapping(t *testing.T) {
+	input := "date,name,amount\n2024-01-01,Lunch,-10\n"
+
+	_, _, err := ParseCSV(strings.NewReader(input), CSVMapping{
+		DateColumn:      "posted",
+		AmountColumn:    "amount",
+		NameColumn:      "name",
+		DefaultCategory: "general",
+		HasHeader:       true,
+	})
+	assert.EqualError(t, err, `date column "posted" not found in header`)
+
+	_, _, err = ParseCSV(strings.NewReader(input), CSVMapping{
+		DateColumn:   "date",
+		AmountColumn: "amount",
+		NameColumn:   "name",
+		HasHeader:    true,
+	})
+	assert.EqualError(t, err, "either a category column or a default category is required")
+}
+
+func TestParseCSV_MaxRows(t *testing.T) {
+	input := "2024-01-01,Lunch,-10\n2024-01-02,Dinner,-20\n"
+
+	_, _, err := ParseCSV(strings.NewReader(input), CSVMapping{
+		DateColumn:      "1",
+		NameColumn:      "2",
+		AmountColumn:    "3",
+		DefaultCategory: "food",
+		MaxRows:         1,
+	})
+	assert.EqualError(t, err, "file has more than 1 rows")
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		raw          string
+		decimalComma bool
+		expected     string
+	}{
+		{"1,234.56", false, "1234.56"},
+		{"-42", false, "-42"},
+		{"+42.10", false, "42.1"},
+		{"(75.00)", false, "-75"},
+		{"Rp 50.000", true, "50000"},
+		{"1.234,56", true, "1234.56"},
+		{"$ -9.99", false, "-9.99"},
+		{"IDR 1,500", false, "1500"},
+		{"1.500,00 EUR", true, "1500"},
+	}
+
+	for _, tt := range tests {
+		amount, err := ParseAmount(tt.raw, tt.decimalComma)
+		require.NoError(t, err, tt.raw)
+		assert.Equal(t, money.MustParse(tt.expected), amount, tt.raw)
+	}
+
+	_, err := ParseAmount("", false)
+	assert.Error(t, err)
+	_, err = ParseAmount("12#5", false)
+	assert.Error(t, err)
+
+	// Letters inside the number are rejected instead of dropped
+	for _, raw := range []string{"12abc34", "1e5", "1O0"} {
+		_, err = ParseAmount(raw, false)
+		assert.Error(t, err, raw)
+	}
+
+	// Amounts the money columns cannot hold are row errors, not failed inserts
+	_, err = ParseAmount("1,000,000,000,000.00", false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ofxTagPattern matches an OFX tag and the text up to the next tag. It covers both OFX 1.x SGML,
+// where elements have no closing tag, and OFX 2.x XML.
+var ofxTagPattern = regexp.MustCompile(`<(/?)([A-Za-z0-9.]+)>([^<]*)`)
+
+// parseOFX extracts the STMTTRN entries of every statement in an OFX file
+func parseOFX(data string, opts StatementOptions) (*Statement, []RowError, error) {
+	if !strings.Contains(strings.ToUpper(data), "<OFX>") {
+		return nil, nil, errors.New("file is not a valid OFX statement")
+	}
+
+	statement := &Statement{}
+	var rowErrors []RowError
+	var entry *statementEntry
+	var payeeName string
+	inPayee := false
+	fingerprints := make(map[string]int)
+	entries := 0
+
+	line, lineOffset := 1, 0
+	for _, match := range ofxTagPattern.FindAllStringSubmatchIndex(data, -1) {
+		closing := match[3] > match[2]
+		tag := strings.ToUpper(data[match[4]:match[5]])
+		value := strings.TrimSpace(html.UnescapeString(data[match[6]:match[7]]))
+
+		if tag == "STMTTRN" && !closing {
+			line += strings.Count(data[lineOffset:match[0]], "\n")
+			lineOffset = match[0]
+
+			entries++
+			if opts.MaxEntries > 0 && entries > opts.MaxEntries {
+				return nil, nil, fmt.Errorf("file has more than %d entries", opts.MaxEntries)
+			}
+			entry = &statementEntry{Line: line}
+			payeeName, inPayee = "", false
+			continue
+		}
+
+		if entry == nil {
+			if tag == "CURDEF" && !closing && statement.Currency == "" {
+				statement.Currency = strings.ToUpper(value)
+			}
+			continue
+		}
+
+		if closing {
+			switch tag {
+			case "PAYEE":
+				inPayee = false
+			case "STMTTRN":
+				if entry.Name == "" {
+					entry.Name = payeeName
+				}
+				record, errs := entry.toRecord(parseOFXDate, ofxDecimalComma(entry.Amount), opts, fingerprints)
+				if len(errs) > 0 {
+					rowErrors = append(rowErrors, errs...)
+				} else {
+					statement.Records = append(statement.Records, record)
+				}
+				entry = nil
+			}
+			continue
+		}
+
+		switch tag {
+		case "PAYEE":
+			inPayee = true
+		case "DTPOSTED":
+			entry.Date = value
+		case "TRNAMT":
+			entry.Amount = value
+		case "FITID":
+			entry.ExternalID = value
+		case "NAME":
+			// NAME is either a direct child of STMTTRN or nested in a PAYEE aggregate
+			if inPayee {
+				payeeName = value
+			} else {
+				entry.Name = value
+			}
+		case "MEMO":
+			entry.Memo = value
+		}
+	}
+
+	if entry != nil {
+		return nil, nil, errors.New("file ends inside a transaction, it may be truncated")
+	}
+	return statement, rowErrors, nil
+}
+
+// parseOFXDate parses the OFX datetime format YYYYMMDD[HHMMSS[.XXX]][[offset:TZ]]
+func parseOFXDate(raw string) (time.Time, error) {
+	value := raw
+	location := time.UTC
+
+	if start := strings.Index(value, "["); start >= 0 {
+		zone := strings.TrimSuffix(value[start+1:], "]")
+		value = value[:start]
+
+		offset, _, _ := strings.Cut(zone, ":")
+		hours, err := strconv.ParseFloat(offset, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time zone %q", zone)
+		}
+		location = time.FixedZone("", int(hours*3600))
+	}
+	value, _, _ = strings.Cut(value, ".")
+
+	var layout string
+	switch len(value) {
+	case 8:
+		layout = "20060102"
+	case 12:
+		layout = "200601021504"
+	case 14:
+		layout = "20060102150405"
+	default:
+		return time.Time{}, fmt.Errorf("invalid OFX date %q", raw)
+	}
+
+	date, err := time.ParseInLocation(layout, value, location)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return date.UTC(), nil
+}
+
+// ofxDecimalComma reports whether an OFX amount uses ',' as decimal separator, which the spec allows
+func ofxDecimalComma(amount string) bool {
+	return strings.Contains(amount, ",") && !strings.Contains(amount, ".")
+}
@@ -0,0 +1,131 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// qifCashAccountTypes are the QIF sections holding plain cash movements; investment and
+// category list sections are not transactions and are rejected or skipped
+var qifCashAccountTypes = map[string]bool{
+	"bank":  true,
+	"cash":  true,
+	"ccard": true,
+	"oth a": true,
+	"oth l": true,
+}
+
+// parseQIF extracts the entries of a QIF file. QIF has no transaction IDs, so every entry gets a
+// content fingerprint that stays stable when the same file is imported again.
+func parseQIF(data string, opts StatementOptions) (*Statement, []RowError, error) {
+	statement := &Statement{}
+	var rowErrors []RowError
+	var entry *statementEntry
+	fingerprints := make(map[string]int)
+	parseDate := qifDateParser(opts.DayFirst)
+	section := ""
+	entries := 0
+
+	finishEntry := func() {
+		record, errs := entry.toRecord(parseDate, opts.DecimalComma, opts, fingerprints)
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, errs...)
+		} else {
+			statement.Records = append(statement.Records, record)
+		}
+		entry = nil
+	}
+
+	for index, rawLine := range strings.Split(data, "\n") {
+		line := strings.TrimRight(rawLine, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "!") {
+			header := strings.ToLower(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(header, "!type:"):
+				section = strings.TrimSpace(strings.TrimPrefix(header, "!type:"))
+				if strings.HasPrefix(section, "invst") {
+					return nil, nil, errors.New("investment accounts are not supported")
+				}
+			case header == "!account":
+				section = "account"
+			}
+			continue
+		}
+
+		if !qifCashAccountTypes[section] {
+			continue // Account, category and class lists
+		}
+
+		code, value := line[0], strings.TrimSpace(line[1:])
+		if code == '^' {
+			if entry != nil {
+				finishEntry()
+			}
+			continue
+		}
+
+		if entry == nil {
+			entries++
+			if opts.MaxEntries > 0 && entries > opts.MaxEntries {
+				return nil, nil, fmt.Errorf("file has more than %d entries", opts.MaxEntries)
+			}
+			entry = &statementEntry{Line: index + 1}
+		}
+
+		switch code {
+		case 'D':
+			entry.Date = value
+		case 'T', 'U':
+			if entry.Amount == "" || code == 'T' {
+				entry.Amount = value
+			}
+		case 'P':
+			entry.Name = value
+		case 'M':
+			entry.Memo = value
+		case 'L':
+			// [Account] marks a transfer, which carries no category
+			if !strings.HasPrefix(value, "[") {
+				entry.Category = value
+			}
+		}
+	}
+
+	// Some exporters omit the terminator after the last entry
+	if entry != nil {
+		finishEntry()
+	}
+	if section == "" {
+		return nil, nil, errors.New("file is not a valid QIF statement")
+	}
+	return statement, rowErrors, nil
+}
+
+// qifDateParser returns a parser for the date styles written by Quicken and banks, such as
+// 01/31/2024, 1/31'24 and 2024-01-31. The day/month order cannot be inferred and comes from the caller.
+func qifDateParser(dayFirst bool) func(string) (time.Time, error) {
+	layouts := []string{"1/2/2006", "1/2/06"}
+	if dayFirst {
+		layouts = []string{"2/1/2006", "2/1/06"}
+	}
+
+	return func(raw string) (time.Time, error) {
+		if date, err := time.Parse("2006-01-02", raw); err == nil {
+			return date, nil
+		}
+
+		value := strings.NewReplacer("'", "/", "-", "/", ".", "/", " ", "").Replace(raw)
+		for _, layout := range layouts {
+			if date, err := time.Parse(layout, value); err == nil {
+				return date, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("invalid QIF date %q", raw)
+	}
+}
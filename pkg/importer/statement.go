@@ -0,0 +1,189 @@
+package importer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Supported bank statement formats
+const (
+	FormatOFX = "ofx"
+	FormatQIF = "qif"
+)
+
+// DefaultStatementCategory is used for statement entries that carry no category
+const DefaultStatementCategory = "uncategorized"
+
+// StatementOptions controls how OFX and QIF entries are turned into records
+type StatementOptions struct {
+	DefaultCategory string // Defaults to DefaultStatementCategory
+	DayFirst        bool   // QIF dates are written DD/MM/YYYY instead of MM/DD/YYYY
+	DecimalComma    bool   // QIF amounts use ',' as decimal separator
+	MaxEntries      int    // Zero means unlimited
+}
+
+// Statement is the content of a bank statement file
+type Statement struct {
+	Currency string // ISO 4217 code declared by the file (OFX CURDEF), empty when unknown
+	Records  []Record
+}
+
+// DetectStatementFormat guesses the format from the file extension, falling back to the content
+func DetectStatementFormat(filename string, content []byte) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ofx", ".qfx":
+		return FormatOFX
+	case ".qif":
+		return FormatQIF
+	}
+
+	head := strings.ToUpper(strings.TrimSpace(string(content[:min(len(content), 1024)])))
+	switch {
+	case strings.HasPrefix(head, "OFXHEADER") || strings.Contains(head, "<OFX>"):
+		return FormatOFX
+	case strings.HasPrefix(head, "!TYPE:") || strings.HasPrefix(head, "!ACCOUNT") || strings.HasPrefix(head, "!OPTION"):
+		return FormatQIF
+	}
+	return ""
+}
+
+// ParseStatement parses an OFX or QIF file. Like ParseCSV, invalid entries are returned as RowErrors
+// and the error is reserved for files that cannot be read at all.
+func ParseStatement(r io.Reader, format string, opts StatementOptions) (*Statement, []RowError, error) {
+	data, err := readText(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.DefaultCategory = strings.TrimSpace(opts.DefaultCategory); opts.DefaultCategory == "" {
+		opts.DefaultCategory = DefaultStatementCategory
+	}
+
+	switch format {
+	case FormatOFX:
+		return parseOFX(data, opts)
+	case FormatQIF:
+		return parseQIF(data, opts)
+	}
+	return nil, nil, fmt.Errorf("unsupported statement format %q", format)
+}
+
+// readText reads r as UTF-8, decoding legacy Windows-1252 files (the default charset of OFX 1.x)
+func readText(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 BOM
+
+	if utf8.Valid(data) {
+		return string(data), nil
+	}
+	decoded, err := charmap.Windows1252.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode file: %w", err)
+	}
+	return string(decoded), nil
+}
+
+// statementEntry is a raw statement line before validation
+type statementEntry struct {
+	Line       int
+	Date       string
+	Amount     string
+	Name       string
+	Memo       string
+	Category   string
+	ExternalID string
+}
+
+// toRecord validates an entry. Long names and notes are truncated rather than rejected since the
+// bank, not the user, chose them; entries without an external ID get a content fingerprint instead.
+func (e statementEntry) toRecord(parseDate func(string) (time.Time, error), decimalComma bool, opts StatementOptions, fingerprints map[string]int) (Record, []RowError) {
+	record := Record{Row: e.Line}
+	var errs []RowError
+	fail := func(column, message string) {
+		errs = append(errs, RowError{Row: e.Line, Column: column, Message: message})
+	}
+
+	if e.Date == "" {
+		fail("date", "date is required")
+	} else if date, err := parseDate(e.Date); err != nil {
+		fail("date", fmt.Sprintf("invalid date %q", e.Date))
+	} else {
+		record.Date = date
+	}
+
+	amount, err := ParseAmount(e.Amount, decimalComma)
+	switch {
+	case err != nil:
+		fail("amount", err.Error())
+	case amount.IsZero():
+		fail("amount", "amount must not be zero")
+	}
+	record.Type = "income"
+	if amount.IsNegative() {
+		record.Type = "expense"
+		amount = amount.Abs()
+	}
+	record.Amount = amount
+
+	record.Name = e.Name
+	if record.Name == "" {
+		record.Name = e.Memo
+	} else if e.Memo != "" && e.Memo != e.Name {
+		record.Note = e.Memo
+	}
+	record.Name = truncateRunes(record.Name, 255)
+	if utf8.RuneCountInString(record.Name) < 2 {
+		fail("name", "payee or memo of at least 2 characters is required")
+	}
+	record.Note = truncateRunes(record.Note, 1000)
+
+	record.Category = e.Category
+	if length := utf8.RuneCountInString(record.Category); length < 2 || length > 100 {
+		record.Category = opts.DefaultCategory
+	}
+
+	if len(errs) > 0 {
+		return record, errs
+	}
+
+	record.ExternalID = e.ExternalID
+	if record.ExternalID == "" {
+		record.ExternalID = fingerprint(record, fingerprints)
+	}
+	return record, nil
+}
+
+// fingerprint derives a stable ID from the entry content. Identical entries in the same file
+// (e.g. two coffees on one day) are told apart by their position among the duplicates.
+func fingerprint(record Record, seen map[string]int) string {
+	key := strings.Join([]string{
+		record.Date.Format(time.RFC3339),
+		record.Type,
+		record.Amount.String(),
+		record.Name,
+		record.Note,
+	}, "|")
+	seen[key]++
+
+	sum := sha256.Sum256([]byte(key + "|" + strconv.Itoa(seen[key])))
+	return "fp:" + hex.EncodeToString(sum[:16])
+}
+
+func truncateRunes(value string, limit int) string {
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+	return string([]rune(value)[:limit])
+}
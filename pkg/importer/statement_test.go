@@ -0,0 +1,140 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseFixture(t *testing.T, name string, opts StatementOptions) (*Statement, []RowError) {
+	t.Helper()
+
+	file, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	defer file.Close()
+
+	content, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	statement, rowErrors, err := ParseStatement(file, DetectStatementFormat(name, content), opts)
+	require.NoError(t, err)
+	return statement, rowErrors
+}
+
+func TestParseStatement_OFXSGML(t *testing.T) {
+	statement, rowErrors := parseFixture(t, "statement_v1.ofx", StatementOptions{})
+
+	assert.Equal(t, "USD", statement.Currency)
+	require.Len(t, statement.Records, 2)
+
+	salary := statement.Records[0]
+	assert.Equal(t, "20240105001", salary.ExternalID)
+	assert.Equal(t, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), salary.Date)
+	assert.Equal(t, money.MustParse("2500"), salary.Amount)
+	assert.Equal(t, "income", salary.Type)
+	assert.Equal(t, "ACME PAYROLL", salary.Name)
+	assert.Equal(t, "January salary", salary.Note)
+	assert.Equal(t, DefaultStatementCategory, salary.Category)
+
+	coffee := statement.Records[1]
+	assert.Equal(t, "20240106001", coffee.ExternalID)
+	assert.Equal(t, time.Date(2024, 1, 6, 14, 30, 0, 0, time.UTC), coffee.Date) // 09:30 EST
+	assert.Equal(t, money.MustParse("42.15"), coffee.Amount)
+	assert.Equal(t, "expense", coffee.Type)
+	assert.Equal(t, "Café & Bakery", coffee.Name) // Decoded from Windows-1252
+
+	require.Len(t, rowErrors, 1)
+	assert.Equal(t, "date", rowErrors[0].Column)
+	assert.Equal(t, 54, rowErrors[0].Row)
+}
+
+func TestParseStatement_OFXXML(t *testing.T) {
+	statement, rowErrors := parseFixture(t, "statement_v2.ofx", StatementOptions{DefaultCategory: "subscriptions"})
+
+	assert.Empty(t, rowErrors)
+	assert.Equal(t, "EUR", statement.Currency)
+	require.Len(t, statement.Records, 2)
+
+	assert.Equal(t, "CC-0001", statement.Records[0].ExternalID)
+	assert.Equal(t, "Streaming Service", statement.Records[0].Name) // From the PAYEE aggregate
+	assert.Equal(t, "Monthly plan", statement.Records[0].Note)
+	assert.Equal(t, money.MustParse("19.99"), statement.Records[0].Amount)
+	assert.Equal(t, "expense", statement.Records[0].Type)
+	assert.Equal(t, "subscriptions", statement.Records[0].Category)
+
+	assert.Equal(t, "Cashback", statement.Records[1].Name)
+	assert.Equal(t, "income", statement.Records[1].Type)
+}
+
+func TestParseStatement_QIF(t *testing.T) {
+	statement, rowErrors := parseFixture(t, "statement.qif", StatementOptions{})
+
+	assert.Empty(t, statement.Currency)
+	require.Len(t, statement.Records, 5)
+
+	salary := statement.Records[0]
+	assert.Equal(t, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), salary.Date)
+	assert.Equal(t, money.MustParse("2500"), salary.Amount)
+	assert.Equal(t, "income", salary.Type)
+	assert.Equal(t, "Salary", salary.Category)
+	assert.True(t, strings.HasPrefix(salary.ExternalID, "fp:"))
+
+	// Identical entries on the same day get distinct, stable IDs
+	first, second := statement.Records[1], statement.Records[2]
+	assert.Equal(t, time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), first.Date)
+	assert.Equal(t, "Food:Coffee", first.Category)
+	assert.NotEqual(t, first.ExternalID, second.ExternalID)
+
+	// Transfers carry no category
+	assert.Equal(t, DefaultStatementCategory, statement.Records[3].Category)
+
+	// The last entry has no terminator and only a U amount
+	assert.Equal(t, "Bookstore", statement.Records[4].Name)
+	assert.Equal(t, money.MustParse("15"), statement.Records[4].Amount)
+
+	require.Len(t, rowErrors, 1)
+	assert.Equal(t, "date", rowErrors[0].Column)
+
+	again, _ := parseFixture(t, "statement.qif", StatementOptions{})
+	for i := range statement.Records {
+		assert.Equal(t, statement.Records[i].ExternalID, again.Records[i].ExternalID)
+	}
+}
+
+func TestParseStatement_QIFDayFirst(t *testing.T) {
+	input := "!Type:CCard\nD31/01/2024\nT-12,50\nPLunch\n^\n"
+
+	statement, rowErrors, err := ParseStatement(strings.NewReader(input), FormatQIF, StatementOptions{DayFirst: true, DecimalComma: true})
+
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	require.Len(t, statement.Records, 1)
+	assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), statement.Records[0].Date)
+	assert.Equal(t, money.MustParse("12.5"), statement.Records[0].Amount)
+}
+
+func TestParseStatement_Rejects(t *testing.T) {
+	_, _, err := ParseStatement(strings.NewReader("!Type:Invst\nD01/05/2024\n^\n"), FormatQIF, StatementOptions{})
+	assert.EqualError(t, err, "investment accounts are not supported")
+
+	_, _, err = ParseStatement(strings.NewReader("<OFX><STMTTRN><TRNAMT>1.00"), FormatOFX, StatementOptions{})
+	assert.EqualError(t, err, "file ends inside a transaction, it may be truncated")
+
+	_, _, err = ParseStatement(strings.NewReader("date,amount\n"), FormatOFX, StatementOptions{})
+	assert.EqualError(t, err, "file is not a valid OFX statement")
+}
+
+func TestDetectStatementFormat(t *testing.T) {
+	assert.Equal(t, FormatOFX, DetectStatementFormat("export.QFX", nil))
+	assert.Equal(t, FormatQIF, DetectStatementFormat("export.qif", nil))
+	assert.Equal(t, FormatOFX, DetectStatementFormat("download", []byte("OFXHEADER:100\nDATA:OFXSGML")))
+	assert.Equal(t, FormatOFX, DetectStatementFormat("download", []byte("<?xml version=\"1.0\"?>\n<OFX>")))
+	assert.Equal(t, FormatQIF, DetectStatementFormat("download", []byte("!Type:Bank\nD01/01/2024")))
+	assert.Equal(t, "", DetectStatementFormat("download.csv", []byte("date,amount")))
+}
@@ -36,6 +36,21 @@ type EmailTemplateData struct {
 	ResetURL string
 }
 
+// BudgetAlertTemplateData represents data for the budget alert email template
+type BudgetAlertTemplateData struct {
+	Name        string
+	BudgetName  string
+	Category    string
+	Threshold   int
+	PercentUsed string
+	Spent       string
+	Limit       string
+	Remaining   string
+	PeriodStart string
+	PeriodEnd   string
+	IsExceeded  bool
+}
+
 // getEmailConfig creates email configuration from config first, then env as fallback
 func getEmailConfig() *EmailConfig {
 	cfg := config.GetConfig()
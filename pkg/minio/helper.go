@@ -37,11 +37,13 @@ type UploadPhotoDto struct {
 }
 
 type UploadPhotoResult struct {
-	Path       string
-	FullURL    string
-	Filename   string
-	BucketName string
-	IsPrivate  bool
+	Path        string
+	FullURL     string
+	Filename    string
+	BucketName  string
+	IsPrivate   bool
+	ContentType string
+	FileSize    int64
 }
 
 type DeletePhotoDto struct {
@@ -97,6 +99,8 @@ func UploadPhotoMinio(ctx context.Context, params UploadPhotoDto) (*UploadPhotoR
 		ext = ".gif"
 	case "image/webp":
 		ext = ".webp"
+	case "application/pdf":
+		ext = ".pdf"
 	default:
 		// Fallback to the original filename extension
 		ext = GetFileExtension(params.FileHeader.Filename)
@@ -165,11 +169,13 @@ func UploadPhotoMinio(ctx context.Context, params UploadPhotoDto) (*UploadPhotoR
 	}
 
 	return &UploadPhotoResult{
-		Path:       uploadResult.Path,
-		FullURL:    fullURL,
-		Filename:   filename,
-		BucketName: bucketName,
-		IsPrivate:  isPrivate,
+		Path:        uploadResult.Path,
+		FullURL:     fullURL,
+		Filename:    filename,
+		BucketName:  bucketName,
+		IsPrivate:   isPrivate,
+		ContentType: uploadResult.ContentType,
+		FileSize:    uploadResult.FileSize,
 	}, nil
 }
 
@@ -0,0 +1,424 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of decimal places kept, matching the decimal(20,8) money columns
+const Scale = 8
+
+// unit is the number of base units in 1
+const unit = 100000000
+
+// Money is an exact fixed-point amount stored as a signed 128-bit count of 10^-8 units.
+// It covers about ±1.7 * 10^30, far beyond the ±10^12 a decimal(20,8) column holds, so every stored amount
+// can be read back and sums of them cannot realistically overflow. Arithmetic that would still leave the
+// range panics instead of silently wrapping around.
+// The zero value is 0 and two amounts can be compared with ==.
+type Money struct {
+	hi int64  // High 64 bits, two's complement
+	lo uint64 // Low 64 bits
+}
+
+// Zero is the zero amount
+var Zero = Money{}
+
+// MaxStored is the largest amount a decimal(20,8) money column holds, larger amounts only come from sums
+var MaxStored = MustParse("999999999999.99999999")
+
+var (
+	ErrInvalid   = errors.New("invalid amount")
+	ErrPrecision = fmt.Errorf("amount has more than %d decimal places", Scale)
+	ErrRange     = errors.New("amount is out of range")
+)
+
+// FromInt returns the amount for a whole number of units
+func FromInt(n int64) Money {
+	m, _ := fromMagnitude(uint128{lo: abs64(n)}.mul(unit), n < 0)
+	return m
+}
+
+// fromUnits returns the amount for a count of 10^-8 units
+func fromUnits(n int64) Money {
+	return Money{hi: n >> 63, lo: uint64(n)}
+}
+
+// FromFloat converts a float, rounding to the nearest 10^-8. It is meant for the boundaries
+// that only offer floats (e.g. drivers); amounts must not be computed as floats.
+func FromFloat(f float64) (Money, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Zero, ErrInvalid
+	}
+	return Parse(strconv.FormatFloat(f, 'f', Scale, 64))
+}
+
+// Parse parses a plain decimal such as "-1234.5", "0.00000001" or "1e3".
+// Values with more than 8 decimal places are rejected rather than rounded.
+func Parse(s string) (Money, error) {
+	value := s
+	negative := false
+	if value != "" && (value[0] == '-' || value[0] == '+') {
+		negative = value[0] == '-'
+		value = value[1:]
+	}
+
+	exponent := 0
+	if i := strings.IndexAny(value, "eE"); i >= 0 {
+		e, err := strconv.Atoi(value[i+1:])
+		if err != nil || e > 40 || e < -40 {
+			return Zero, fmt.Errorf("%w %q", ErrInvalid, s)
+		}
+		exponent = e
+		value = value[:i]
+	}
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" && fraction == "" {
+		return Zero, fmt.Errorf("%w %q", ErrInvalid, s)
+	}
+	digits := whole + fraction
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return Zero, fmt.Errorf("%w %q", ErrInvalid, s)
+		}
+	}
+
+	// digits * 10^(exponent - len(fraction)) expressed in base units
+	shift := exponent - len(fraction) + Scale
+	digits = strings.TrimLeft(digits, "0")
+	for shift < 0 {
+		if digits == "" {
+			break
+		}
+		if digits[len(digits)-1] != '0' {
+			return Zero, fmt.Errorf("%w: %q", ErrPrecision, s)
+		}
+		digits = digits[:len(digits)-1]
+		shift++
+	}
+	if digits == "" {
+		return Zero, nil
+	}
+	if len(digits)+shift > 39 {
+		return Zero, fmt.Errorf("%w: %q", ErrRange, s)
+	}
+
+	var units uint128
+	overflow := false
+	for i := 0; i < len(digits)+shift; i++ {
+		digit := uint64(0)
+		if i < len(digits) {
+			digit = uint64(digits[i] - '0')
+		}
+		var carry bool
+		units, carry = units.mulAdd(10, digit)
+		overflow = overflow || carry
+	}
+
+	m, ok := fromMagnitude(units, negative)
+	if overflow || !ok {
+		return Zero, fmt.Errorf("%w: %q", ErrRange, s)
+	}
+	return m, nil
+}
+
+// MustParse is like Parse but panics on error, for constants and tests
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) Money {
+	lo, carry := bits.Add64(m.lo, other.lo, 0)
+	hi, _ := bits.Add64(uint64(m.hi), uint64(other.hi), carry)
+	sum := Money{hi: int64(hi), lo: lo}
+	// Overflow happened when both operands have the same sign and the sum has the other one
+	if (m.hi >= 0) == (other.hi >= 0) && (sum.hi >= 0) != (m.hi >= 0) {
+		panic(ErrRange)
+	}
+	return sum
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) Money {
+	return m.Add(other.Neg())
+}
+
+// Mul returns m multiplied by a whole number, e.g. a per-period amount times the number of periods
+func (m Money) Mul(n int64) Money {
+	product, overflow := m.magnitude().mulOverflow(abs64(n))
+	result, ok := fromMagnitude(product, m.IsNegative() != (n < 0))
+	if overflow || !ok {
+		panic(ErrRange)
+	}
+	return result
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	result, ok := fromMagnitude(m.magnitude(), !m.IsNegative())
+	if !ok {
+		panic(ErrRange)
+	}
+	return result
+}
+
+// Abs returns the absolute value of m
+func (m Money) Abs() Money {
+	if m.IsNegative() {
+		return m.Neg()
+	}
+	return m
+}
+
+// Cmp returns -1, 0 or +1 depending on whether m is less than, equal to or greater than other
+func (m Money) Cmp(other Money) int {
+	switch {
+	case m.hi < other.hi:
+		return -1
+	case m.hi > other.hi:
+		return 1
+	case m.lo < other.lo:
+		return -1
+	case m.lo > other.lo:
+		return 1
+	}
+	return 0
+}
+
+// Storable reports whether m fits a decimal(20,8) money column, i.e. lies within ±MaxStored
+func (m Money) Storable() bool {
+	return m.Cmp(MaxStored) <= 0 && m.Cmp(MaxStored.Neg()) >= 0
+}
+
+// IsZero reports whether m is 0
+func (m Money) IsZero() bool {
+	return m == Zero
+}
+
+// IsNegative reports whether m is below 0
+func (m Money) IsNegative() bool {
+	return m.hi < 0
+}
+
+// IsPositive reports whether m is above 0
+func (m Money) IsPositive() bool {
+	return m.hi > 0 || (m.hi == 0 && m.lo > 0)
+}
+
+// Float64 returns the closest float, for ratios and display only
+func (m Money) Float64() float64 {
+	whole, fraction := m.magnitude().divMod(unit)
+	f := whole.float64() + float64(fraction)/unit
+	if m.IsNegative() {
+		return -f
+	}
+	return f
+}
+
+// String formats m as a plain decimal without trailing zeros, e.g. "1500", "-0.25"
+func (m Money) String() string {
+	return string(m.appendDecimal(nil))
+}
+
+func (m Money) appendDecimal(buf []byte) []byte {
+	if m.IsNegative() {
+		buf = append(buf, '-')
+	}
+	whole, fraction := m.magnitude().divMod(unit)
+	buf = whole.appendDecimal(buf)
+
+	if fraction == 0 {
+		return buf
+	}
+	digits := strconv.FormatUint(fraction+unit, 10)[1:] // Zero padded to Scale digits
+	buf = append(buf, '.')
+	return append(buf, strings.TrimRight(digits, "0")...)
+}
+
+// Round rounds m to the given number of decimal places (0 to 8), halves away from zero
+func (m Money) Round(places int) Money {
+	if places < 0 || places >= Scale {
+		return m
+	}
+	step := uint64(math.Pow10(Scale - places))
+	quotient, remainder := m.magnitude().divMod(step)
+	if remainder >= (step+1)/2 {
+		quotient, _ = quotient.mulAdd(1, 1)
+	}
+	rounded, overflow := quotient.mulOverflow(step)
+	result, ok := fromMagnitude(rounded, m.IsNegative())
+	if overflow || !ok {
+		panic(ErrRange)
+	}
+	return result
+}
+
+// StringFixed formats m rounded to exactly the given number of decimal places (0 to 8), e.g. "1500.00"
+func (m Money) StringFixed(places int) string {
+	places = min(max(places, 0), Scale)
+	rounded := m.Round(places)
+
+	whole, fraction, _ := strings.Cut(rounded.String(), ".")
+	if places == 0 {
+		return whole
+	}
+	return whole + "." + fraction + strings.Repeat("0", places-len(fraction))
+}
+
+// MarshalJSON encodes m as an exact JSON number
+func (m Money) MarshalJSON() ([]byte, error) {
+	return m.appendDecimal(nil), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a string holding one, without going through float64
+func (m *Money) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if value == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = strings.TrimSpace(unquoted)
+	}
+
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, passing the amount to the database as an exact decimal string
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan implements sql.Scanner for numeric columns
+func (m *Money) Scan(src any) error {
+	var (
+		parsed Money
+		err    error
+	)
+	switch v := src.(type) {
+	case nil:
+		parsed = Zero
+	case []byte:
+		parsed, err = Parse(string(v))
+	case string:
+		parsed, err = Parse(v)
+	case int64:
+		parsed = FromInt(v)
+	case float64:
+		parsed, err = FromFloat(v)
+	default:
+		return fmt.Errorf("cannot scan %T into money.Money", src)
+	}
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Sum adds up amounts exactly
+func Sum(amounts ...Money) Money {
+	total := Zero
+	for _, amount := range amounts {
+		total = total.Add(amount)
+	}
+	return total
+}
+
+func abs64(n int64) uint64 {
+	if n < 0 {
+		return -uint64(n)
+	}
+	return uint64(n)
+}
+
+// magnitude returns |m| as an unsigned 128-bit number
+func (m Money) magnitude() uint128 {
+	u := uint128{hi: uint64(m.hi), lo: m.lo}
+	if m.IsNegative() {
+		lo, borrow := bits.Sub64(0, u.lo, 0)
+		hi, _ := bits.Sub64(0, u.hi, borrow)
+		u = uint128{hi: hi, lo: lo}
+	}
+	return u
+}
+
+// fromMagnitude returns the amount with magnitude u and the given sign, false when u is out of range
+func fromMagnitude(u uint128, negative bool) (Money, bool) {
+	if u.hi > math.MaxInt64 {
+		return Zero, false
+	}
+	m := Money{hi: int64(u.hi), lo: u.lo}
+	if negative && !m.IsZero() {
+		lo, borrow := bits.Sub64(0, m.lo, 0)
+		hi, _ := bits.Sub64(0, uint64(m.hi), borrow)
+		m = Money{hi: int64(hi), lo: lo}
+	}
+	return m, true
+}
+
+// uint128 is an unsigned 128-bit number, the magnitude of an amount
+type uint128 struct {
+	hi, lo uint64
+}
+
+// mulAdd returns u*n + add and whether it overflowed
+func (u uint128) mulAdd(n, add uint64) (uint128, bool) {
+	product, overflow := u.mulOverflow(n)
+	lo, carry := bits.Add64(product.lo, add, 0)
+	hi, carry := bits.Add64(product.hi, 0, carry)
+	return uint128{hi: hi, lo: lo}, overflow || carry != 0
+}
+
+// mul returns u*n, for products known to fit
+func (u uint128) mul(n uint64) uint128 {
+	product, _ := u.mulOverflow(n)
+	return product
+}
+
+// mulOverflow returns u*n and whether it overflowed
+func (u uint128) mulOverflow(n uint64) (uint128, bool) {
+	carry, lo := bits.Mul64(u.lo, n)
+	hiCarry, hi := bits.Mul64(u.hi, n)
+	hi, sumCarry := bits.Add64(hi, carry, 0)
+	return uint128{hi: hi, lo: lo}, hiCarry != 0 || sumCarry != 0
+}
+
+// divMod returns u / d and u % d
+func (u uint128) divMod(d uint64) (uint128, uint64) {
+	hi, remainder := u.hi/d, u.hi%d
+	lo, remainder := bits.Div64(remainder, u.lo, d)
+	return uint128{hi: hi, lo: lo}, remainder
+}
+
+func (u uint128) float64() float64 {
+	return float64(u.hi)*(1<<64) + float64(u.lo)
+}
+
+// appendDecimal appends u in base 10
+func (u uint128) appendDecimal(buf []byte) []byte {
+	if u.hi == 0 {
+		return strconv.AppendUint(buf, u.lo, 10)
+	}
+	const chunk = 10000000000000000000 // 10^19, the largest power of ten in a uint64
+	quotient, remainder := u.divMod(chunk)
+	buf = quotient.appendDecimal(buf)
+	digits := strconv.FormatUint(remainder, 10)
+	buf = append(buf, strings.Repeat("0", 19-len(digits))...)
+	return append(buf, digits...)
+}
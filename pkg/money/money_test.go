@@ -0,0 +1,179 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSum_ManySmallAmountsIsExact(t *testing.T) {
+	cent := MustParse("0.01")
+	total := Zero
+	floatTotal := 0.0
+	for i := 0; i < 1_000_000; i++ {
+		total = total.Add(cent)
+		floatTotal += 0.01
+	}
+
+	assert.Equal(t, FromInt(10000), total)
+	assert.Equal(t, "10000", total.String())
+	assert.NotEqual(t, 10000.0, floatTotal) // The float64 sum this type replaces drifts
+
+	// Adding the same amounts back out returns exactly to zero
+	for i := 0; i < 1_000_000; i++ {
+		total = total.Sub(cent)
+	}
+	assert.True(t, total.IsZero())
+}
+
+func TestSum_MixedIncomeAndExpenses(t *testing.T) {
+	amounts := []Money{MustParse("0.1"), MustParse("0.2"), MustParse("-0.3")}
+	for i := 0; i < 10; i++ {
+		amounts = append(amounts, amounts...)
+	}
+
+	assert.Equal(t, Zero, Sum(amounts...))
+	assert.Equal(t, MustParse("0.3"), MustParse("0.1").Add(MustParse("0.2")))
+}
+
+func TestParse(t *testing.T) {
+	cases := map[string]Money{
+		"0":                   Zero,
+		"1500":                fromUnits(150000000000),
+		"-0.25":               fromUnits(-25000000),
+		"+42.15":              fromUnits(4215000000),
+		".5":                  fromUnits(50000000),
+		"7.":                  fromUnits(700000000),
+		"0.00000001":          fromUnits(1),
+		"12.3400000000":       fromUnits(1234000000),
+		"1e3":                 fromUnits(100000000000),
+		"2.5E-2":              fromUnits(2500000),
+		"92233720368.5477580": fromUnits(9223372036854775800),
+	}
+	for input, expected := range cases {
+		actual, err := Parse(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, actual, input)
+	}
+
+	for _, input := range []string{"", "-", ".", "abc", "1,5", "1.2.3", "1e", "--1"} {
+		_, err := Parse(input)
+		assert.ErrorIs(t, err, ErrInvalid, input)
+	}
+
+	_, err := Parse("0.000000001")
+	assert.ErrorIs(t, err, ErrPrecision)
+	_, err = Parse("1e31")
+	assert.ErrorIs(t, err, ErrRange)
+	_, err = Parse("-1701411834604692317316873037158.84105728")
+	assert.ErrorIs(t, err, ErrRange)
+}
+
+func TestParse_BeyondInt64Units(t *testing.T) {
+	// The largest decimal(20,8) value and amounts well past the old ±92 billion limit round-trip exactly
+	for _, input := range []string{
+		"999999999999.99999999",
+		"-999999999999.99999999",
+		"5000000000000000",
+		"1701411834604692317316873037158.84105727",
+		"-1701411834604692317316873037158.84105727",
+	} {
+		m, err := Parse(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, input, m.String(), input)
+	}
+}
+
+func TestString(t *testing.T) {
+	assert.Equal(t, "0", Zero.String())
+	assert.Equal(t, "1000.5", MustParse("1000.50").String())
+	assert.Equal(t, "-0.00000001", fromUnits(-1).String())
+	assert.Equal(t, "-92233720368.54775808", fromUnits(math.MinInt64).String())
+	assert.Equal(t, "184467440737.09551616", fromUnits(math.MaxInt64).Add(fromUnits(math.MaxInt64)).Add(fromUnits(2)).String())
+}
+
+func TestStringFixed(t *testing.T) {
+	assert.Equal(t, "1500.00", FromInt(1500).StringFixed(2))
+	assert.Equal(t, "0.13", MustParse("0.125").StringFixed(2))
+	assert.Equal(t, "-0.13", MustParse("-0.125").StringFixed(2))
+	assert.Equal(t, "-0.12", MustParse("-0.124").StringFixed(2))
+	assert.Equal(t, "10", MustParse("9.5").StringFixed(0))
+	assert.Equal(t, "0.00000001", fromUnits(1).StringFixed(8))
+	assert.Equal(t, "123456789012345.68", MustParse("123456789012345.675").StringFixed(2))
+}
+
+func TestArithmetic(t *testing.T) {
+	assert.Equal(t, MustParse("450000"), MustParse("150000").Mul(3))
+	assert.Equal(t, MustParse("-450000"), MustParse("150000").Mul(-3))
+	assert.Equal(t, MustParse("12.5"), MustParse("-12.5").Abs())
+	assert.Equal(t, -1, MustParse("1.99").Cmp(MustParse("2")))
+	assert.InDelta(t, 58.14, MustParse("58.14").Float64(), 1e-9)
+
+	assert.InDelta(t, -5e15, MustParse("-5000000000000000").Float64(), 1)
+
+	// Sums of IDR balances past the old int64 limit stay exact
+	balance := MustParse("90000000000")
+	assert.Equal(t, MustParse("180000000000"), balance.Add(balance))
+	assert.Equal(t, MustParse("-10000000000"), balance.Sub(MustParse("100000000000")))
+	assert.Equal(t, MustParse("900000000000"), balance.Mul(10))
+	assert.Equal(t, 1, balance.Mul(10).Cmp(balance))
+	assert.Equal(t, -1, balance.Neg().Cmp(Zero))
+
+	largest := Money{hi: math.MaxInt64, lo: math.MaxUint64}
+	assert.PanicsWithValue(t, ErrRange, func() { largest.Add(fromUnits(1)) })
+	assert.PanicsWithValue(t, ErrRange, func() { largest.Neg().Sub(fromUnits(2)) })
+	assert.PanicsWithValue(t, ErrRange, func() { largest.Mul(2) })
+}
+
+func TestStorable(t *testing.T) {
+	assert.True(t, Zero.Storable())
+	assert.True(t, MaxStored.Storable())
+	assert.True(t, MaxStored.Neg().Storable())
+	assert.False(t, MaxStored.Add(fromUnits(1)).Storable())
+	assert.False(t, MaxStored.Neg().Sub(fromUnits(1)).Storable())
+	assert.False(t, MustParse("1e15").Storable())
+}
+
+func TestJSON(t *testing.T) {
+	var payload struct {
+		Cost    Money `json:"cost"`
+		Balance Money `json:"balance"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"cost": 50000.10, "balance": "0.30"}`), &payload))
+	assert.Equal(t, MustParse("50000.1"), payload.Cost)
+	assert.Equal(t, MustParse("0.3"), payload.Balance)
+
+	encoded, err := json.Marshal(payload)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"cost": 50000.1, "balance": 0.3}`, string(encoded))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"cost": true}`), &payload))
+	assert.Error(t, json.Unmarshal([]byte(`{"cost": 0.123456789}`), &payload))
+}
+
+func TestScanAndValue(t *testing.T) {
+	var m Money
+	require.NoError(t, m.Scan([]byte("1234.56780000")))
+	assert.Equal(t, MustParse("1234.5678"), m)
+
+	require.NoError(t, m.Scan(int64(-7)))
+	assert.Equal(t, FromInt(-7), m)
+
+	require.NoError(t, m.Scan([]byte("-999999999999.99999999")))
+	assert.Equal(t, "-999999999999.99999999", m.String())
+
+	require.NoError(t, m.Scan(0.1))
+	assert.Equal(t, MustParse("0.1"), m)
+
+	require.NoError(t, m.Scan(nil))
+	assert.True(t, m.IsZero())
+
+	assert.Error(t, m.Scan(true))
+
+	value, err := MustParse("-0.5").Value()
+	require.NoError(t, err)
+	assert.Equal(t, "-0.5", value)
+}
@@ -0,0 +1,175 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RateScale is the number of decimal places kept when a rate is stored, matching the decimal(24,12) column
+const RateScale = 12
+
+var ErrInvalidRate = errors.New("exchange rate must be a positive decimal")
+
+// Rate is an exchange rate, the price of one unit of a base currency in a quote currency.
+// It is kept as an exact fraction so inverted and cross rates do not lose precision until an
+// amount is converted. The zero value is not a valid rate.
+type Rate struct {
+	value *big.Rat
+}
+
+// ParseRate parses a positive decimal such as "16250.5" or "0.0000615", rounded to 12 decimal places
+func ParseRate(s string) (Rate, error) {
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || value.Sign() <= 0 {
+		return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+
+	rate := Rate{value: roundRat(value, RateScale)}
+	if rate.IsZero() {
+		return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	return rate, nil
+}
+
+// MustParseRate is like ParseRate but panics on error, for constants and tests
+func MustParseRate(s string) Rate {
+	r, err := ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// IsZero reports whether r is unset
+func (r Rate) IsZero() bool {
+	return r.value == nil || r.value.Sign() == 0
+}
+
+// Invert returns the rate in the opposite direction (quote to base)
+func (r Rate) Invert() Rate {
+	if r.IsZero() {
+		return Rate{}
+	}
+	return Rate{value: new(big.Rat).Inv(r.value)}
+}
+
+// Mul chains two rates, e.g. IDR->USD times USD->EUR gives IDR->EUR
+func (r Rate) Mul(other Rate) Rate {
+	if r.IsZero() || other.IsZero() {
+		return Rate{}
+	}
+	return Rate{value: new(big.Rat).Mul(r.value, other.value)}
+}
+
+// Equal reports whether both rates are the same number
+func (r Rate) Equal(other Rate) bool {
+	if r.IsZero() || other.IsZero() {
+		return r.IsZero() == other.IsZero()
+	}
+	return r.value.Cmp(other.value) == 0
+}
+
+// String formats r with up to 12 decimal places and no trailing zeros
+func (r Rate) String() string {
+	if r.IsZero() {
+		return "0"
+	}
+	formatted := r.value.FloatString(RateScale)
+	formatted = strings.TrimRight(formatted, "0")
+	return strings.TrimSuffix(formatted, ".")
+}
+
+// Convert returns m expressed in the quote currency of rate, rounded half away from zero to 8 decimal places.
+// It returns ErrRange when the converted amount does not fit in a Money.
+func (m Money) Convert(rate Rate) (Money, error) {
+	if rate.IsZero() {
+		return Zero, ErrInvalidRate
+	}
+
+	amount := new(big.Rat).SetFrac(m.bigInt(), big.NewInt(unit))
+	amount.Mul(amount, rate.value)
+
+	return Parse(roundRat(amount, Scale).FloatString(Scale))
+}
+
+// bigInt returns m as a count of base units
+func (m Money) bigInt() *big.Int {
+	magnitude := m.magnitude()
+	units := new(big.Int).SetUint64(magnitude.hi)
+	units.Lsh(units, 64).Or(units, new(big.Int).SetUint64(magnitude.lo))
+	if m.IsNegative() {
+		units.Neg(units)
+	}
+	return units
+}
+
+// roundRat rounds value to the given number of decimal places, halves away from zero
+func roundRat(value *big.Rat, places int) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil)
+	scaled := new(big.Int).Mul(value.Num(), scale)
+
+	quotient, remainder := new(big.Int).QuoRem(scaled, value.Denom(), new(big.Int))
+	if remainder.Sign() != 0 && new(big.Int).Mul(remainder.Abs(remainder), big.NewInt(2)).Cmp(value.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+	}
+	return new(big.Rat).SetFrac(quotient, scale)
+}
+
+// MarshalJSON encodes r as a JSON number
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a string holding one
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if value == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	parsed, err := ParseRate(value)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Value implements driver.Valuer
+func (r Rate) Value() (driver.Value, error) {
+	if r.IsZero() {
+		return nil, ErrInvalidRate
+	}
+	return r.String(), nil
+}
+
+// Scan implements sql.Scanner for numeric columns
+func (r *Rate) Scan(src any) error {
+	var value string
+	switch v := src.(type) {
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	default:
+		return fmt.Errorf("cannot scan %T into money.Rate", src)
+	}
+
+	parsed, err := ParseRate(value)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
@@ -0,0 +1,63 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	assert.Equal(t, "16250.5", MustParseRate("16250.50").String())
+	assert.Equal(t, "0.000061538462", MustParseRate("0.0000615384615384").String()) // Rounded to 12 places
+
+	for _, input := range []string{"", "0", "-1.5", "abc", "0.0000000000001"} {
+		_, err := ParseRate(input)
+		assert.ErrorIs(t, err, ErrInvalidRate, input)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	usdToIDR := MustParseRate("16250")
+
+	convert := func(m Money, rate Rate) Money {
+		converted, err := m.Convert(rate)
+		require.NoError(t, err)
+		return converted
+	}
+
+	assert.Equal(t, MustParse("1625000"), convert(FromInt(100), usdToIDR))
+	assert.Equal(t, MustParse("100"), convert(MustParse("1625000"), usdToIDR.Invert())) // Inverse stays exact
+	assert.Equal(t, MustParse("-0.00000062"), convert(MustParse("-0.01"), usdToIDR.Invert()))
+
+	// Cross rate through a common base: EUR->USD->IDR
+	eurToUSD := MustParseRate("1.08")
+	assert.Equal(t, MustParse("17550"), convert(FromInt(1), eurToUSD.Mul(usdToIDR)))
+
+	// Out of range and invalid rates are errors, not panics
+	_, err := MustParse("999999999999999999999999").Convert(MustParseRate("100000000"))
+	assert.ErrorIs(t, err, ErrRange)
+	_, err = FromInt(1).Convert(Rate{})
+	assert.ErrorIs(t, err, ErrInvalidRate)
+}
+
+func TestRateJSONAndScan(t *testing.T) {
+	var payload struct {
+		Rate Rate `json:"rate"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"rate": "0.92"}`), &payload))
+	assert.True(t, MustParseRate("0.92").Equal(payload.Rate))
+
+	encoded, err := json.Marshal(payload)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"rate": 0.92}`, string(encoded))
+
+	var scanned Rate
+	require.NoError(t, scanned.Scan([]byte("16250.000000000000")))
+	assert.Equal(t, "16250", scanned.String())
+	assert.Error(t, scanned.Scan(nil))
+
+	_, err = Rate{}.Value()
+	assert.ErrorIs(t, err, ErrInvalidRate)
+}
@@ -39,6 +39,23 @@ var (
 		AllowedTypes: []string{"image/jpeg", "image/png"},
 		Required:     false,
 	}
+
+	// CSVImportValidation defines validation rules for transaction and exchange rate CSV imports
+	CSVImportValidation = validator.FileValidation{
+		MaxSize:      4 * 1024 * 1024, // 4MB, within Fiber's default body limit
+		AllowedTypes: []string{"text/csv"},
+		Required:     true,
+	}
+
+	// StatementValidation defines validation rules for OFX/QIF bank statement uploads
+	StatementValidation = validator.FileValidation{
+		MaxSize:      4 * 1024 * 1024,                    // 4MB, within Fiber's default body limit
+		AllowedTypes: []string{"text/plain", "text/csv"}, // Single-line OFX files may sniff as CSV
+		Required:     true,
+	}
+
+	// TransactionAttachmentValidation defines validation rules for receipts attached to transactions (documents and JPEG/PNG images)
+	TransactionAttachmentValidation = CreateCustomDocumentValidation(5, true, true)
 )
 
 // File size constants for easy reference
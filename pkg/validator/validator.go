@@ -12,6 +12,8 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/currency"
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
 )
 
 // ============================================================================
@@ -49,10 +51,24 @@ func New() *Validator {
 	// Register custom validators
 	v.validate.RegisterValidation("datetime", v.validateDateTime)
 	v.validate.RegisterValidation("strongpassword", v.validateStrongPassword)
+	v.validate.RegisterValidation("currency", v.validateCurrency)
+	v.validate.RegisterValidation("money", v.validateMoney)
+
+	// Amounts are validated as numbers, so tags like gt=0 and min=0 apply to them
+	v.validate.RegisterCustomTypeFunc(validateMoneyValue, money.Money{})
 
 	return v
 }
 
+// validateMoneyValue exposes an amount to the validation tags as a float, which is exact enough for comparing
+// it against a bound
+func validateMoneyValue(field reflect.Value) interface{} {
+	if amount, ok := field.Interface().(money.Money); ok {
+		return amount.Float64()
+	}
+	return nil
+}
+
 // ============================================================================
 // Core Validation Methods
 // ============================================================================
@@ -229,6 +245,26 @@ func (v *Validator) validateStrongPassword(fl validator.FieldLevel) bool {
 	return true
 }
 
+// validateCurrency validates an ISO 4217 currency code, in any letter case
+func (v *Validator) validateCurrency(fl validator.FieldLevel) bool {
+	return currency.IsValid(fl.Field().String())
+}
+
+// validateMoney validates that an amount fits the decimal(20,8) money columns. Amounts reach the validation
+// tags as floats, so the amount is read from its struct field to compare it exactly.
+func (v *Validator) validateMoney(fl validator.FieldLevel) bool {
+	field := fl.Parent().FieldByName(fl.StructFieldName())
+	for field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return true
+		}
+		field = field.Elem()
+	}
+
+	amount, ok := field.Interface().(money.Money)
+	return ok && amount.Storable()
+}
+
 // ============================================================================
 // Helper Methods
 // ============================================================================
@@ -258,6 +294,10 @@ func (v *Validator) getValidationErrorMessage(err validator.FieldError, fieldNam
 		return fmt.Sprintf("field '%s' must be a valid date in format %s", fieldName, err.Param())
 	case "strongpassword":
 		return fmt.Sprintf("field '%s' must contain at least 1 uppercase letter, 1 number, and 1 special character", fieldName)
+	case "currency":
+		return fmt.Sprintf("field '%s' must be an ISO 4217 currency code such as IDR or USD", fieldName)
+	case "money":
+		return fmt.Sprintf("field '%s' must not exceed %s in absolute value", fieldName, money.MaxStored)
 	case "required":
 		return fmt.Sprintf("field '%s' is required", fieldName)
 	case "email":
@@ -320,12 +360,12 @@ func (v *Validator) detectFileContentType(file *multipart.FileHeader) string {
 
 	// Read first 512 bytes to detect content type
 	buffer := make([]byte, 512)
-	_, err = fileReader.Read(buffer)
+	n, err := fileReader.Read(buffer)
 	if err != nil {
 		return "unknown"
 	}
 
-	return v.detectContentType(buffer)
+	return v.detectContentType(buffer[:n])
 }
 
 // detectContentType detects the content type from file bytes
@@ -349,9 +389,34 @@ func (v *Validator) detectContentType(data []byte) string {
 		return "image/gif"
 	}
 
+	// Check for delimited text (CSV), there are no magic bytes so sniff the first line
+	if v.isPlainText(data) {
+		firstLine, _, _ := strings.Cut(string(data), "\n")
+		// Every delimiter the CSV import accepts: comma, semicolon, tab and pipe
+		if strings.ContainsAny(firstLine, ",;\t|") {
+			return "text/csv"
+		}
+		return "text/plain"
+	}
+
 	return "unknown"
 }
 
+// isPlainText reports whether data is text without binary control characters.
+// Legacy single-byte encodings (e.g. Windows-1252 bank exports) count as text too.
+func (v *Validator) isPlainText(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+
+	for _, b := range data {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
 // isContentTypeAllowed checks if the content type is in the allowed list
 func (v *Validator) isContentTypeAllowed(contentType string, allowedTypes []string) bool {
 	for _, allowedType := range allowedTypes {
@@ -0,0 +1,59 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/naufalfazanadi/finance-manager-go/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectContentType_DelimitedText(t *testing.T) {
+	v := New()
+
+	for _, input := range []string{
+		"date,name,amount\n2024-01-05,Coffee,4.50\n",
+		"date;name;amount\n",
+		"date\tname\tamount\n",
+		"date|name|amount\n2024-01-05|Coffee|4.50\n",
+	} {
+		assert.Equal(t, "text/csv", v.detectContentType([]byte(input)), input)
+	}
+
+	assert.Equal(t, "text/plain", v.detectContentType([]byte("just a note\n")))
+}
+
+func TestValidate_MoneyBounds(t *testing.T) {
+	v := New()
+	type request struct {
+		Amount  money.Money  `validate:"required,gt=0"`
+		Balance *money.Money `validate:"omitempty,min=0"`
+	}
+
+	assert.NoError(t, v.Validate(&request{Amount: money.MustParse("0.00000001")}))
+	assert.NoError(t, v.Validate(&request{Amount: money.MustParse("500000000000")}))
+	assert.Error(t, v.Validate(&request{}))
+	assert.Error(t, v.Validate(&request{Amount: money.MustParse("-1")}))
+
+	negative := money.MustParse("-0.5")
+	assert.Error(t, v.Validate(&request{Amount: money.FromInt(1), Balance: &negative}))
+}
+
+func TestValidate_MoneyFitsColumn(t *testing.T) {
+	v := New()
+	type request struct {
+		Amount  money.Money  `validate:"required,gt=0,money"`
+		Balance *money.Money `validate:"omitempty,min=0,money"`
+	}
+
+	assert.NoError(t, v.Validate(&request{Amount: money.MaxStored}))
+	assert.NoError(t, v.Validate(&request{Amount: money.FromInt(1), Balance: &money.MaxStored}))
+
+	// One unit past the column, which a float comparison could not tell apart from the largest stored amount
+	tooLarge := money.MaxStored.Add(money.MustParse("0.00000001"))
+	err := v.Validate(&request{Amount: tooLarge})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "field 'amount' must not exceed 999999999999.99999999")
+	assert.Error(t, v.Validate(&request{Amount: money.FromInt(1), Balance: &tooLarge}))
+	assert.Error(t, v.Validate(&request{Amount: money.MustParse("1e15")}))
+}